@@ -6,6 +6,7 @@ import (
 	"log"
 	"net"
 	"os"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/go-logr/stdr"
@@ -19,9 +20,11 @@ import (
 )
 
 var (
-	minMemberCount int
-	linearCutoff   int
-	maxMemberCount int
+	minMemberCount        int
+	linearCutoff          int
+	maxMemberCount        int
+	warmupProtocolPeriods int
+	phiThreshold          float64
 )
 
 // failureDetectionCmd represents the firstdetection command.
@@ -29,12 +32,13 @@ var failureDetectionCmd = &cobra.Command{
 	Use:   "failure-detection",
 	Short: "How long a cluster needs to detect a failed member.",
 	Long: `Simulates clusters of different sizes with one member failed.
-Measures the number of protocol periods until any non-faulty member declares the failed member as faulty.`,
+Measures the number of protocol periods until any non-faulty member declares the failed member as faulty.
+Every cluster size is simulated once with the SWIM failure detector and once with the phi accrual failure detector.`,
 	SilenceUsage: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		logger := stdr.New(log.New(os.Stdout, "", log.LstdFlags))
 
-		return Simulate(minMemberCount, linearCutoff, maxMemberCount, logger)
+		return Simulate(minMemberCount, linearCutoff, maxMemberCount, warmupProtocolPeriods, phiThreshold, logger)
 	},
 }
 
@@ -59,30 +63,72 @@ func RegisterSubCommand(command *cobra.Command) {
 		512,
 		"The maximum member count to simulate.",
 	)
+	failureDetectionCmd.PersistentFlags().IntVar(
+		&warmupProtocolPeriods,
+		"warmup-protocol-periods",
+		64,
+		"The number of protocol periods all members are healthy before one member fails. The phi accrual failure detector needs this time to learn the inter-arrival times.",
+	)
+	failureDetectionCmd.PersistentFlags().Float64Var(
+		&phiThreshold,
+		"phi-threshold",
+		membership.DefaultConfig.PhiThreshold,
+		"The suspicion level above which the phi accrual failure detector declares a member as suspect.",
+	)
+}
+
+// clock is the simulated time all members share. The phi accrual failure detector measures inter-arrival times with
+// it, as the simulation runs protocol periods faster than in real time.
+type clock struct {
+	now time.Time
+}
+
+// Now returns the simulated time.
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+// advance moves the simulated time forward by one protocol period.
+func (c *clock) advance() {
+	c.now = c.now.Add(time.Second)
 }
 
-// Simulate measures the time in protocol periods in which a failed member is detected by any other member.
-func Simulate(minMemberCount int, linearCutoff int, maxMemberCount int, logger logr.Logger) error {
+// Simulate measures the time in protocol periods in which a failed member is detected by any other member. Every
+// cluster size is simulated with both failure detectors.
+func Simulate(minMemberCount int, linearCutoff int, maxMemberCount int, warmupProtocolPeriods int, phiThreshold float64, logger logr.Logger) error {
 	for memberCount := range utility.ClusterSize(minMemberCount, linearCutoff, maxMemberCount) {
-		memoryTransport := transport.NewMemory()
+		for _, failureDetector := range []membership.FailureDetector{
+			membership.FailureDetectorSWIM,
+			membership.FailureDetectorPhiAccrual,
+		} {
+			memoryTransport := transport.NewMemory()
+			simulatedClock := &clock{}
 
-		lists, err := buildCluster(memberCount, memoryTransport)
-		if err != nil {
-			return err
-		}
-		if err := runProtocol(logger, lists, memoryTransport, memberCount); err != nil {
-			return err
+			lists, err := buildCluster(memberCount, memoryTransport, simulatedClock, failureDetector, phiThreshold)
+			if err != nil {
+				return err
+			}
+			if err := warmup(lists, memoryTransport, simulatedClock, warmupProtocolPeriods); err != nil {
+				return err
+			}
+
+			// We simulate the last member to crash.
+			failedList := lists[len(lists)-1]
+			memoryTransport.RemoveTarget(failedList.Config().AdvertisedAddress)
+			lists = lists[:len(lists)-1]
+
+			if err := runProtocol(logger, lists, memoryTransport, simulatedClock, memberCount, failureDetector); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
 }
 
-func buildCluster(memberCount int, memoryTransport *transport.Memory) ([]*membership.List, error) {
-	// Create our membership lists and make them know each other. Note that we are adding one member less but
-	// still force all members to know of the last one which we do not add. This is the member we simulate to be
-	// faulty.
+func buildCluster(memberCount int, memoryTransport *transport.Memory, simulatedClock *clock, failureDetector membership.FailureDetector, phiThreshold float64) ([]*membership.List, error) {
+	// Create our membership lists and make them know each other.
 	lists := make([]*membership.List, 0, memberCount)
-	for i := range memberCount - 1 {
+	for i := range memberCount {
 		address := encoding.NewAddress(net.IPv4(255, 255, 255, 255), i+1)
 		options := []membership.Option{
 			membership.WithLogger(logr.Discard()),
@@ -90,6 +136,9 @@ func buildCluster(memberCount int, memoryTransport *transport.Memory) ([]*member
 			membership.WithUDPClient(memoryTransport.Client()),
 			membership.WithTCPClient(memoryTransport.Client()),
 			membership.WithRoundTripTimeTracker(roundtriptime.NewTracker()),
+			membership.WithFailureDetector(failureDetector),
+			membership.WithPhiThreshold(phiThreshold),
+			membership.WithClock(simulatedClock.Now),
 		}
 		for j := range memberCount {
 			options = append(options,
@@ -112,37 +161,60 @@ func buildCluster(memberCount int, memoryTransport *transport.Memory) ([]*member
 	return lists, nil
 }
 
-//nolint:cyclop
-func runProtocol(logger logr.Logger, lists []*membership.List, memoryTransport *transport.Memory, memberCount int) error {
-	for i := range 1024 {
-		for _, list := range lists {
-			if err := list.DirectPing(); err != nil {
-				return err
-			}
+// warmup runs the protocol with all members being healthy.
+func warmup(lists []*membership.List, memoryTransport *transport.Memory, simulatedClock *clock, protocolPeriods int) error {
+	for range protocolPeriods {
+		if err := runProtocolPeriod(lists, memoryTransport, simulatedClock); err != nil {
+			return err
 		}
-		if err := memoryTransport.FlushAllPendingSends(); err != nil {
+	}
+	return nil
+}
+
+// runProtocolPeriod runs a single protocol period for all given members.
+func runProtocolPeriod(lists []*membership.List, memoryTransport *transport.Memory, simulatedClock *clock) error {
+	for _, list := range lists {
+		if err := list.DirectPing(); err != nil {
 			return err
 		}
+	}
+	if err := memoryTransport.FlushAllPendingSends(); err != nil {
+		return err
+	}
 
-		for _, list := range lists {
-			if err := list.IndirectPing(); err != nil {
-				return err
-			}
+	for _, list := range lists {
+		if err := list.IndirectPing(); err != nil {
+			return err
 		}
-		if err := memoryTransport.FlushAllPendingSends(); err != nil {
+	}
+	if err := memoryTransport.FlushAllPendingSends(); err != nil {
+		return err
+	}
+
+	for _, list := range lists {
+		if err := list.EndOfProtocolPeriod(); err != nil {
 			return err
 		}
+	}
+	simulatedClock.advance()
+	return nil
+}
 
-		for _, list := range lists {
-			if err := list.EndOfProtocolPeriod(); err != nil {
-				return err
-			}
+func runProtocol(logger logr.Logger, lists []*membership.List, memoryTransport *transport.Memory, simulatedClock *clock, memberCount int, failureDetector membership.FailureDetector) error {
+	for i := range 1024 {
+		if err := runProtocolPeriod(lists, memoryTransport, simulatedClock); err != nil {
+			return err
 		}
 		for _, list := range lists {
 			if list.Len() == memberCount-1 {
 				continue
 			}
-			logger.Info("Member failure detected", "cluster-size", memberCount, "protocol-periods", i+1)
+			logger.Info(
+				"Member failure detected",
+				"cluster-size", memberCount,
+				"failure-detector", failureDetector,
+				"protocol-periods", i+1,
+			)
 			return nil
 		}
 	}
@@ -0,0 +1,270 @@
+package partialview
+
+import (
+	"log"
+	"math/rand"
+	"net"
+	"os"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/stdr"
+	"github.com/spf13/cobra"
+
+	"github.com/backbone81/membership/internal/encoding"
+	"github.com/backbone81/membership/internal/membership"
+	"github.com/backbone81/membership/internal/roundtriptime"
+	"github.com/backbone81/membership/internal/transport"
+)
+
+var (
+	memberCount          int
+	churnRate            float64
+	protocolPeriodCount  int
+	shuffleInterval      int
+	activeViewSize       int
+	passiveViewSize      int
+	warmupProtocolPeriod int
+	bootstrapMemberCount int
+)
+
+// partialViewCmd represents the partial-view command.
+var partialViewCmd = &cobra.Command{
+	Use:   "partial-view",
+	Short: "Connectivity of a cluster in partial view mode under churn.",
+	Long: `Simulates a cluster in partial view mode where members crash and new members join every protocol period.
+Measures the connectivity of the overlay formed by the active views, which is the fraction of members in the biggest
+connected component, as well as the number of members without any active member.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger := stdr.New(log.New(os.Stdout, "", log.LstdFlags))
+
+		return Simulate(logger)
+	},
+}
+
+func RegisterSubCommand(command *cobra.Command) {
+	command.AddCommand(partialViewCmd)
+
+	partialViewCmd.PersistentFlags().IntVar(
+		&memberCount,
+		"member-count",
+		1024,
+		"The member count to simulate.",
+	)
+	partialViewCmd.PersistentFlags().Float64Var(
+		&churnRate,
+		"churn-rate",
+		0.01,
+		"The fraction of members which crash and are replaced by new members every protocol period.",
+	)
+	partialViewCmd.PersistentFlags().IntVar(
+		&protocolPeriodCount,
+		"protocol-periods",
+		200,
+		"The number of protocol periods to simulate.",
+	)
+	partialViewCmd.PersistentFlags().IntVar(
+		&warmupProtocolPeriod,
+		"warmup-protocol-periods",
+		20,
+		"The number of protocol periods without churn at the start of the simulation.",
+	)
+	partialViewCmd.PersistentFlags().IntVar(
+		&bootstrapMemberCount,
+		"bootstrap-member-count",
+		3,
+		"The number of random members a new member knows about as bootstrap members.",
+	)
+	partialViewCmd.PersistentFlags().IntVar(
+		&shuffleInterval,
+		"shuffle-interval",
+		10,
+		"The number of protocol periods between two shuffles of a member.",
+	)
+	partialViewCmd.PersistentFlags().IntVar(
+		&activeViewSize,
+		"active-view-size",
+		membership.DefaultConfig.ActiveViewSize,
+		"The maximum number of members in the active view.",
+	)
+	partialViewCmd.PersistentFlags().IntVar(
+		&passiveViewSize,
+		"passive-view-size",
+		membership.DefaultConfig.PassiveViewSize,
+		"The maximum number of members in the passive view.",
+	)
+}
+
+// simulation holds the state of the simulated cluster.
+type simulation struct {
+	memoryTransport *transport.Memory
+	lists           []*membership.List
+	nextAddress     int
+}
+
+// Simulate runs the protocol on a cluster with churn and logs the connectivity after every protocol period.
+func Simulate(logger logr.Logger) error {
+	sim := simulation{
+		memoryTransport: transport.NewMemory(),
+		lists:           make([]*membership.List, 0, memberCount),
+	}
+
+	// Members join one after the other through members which joined before.
+	for range memberCount {
+		sim.join()
+		if err := sim.memoryTransport.FlushAllPendingSends(); err != nil {
+			return err
+		}
+	}
+
+	var churn float64
+	for protocolPeriod := range protocolPeriodCount {
+		if protocolPeriod >= warmupProtocolPeriod {
+			// We accumulate the churn to support churn rates which result in less than one member per protocol period.
+			churn += churnRate * float64(len(sim.lists))
+			for ; churn >= 1; churn-- {
+				sim.crash()
+				sim.join()
+			}
+		}
+
+		if err := sim.runProtocolPeriod(protocolPeriod); err != nil {
+			return err
+		}
+
+		largestComponent, isolated := sim.connectivity()
+		logger.Info(
+			"Connectivity",
+			"protocol-period", protocolPeriod+1,
+			"cluster-size", len(sim.lists),
+			"largest-component", largestComponent,
+			"connectivity", float64(largestComponent)/float64(len(sim.lists)),
+			"isolated-members", isolated,
+		)
+	}
+	return nil
+}
+
+// join adds a new member which knows about some random members of the cluster as bootstrap members.
+func (s *simulation) join() {
+	s.nextAddress++
+	address := encoding.NewAddress(net.IPv4(10, byte(s.nextAddress>>16), byte(s.nextAddress>>8), byte(s.nextAddress)), 1024)
+	options := []membership.Option{
+		membership.WithLogger(logr.Discard()),
+		membership.WithAdvertisedAddress(address),
+		membership.WithUDPClient(s.memoryTransport.Client()),
+		membership.WithTCPClient(s.memoryTransport.Client()),
+		membership.WithRoundTripTimeTracker(roundtriptime.NewTracker()),
+		membership.WithPartialView(true),
+		membership.WithActiveViewSize(activeViewSize),
+		membership.WithPassiveViewSize(passiveViewSize),
+	}
+	for range min(bootstrapMemberCount, len(s.lists)) {
+		contact := s.lists[rand.Intn(len(s.lists))] //nolint:gosec // we do not need crypto/rand here
+		options = append(options, membership.WithBootstrapMember(contact.Config().AdvertisedAddress))
+	}
+	newList := membership.NewList(options...)
+	s.memoryTransport.AddTarget(address, newList)
+	s.lists = append(s.lists, newList)
+
+	// The join is sent at the end of the protocol period. We trigger it right away to not delay the join.
+	_ = newList.EndOfProtocolPeriod()
+}
+
+// crash removes a random member without a graceful shutdown.
+func (s *simulation) crash() {
+	index := rand.Intn(len(s.lists)) //nolint:gosec // we do not need crypto/rand here
+	s.memoryTransport.RemoveTarget(s.lists[index].Config().AdvertisedAddress)
+	s.lists[index] = s.lists[len(s.lists)-1]
+	s.lists = s.lists[:len(s.lists)-1]
+}
+
+// runProtocolPeriod runs a single protocol period on all members.
+func (s *simulation) runProtocolPeriod(protocolPeriod int) error {
+	for _, list := range s.lists {
+		if err := list.DirectPing(); err != nil {
+			return err
+		}
+	}
+	if err := s.memoryTransport.FlushAllPendingSends(); err != nil {
+		return err
+	}
+
+	for _, list := range s.lists {
+		if err := list.IndirectPing(); err != nil {
+			return err
+		}
+	}
+	if err := s.memoryTransport.FlushAllPendingSends(); err != nil {
+		return err
+	}
+
+	for _, list := range s.lists {
+		if err := list.EndOfProtocolPeriod(); err != nil {
+			return err
+		}
+	}
+	if err := s.memoryTransport.FlushAllPendingSends(); err != nil {
+		return err
+	}
+
+	for i, list := range s.lists {
+		// We spread out the shuffles over the protocol periods.
+		if (protocolPeriod+i)%shuffleInterval != 0 {
+			continue
+		}
+		if err := list.RequestList(); err != nil {
+			return err
+		}
+	}
+	return s.memoryTransport.FlushAllPendingSends()
+}
+
+// connectivity returns the size of the biggest connected component of the overlay formed by the active views and the
+// number of members without any member in their active view which is still running. Active views are treated as
+// undirected, as they are symmetric except for short periods of time.
+func (s *simulation) connectivity() (int, int) {
+	indexes := make(map[encoding.Address]int, len(s.lists))
+	for i, list := range s.lists {
+		indexes[list.Config().AdvertisedAddress] = i
+	}
+
+	parents := make([]int, len(s.lists))
+	for i := range parents {
+		parents[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parents[i] != i {
+			parents[i] = find(parents[i])
+		}
+		return parents[i]
+	}
+
+	var isolated int
+	for i, list := range s.lists {
+		neighbors := 0
+		list.ForEach(func(address encoding.Address) bool {
+			j, found := indexes[address]
+			if !found {
+				// The member crashed and was not detected as faulty yet.
+				return true
+			}
+			neighbors++
+			parents[find(i)] = find(j)
+			return true
+		})
+		if neighbors == 0 {
+			isolated++
+		}
+	}
+
+	componentSizes := make(map[int]int, len(s.lists))
+	var largestComponent int
+	for i := range s.lists {
+		root := find(i)
+		componentSizes[root]++
+		largestComponent = max(largestComponent, componentSizes[root])
+	}
+	return largestComponent, isolated
+}
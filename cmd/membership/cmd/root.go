@@ -11,6 +11,7 @@ import (
 	"github.com/backbone81/membership/cmd/membership/cmd/joinpropagation"
 	"github.com/backbone81/membership/cmd/membership/cmd/keygen"
 	"github.com/backbone81/membership/cmd/membership/cmd/lossyjoin"
+	"github.com/backbone81/membership/cmd/membership/cmd/partialview"
 	"github.com/backbone81/membership/cmd/membership/cmd/statistics"
 )
 
@@ -37,5 +38,6 @@ func init() {
 	joinpropagation.RegisterSubCommand(rootCmd)
 	keygen.RegisterSubCommand(rootCmd)
 	lossyjoin.RegisterSubCommand(rootCmd)
+	partialview.RegisterSubCommand(rootCmd)
 	statistics.RegisterSubCommand(rootCmd)
 }
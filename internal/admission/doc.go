@@ -0,0 +1,3 @@
+// Package admission provides static network rules which decide if a member is allowed to join the membership list.
+// This protects a cluster from members which share the encryption key but belong to a different environment.
+package admission
@@ -0,0 +1,61 @@
+package admission
+
+import (
+	"github.com/backbone81/membership/internal/encoding"
+)
+
+// Verdict is the outcome of checking an address against a filter.
+type Verdict int
+
+const (
+	// VerdictAdmitted means that the address is allowed to join.
+	VerdictAdmitted Verdict = iota
+
+	// VerdictDenied means that the address matches a deny rule.
+	VerdictDenied
+
+	// VerdictNotAllowed means that there are allow rules, but the address matches none of them.
+	VerdictNotAllowed
+)
+
+// String returns a human-readable representation of the verdict.
+func (v Verdict) String() string {
+	switch v {
+	case VerdictAdmitted:
+		return "admitted"
+	case VerdictDenied:
+		return "denied"
+	case VerdictNotAllowed:
+		return "not_allowed"
+	default:
+		return "unknown"
+	}
+}
+
+// Filter decides about addresses with allow and deny rules. Deny rules take precedence over allow rules. When there
+// are no allow rules, every address which is not denied is admitted.
+type Filter struct {
+	// Allow holds the rules of which at least one must match, unless empty.
+	Allow []Rule
+
+	// Deny holds the rules of which none must match.
+	Deny []Rule
+}
+
+// Check returns the verdict for the given address.
+func (f Filter) Check(address encoding.Address) Verdict {
+	for _, rule := range f.Deny {
+		if rule.Matches(address) {
+			return VerdictDenied
+		}
+	}
+	if len(f.Allow) == 0 {
+		return VerdictAdmitted
+	}
+	for _, rule := range f.Allow {
+		if rule.Matches(address) {
+			return VerdictAdmitted
+		}
+	}
+	return VerdictNotAllowed
+}
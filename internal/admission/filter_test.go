@@ -0,0 +1,52 @@
+package admission_test
+
+import (
+	"net"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/admission"
+	"github.com/backbone81/membership/internal/encoding"
+)
+
+var _ = Describe("Filter", func() {
+	production := encoding.NewAddress(net.IPv4(10, 0, 0, 1), 3000)
+	staging := encoding.NewAddress(net.IPv4(10, 1, 0, 1), 3000)
+	external := encoding.NewAddress(net.IPv4(192, 168, 0, 1), 3000)
+
+	It("should admit everything without rules", func() {
+		var filter admission.Filter
+		Expect(filter.Check(production)).To(Equal(admission.VerdictAdmitted))
+		Expect(filter.Check(external)).To(Equal(admission.VerdictAdmitted))
+	})
+
+	It("should only admit allowed addresses", func() {
+		filter := admission.Filter{
+			Allow: MustParseRules("10.0.0.0/8"),
+		}
+		Expect(filter.Check(production)).To(Equal(admission.VerdictAdmitted))
+		Expect(filter.Check(external)).To(Equal(admission.VerdictNotAllowed))
+	})
+
+	It("should give deny rules precedence over allow rules", func() {
+		filter := admission.Filter{
+			Allow: MustParseRules("10.0.0.0/8"),
+			Deny:  MustParseRules("10.1.0.0/16"),
+		}
+		Expect(filter.Check(production)).To(Equal(admission.VerdictAdmitted))
+		Expect(filter.Check(staging)).To(Equal(admission.VerdictDenied))
+		Expect(filter.Check(external)).To(Equal(admission.VerdictNotAllowed))
+	})
+
+	It("should not allocate memory", func() {
+		filter := admission.Filter{
+			Allow: MustParseRules("10.0.0.0/8", "fd00::/8"),
+			Deny:  MustParseRules("10.1.0.0/16"),
+		}
+		Expect(testing.AllocsPerRun(100, func() {
+			filter.Check(external)
+		})).To(BeZero())
+	})
+})
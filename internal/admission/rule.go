@@ -0,0 +1,111 @@
+package admission
+
+import (
+	"fmt"
+	"math"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/backbone81/membership/internal/encoding"
+)
+
+// Rule matches member addresses by network and port range.
+type Rule struct {
+	// Prefix is the network the address must be part of.
+	Prefix netip.Prefix
+
+	// MinPort is the smallest port the address must have.
+	MinPort uint16
+
+	// MaxPort is the biggest port the address must have.
+	MaxPort uint16
+}
+
+// ParseRule parses a rule from its textual representation. The rule consists of a network in CIDR notation, optionally
+// followed by a colon and a single port or a port range. Examples are "10.0.0.0/8", "10.0.0.0/8:3000",
+// "10.0.0.0/8:3000-3999" and "fd00::/8:3000".
+func ParseRule(text string) (Rule, error) {
+	slash := strings.Index(text, "/")
+	if slash == -1 {
+		return Rule{}, fmt.Errorf("rule %q is missing the network prefix length", text)
+	}
+
+	network, ports := text, ""
+	if colon := strings.Index(text[slash:], ":"); colon != -1 {
+		network, ports = text[:slash+colon], text[slash+colon+1:]
+	}
+	prefix, err := netip.ParsePrefix(network)
+	if err != nil {
+		return Rule{}, fmt.Errorf("rule %q has an invalid network: %w", text, err)
+	}
+	rule := Rule{
+		Prefix:  prefix.Masked(),
+		MinPort: 0,
+		MaxPort: math.MaxUint16,
+	}
+	if ports == "" {
+		return rule, nil
+	}
+
+	minPort, maxPort, isRange := strings.Cut(ports, "-")
+	if !isRange {
+		maxPort = minPort
+	}
+	if rule.MinPort, err = parsePort(minPort); err != nil {
+		return Rule{}, fmt.Errorf("rule %q has an invalid port: %w", text, err)
+	}
+	if rule.MaxPort, err = parsePort(maxPort); err != nil {
+		return Rule{}, fmt.Errorf("rule %q has an invalid port: %w", text, err)
+	}
+	if rule.MaxPort < rule.MinPort {
+		return Rule{}, fmt.Errorf("rule %q has an empty port range", text)
+	}
+	return rule, nil
+}
+
+// ParseRules parses all given rules. See ParseRule for the format of a single rule.
+func ParseRules(texts []string) ([]Rule, error) {
+	rules := make([]Rule, 0, len(texts))
+	for _, text := range texts {
+		rule, err := ParseRule(text)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func parsePort(text string) (uint16, error) {
+	port, err := strconv.ParseUint(text, 10, 16)
+	if err != nil {
+		return 0, err
+	}
+	return uint16(port), nil
+}
+
+// Matches reports if the given address is part of the network and port range of the rule.
+func (r Rule) Matches(address encoding.Address) bool {
+	port := address.Port()
+	if port < int(r.MinPort) || int(r.MaxPort) < port {
+		return false
+	}
+	ip := netip.AddrFrom16([16]byte(address[:16]))
+	if r.Prefix.Addr().Is4() {
+		ip = ip.Unmap()
+	}
+	return r.Prefix.Contains(ip)
+}
+
+// String returns the textual representation of the rule as accepted by ParseRule.
+func (r Rule) String() string {
+	switch {
+	case r.MinPort == 0 && r.MaxPort == math.MaxUint16:
+		return r.Prefix.String()
+	case r.MinPort == r.MaxPort:
+		return fmt.Sprintf("%s:%d", r.Prefix, r.MinPort)
+	default:
+		return fmt.Sprintf("%s:%d-%d", r.Prefix, r.MinPort, r.MaxPort)
+	}
+}
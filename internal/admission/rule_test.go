@@ -0,0 +1,52 @@
+package admission_test
+
+import (
+	"net"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/admission"
+	"github.com/backbone81/membership/internal/encoding"
+)
+
+var _ = Describe("Rule", func() {
+	DescribeTable("should parse and print rules",
+		func(text string, expected string) {
+			rule, err := admission.ParseRule(text)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rule.String()).To(Equal(expected))
+		},
+		Entry("IPv4 network", "10.0.0.0/8", "10.0.0.0/8"),
+		Entry("IPv4 network which is not masked", "10.1.2.3/8", "10.0.0.0/8"),
+		Entry("IPv4 network with port", "10.0.0.0/8:3000", "10.0.0.0/8:3000"),
+		Entry("IPv4 network with port range", "10.0.0.0/8:3000-3999", "10.0.0.0/8:3000-3999"),
+		Entry("IPv6 network", "fd00::/8", "fd00::/8"),
+		Entry("IPv6 network with port", "fd00::/8:3000", "fd00::/8:3000"),
+	)
+
+	DescribeTable("should reject invalid rules",
+		func(text string) {
+			Expect(admission.ParseRule(text)).Error().To(HaveOccurred())
+		},
+		Entry("missing prefix length", "10.0.0.0"),
+		Entry("invalid network", "10.0.0/8"),
+		Entry("invalid port", "10.0.0.0/8:abc"),
+		Entry("port out of range", "10.0.0.0/8:70000"),
+		Entry("empty port range", "10.0.0.0/8:3999-3000"),
+	)
+
+	DescribeTable("should match addresses",
+		func(text string, address encoding.Address, expected bool) {
+			rules := MustParseRules(text)
+			Expect(rules[0].Matches(address)).To(Equal(expected))
+		},
+		Entry("IPv4 inside network", "10.0.0.0/8", encoding.NewAddress(net.IPv4(10, 1, 2, 3), 3000), true),
+		Entry("IPv4 outside network", "10.0.0.0/8", encoding.NewAddress(net.IPv4(11, 1, 2, 3), 3000), false),
+		Entry("IPv4 inside port range", "10.0.0.0/8:3000-3999", encoding.NewAddress(net.IPv4(10, 1, 2, 3), 3999), true),
+		Entry("IPv4 outside port range", "10.0.0.0/8:3000-3999", encoding.NewAddress(net.IPv4(10, 1, 2, 3), 4000), false),
+		Entry("IPv6 inside network", "fd00::/8", encoding.NewAddress(net.ParseIP("fd12::1"), 3000), true),
+		Entry("IPv6 outside network", "fd00::/8", encoding.NewAddress(net.ParseIP("fe80::1"), 3000), false),
+		Entry("IPv4 against IPv6 network", "fd00::/8", encoding.NewAddress(net.IPv4(10, 1, 2, 3), 3000), false),
+	)
+})
@@ -0,0 +1,21 @@
+package admission_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/admission"
+)
+
+func TestSuite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Admission Suite")
+}
+
+func MustParseRules(texts ...string) []admission.Rule {
+	rules, err := admission.ParseRules(texts)
+	Expect(err).ToNot(HaveOccurred())
+	return rules
+}
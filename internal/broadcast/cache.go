@@ -0,0 +1,84 @@
+package broadcast
+
+import (
+	"github.com/backbone81/membership/internal/encoding"
+)
+
+// cacheEntry is a single broadcast which was received.
+type cacheEntry struct {
+	// payload is the data of the broadcast.
+	payload []byte
+
+	// period is the protocol period the broadcast was received in.
+	period int
+}
+
+// Cache remembers the broadcasts which were received recently. This allows us to deliver every broadcast only once,
+// to detect redundant links in the broadcast tree and to answer grafts of members which missed a broadcast.
+//
+// Cache is not safe for concurrent use by multiple goroutines. Callers must serialize access to all methods.
+type Cache struct {
+	// entries maps every broadcast to its payload.
+	entries map[key]cacheEntry
+
+	// period is the current protocol period.
+	period int
+}
+
+// NewCache creates a new broadcast cache.
+func NewCache() *Cache {
+	return &Cache{
+		entries: make(map[key]cacheEntry),
+	}
+}
+
+// Len returns the number of broadcasts remembered.
+func (c *Cache) Len() int {
+	return len(c.entries)
+}
+
+// Add remembers the broadcast with the given origin and id. Reports false when the broadcast was already received
+// before. The cache takes ownership of the payload.
+func (c *Cache) Add(origin encoding.Address, broadcastID uint32, payload []byte) bool {
+	entryKey := key{
+		origin:      origin,
+		broadcastID: broadcastID,
+	}
+	if _, found := c.entries[entryKey]; found {
+		return false
+	}
+	c.entries[entryKey] = cacheEntry{
+		payload: payload,
+		period:  c.period,
+	}
+	return true
+}
+
+// Contains reports if the broadcast with the given origin and id was received.
+func (c *Cache) Contains(origin encoding.Address, broadcastID uint32) bool {
+	_, found := c.entries[key{
+		origin:      origin,
+		broadcastID: broadcastID,
+	}]
+	return found
+}
+
+// Get returns the payload of the broadcast with the given origin and id. Reports false when the broadcast is unknown.
+func (c *Cache) Get(origin encoding.Address, broadcastID uint32) ([]byte, bool) {
+	entry, found := c.entries[key{
+		origin:      origin,
+		broadcastID: broadcastID,
+	}]
+	return entry.payload, found
+}
+
+// EndOfProtocolPeriod moves to the next protocol period and forgets all broadcasts which were received more than
+// maxAge protocol periods ago.
+func (c *Cache) EndOfProtocolPeriod(maxAge int) {
+	c.period++
+	for entryKey, entry := range c.entries {
+		if c.period-entry.period > maxAge {
+			delete(c.entries, entryKey)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package broadcast_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/broadcast"
+)
+
+var _ = Describe("Cache", func() {
+	It("should report broadcasts only once", func() {
+		cache := broadcast.NewCache()
+		Expect(cache.Add(TestAddress, 1, []byte("first"))).To(BeTrue())
+		Expect(cache.Add(TestAddress, 1, []byte("second"))).To(BeFalse())
+		Expect(cache.Add(TestAddress, 2, nil)).To(BeTrue())
+		Expect(cache.Add(TestAddress2, 1, nil)).To(BeTrue())
+		Expect(cache.Len()).To(Equal(3))
+		Expect(cache.Contains(TestAddress, 1)).To(BeTrue())
+		Expect(cache.Contains(TestAddress2, 2)).To(BeFalse())
+	})
+
+	It("should return the payload", func() {
+		cache := broadcast.NewCache()
+		Expect(cache.Add(TestAddress, 1, []byte("payload"))).To(BeTrue())
+		payload, found := cache.Get(TestAddress, 1)
+		Expect(found).To(BeTrue())
+		Expect(payload).To(Equal([]byte("payload")))
+		_, found = cache.Get(TestAddress, 2)
+		Expect(found).To(BeFalse())
+	})
+
+	It("should forget broadcasts after the maximum age", func() {
+		cache := broadcast.NewCache()
+		Expect(cache.Add(TestAddress, 1, nil)).To(BeTrue())
+		cache.EndOfProtocolPeriod(2)
+		cache.EndOfProtocolPeriod(2)
+		Expect(cache.Len()).To(Equal(1))
+		cache.EndOfProtocolPeriod(2)
+		Expect(cache.Len()).To(Equal(0))
+		Expect(cache.Add(TestAddress, 1, nil)).To(BeTrue())
+	})
+})
@@ -0,0 +1,13 @@
+package broadcast
+
+// Config is the configuration for the announcement queue.
+type Config struct {
+	// MaxTransmissionCount is the maximum number of times announcements in the queue are transmitted before they are
+	// dropped.
+	MaxTransmissionCount int
+}
+
+// DefaultConfig provides a default configuration for the announcement queue with sane defaults for most situations.
+var DefaultConfig = Config{
+	MaxTransmissionCount: 8,
+}
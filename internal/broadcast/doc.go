@@ -0,0 +1,5 @@
+// Package broadcast provides the bookkeeping for disseminating broadcasts along an epidemic broadcast tree (Plumtree).
+// Broadcasts are pushed eagerly to a few members over TCP and announced lazily to all other members as gossip.
+// Members which learn about a broadcast only through an announcement graft the announcing member, which repairs the
+// tree.
+package broadcast
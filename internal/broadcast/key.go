@@ -0,0 +1,11 @@
+package broadcast
+
+import (
+	"github.com/backbone81/membership/internal/encoding"
+)
+
+// key identifies a broadcast within the cluster.
+type key struct {
+	origin      encoding.Address
+	broadcastID uint32
+}
@@ -0,0 +1,101 @@
+package broadcast
+
+import (
+	"github.com/backbone81/membership/internal/encoding"
+)
+
+// Graft is a request for a broadcast which was announced to us, but which we did not receive.
+type Graft struct {
+	// Destination is the member which announced the broadcast and which is asked for it.
+	Destination encoding.Address
+
+	// Origin is the member which started the broadcast.
+	Origin encoding.Address
+
+	// BroadcastID identifies the broadcast together with the origin.
+	BroadcastID uint32
+}
+
+// missingEntry is a single broadcast which was announced, but not received.
+type missingEntry struct {
+	// announcers holds the members which announced the broadcast in the order of their announcements.
+	announcers []encoding.Address
+
+	// waitingPeriods is the number of protocol periods we are waiting for the broadcast since the last graft.
+	waitingPeriods int
+}
+
+// Missing keeps track of the broadcasts which were announced, but not received yet. When a broadcast does not arrive
+// in time, the members which announced it are grafted one after the other.
+//
+// Missing is not safe for concurrent use by multiple goroutines. Callers must serialize access to all methods.
+type Missing struct {
+	// entries maps every missing broadcast to the members which announced it.
+	entries map[key]*missingEntry
+}
+
+// NewMissing creates a new list of missing broadcasts.
+func NewMissing() *Missing {
+	return &Missing{
+		entries: make(map[key]*missingEntry),
+	}
+}
+
+// Len returns the number of missing broadcasts.
+func (m *Missing) Len() int {
+	return len(m.entries)
+}
+
+// Announce records that the given source announced the broadcast with the given origin and id. Announcements of the
+// same source are only recorded once.
+func (m *Missing) Announce(source encoding.Address, origin encoding.Address, broadcastID uint32) {
+	entryKey := key{
+		origin:      origin,
+		broadcastID: broadcastID,
+	}
+	entry, found := m.entries[entryKey]
+	if !found {
+		entry = &missingEntry{}
+		m.entries[entryKey] = entry
+	}
+	for _, announcer := range entry.announcers {
+		if announcer.Equal(source) {
+			return
+		}
+	}
+	entry.announcers = append(entry.announcers, source)
+}
+
+// Remove forgets about the broadcast with the given origin and id, because it was received.
+func (m *Missing) Remove(origin encoding.Address, broadcastID uint32) {
+	delete(m.entries, key{
+		origin:      origin,
+		broadcastID: broadcastID,
+	})
+}
+
+// EndOfProtocolPeriod moves to the next protocol period. Every broadcast which did not arrive within timeout protocol
+// periods results in a graft of the member which announced it first. That member is not grafted again for that
+// broadcast. Broadcasts are forgotten when all announcing members were grafted.
+// Returns dst with the grafts appended.
+func (m *Missing) EndOfProtocolPeriod(timeout int, dst []Graft) []Graft {
+	for entryKey, entry := range m.entries {
+		entry.waitingPeriods++
+		if entry.waitingPeriods < timeout {
+			continue
+		}
+		if len(entry.announcers) == 0 {
+			// All announcing members were grafted, and we still did not receive the broadcast. We give up.
+			delete(m.entries, entryKey)
+			continue
+		}
+		dst = append(dst, Graft{
+			Destination: entry.announcers[0],
+			Origin:      entryKey.origin,
+			BroadcastID: entryKey.broadcastID,
+		})
+		entry.announcers = entry.announcers[1:]
+		entry.waitingPeriods = 0
+	}
+	return dst
+}
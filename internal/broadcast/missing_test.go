@@ -0,0 +1,43 @@
+package broadcast_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/broadcast"
+)
+
+var _ = Describe("Missing", func() {
+	It("should graft after the timeout", func() {
+		missing := broadcast.NewMissing()
+		missing.Announce(TestAddress2, TestAddress, 1)
+		Expect(missing.EndOfProtocolPeriod(2, nil)).To(BeEmpty())
+		Expect(missing.EndOfProtocolPeriod(2, nil)).To(Equal([]broadcast.Graft{
+			{
+				Destination: TestAddress2,
+				Origin:      TestAddress,
+				BroadcastID: 1,
+			},
+		}))
+	})
+
+	It("should graft every announcing member once", func() {
+		missing := broadcast.NewMissing()
+		missing.Announce(TestAddress2, TestAddress, 1)
+		missing.Announce(TestAddress2, TestAddress, 1)
+		missing.Announce(TestAddress3, TestAddress, 1)
+		Expect(missing.EndOfProtocolPeriod(1, nil)).To(ConsistOf(HaveField("Destination", TestAddress2)))
+		Expect(missing.EndOfProtocolPeriod(1, nil)).To(ConsistOf(HaveField("Destination", TestAddress3)))
+		Expect(missing.Len()).To(Equal(1))
+		Expect(missing.EndOfProtocolPeriod(1, nil)).To(BeEmpty())
+		Expect(missing.Len()).To(Equal(0))
+	})
+
+	It("should not graft received broadcasts", func() {
+		missing := broadcast.NewMissing()
+		missing.Announce(TestAddress2, TestAddress, 1)
+		missing.Remove(TestAddress, 1)
+		Expect(missing.Len()).To(Equal(0))
+		Expect(missing.EndOfProtocolPeriod(1, nil)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,68 @@
+package broadcast_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/broadcast"
+	"github.com/backbone81/membership/internal/encoding"
+)
+
+var _ = Describe("Queue", func() {
+	It("should not append anything when empty", func() {
+		queue := broadcast.NewQueue()
+		Expect(queue.AppendToBuffer([]byte{1}, 512)).To(Equal([]byte{1}))
+	})
+
+	It("should append announcements and drop them after the maximum transmission count", func() {
+		queue := broadcast.NewQueue(broadcast.WithMaxTransmissionCount(2))
+		Expect(queue.Add(encoding.MessageIHave{
+			Source:      TestAddress,
+			Origin:      TestAddress2,
+			BroadcastID: 1,
+		})).To(Succeed())
+		Expect(queue.Len()).To(Equal(1))
+
+		buffer := queue.AppendToBuffer(nil, 512)
+		var message encoding.MessageIHave
+		n, err := message.FromBuffer(buffer)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(n).To(Equal(len(buffer)))
+		Expect(message.BroadcastID).To(Equal(uint32(1)))
+		Expect(queue.Len()).To(Equal(1))
+
+		Expect(queue.AppendToBuffer(nil, 512)).ToNot(BeEmpty())
+		Expect(queue.Len()).To(Equal(0))
+	})
+
+	It("should drop announcements when lowering the maximum transmission count", func() {
+		queue := broadcast.NewQueue()
+		Expect(queue.Add(encoding.MessageIHave{
+			Source:      TestAddress,
+			Origin:      TestAddress2,
+			BroadcastID: 1,
+		})).To(Succeed())
+		Expect(queue.AppendToBuffer(nil, 512)).ToNot(BeEmpty())
+		queue.SetMaxTransmissionCount(1)
+		Expect(queue.AppendToBuffer(nil, 512)).To(BeEmpty())
+		Expect(queue.Len()).To(Equal(0))
+	})
+
+	It("should skip announcements which do not fit", func() {
+		queue := broadcast.NewQueue(broadcast.WithMaxTransmissionCount(1))
+		for broadcastID := range uint32(2) {
+			Expect(queue.Add(encoding.MessageIHave{
+				Source:      TestAddress,
+				Origin:      TestAddress2,
+				BroadcastID: broadcastID,
+			})).To(Succeed())
+		}
+
+		buffer := queue.AppendToBuffer(nil, 30)
+		var message encoding.MessageIHave
+		n, err := message.FromBuffer(buffer)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(n).To(Equal(len(buffer)))
+		Expect(queue.Len()).To(Equal(1))
+	})
+})
@@ -0,0 +1,20 @@
+package encoding
+
+import (
+	"errors"
+)
+
+// AppendBroadcastIDToBuffer appends the broadcast id to the provided buffer encoded for network transfer.
+// Returns the buffer with the data appended, the number of bytes appended and any error which occurred.
+func AppendBroadcastIDToBuffer(buffer []byte, broadcastID uint32) ([]byte, int, error) {
+	return Endian.AppendUint32(buffer, broadcastID), 4, nil
+}
+
+// BroadcastIDFromBuffer reads the broadcast id from the provided buffer.
+// Returns the broadcast id, the number of bytes read and any error which occurred.
+func BroadcastIDFromBuffer(buffer []byte) (uint32, int, error) {
+	if len(buffer) < 4 {
+		return 0, 0, errors.New("broadcast id buffer too small")
+	}
+	return Endian.Uint32(buffer), 4, nil
+}
@@ -0,0 +1,29 @@
+package encoding_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/encoding"
+)
+
+var _ = Describe("BroadcastID", func() {
+	It("should read from buffer", func() {
+		buffer, appendN, err := encoding.AppendBroadcastIDToBuffer(nil, 0xdeadbeef)
+		Expect(err).ToNot(HaveOccurred())
+
+		broadcastID, readN, err := encoding.BroadcastIDFromBuffer(buffer)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(readN).To(Equal(appendN))
+		Expect(broadcastID).To(Equal(uint32(0xdeadbeef)))
+	})
+
+	It("should fail to read from buffer which is too small", func() {
+		buffer, _, err := encoding.AppendBroadcastIDToBuffer(nil, 7)
+		Expect(err).ToNot(HaveOccurred())
+
+		for i := len(buffer) - 1; i >= 0; i-- {
+			Expect(encoding.BroadcastIDFromBuffer(buffer[:i])).Error().To(HaveOccurred())
+		}
+	})
+})
@@ -0,0 +1,135 @@
+package encoding
+
+import (
+	"errors"
+	"math"
+)
+
+// AppendBytesToBuffer appends the given bytes prefixed with their length to the provided buffer encoded for network
+// transfer.
+// Returns the buffer with the data appended, the number of bytes appended and any error which occurred.
+func AppendBytesToBuffer(buffer []byte, data []byte) ([]byte, int, error) {
+	if math.MaxUint16 < len(data) {
+		return buffer, 0, errors.New("bytes too long")
+	}
+	buffer = Endian.AppendUint16(buffer, uint16(len(data))) //nolint:gosec // already checked before
+	return append(buffer, data...), 2 + len(data), nil
+}
+
+// BytesFromBuffer reads bytes prefixed with their length from the provided buffer. Note that the returned bytes share
+// memory with the provided buffer. Copy them if you need them after the buffer is re-used.
+// Returns the bytes, the number of bytes read and any error which occurred.
+func BytesFromBuffer(buffer []byte) ([]byte, int, error) {
+	if len(buffer) < 2 {
+		return nil, 0, errors.New("bytes buffer too small")
+	}
+	length := int(Endian.Uint16(buffer))
+	if len(buffer) < 2+length {
+		return nil, 0, errors.New("bytes buffer too small")
+	}
+	return buffer[2 : 2+length], 2 + length, nil
+}
+
+// AppendLargeBytesToBuffer appends the given bytes prefixed with their length to the provided buffer encoded for
+// network transfer. In contrast to AppendBytesToBuffer, the length is encoded with four bytes to allow for data which
+// exceeds a datagram and is transferred over TCP.
+// Returns the buffer with the data appended, the number of bytes appended and any error which occurred.
+func AppendLargeBytesToBuffer(buffer []byte, data []byte) ([]byte, int, error) {
+	if math.MaxUint32 < uint64(len(data)) {
+		return buffer, 0, errors.New("bytes too long")
+	}
+	buffer = Endian.AppendUint32(buffer, uint32(len(data))) //nolint:gosec // already checked before
+	return append(buffer, data...), 4 + len(data), nil
+}
+
+// LargeBytesFromBuffer reads bytes prefixed with their four byte length from the provided buffer. Note that the
+// returned bytes share memory with the provided buffer. Copy them if you need them after the buffer is re-used.
+// Returns the bytes, the number of bytes read and any error which occurred.
+func LargeBytesFromBuffer(buffer []byte) ([]byte, int, error) {
+	if len(buffer) < 4 {
+		return nil, 0, errors.New("large bytes buffer too small")
+	}
+	length := uint64(Endian.Uint32(buffer))
+	if uint64(len(buffer)) < 4+length {
+		return nil, 0, errors.New("large bytes buffer too small")
+	}
+	return buffer[4 : 4+length], 4 + int(length), nil //nolint:gosec // length fits, as it is smaller than the buffer
+}
+
+// AppendAddressCountToBuffer appends the number of addresses to the provided buffer encoded for network transfer.
+// Returns the buffer with the data appended, the number of bytes appended and any error which occurred.
+func AppendAddressCountToBuffer(buffer []byte, addressCount int) ([]byte, int, error) {
+	if addressCount < 0 || math.MaxUint16 < addressCount {
+		return buffer, 0, errors.New("address count out of bounds")
+	}
+	return Endian.AppendUint16(buffer, uint16(addressCount)), 2, nil //nolint:gosec // already checked before
+}
+
+// AddressCountFromBuffer reads the number of addresses from the provided buffer.
+// Returns the number of addresses, the number of bytes read and any error which occurred.
+func AddressCountFromBuffer(buffer []byte) (int, int, error) {
+	if len(buffer) < 2 {
+		return 0, 0, errors.New("address count buffer too small")
+	}
+	return int(Endian.Uint16(buffer)), 2, nil
+}
+
+// AppendAddressesToBuffer appends the given addresses prefixed with their count to the provided buffer encoded for
+// network transfer.
+// Returns the buffer with the data appended, the number of bytes appended and any error which occurred.
+func AppendAddressesToBuffer(buffer []byte, addresses []Address) ([]byte, int, error) {
+	countBuffer, countN, err := AppendAddressCountToBuffer(buffer, len(addresses))
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	addressesBuffer := countBuffer
+	addressesN := countN
+	for _, address := range addresses {
+		appendedBuffer, n, err := AppendAddressToBuffer(addressesBuffer, address)
+		if err != nil {
+			return buffer, 0, err
+		}
+		addressesBuffer = appendedBuffer
+		addressesN += n
+	}
+	return addressesBuffer, addressesN, nil
+}
+
+// AddressesFromBuffer reads addresses prefixed with their count from the provided buffer. The addresses are appended
+// to the given slice, which allows the caller to re-use memory.
+// Returns the addresses, the number of bytes read and any error which occurred.
+func AddressesFromBuffer(buffer []byte, addresses []Address) ([]Address, int, error) {
+	count, offset, err := AddressCountFromBuffer(buffer)
+	if err != nil {
+		return addresses, 0, err
+	}
+
+	for range count {
+		address, n, err := AddressFromBuffer(buffer[offset:])
+		if err != nil {
+			return addresses, 0, err
+		}
+		offset += n
+		addresses = append(addresses, address)
+	}
+	return addresses, offset, nil
+}
+
+// AppendBoolToBuffer appends the given bool to the provided buffer encoded for network transfer.
+// Returns the buffer with the data appended, the number of bytes appended and any error which occurred.
+func AppendBoolToBuffer(buffer []byte, value bool) ([]byte, int, error) {
+	if value {
+		return append(buffer, 1), 1, nil
+	}
+	return append(buffer, 0), 1, nil
+}
+
+// BoolFromBuffer reads a bool from the provided buffer.
+// Returns the bool, the number of bytes read and any error which occurred.
+func BoolFromBuffer(buffer []byte) (bool, int, error) {
+	if len(buffer) < 1 {
+		return false, 0, errors.New("bool buffer too small")
+	}
+	return buffer[0] != 0, 1, nil
+}
@@ -0,0 +1,134 @@
+package encoding_test
+
+import (
+	"math"
+	"net"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/encoding"
+)
+
+var _ = Describe("Bytes", func() {
+	It("should read from buffer", func() {
+		buffer, appendN, err := encoding.AppendBytesToBuffer(nil, []byte("data"))
+		Expect(err).ToNot(HaveOccurred())
+
+		data, readN, err := encoding.BytesFromBuffer(buffer)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(readN).To(Equal(appendN))
+		Expect(data).To(Equal([]byte("data")))
+	})
+
+	It("should fail to append bytes which are too long", func() {
+		Expect(encoding.AppendBytesToBuffer(nil, make([]byte, math.MaxUint16+1))).Error().To(HaveOccurred())
+	})
+
+	It("should fail to read from buffer which is too small", func() {
+		buffer, _, err := encoding.AppendBytesToBuffer(nil, []byte("data"))
+		Expect(err).ToNot(HaveOccurred())
+
+		for i := len(buffer) - 1; i >= 0; i-- {
+			Expect(encoding.BytesFromBuffer(buffer[:i])).Error().To(HaveOccurred())
+		}
+	})
+})
+
+var _ = Describe("LargeBytes", func() {
+	It("should read from buffer", func() {
+		data := make([]byte, math.MaxUint16+1)
+		buffer, appendN, err := encoding.AppendLargeBytesToBuffer(nil, data)
+		Expect(err).ToNot(HaveOccurred())
+
+		readData, readN, err := encoding.LargeBytesFromBuffer(buffer)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(readN).To(Equal(appendN))
+		Expect(readData).To(Equal(data))
+	})
+
+	It("should fail to read from buffer which is too small", func() {
+		buffer, _, err := encoding.AppendLargeBytesToBuffer(nil, []byte("data"))
+		Expect(err).ToNot(HaveOccurred())
+
+		for i := len(buffer) - 1; i >= 0; i-- {
+			Expect(encoding.LargeBytesFromBuffer(buffer[:i])).Error().To(HaveOccurred())
+		}
+	})
+})
+
+var _ = Describe("AddressCount", func() {
+	It("should read from buffer", func() {
+		buffer, appendN, err := encoding.AppendAddressCountToBuffer(nil, 42)
+		Expect(err).ToNot(HaveOccurred())
+
+		count, readN, err := encoding.AddressCountFromBuffer(buffer)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(readN).To(Equal(appendN))
+		Expect(count).To(Equal(42))
+	})
+
+	It("should fail to append invalid address count", func() {
+		Expect(encoding.AppendAddressCountToBuffer(nil, -1)).Error().To(HaveOccurred())
+		Expect(encoding.AppendAddressCountToBuffer(nil, math.MaxUint16+1)).Error().To(HaveOccurred())
+	})
+
+	It("should fail to read from buffer which is too small", func() {
+		Expect(encoding.AddressCountFromBuffer([]byte{1})).Error().To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Addresses", func() {
+	addresses := []encoding.Address{
+		encoding.NewAddress(net.IPv4(1, 2, 3, 4), 1024),
+		encoding.NewAddress(net.IPv4(11, 12, 13, 14), 1024),
+	}
+
+	It("should read from buffer", func() {
+		buffer, appendN, err := encoding.AppendAddressesToBuffer(nil, addresses)
+		Expect(err).ToNot(HaveOccurred())
+
+		readAddresses, readN, err := encoding.AddressesFromBuffer(buffer, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(readN).To(Equal(appendN))
+		Expect(readAddresses).To(Equal(addresses))
+	})
+
+	It("should read empty addresses from buffer", func() {
+		buffer, _, err := encoding.AppendAddressesToBuffer(nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		readAddresses, _, err := encoding.AddressesFromBuffer(buffer, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(readAddresses).To(BeEmpty())
+	})
+
+	It("should fail to read from buffer which is too small", func() {
+		buffer, _, err := encoding.AppendAddressesToBuffer(nil, addresses)
+		Expect(err).ToNot(HaveOccurred())
+
+		for i := len(buffer) - 1; i >= 0; i-- {
+			_, _, err := encoding.AddressesFromBuffer(buffer[:i], nil)
+			Expect(err).To(HaveOccurred())
+		}
+	})
+})
+
+var _ = Describe("Bool", func() {
+	DescribeTable("should read from buffer",
+		func(value bool) {
+			buffer, appendN, err := encoding.AppendBoolToBuffer(nil, value)
+			Expect(err).ToNot(HaveOccurred())
+
+			readValue, readN, err := encoding.BoolFromBuffer(buffer)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(readN).To(Equal(appendN))
+			Expect(readValue).To(Equal(value))
+		},
+		Entry("true", true),
+		Entry("false", false),
+	)
+
+	It("should fail to read from nil buffer", func() {
+		Expect(encoding.BoolFromBuffer(nil)).Error().To(HaveOccurred())
+	})
+})
@@ -19,7 +19,6 @@ func CompareMember(lhs Member, rhs Member) int {
 }
 
 // AppendMemberToBuffer appends the member to the provided buffer encoded for network transfer.
-// Note that the LastStateChange is not encoded to the buffer.
 // Returns the buffer with the data appended, the number of bytes appended and any error which occurred.
 func AppendMemberToBuffer(buffer []byte, member Member) ([]byte, int, error) {
 	addressBuffer, addressN, err := AppendAddressToBuffer(buffer, member.Address)
@@ -41,7 +40,6 @@ func AppendMemberToBuffer(buffer []byte, member Member) ([]byte, int, error) {
 }
 
 // MemberFromBuffer reads the member from the provided buffer.
-// Note that the LastStateChange is always the zero value.
 // Returns the member, the number of bytes read and any error which occurred.
 func MemberFromBuffer(buffer []byte) (Member, int, error) {
 	address, addressN, err := AddressFromBuffer(buffer)
@@ -20,5 +20,5 @@ func MemberCountFromBuffer(buffer []byte) (int, int, error) {
 	if len(buffer) < 4 {
 		return 0, 0, errors.New("member count buffer too small")
 	}
-	return int(Endian.Uint16(buffer)), 4, nil
+	return int(Endian.Uint32(buffer)), 4, nil
 }
@@ -56,6 +56,22 @@ var _ = Describe("MemberCount", func() {
 		Expect(appendMemberCount).To(Equal(readMemberCount))
 	})
 
+	DescribeTable("should read from buffer with valid member counts",
+		func(appendMemberCount int) {
+			buffer, appendN, err := encoding.AppendMemberCountToBuffer(nil, appendMemberCount)
+			Expect(err).ToNot(HaveOccurred())
+
+			readMemberCount, readN, err := encoding.MemberCountFromBuffer(buffer)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(appendN).To(Equal(readN))
+			Expect(appendMemberCount).To(Equal(readMemberCount))
+		},
+		Entry("zero", 0),
+		Entry("bigger than 16 bit", math.MaxUint16+1),
+		Entry("max", math.MaxUint32),
+	)
+
 	It("should fail to read from nil buffer", func() {
 		Expect(encoding.MemberCountFromBuffer(nil)).Error().To(HaveOccurred())
 	})
@@ -9,6 +9,10 @@ const (
 	MemberStateAlive MemberState = iota + 1 // We start with a placeholder member state to detect missing states.
 	MemberStateSuspect
 	MemberStateFaulty
+
+	// MemberStateForceRemoved is only exchanged during a full member list sync. It carries the tombstone of a force
+	// removed member, which is kept around longer than the tombstone of a faulty member.
+	MemberStateForceRemoved
 )
 
 // AppendMemberStateToBuffer appends the member state to the provided buffer encoded for network transfer.
@@ -24,8 +24,38 @@ type Message struct {
 	// for every direct ping we send out.
 	SequenceNumber uint16
 
-	// Members is the full member list returned by the member.
+	// Members is the full member list returned by the member or pushed by the member with a list request.
 	Members []Member
+
+	// QueryID identifies a query together with the source of the query.
+	QueryID uint32
+
+	// Name is the name of a query.
+	Name string
+
+	// Payload is the data of a query or the answer to a query.
+	Payload []byte
+
+	// Filter is the list of members which should answer a query.
+	Filter []Address
+
+	// TimeToLive is the number of hops a random walk through the active views still takes.
+	TimeToLive uint8
+
+	// HighPriority is set for neighbor requests which must not be rejected.
+	HighPriority bool
+
+	// Accepted reports if a neighbor request was accepted.
+	Accepted bool
+
+	// Addresses is the sample of members exchanged by a shuffle.
+	Addresses []Address
+
+	// Origin is the member which started a broadcast.
+	Origin Address
+
+	// BroadcastID identifies a broadcast together with the origin of the broadcast.
+	BroadcastID uint32
 }
 
 //nolint:cyclop
@@ -49,6 +79,36 @@ func (m Message) String() string {
 		return m.ToListRequest().String()
 	case MessageTypeListResponse:
 		return m.ToListResponse().String()
+	case MessageTypeQuery:
+		return m.ToQuery().String()
+	case MessageTypeQueryAck:
+		return m.ToQueryAck().String()
+	case MessageTypeQueryResponse:
+		return m.ToQueryResponse().String()
+	case MessageTypeForceRemove:
+		return m.ToForceRemove().String()
+	case MessageTypeJoin:
+		return m.ToJoin().String()
+	case MessageTypeForwardJoin:
+		return m.ToForwardJoin().String()
+	case MessageTypeNeighbor:
+		return m.ToNeighbor().String()
+	case MessageTypeNeighborReply:
+		return m.ToNeighborReply().String()
+	case MessageTypeDisconnect:
+		return m.ToDisconnect().String()
+	case MessageTypeShuffle:
+		return m.ToShuffle().String()
+	case MessageTypeShuffleReply:
+		return m.ToShuffleReply().String()
+	case MessageTypeBroadcast:
+		return m.ToBroadcast().String()
+	case MessageTypeIHave:
+		return m.ToIHave().String()
+	case MessageTypeGraft:
+		return m.ToGraft().String()
+	case MessageTypePrune:
+		return m.ToPrune().String()
 	default:
 		return "<unknown message type>"
 	}
@@ -75,6 +135,36 @@ func (m Message) AppendToBuffer(buffer []byte) ([]byte, int, error) {
 		return m.ToListRequest().AppendToBuffer(buffer)
 	case MessageTypeListResponse:
 		return m.ToListResponse().AppendToBuffer(buffer)
+	case MessageTypeQuery:
+		return m.ToQuery().AppendToBuffer(buffer)
+	case MessageTypeQueryAck:
+		return m.ToQueryAck().AppendToBuffer(buffer)
+	case MessageTypeQueryResponse:
+		return m.ToQueryResponse().AppendToBuffer(buffer)
+	case MessageTypeForceRemove:
+		return m.ToForceRemove().AppendToBuffer(buffer)
+	case MessageTypeJoin:
+		return m.ToJoin().AppendToBuffer(buffer)
+	case MessageTypeForwardJoin:
+		return m.ToForwardJoin().AppendToBuffer(buffer)
+	case MessageTypeNeighbor:
+		return m.ToNeighbor().AppendToBuffer(buffer)
+	case MessageTypeNeighborReply:
+		return m.ToNeighborReply().AppendToBuffer(buffer)
+	case MessageTypeDisconnect:
+		return m.ToDisconnect().AppendToBuffer(buffer)
+	case MessageTypeShuffle:
+		return m.ToShuffle().AppendToBuffer(buffer)
+	case MessageTypeShuffleReply:
+		return m.ToShuffleReply().AppendToBuffer(buffer)
+	case MessageTypeBroadcast:
+		return m.ToBroadcast().AppendToBuffer(buffer)
+	case MessageTypeIHave:
+		return m.ToIHave().AppendToBuffer(buffer)
+	case MessageTypeGraft:
+		return m.ToGraft().AppendToBuffer(buffer)
+	case MessageTypePrune:
+		return m.ToPrune().AppendToBuffer(buffer)
 	default:
 		return buffer, 0, fmt.Errorf("unknown message type %d", m.Type)
 	}
@@ -114,6 +204,7 @@ func (m Message) ToDirectAck() MessageDirectAck {
 	return MessageDirectAck{
 		Source:         m.Source,
 		SequenceNumber: m.SequenceNumber,
+		Payload:        m.Payload,
 	}
 }
 
@@ -129,12 +220,14 @@ func (m Message) ToIndirectAck() MessageIndirectAck {
 	return MessageIndirectAck{
 		Source:         m.Source,
 		SequenceNumber: m.SequenceNumber,
+		Payload:        m.Payload,
 	}
 }
 
 func (m Message) ToListRequest() MessageListRequest {
 	return MessageListRequest{
-		Source: m.Source,
+		Source:  m.Source,
+		Members: m.Members,
 	}
 }
 
@@ -144,3 +237,117 @@ func (m Message) ToListResponse() MessageListResponse {
 		Members: m.Members,
 	}
 }
+
+func (m Message) ToQuery() MessageQuery {
+	return MessageQuery{
+		Source:  m.Source,
+		QueryID: m.QueryID,
+		Name:    m.Name,
+		Payload: m.Payload,
+		Filter:  m.Filter,
+	}
+}
+
+func (m Message) ToQueryAck() MessageQueryAck {
+	return MessageQueryAck{
+		Source:  m.Source,
+		QueryID: m.QueryID,
+	}
+}
+
+func (m Message) ToQueryResponse() MessageQueryResponse {
+	return MessageQueryResponse{
+		Source:  m.Source,
+		QueryID: m.QueryID,
+		Payload: m.Payload,
+	}
+}
+
+func (m Message) ToForceRemove() MessageForceRemove {
+	return MessageForceRemove{
+		Source:            m.Source,
+		Destination:       m.Destination,
+		IncarnationNumber: m.IncarnationNumber,
+	}
+}
+
+func (m Message) ToJoin() MessageJoin {
+	return MessageJoin{
+		Source: m.Source,
+	}
+}
+
+func (m Message) ToForwardJoin() MessageForwardJoin {
+	return MessageForwardJoin{
+		Source:      m.Source,
+		Destination: m.Destination,
+		TimeToLive:  m.TimeToLive,
+	}
+}
+
+func (m Message) ToNeighbor() MessageNeighbor {
+	return MessageNeighbor{
+		Source:       m.Source,
+		HighPriority: m.HighPriority,
+	}
+}
+
+func (m Message) ToNeighborReply() MessageNeighborReply {
+	return MessageNeighborReply{
+		Source:   m.Source,
+		Accepted: m.Accepted,
+	}
+}
+
+func (m Message) ToDisconnect() MessageDisconnect {
+	return MessageDisconnect{
+		Source: m.Source,
+	}
+}
+
+func (m Message) ToShuffle() MessageShuffle {
+	return MessageShuffle{
+		Source:      m.Source,
+		Destination: m.Destination,
+		TimeToLive:  m.TimeToLive,
+		Addresses:   m.Addresses,
+	}
+}
+
+func (m Message) ToShuffleReply() MessageShuffleReply {
+	return MessageShuffleReply{
+		Source:    m.Source,
+		Addresses: m.Addresses,
+	}
+}
+
+func (m Message) ToBroadcast() MessageBroadcast {
+	return MessageBroadcast{
+		Source:      m.Source,
+		Origin:      m.Origin,
+		BroadcastID: m.BroadcastID,
+		Payload:     m.Payload,
+	}
+}
+
+func (m Message) ToIHave() MessageIHave {
+	return MessageIHave{
+		Source:      m.Source,
+		Origin:      m.Origin,
+		BroadcastID: m.BroadcastID,
+	}
+}
+
+func (m Message) ToGraft() MessageGraft {
+	return MessageGraft{
+		Source:      m.Source,
+		Origin:      m.Origin,
+		BroadcastID: m.BroadcastID,
+	}
+}
+
+func (m Message) ToPrune() MessagePrune {
+	return MessagePrune{
+		Source: m.Source,
+	}
+}
@@ -0,0 +1,104 @@
+package encoding
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MessageBroadcast carries the payload of a broadcast. It is pushed eagerly along the broadcast tree over TCP, which
+// allows for payloads exceeding the size of a datagram. It is also sent in response to a graft.
+type MessageBroadcast struct {
+	// Source is the member which pushed the broadcast to the destination.
+	Source Address
+
+	// Origin is the member which started the broadcast.
+	Origin Address
+
+	// BroadcastID identifies the broadcast together with the origin.
+	BroadcastID uint32
+
+	// Payload is the data of the broadcast. Note that the payload shares memory with the buffer the message was read
+	// from.
+	Payload []byte
+}
+
+// ToMessage converts the specific message into the general purpose message.
+func (m MessageBroadcast) ToMessage() Message {
+	return Message{
+		Type:        MessageTypeBroadcast,
+		Source:      m.Source,
+		Origin:      m.Origin,
+		BroadcastID: m.BroadcastID,
+		Payload:     m.Payload,
+	}
+}
+
+func (m MessageBroadcast) String() string {
+	return fmt.Sprintf("Broadcast (by %s, origin %s, id %d)", m.Source, m.Origin, m.BroadcastID)
+}
+
+// AppendToBuffer appends the message to the provided buffer encoded for network transfer.
+// Returns the buffer with the data appended, the number of bytes appended and any error which occurred.
+func (m MessageBroadcast) AppendToBuffer(buffer []byte) ([]byte, int, error) {
+	messageTypeBuffer, messageTypeN, err := AppendMessageTypeToBuffer(buffer, MessageTypeBroadcast)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	sourceBuffer, sourceN, err := AppendAddressToBuffer(messageTypeBuffer, m.Source)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	originBuffer, originN, err := AppendAddressToBuffer(sourceBuffer, m.Origin)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	broadcastIDBuffer, broadcastIDN, err := AppendBroadcastIDToBuffer(originBuffer, m.BroadcastID)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	payloadBuffer, payloadN, err := AppendLargeBytesToBuffer(broadcastIDBuffer, m.Payload)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	return payloadBuffer, messageTypeN + sourceN + originN + broadcastIDN + payloadN, nil
+}
+
+// FromBuffer reads the message from the provided buffer.
+// Returns the number of bytes read and any error which occurred.
+func (m *MessageBroadcast) FromBuffer(buffer []byte) (int, error) {
+	messageType, messageTypeN, err := MessageTypeFromBuffer(buffer)
+	if err != nil {
+		return 0, err
+	}
+	if messageType != MessageTypeBroadcast {
+		return 0, errors.New("invalid message type")
+	}
+
+	var sourceN, originN, broadcastIDN, payloadN int
+	m.Source, sourceN, err = AddressFromBuffer(buffer[messageTypeN:])
+	if err != nil {
+		return 0, err
+	}
+
+	m.Origin, originN, err = AddressFromBuffer(buffer[messageTypeN+sourceN:])
+	if err != nil {
+		return 0, err
+	}
+
+	m.BroadcastID, broadcastIDN, err = BroadcastIDFromBuffer(buffer[messageTypeN+sourceN+originN:])
+	if err != nil {
+		return 0, err
+	}
+
+	m.Payload, payloadN, err = LargeBytesFromBuffer(buffer[messageTypeN+sourceN+originN+broadcastIDN:])
+	if err != nil {
+		return 0, err
+	}
+
+	return messageTypeN + sourceN + originN + broadcastIDN + payloadN, nil
+}
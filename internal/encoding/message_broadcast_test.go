@@ -0,0 +1,83 @@
+package encoding_test
+
+import (
+	"net"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/encoding"
+)
+
+var testMessageBroadcast = encoding.MessageBroadcast{
+	Source:      encoding.NewAddress(net.IPv4(1, 2, 3, 4), 1024),
+	Origin:      encoding.NewAddress(net.IPv4(5, 6, 7, 8), 1024),
+	BroadcastID: 42,
+	Payload:     []byte("payload"),
+}
+
+var _ = Describe("MessageBroadcast", func() {
+	It("should append to nil buffer", func() {
+		buffer, _, err := testMessageBroadcast.AppendToBuffer(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buffer).ToNot(BeNil())
+	})
+
+	It("should append to buffer", func() {
+		var localBuffer [10]byte
+		buffer, _, err := testMessageBroadcast.AppendToBuffer(localBuffer[:0])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buffer).ToNot(BeNil())
+	})
+
+	It("should read from buffer", func() {
+		buffer, appendN, err := testMessageBroadcast.AppendToBuffer(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buffer).ToNot(BeNil())
+
+		var readMessage encoding.MessageBroadcast
+		readN, err := readMessage.FromBuffer(buffer)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(appendN).To(Equal(readN))
+		Expect(testMessageBroadcast).To(Equal(readMessage))
+	})
+
+	It("should fail to read from nil buffer", func() {
+		var readMessage encoding.MessageBroadcast
+		Expect(readMessage.FromBuffer(nil)).Error().To(HaveOccurred())
+	})
+
+	It("should fail to read from buffer which is too small", func() {
+		buffer, _, err := testMessageBroadcast.AppendToBuffer(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buffer).ToNot(BeNil())
+
+		for i := len(buffer) - 1; i >= 0; i-- {
+			Expect(testMessageBroadcast.FromBuffer(buffer[:i])).Error().To(HaveOccurred())
+		}
+	})
+})
+
+func BenchmarkMessageBroadcast_AppendToBuffer(b *testing.B) {
+	var buffer [1024]byte
+	for b.Loop() {
+		if _, _, err := testMessageBroadcast.AppendToBuffer(buffer[:0]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMessageBroadcast_FromBuffer(b *testing.B) {
+	buffer, _, err := testMessageBroadcast.AppendToBuffer(nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for b.Loop() {
+		if _, err := testMessageBroadcast.FromBuffer(buffer); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -15,6 +15,10 @@ type MessageDirectAck struct {
 	// SequenceNumber is the same sequence which the member received with the direct ping. This makes sure that direct
 	// acks which arrive too late are ignored.
 	SequenceNumber uint16
+
+	// Payload is the application payload the member attached to the ack. It is empty when the member has no payload.
+	// Note that the payload shares memory with the buffer the message was read from.
+	Payload []byte
 }
 
 func (m MessageDirectAck) String() string {
@@ -27,12 +31,17 @@ func (m MessageDirectAck) ToMessage() Message {
 		Type:           MessageTypeDirectAck,
 		Source:         m.Source,
 		SequenceNumber: m.SequenceNumber,
+		Payload:        m.Payload,
 	}
 }
 
 // AppendToBuffer appends the message to the provided buffer encoded for network transfer.
 // Returns the buffer with the data appended, the number of bytes appended and any error which occurred.
 func (m MessageDirectAck) AppendToBuffer(buffer []byte) ([]byte, int, error) {
+	if len(m.Payload) > 0 {
+		return m.appendWithPayloadToBuffer(buffer)
+	}
+
 	messageTypeBuffer, messageTypeN, err := AppendMessageTypeToBuffer(buffer, MessageTypeDirectAck)
 	if err != nil {
 		return buffer, 0, err
@@ -51,6 +60,34 @@ func (m MessageDirectAck) AppendToBuffer(buffer []byte) ([]byte, int, error) {
 	return sequenceNumberBuffer, messageTypeN + sourceN + sequenceNumberN, nil
 }
 
+// appendWithPayloadToBuffer appends the message with its payload to the provided buffer. The payload is sent with its
+// own message type. That way acks without payload are encoded exactly like before acks could carry a payload. Members
+// running an older version do not understand acks with a payload and drop them with the rest of the datagram.
+// Returns the buffer with the data appended, the number of bytes appended and any error which occurred.
+func (m MessageDirectAck) appendWithPayloadToBuffer(buffer []byte) ([]byte, int, error) {
+	messageTypeBuffer, messageTypeN, err := AppendMessageTypeToBuffer(buffer, MessageTypeDirectAckWithPayload)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	sourceBuffer, sourceN, err := AppendAddressToBuffer(messageTypeBuffer, m.Source)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	sequenceNumberBuffer, sequenceNumberN, err := AppendSequenceNumberToBuffer(sourceBuffer, m.SequenceNumber)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	payloadBuffer, payloadN, err := AppendBytesToBuffer(sequenceNumberBuffer, m.Payload)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	return payloadBuffer, messageTypeN + sourceN + sequenceNumberN + payloadN, nil
+}
+
 // FromBuffer reads the message from the provided buffer.
 // Returns the number of bytes read and any error which occurred.
 func (m *MessageDirectAck) FromBuffer(buffer []byte) (int, error) {
@@ -58,11 +95,11 @@ func (m *MessageDirectAck) FromBuffer(buffer []byte) (int, error) {
 	if err != nil {
 		return 0, err
 	}
-	if messageType != MessageTypeDirectAck {
+	if messageType != MessageTypeDirectAck && messageType != MessageTypeDirectAckWithPayload {
 		return 0, errors.New("invalid message type")
 	}
 
-	var sourceN, sequenceNumberN int
+	var sourceN, sequenceNumberN, payloadN int
 	m.Source, sourceN, err = AddressFromBuffer(buffer[messageTypeN:])
 	if err != nil {
 		return 0, err
@@ -73,5 +110,17 @@ func (m *MessageDirectAck) FromBuffer(buffer []byte) (int, error) {
 		return 0, err
 	}
 
-	return messageTypeN + sourceN + sequenceNumberN, nil
+	m.Payload = nil
+	if messageType == MessageTypeDirectAckWithPayload {
+		m.Payload, payloadN, err = BytesFromBuffer(buffer[messageTypeN+sourceN+sequenceNumberN:])
+		if err != nil {
+			return 0, err
+		}
+		if len(m.Payload) == 0 {
+			// We do not want to hand out empty slices into the buffer.
+			m.Payload = nil
+		}
+	}
+
+	return messageTypeN + sourceN + sequenceNumberN + payloadN, nil
 }
@@ -49,6 +49,38 @@ var _ = Describe("MessageDirectAck", func() {
 		Expect(appendMessage).To(Equal(readMessage))
 	})
 
+	It("should read from buffer with payload", func() {
+		appendMessage := encoding.MessageDirectAck{
+			Source:         encoding.NewAddress(net.IPv4(1, 2, 3, 4), 1024),
+			SequenceNumber: 7,
+			Payload:        []byte("load=42"),
+		}
+		buffer, appendN, err := appendMessage.AppendToBuffer(nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		var readMessage encoding.MessageDirectAck
+		readN, err := readMessage.FromBuffer(buffer)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(appendN).To(Equal(readN))
+		Expect(appendMessage).To(Equal(readMessage))
+	})
+
+	It("should only use the message type with payload when there is a payload", func() {
+		message := encoding.MessageDirectAck{
+			Source:         encoding.NewAddress(net.IPv4(1, 2, 3, 4), 1024),
+			SequenceNumber: 7,
+		}
+		buffer, _, err := message.AppendToBuffer(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(encoding.MessageType(buffer[0])).To(Equal(encoding.MessageTypeDirectAck))
+
+		message.Payload = []byte("load=42")
+		buffer, _, err = message.AppendToBuffer(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(encoding.MessageType(buffer[0])).To(Equal(encoding.MessageTypeDirectAckWithPayload))
+	})
+
 	It("should fail to read from nil buffer", func() {
 		var readMessage encoding.MessageDirectAck
 		Expect(readMessage.FromBuffer(nil)).Error().To(HaveOccurred())
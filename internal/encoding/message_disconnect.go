@@ -0,0 +1,61 @@
+package encoding
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MessageDisconnect informs the destination that the source removed it from its active view. The destination moves
+// the source to its passive view in turn.
+type MessageDisconnect struct {
+	// Source is the member which removed the destination from its active view.
+	Source Address
+}
+
+// ToMessage converts the specific message into the general purpose message.
+func (m MessageDisconnect) ToMessage() Message {
+	return Message{
+		Type:   MessageTypeDisconnect,
+		Source: m.Source,
+	}
+}
+
+func (m MessageDisconnect) String() string {
+	return fmt.Sprintf("Disconnect (by %s)", m.Source)
+}
+
+// AppendToBuffer appends the message to the provided buffer encoded for network transfer.
+// Returns the buffer with the data appended, the number of bytes appended and any error which occurred.
+func (m MessageDisconnect) AppendToBuffer(buffer []byte) ([]byte, int, error) {
+	messageTypeBuffer, messageTypeN, err := AppendMessageTypeToBuffer(buffer, MessageTypeDisconnect)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	sourceBuffer, sourceN, err := AppendAddressToBuffer(messageTypeBuffer, m.Source)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	return sourceBuffer, messageTypeN + sourceN, nil
+}
+
+// FromBuffer reads the message from the provided buffer.
+// Returns the number of bytes read and any error which occurred.
+func (m *MessageDisconnect) FromBuffer(buffer []byte) (int, error) {
+	messageType, messageTypeN, err := MessageTypeFromBuffer(buffer)
+	if err != nil {
+		return 0, err
+	}
+	if messageType != MessageTypeDisconnect {
+		return 0, errors.New("invalid message type")
+	}
+
+	var sourceN int
+	m.Source, sourceN, err = AddressFromBuffer(buffer[messageTypeN:])
+	if err != nil {
+		return 0, err
+	}
+
+	return messageTypeN + sourceN, nil
+}
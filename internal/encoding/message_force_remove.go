@@ -0,0 +1,105 @@
+//nolint:dupl
+package encoding
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MessageForceRemove declares the destination as permanently gone by an administrative decision of the source. In
+// contrast to MessageFaulty, it takes precedence over alive, suspect and faulty messages with the same incarnation
+// number, and the destination is kept as a tombstone for longer.
+type MessageForceRemove struct {
+	// Source is the member which force removed the destination.
+	Source Address
+
+	// Destination is the member which was force removed by source.
+	Destination Address
+
+	// IncarnationNumber is the incarnation number source assigned to the removal. It is bigger than any incarnation
+	// number source knew about for destination.
+	IncarnationNumber uint16
+}
+
+// ToMessage converts the specific message into the general purpose message.
+func (m MessageForceRemove) ToMessage() Message {
+	return Message{
+		Type:              MessageTypeForceRemove,
+		Source:            m.Source,
+		Destination:       m.Destination,
+		IncarnationNumber: m.IncarnationNumber,
+	}
+}
+
+func (m MessageForceRemove) String() string {
+	return fmt.Sprintf("ForceRemove %s (by %s, incarnation %d)", m.Destination, m.Source, m.IncarnationNumber)
+}
+
+// AppendToBuffer appends the message to the provided buffer encoded for network transfer.
+// Returns the buffer with the data appended, the number of bytes appended and any error which occurred.
+func (m MessageForceRemove) AppendToBuffer(buffer []byte) ([]byte, int, error) {
+	messageTypeBuffer, messageTypeN, err := AppendMessageTypeToBuffer(buffer, MessageTypeForceRemove)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	sourceBuffer, sourceN, err := AppendAddressToBuffer(messageTypeBuffer, m.Source)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	destinationBuffer, destinationN, err := AppendAddressToBuffer(sourceBuffer, m.Destination)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	incarnationNumberBuffer, incarnationNumberN, err := AppendIncarnationNumberToBuffer(destinationBuffer, m.IncarnationNumber)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	return incarnationNumberBuffer, messageTypeN + sourceN + destinationN + incarnationNumberN, nil
+}
+
+// FromBuffer reads the message from the provided buffer.
+// Returns the number of bytes read and any error which occurred.
+func (m *MessageForceRemove) FromBuffer(buffer []byte) (int, error) {
+	messageType, messageTypeN, err := MessageTypeFromBuffer(buffer)
+	if err != nil {
+		return 0, err
+	}
+	if messageType != MessageTypeForceRemove {
+		return 0, errors.New("invalid message type")
+	}
+
+	var sourceN, destinationN, incarnationNumberN int
+	m.Source, sourceN, err = AddressFromBuffer(buffer[messageTypeN:])
+	if err != nil {
+		return 0, err
+	}
+
+	m.Destination, destinationN, err = AddressFromBuffer(buffer[messageTypeN+sourceN:])
+	if err != nil {
+		return 0, err
+	}
+
+	m.IncarnationNumber, incarnationNumberN, err = IncarnationNumberFromBuffer(buffer[messageTypeN+sourceN+destinationN:])
+	if err != nil {
+		return 0, err
+	}
+
+	return messageTypeN + sourceN + destinationN + incarnationNumberN, nil
+}
+
+// GetAddress returns the address which is relevant for this message. Needed for the gossip queue to check for equality.
+func (m *MessageForceRemove) GetAddress() Address {
+	return m.Destination
+}
+
+func (m *MessageForceRemove) GetType() MessageType {
+	return MessageTypeForceRemove
+}
+
+func (m *MessageForceRemove) GetIncarnationNumber() uint16 {
+	return m.IncarnationNumber
+}
@@ -0,0 +1,82 @@
+package encoding_test
+
+import (
+	"net"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/encoding"
+)
+
+var testMessageForceRemove = encoding.MessageForceRemove{
+	Source:            encoding.NewAddress(net.IPv4(1, 2, 3, 4), 1024),
+	Destination:       encoding.NewAddress(net.IPv4(11, 12, 13, 14), 1024),
+	IncarnationNumber: 7,
+}
+
+var _ = Describe("MessageForceRemove", func() {
+	It("should append to nil buffer", func() {
+		buffer, _, err := testMessageForceRemove.AppendToBuffer(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buffer).ToNot(BeNil())
+	})
+
+	It("should append to buffer", func() {
+		var localBuffer [10]byte
+		buffer, _, err := testMessageForceRemove.AppendToBuffer(localBuffer[:0])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buffer).ToNot(BeNil())
+	})
+
+	It("should read from buffer", func() {
+		buffer, appendN, err := testMessageForceRemove.AppendToBuffer(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buffer).ToNot(BeNil())
+
+		var readMessage encoding.MessageForceRemove
+		readN, err := readMessage.FromBuffer(buffer)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(appendN).To(Equal(readN))
+		Expect(testMessageForceRemove).To(Equal(readMessage))
+	})
+
+	It("should fail to read from nil buffer", func() {
+		var readMessage encoding.MessageForceRemove
+		Expect(readMessage.FromBuffer(nil)).Error().To(HaveOccurred())
+	})
+
+	It("should fail to read from buffer which is too small", func() {
+		buffer, _, err := testMessageForceRemove.AppendToBuffer(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buffer).ToNot(BeNil())
+
+		for i := len(buffer) - 1; i >= 0; i-- {
+			Expect(testMessageForceRemove.FromBuffer(buffer[:i])).Error().To(HaveOccurred())
+		}
+	})
+})
+
+func BenchmarkMessageForceRemove_AppendToBuffer(b *testing.B) {
+	var buffer [1024]byte
+	for b.Loop() {
+		if _, _, err := testMessageForceRemove.AppendToBuffer(buffer[:0]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMessageForceRemove_FromBuffer(b *testing.B) {
+	buffer, _, err := testMessageForceRemove.AppendToBuffer(nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for b.Loop() {
+		if _, err := testMessageForceRemove.FromBuffer(buffer); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package encoding
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MessageForwardJoin announces a member which joined the cluster through a random walk over the active views. Every
+// member on the walk might add the joining member to its active or passive view.
+type MessageForwardJoin struct {
+	// Source is the member which forwarded the message.
+	Source Address
+
+	// Destination is the member which joined the cluster.
+	Destination Address
+
+	// TimeToLive is the number of hops the random walk still takes.
+	TimeToLive uint8
+}
+
+// ToMessage converts the specific message into the general purpose message.
+func (m MessageForwardJoin) ToMessage() Message {
+	return Message{
+		Type:        MessageTypeForwardJoin,
+		Source:      m.Source,
+		Destination: m.Destination,
+		TimeToLive:  m.TimeToLive,
+	}
+}
+
+func (m MessageForwardJoin) String() string {
+	return fmt.Sprintf("ForwardJoin %s (by %s, time to live %d)", m.Destination, m.Source, m.TimeToLive)
+}
+
+// AppendToBuffer appends the message to the provided buffer encoded for network transfer.
+// Returns the buffer with the data appended, the number of bytes appended and any error which occurred.
+func (m MessageForwardJoin) AppendToBuffer(buffer []byte) ([]byte, int, error) {
+	messageTypeBuffer, messageTypeN, err := AppendMessageTypeToBuffer(buffer, MessageTypeForwardJoin)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	sourceBuffer, sourceN, err := AppendAddressToBuffer(messageTypeBuffer, m.Source)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	destinationBuffer, destinationN, err := AppendAddressToBuffer(sourceBuffer, m.Destination)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	timeToLiveBuffer, timeToLiveN, err := AppendTimeToLiveToBuffer(destinationBuffer, m.TimeToLive)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	return timeToLiveBuffer, messageTypeN + sourceN + destinationN + timeToLiveN, nil
+}
+
+// FromBuffer reads the message from the provided buffer.
+// Returns the number of bytes read and any error which occurred.
+func (m *MessageForwardJoin) FromBuffer(buffer []byte) (int, error) {
+	messageType, messageTypeN, err := MessageTypeFromBuffer(buffer)
+	if err != nil {
+		return 0, err
+	}
+	if messageType != MessageTypeForwardJoin {
+		return 0, errors.New("invalid message type")
+	}
+
+	var sourceN, destinationN, timeToLiveN int
+	m.Source, sourceN, err = AddressFromBuffer(buffer[messageTypeN:])
+	if err != nil {
+		return 0, err
+	}
+
+	m.Destination, destinationN, err = AddressFromBuffer(buffer[messageTypeN+sourceN:])
+	if err != nil {
+		return 0, err
+	}
+
+	m.TimeToLive, timeToLiveN, err = TimeToLiveFromBuffer(buffer[messageTypeN+sourceN+destinationN:])
+	if err != nil {
+		return 0, err
+	}
+
+	return messageTypeN + sourceN + destinationN + timeToLiveN, nil
+}
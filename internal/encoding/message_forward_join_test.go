@@ -0,0 +1,82 @@
+package encoding_test
+
+import (
+	"net"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/encoding"
+)
+
+var testMessageForwardJoin = encoding.MessageForwardJoin{
+	Source:      encoding.NewAddress(net.IPv4(1, 2, 3, 4), 1024),
+	Destination: encoding.NewAddress(net.IPv4(11, 12, 13, 14), 1024),
+	TimeToLive:  6,
+}
+
+var _ = Describe("MessageForwardJoin", func() {
+	It("should append to nil buffer", func() {
+		buffer, _, err := testMessageForwardJoin.AppendToBuffer(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buffer).ToNot(BeNil())
+	})
+
+	It("should append to buffer", func() {
+		var localBuffer [10]byte
+		buffer, _, err := testMessageForwardJoin.AppendToBuffer(localBuffer[:0])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buffer).ToNot(BeNil())
+	})
+
+	It("should read from buffer", func() {
+		buffer, appendN, err := testMessageForwardJoin.AppendToBuffer(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buffer).ToNot(BeNil())
+
+		var readMessage encoding.MessageForwardJoin
+		readN, err := readMessage.FromBuffer(buffer)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(appendN).To(Equal(readN))
+		Expect(testMessageForwardJoin).To(Equal(readMessage))
+	})
+
+	It("should fail to read from nil buffer", func() {
+		var readMessage encoding.MessageForwardJoin
+		Expect(readMessage.FromBuffer(nil)).Error().To(HaveOccurred())
+	})
+
+	It("should fail to read from buffer which is too small", func() {
+		buffer, _, err := testMessageForwardJoin.AppendToBuffer(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buffer).ToNot(BeNil())
+
+		for i := len(buffer) - 1; i >= 0; i-- {
+			Expect(testMessageForwardJoin.FromBuffer(buffer[:i])).Error().To(HaveOccurred())
+		}
+	})
+})
+
+func BenchmarkMessageForwardJoin_AppendToBuffer(b *testing.B) {
+	var buffer [1024]byte
+	for b.Loop() {
+		if _, _, err := testMessageForwardJoin.AppendToBuffer(buffer[:0]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMessageForwardJoin_FromBuffer(b *testing.B) {
+	buffer, _, err := testMessageForwardJoin.AppendToBuffer(nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for b.Loop() {
+		if _, err := testMessageForwardJoin.FromBuffer(buffer); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package encoding
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MessageGraft asks the destination to send the broadcast with the given origin and id, which the source missed. The
+// destination also pushes future broadcasts eagerly to the source, which repairs the broadcast tree.
+type MessageGraft struct {
+	// Source is the member which missed the broadcast.
+	Source Address
+
+	// Origin is the member which started the broadcast.
+	Origin Address
+
+	// BroadcastID identifies the broadcast together with the origin.
+	BroadcastID uint32
+}
+
+// ToMessage converts the specific message into the general purpose message.
+func (m MessageGraft) ToMessage() Message {
+	return Message{
+		Type:        MessageTypeGraft,
+		Source:      m.Source,
+		Origin:      m.Origin,
+		BroadcastID: m.BroadcastID,
+	}
+}
+
+func (m MessageGraft) String() string {
+	return fmt.Sprintf("Graft (by %s, origin %s, id %d)", m.Source, m.Origin, m.BroadcastID)
+}
+
+// AppendToBuffer appends the message to the provided buffer encoded for network transfer.
+// Returns the buffer with the data appended, the number of bytes appended and any error which occurred.
+func (m MessageGraft) AppendToBuffer(buffer []byte) ([]byte, int, error) {
+	messageTypeBuffer, messageTypeN, err := AppendMessageTypeToBuffer(buffer, MessageTypeGraft)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	sourceBuffer, sourceN, err := AppendAddressToBuffer(messageTypeBuffer, m.Source)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	originBuffer, originN, err := AppendAddressToBuffer(sourceBuffer, m.Origin)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	broadcastIDBuffer, broadcastIDN, err := AppendBroadcastIDToBuffer(originBuffer, m.BroadcastID)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	return broadcastIDBuffer, messageTypeN + sourceN + originN + broadcastIDN, nil
+}
+
+// FromBuffer reads the message from the provided buffer.
+// Returns the number of bytes read and any error which occurred.
+func (m *MessageGraft) FromBuffer(buffer []byte) (int, error) {
+	messageType, messageTypeN, err := MessageTypeFromBuffer(buffer)
+	if err != nil {
+		return 0, err
+	}
+	if messageType != MessageTypeGraft {
+		return 0, errors.New("invalid message type")
+	}
+
+	var sourceN, originN, broadcastIDN int
+	m.Source, sourceN, err = AddressFromBuffer(buffer[messageTypeN:])
+	if err != nil {
+		return 0, err
+	}
+
+	m.Origin, originN, err = AddressFromBuffer(buffer[messageTypeN+sourceN:])
+	if err != nil {
+		return 0, err
+	}
+
+	m.BroadcastID, broadcastIDN, err = BroadcastIDFromBuffer(buffer[messageTypeN+sourceN+originN:])
+	if err != nil {
+		return 0, err
+	}
+
+	return messageTypeN + sourceN + originN + broadcastIDN, nil
+}
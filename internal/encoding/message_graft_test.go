@@ -0,0 +1,82 @@
+package encoding_test
+
+import (
+	"net"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/encoding"
+)
+
+var testMessageGraft = encoding.MessageGraft{
+	Source:      encoding.NewAddress(net.IPv4(1, 2, 3, 4), 1024),
+	Origin:      encoding.NewAddress(net.IPv4(5, 6, 7, 8), 1024),
+	BroadcastID: 42,
+}
+
+var _ = Describe("MessageGraft", func() {
+	It("should append to nil buffer", func() {
+		buffer, _, err := testMessageGraft.AppendToBuffer(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buffer).ToNot(BeNil())
+	})
+
+	It("should append to buffer", func() {
+		var localBuffer [10]byte
+		buffer, _, err := testMessageGraft.AppendToBuffer(localBuffer[:0])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buffer).ToNot(BeNil())
+	})
+
+	It("should read from buffer", func() {
+		buffer, appendN, err := testMessageGraft.AppendToBuffer(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buffer).ToNot(BeNil())
+
+		var readMessage encoding.MessageGraft
+		readN, err := readMessage.FromBuffer(buffer)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(appendN).To(Equal(readN))
+		Expect(testMessageGraft).To(Equal(readMessage))
+	})
+
+	It("should fail to read from nil buffer", func() {
+		var readMessage encoding.MessageGraft
+		Expect(readMessage.FromBuffer(nil)).Error().To(HaveOccurred())
+	})
+
+	It("should fail to read from buffer which is too small", func() {
+		buffer, _, err := testMessageGraft.AppendToBuffer(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buffer).ToNot(BeNil())
+
+		for i := len(buffer) - 1; i >= 0; i-- {
+			Expect(testMessageGraft.FromBuffer(buffer[:i])).Error().To(HaveOccurred())
+		}
+	})
+})
+
+func BenchmarkMessageGraft_AppendToBuffer(b *testing.B) {
+	var buffer [1024]byte
+	for b.Loop() {
+		if _, _, err := testMessageGraft.AppendToBuffer(buffer[:0]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMessageGraft_FromBuffer(b *testing.B) {
+	buffer, _, err := testMessageGraft.AppendToBuffer(nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for b.Loop() {
+		if _, err := testMessageGraft.FromBuffer(buffer); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
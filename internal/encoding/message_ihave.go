@@ -0,0 +1,89 @@
+package encoding
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MessageIHave announces that the source received the broadcast with the given origin and id. It is piggybacked as
+// gossip on pings and acks and is not relayed. Members which did not receive the broadcast in time graft the source.
+type MessageIHave struct {
+	// Source is the member which received the broadcast.
+	Source Address
+
+	// Origin is the member which started the broadcast.
+	Origin Address
+
+	// BroadcastID identifies the broadcast together with the origin.
+	BroadcastID uint32
+}
+
+// ToMessage converts the specific message into the general purpose message.
+func (m MessageIHave) ToMessage() Message {
+	return Message{
+		Type:        MessageTypeIHave,
+		Source:      m.Source,
+		Origin:      m.Origin,
+		BroadcastID: m.BroadcastID,
+	}
+}
+
+func (m MessageIHave) String() string {
+	return fmt.Sprintf("IHave (by %s, origin %s, id %d)", m.Source, m.Origin, m.BroadcastID)
+}
+
+// AppendToBuffer appends the message to the provided buffer encoded for network transfer.
+// Returns the buffer with the data appended, the number of bytes appended and any error which occurred.
+func (m MessageIHave) AppendToBuffer(buffer []byte) ([]byte, int, error) {
+	messageTypeBuffer, messageTypeN, err := AppendMessageTypeToBuffer(buffer, MessageTypeIHave)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	sourceBuffer, sourceN, err := AppendAddressToBuffer(messageTypeBuffer, m.Source)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	originBuffer, originN, err := AppendAddressToBuffer(sourceBuffer, m.Origin)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	broadcastIDBuffer, broadcastIDN, err := AppendBroadcastIDToBuffer(originBuffer, m.BroadcastID)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	return broadcastIDBuffer, messageTypeN + sourceN + originN + broadcastIDN, nil
+}
+
+// FromBuffer reads the message from the provided buffer.
+// Returns the number of bytes read and any error which occurred.
+func (m *MessageIHave) FromBuffer(buffer []byte) (int, error) {
+	messageType, messageTypeN, err := MessageTypeFromBuffer(buffer)
+	if err != nil {
+		return 0, err
+	}
+	if messageType != MessageTypeIHave {
+		return 0, errors.New("invalid message type")
+	}
+
+	var sourceN, originN, broadcastIDN int
+	m.Source, sourceN, err = AddressFromBuffer(buffer[messageTypeN:])
+	if err != nil {
+		return 0, err
+	}
+
+	m.Origin, originN, err = AddressFromBuffer(buffer[messageTypeN+sourceN:])
+	if err != nil {
+		return 0, err
+	}
+
+	m.BroadcastID, broadcastIDN, err = BroadcastIDFromBuffer(buffer[messageTypeN+sourceN+originN:])
+	if err != nil {
+		return 0, err
+	}
+
+	return messageTypeN + sourceN + originN + broadcastIDN, nil
+}
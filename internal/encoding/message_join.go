@@ -0,0 +1,61 @@
+package encoding
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MessageJoin asks the destination to add the source to its active view. It is sent to a bootstrap member by a member
+// which runs in partial view mode and does not know any other member yet.
+type MessageJoin struct {
+	// Source is the member which wants to join the cluster.
+	Source Address
+}
+
+// ToMessage converts the specific message into the general purpose message.
+func (m MessageJoin) ToMessage() Message {
+	return Message{
+		Type:   MessageTypeJoin,
+		Source: m.Source,
+	}
+}
+
+func (m MessageJoin) String() string {
+	return fmt.Sprintf("Join (by %s)", m.Source)
+}
+
+// AppendToBuffer appends the message to the provided buffer encoded for network transfer.
+// Returns the buffer with the data appended, the number of bytes appended and any error which occurred.
+func (m MessageJoin) AppendToBuffer(buffer []byte) ([]byte, int, error) {
+	messageTypeBuffer, messageTypeN, err := AppendMessageTypeToBuffer(buffer, MessageTypeJoin)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	sourceBuffer, sourceN, err := AppendAddressToBuffer(messageTypeBuffer, m.Source)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	return sourceBuffer, messageTypeN + sourceN, nil
+}
+
+// FromBuffer reads the message from the provided buffer.
+// Returns the number of bytes read and any error which occurred.
+func (m *MessageJoin) FromBuffer(buffer []byte) (int, error) {
+	messageType, messageTypeN, err := MessageTypeFromBuffer(buffer)
+	if err != nil {
+		return 0, err
+	}
+	if messageType != MessageTypeJoin {
+		return 0, errors.New("invalid message type")
+	}
+
+	var sourceN int
+	m.Source, sourceN, err = AddressFromBuffer(buffer[messageTypeN:])
+	if err != nil {
+		return 0, err
+	}
+
+	return messageTypeN + sourceN, nil
+}
@@ -7,9 +7,18 @@ import (
 
 // MessageListRequest asks the recipient to send its current full member list to the source. This helps in making sure
 // new-joiners quickly get an overview over all members.
+//
+// The request can optionally carry the member list of the source. This allows for a push-pull list sync where the
+// recipient merges the members of the source into its own list, before responding with its own member list. That way
+// a single round trip heals the view of both sides. When the request carries members, it can become quite big and
+// should always be transmitted over TCP and not UDP. Requests with members use their own message type, which members
+// running an older version do not understand.
 type MessageListRequest struct {
 	// Source is the member sending this message
 	Source Address
+
+	// Members is the full member list of the source. It is empty for requests which only pull the member list.
+	Members []Member
 }
 
 func (m MessageListRequest) String() string {
@@ -19,14 +28,19 @@ func (m MessageListRequest) String() string {
 // ToMessage converts the specific message into the general purpose message.
 func (m MessageListRequest) ToMessage() Message {
 	return Message{
-		Type:   MessageTypeListRequest,
-		Source: m.Source,
+		Type:    MessageTypeListRequest,
+		Source:  m.Source,
+		Members: m.Members,
 	}
 }
 
 // AppendToBuffer appends the message to the provided buffer encoded for network transfer.
 // Returns the buffer with the data appended, the number of bytes appended and any error which occurred.
 func (m MessageListRequest) AppendToBuffer(buffer []byte) ([]byte, int, error) {
+	if len(m.Members) > 0 {
+		return m.appendWithMembersToBuffer(buffer)
+	}
+
 	messageTypeBuffer, messageTypeN, err := AppendMessageTypeToBuffer(buffer, MessageTypeListRequest)
 	if err != nil {
 		return buffer, 0, err
@@ -40,6 +54,40 @@ func (m MessageListRequest) AppendToBuffer(buffer []byte) ([]byte, int, error) {
 	return sourceBuffer, messageTypeN + sourceN, nil
 }
 
+// appendWithMembersToBuffer appends the message with its members to the provided buffer. The members are sent with
+// their own message type. That way requests which only pull the member list are encoded exactly like before requests
+// could carry members.
+// Returns the buffer with the data appended, the number of bytes appended and any error which occurred.
+func (m MessageListRequest) appendWithMembersToBuffer(buffer []byte) ([]byte, int, error) {
+	messageTypeBuffer, messageTypeN, err := AppendMessageTypeToBuffer(buffer, MessageTypeListRequestWithMembers)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	sourceBuffer, sourceN, err := AppendAddressToBuffer(messageTypeBuffer, m.Source)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	countBuffer, countN, err := AppendMemberCountToBuffer(sourceBuffer, len(m.Members))
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	memberBuffer := countBuffer
+	var memberN int
+	for _, member := range m.Members {
+		appendedBuffer, n, err := AppendMemberToBuffer(memberBuffer, member)
+		if err != nil {
+			return buffer, 0, err
+		}
+		memberBuffer = appendedBuffer
+		memberN += n
+	}
+
+	return memberBuffer, messageTypeN + sourceN + countN + memberN, nil
+}
+
 // FromBuffer reads the message from the provided buffer.
 // Returns the number of bytes read and any error which occurred.
 func (m *MessageListRequest) FromBuffer(buffer []byte) (int, error) {
@@ -47,7 +95,7 @@ func (m *MessageListRequest) FromBuffer(buffer []byte) (int, error) {
 	if err != nil {
 		return 0, err
 	}
-	if messageType != MessageTypeListRequest {
+	if messageType != MessageTypeListRequest && messageType != MessageTypeListRequestWithMembers {
 		return 0, errors.New("invalid message type")
 	}
 
@@ -57,5 +105,34 @@ func (m *MessageListRequest) FromBuffer(buffer []byte) (int, error) {
 		return 0, err
 	}
 
-	return messageTypeN + sourceN, nil
+	if messageType == MessageTypeListRequest {
+		m.Members = m.Members[:0]
+		return messageTypeN + sourceN, nil
+	}
+
+	count, countN, err := MemberCountFromBuffer(buffer[messageTypeN+sourceN:])
+	if err != nil {
+		return 0, err
+	}
+
+	if cap(m.Members) < count {
+		m.Members = make([]Member, 0, count)
+	}
+	if len(m.Members) != 0 {
+		// We reset the members slice after the check for capacity. In case the capacity is not enough, we save the
+		// reset because the length after the capacity increase already is 0.
+		m.Members = m.Members[:0]
+	}
+
+	var memberN int
+	for range count {
+		member, n, err := MemberFromBuffer(buffer[messageTypeN+sourceN+countN+memberN:])
+		if err != nil {
+			return 0, err
+		}
+		memberN += n
+		m.Members = append(m.Members, member)
+	}
+
+	return messageTypeN + sourceN + countN + memberN, nil
 }
@@ -46,6 +46,56 @@ var _ = Describe("MessageListRequest", func() {
 		Expect(appendMessage).To(Equal(readMessage))
 	})
 
+	It("should read from buffer with members", func() {
+		appendMessage := encoding.MessageListRequest{
+			Source: encoding.NewAddress(net.IPv4(1, 2, 3, 4), 1024),
+			Members: []encoding.Member{
+				{
+					Address:           encoding.NewAddress(net.IPv4(1, 2, 3, 4), 1024),
+					State:             encoding.MemberStateAlive,
+					IncarnationNumber: 1,
+				},
+				{
+					Address:           encoding.NewAddress(net.IPv4(11, 12, 13, 14), 1024),
+					State:             encoding.MemberStateFaulty,
+					IncarnationNumber: 2,
+				},
+			},
+		}
+		buffer, appendN, err := appendMessage.AppendToBuffer(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buffer).ToNot(BeNil())
+
+		var readMessage encoding.MessageListRequest
+		readN, err := readMessage.FromBuffer(buffer)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(appendN).To(Equal(readN))
+		Expect(appendMessage).To(Equal(readMessage))
+	})
+
+	It("should only use the message type with members when there are members", func() {
+		message := encoding.MessageListRequest{
+			Source: encoding.NewAddress(net.IPv4(1, 2, 3, 4), 1024),
+		}
+		buffer, _, err := message.AppendToBuffer(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(encoding.MessageType(buffer[0])).To(Equal(encoding.MessageTypeListRequest))
+		addressBuffer, _, err := encoding.AppendAddressToBuffer(nil, message.Source)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buffer[1:]).To(Equal(addressBuffer))
+
+		message.Members = []encoding.Member{
+			{
+				Address: encoding.NewAddress(net.IPv4(11, 12, 13, 14), 1024),
+				State:   encoding.MemberStateAlive,
+			},
+		}
+		buffer, _, err = message.AppendToBuffer(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(encoding.MessageType(buffer[0])).To(Equal(encoding.MessageTypeListRequestWithMembers))
+	})
+
 	It("should fail to read from nil buffer", func() {
 		var readMessage encoding.MessageListRequest
 		Expect(readMessage.FromBuffer(nil)).Error().To(HaveOccurred())
@@ -54,6 +104,13 @@ var _ = Describe("MessageListRequest", func() {
 	It("should fail to read from buffer which is too small", func() {
 		message := encoding.MessageListRequest{
 			Source: encoding.NewAddress(net.IPv4(1, 2, 3, 4), 1024),
+			Members: []encoding.Member{
+				{
+					Address:           encoding.NewAddress(net.IPv4(1, 2, 3, 4), 1024),
+					State:             encoding.MemberStateAlive,
+					IncarnationNumber: 1,
+				},
+			},
 		}
 		buffer, _, err := message.AppendToBuffer(nil)
 		Expect(err).ToNot(HaveOccurred())
@@ -0,0 +1,76 @@
+package encoding
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MessageNeighbor asks the destination to add the source to its active view. The destination answers with
+// MessageNeighborReply.
+type MessageNeighbor struct {
+	// Source is the member which wants to become a neighbor.
+	Source Address
+
+	// HighPriority is set when the active view of source is empty. The destination must accept a request with high
+	// priority, even if its active view is full.
+	HighPriority bool
+}
+
+// ToMessage converts the specific message into the general purpose message.
+func (m MessageNeighbor) ToMessage() Message {
+	return Message{
+		Type:         MessageTypeNeighbor,
+		Source:       m.Source,
+		HighPriority: m.HighPriority,
+	}
+}
+
+func (m MessageNeighbor) String() string {
+	return fmt.Sprintf("Neighbor (by %s, high priority %t)", m.Source, m.HighPriority)
+}
+
+// AppendToBuffer appends the message to the provided buffer encoded for network transfer.
+// Returns the buffer with the data appended, the number of bytes appended and any error which occurred.
+func (m MessageNeighbor) AppendToBuffer(buffer []byte) ([]byte, int, error) {
+	messageTypeBuffer, messageTypeN, err := AppendMessageTypeToBuffer(buffer, MessageTypeNeighbor)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	sourceBuffer, sourceN, err := AppendAddressToBuffer(messageTypeBuffer, m.Source)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	highPriorityBuffer, highPriorityN, err := AppendBoolToBuffer(sourceBuffer, m.HighPriority)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	return highPriorityBuffer, messageTypeN + sourceN + highPriorityN, nil
+}
+
+// FromBuffer reads the message from the provided buffer.
+// Returns the number of bytes read and any error which occurred.
+func (m *MessageNeighbor) FromBuffer(buffer []byte) (int, error) {
+	messageType, messageTypeN, err := MessageTypeFromBuffer(buffer)
+	if err != nil {
+		return 0, err
+	}
+	if messageType != MessageTypeNeighbor {
+		return 0, errors.New("invalid message type")
+	}
+
+	var sourceN, highPriorityN int
+	m.Source, sourceN, err = AddressFromBuffer(buffer[messageTypeN:])
+	if err != nil {
+		return 0, err
+	}
+
+	m.HighPriority, highPriorityN, err = BoolFromBuffer(buffer[messageTypeN+sourceN:])
+	if err != nil {
+		return 0, err
+	}
+
+	return messageTypeN + sourceN + highPriorityN, nil
+}
@@ -0,0 +1,74 @@
+package encoding
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MessageNeighborReply answers a MessageNeighbor. When accepted, both members have each other in their active view.
+type MessageNeighborReply struct {
+	// Source is the member which answers the neighbor request.
+	Source Address
+
+	// Accepted reports if source added the requesting member to its active view.
+	Accepted bool
+}
+
+// ToMessage converts the specific message into the general purpose message.
+func (m MessageNeighborReply) ToMessage() Message {
+	return Message{
+		Type:     MessageTypeNeighborReply,
+		Source:   m.Source,
+		Accepted: m.Accepted,
+	}
+}
+
+func (m MessageNeighborReply) String() string {
+	return fmt.Sprintf("NeighborReply (by %s, accepted %t)", m.Source, m.Accepted)
+}
+
+// AppendToBuffer appends the message to the provided buffer encoded for network transfer.
+// Returns the buffer with the data appended, the number of bytes appended and any error which occurred.
+func (m MessageNeighborReply) AppendToBuffer(buffer []byte) ([]byte, int, error) {
+	messageTypeBuffer, messageTypeN, err := AppendMessageTypeToBuffer(buffer, MessageTypeNeighborReply)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	sourceBuffer, sourceN, err := AppendAddressToBuffer(messageTypeBuffer, m.Source)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	acceptedBuffer, acceptedN, err := AppendBoolToBuffer(sourceBuffer, m.Accepted)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	return acceptedBuffer, messageTypeN + sourceN + acceptedN, nil
+}
+
+// FromBuffer reads the message from the provided buffer.
+// Returns the number of bytes read and any error which occurred.
+func (m *MessageNeighborReply) FromBuffer(buffer []byte) (int, error) {
+	messageType, messageTypeN, err := MessageTypeFromBuffer(buffer)
+	if err != nil {
+		return 0, err
+	}
+	if messageType != MessageTypeNeighborReply {
+		return 0, errors.New("invalid message type")
+	}
+
+	var sourceN, acceptedN int
+	m.Source, sourceN, err = AddressFromBuffer(buffer[messageTypeN:])
+	if err != nil {
+		return 0, err
+	}
+
+	m.Accepted, acceptedN, err = BoolFromBuffer(buffer[messageTypeN+sourceN:])
+	if err != nil {
+		return 0, err
+	}
+
+	return messageTypeN + sourceN + acceptedN, nil
+}
@@ -0,0 +1,61 @@
+package encoding
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MessagePrune informs the destination that the source received a broadcast twice. The destination stops pushing
+// broadcasts eagerly to the source and only announces them lazily from then on.
+type MessagePrune struct {
+	// Source is the member which received the duplicate broadcast.
+	Source Address
+}
+
+// ToMessage converts the specific message into the general purpose message.
+func (m MessagePrune) ToMessage() Message {
+	return Message{
+		Type:   MessageTypePrune,
+		Source: m.Source,
+	}
+}
+
+func (m MessagePrune) String() string {
+	return fmt.Sprintf("Prune (by %s)", m.Source)
+}
+
+// AppendToBuffer appends the message to the provided buffer encoded for network transfer.
+// Returns the buffer with the data appended, the number of bytes appended and any error which occurred.
+func (m MessagePrune) AppendToBuffer(buffer []byte) ([]byte, int, error) {
+	messageTypeBuffer, messageTypeN, err := AppendMessageTypeToBuffer(buffer, MessageTypePrune)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	sourceBuffer, sourceN, err := AppendAddressToBuffer(messageTypeBuffer, m.Source)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	return sourceBuffer, messageTypeN + sourceN, nil
+}
+
+// FromBuffer reads the message from the provided buffer.
+// Returns the number of bytes read and any error which occurred.
+func (m *MessagePrune) FromBuffer(buffer []byte) (int, error) {
+	messageType, messageTypeN, err := MessageTypeFromBuffer(buffer)
+	if err != nil {
+		return 0, err
+	}
+	if messageType != MessageTypePrune {
+		return 0, errors.New("invalid message type")
+	}
+
+	var sourceN int
+	m.Source, sourceN, err = AddressFromBuffer(buffer[messageTypeN:])
+	if err != nil {
+		return 0, err
+	}
+
+	return messageTypeN + sourceN, nil
+}
@@ -0,0 +1,159 @@
+package encoding
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MessageQuery asks all members to answer a question. It is disseminated through the cluster as gossip. Every member
+// which matches the filter acknowledges the query and responds directly to the source, if it has a handler registered
+// for the name of the query.
+type MessageQuery struct {
+	// Source is the member which initiated the query. Acks and responses are sent to this member.
+	Source Address
+
+	// QueryID identifies the query together with the source. It is used for deduplicating the query and for matching
+	// acks and responses to the query.
+	QueryID uint32
+
+	// Name is the name of the query. It selects the handler which answers the query.
+	Name string
+
+	// Payload is the data the handler needs for answering the query. Note that the payload shares memory with the
+	// buffer the message was read from.
+	Payload []byte
+
+	// Filter is the list of members which should answer the query. All members answer when empty.
+	Filter []Address
+}
+
+func (m MessageQuery) String() string {
+	return fmt.Sprintf("Query %q (by %s, id %d)", m.Name, m.Source, m.QueryID)
+}
+
+// ToMessage converts the specific message into the general purpose message.
+func (m MessageQuery) ToMessage() Message {
+	return Message{
+		Type:    MessageTypeQuery,
+		Source:  m.Source,
+		QueryID: m.QueryID,
+		Name:    m.Name,
+		Payload: m.Payload,
+		Filter:  m.Filter,
+	}
+}
+
+// Matches reports if the given address should answer the query.
+func (m MessageQuery) Matches(address Address) bool {
+	if len(m.Filter) == 0 {
+		return true
+	}
+	for _, filterAddress := range m.Filter {
+		if filterAddress.Equal(address) {
+			return true
+		}
+	}
+	return false
+}
+
+// AppendToBuffer appends the message to the provided buffer encoded for network transfer.
+// Returns the buffer with the data appended, the number of bytes appended and any error which occurred.
+func (m MessageQuery) AppendToBuffer(buffer []byte) ([]byte, int, error) {
+	messageTypeBuffer, messageTypeN, err := AppendMessageTypeToBuffer(buffer, MessageTypeQuery)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	sourceBuffer, sourceN, err := AppendAddressToBuffer(messageTypeBuffer, m.Source)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	queryIDBuffer, queryIDN, err := AppendQueryIDToBuffer(sourceBuffer, m.QueryID)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	nameBuffer, nameN, err := AppendBytesToBuffer(queryIDBuffer, []byte(m.Name))
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	payloadBuffer, payloadN, err := AppendBytesToBuffer(nameBuffer, m.Payload)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	countBuffer, countN, err := AppendAddressCountToBuffer(payloadBuffer, len(m.Filter))
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	filterBuffer := countBuffer
+	var filterN int
+	for _, address := range m.Filter {
+		appendedBuffer, n, err := AppendAddressToBuffer(filterBuffer, address)
+		if err != nil {
+			return buffer, 0, err
+		}
+		filterBuffer = appendedBuffer
+		filterN += n
+	}
+
+	return filterBuffer, messageTypeN + sourceN + queryIDN + nameN + payloadN + countN + filterN, nil
+}
+
+// FromBuffer reads the message from the provided buffer. The filter slice of the message is re-used to reduce memory
+// allocations.
+// Returns the number of bytes read and any error which occurred.
+func (m *MessageQuery) FromBuffer(buffer []byte) (int, error) {
+	messageType, messageTypeN, err := MessageTypeFromBuffer(buffer)
+	if err != nil {
+		return 0, err
+	}
+	if messageType != MessageTypeQuery {
+		return 0, errors.New("invalid message type")
+	}
+
+	var sourceN, queryIDN int
+	m.Source, sourceN, err = AddressFromBuffer(buffer[messageTypeN:])
+	if err != nil {
+		return 0, err
+	}
+
+	m.QueryID, queryIDN, err = QueryIDFromBuffer(buffer[messageTypeN+sourceN:])
+	if err != nil {
+		return 0, err
+	}
+
+	name, nameN, err := BytesFromBuffer(buffer[messageTypeN+sourceN+queryIDN:])
+	if err != nil {
+		return 0, err
+	}
+	m.Name = string(name)
+
+	var payloadN int
+	m.Payload, payloadN, err = BytesFromBuffer(buffer[messageTypeN+sourceN+queryIDN+nameN:])
+	if err != nil {
+		return 0, err
+	}
+
+	offset := messageTypeN + sourceN + queryIDN + nameN + payloadN
+	count, countN, err := AddressCountFromBuffer(buffer[offset:])
+	if err != nil {
+		return 0, err
+	}
+	offset += countN
+
+	m.Filter = m.Filter[:0]
+	for range count {
+		address, n, err := AddressFromBuffer(buffer[offset:])
+		if err != nil {
+			return 0, err
+		}
+		offset += n
+		m.Filter = append(m.Filter, address)
+	}
+
+	return offset, nil
+}
@@ -0,0 +1,76 @@
+//nolint:dupl
+package encoding
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MessageQueryAck is sent back to the source of a query by every member which received the query and matches its
+// filter. This allows the source to tell how many members the query reached.
+type MessageQueryAck struct {
+	// Source is the member which received the query.
+	Source Address
+
+	// QueryID is the id of the query which was received.
+	QueryID uint32
+}
+
+func (m MessageQueryAck) String() string {
+	return fmt.Sprintf("QueryAck (by %s, id %d)", m.Source, m.QueryID)
+}
+
+// ToMessage converts the specific message into the general purpose message.
+func (m MessageQueryAck) ToMessage() Message {
+	return Message{
+		Type:    MessageTypeQueryAck,
+		Source:  m.Source,
+		QueryID: m.QueryID,
+	}
+}
+
+// AppendToBuffer appends the message to the provided buffer encoded for network transfer.
+// Returns the buffer with the data appended, the number of bytes appended and any error which occurred.
+func (m MessageQueryAck) AppendToBuffer(buffer []byte) ([]byte, int, error) {
+	messageTypeBuffer, messageTypeN, err := AppendMessageTypeToBuffer(buffer, MessageTypeQueryAck)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	sourceBuffer, sourceN, err := AppendAddressToBuffer(messageTypeBuffer, m.Source)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	queryIDBuffer, queryIDN, err := AppendQueryIDToBuffer(sourceBuffer, m.QueryID)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	return queryIDBuffer, messageTypeN + sourceN + queryIDN, nil
+}
+
+// FromBuffer reads the message from the provided buffer.
+// Returns the number of bytes read and any error which occurred.
+func (m *MessageQueryAck) FromBuffer(buffer []byte) (int, error) {
+	messageType, messageTypeN, err := MessageTypeFromBuffer(buffer)
+	if err != nil {
+		return 0, err
+	}
+	if messageType != MessageTypeQueryAck {
+		return 0, errors.New("invalid message type")
+	}
+
+	var sourceN, queryIDN int
+	m.Source, sourceN, err = AddressFromBuffer(buffer[messageTypeN:])
+	if err != nil {
+		return 0, err
+	}
+
+	m.QueryID, queryIDN, err = QueryIDFromBuffer(buffer[messageTypeN+sourceN:])
+	if err != nil {
+		return 0, err
+	}
+
+	return messageTypeN + sourceN + queryIDN, nil
+}
@@ -0,0 +1,89 @@
+package encoding
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MessageQueryResponse is the answer of a member to a query. It is sent directly to the source of the query.
+type MessageQueryResponse struct {
+	// Source is the member which answered the query.
+	Source Address
+
+	// QueryID is the id of the query which was answered.
+	QueryID uint32
+
+	// Payload is the answer to the query. Note that the payload shares memory with the buffer the message was read
+	// from.
+	Payload []byte
+}
+
+func (m MessageQueryResponse) String() string {
+	return fmt.Sprintf("QueryResponse (by %s, id %d)", m.Source, m.QueryID)
+}
+
+// ToMessage converts the specific message into the general purpose message.
+func (m MessageQueryResponse) ToMessage() Message {
+	return Message{
+		Type:    MessageTypeQueryResponse,
+		Source:  m.Source,
+		QueryID: m.QueryID,
+		Payload: m.Payload,
+	}
+}
+
+// AppendToBuffer appends the message to the provided buffer encoded for network transfer.
+// Returns the buffer with the data appended, the number of bytes appended and any error which occurred.
+func (m MessageQueryResponse) AppendToBuffer(buffer []byte) ([]byte, int, error) {
+	messageTypeBuffer, messageTypeN, err := AppendMessageTypeToBuffer(buffer, MessageTypeQueryResponse)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	sourceBuffer, sourceN, err := AppendAddressToBuffer(messageTypeBuffer, m.Source)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	queryIDBuffer, queryIDN, err := AppendQueryIDToBuffer(sourceBuffer, m.QueryID)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	payloadBuffer, payloadN, err := AppendBytesToBuffer(queryIDBuffer, m.Payload)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	return payloadBuffer, messageTypeN + sourceN + queryIDN + payloadN, nil
+}
+
+// FromBuffer reads the message from the provided buffer.
+// Returns the number of bytes read and any error which occurred.
+func (m *MessageQueryResponse) FromBuffer(buffer []byte) (int, error) {
+	messageType, messageTypeN, err := MessageTypeFromBuffer(buffer)
+	if err != nil {
+		return 0, err
+	}
+	if messageType != MessageTypeQueryResponse {
+		return 0, errors.New("invalid message type")
+	}
+
+	var sourceN, queryIDN, payloadN int
+	m.Source, sourceN, err = AddressFromBuffer(buffer[messageTypeN:])
+	if err != nil {
+		return 0, err
+	}
+
+	m.QueryID, queryIDN, err = QueryIDFromBuffer(buffer[messageTypeN+sourceN:])
+	if err != nil {
+		return 0, err
+	}
+
+	m.Payload, payloadN, err = BytesFromBuffer(buffer[messageTypeN+sourceN+queryIDN:])
+	if err != nil {
+		return 0, err
+	}
+
+	return messageTypeN + sourceN + queryIDN + payloadN, nil
+}
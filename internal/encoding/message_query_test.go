@@ -0,0 +1,160 @@
+package encoding_test
+
+import (
+	"net"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/encoding"
+)
+
+var _ = Describe("MessageQuery", func() {
+	It("should append to nil buffer", func() {
+		message := encoding.MessageQuery{
+			Source:  encoding.NewAddress(net.IPv4(1, 2, 3, 4), 1024),
+			QueryID: 7,
+			Name:    "version",
+			Payload: []byte("payload"),
+			Filter: []encoding.Address{
+				encoding.NewAddress(net.IPv4(5, 6, 7, 8), 1024),
+			},
+		}
+		buffer, _, err := message.AppendToBuffer(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buffer).ToNot(BeNil())
+	})
+
+	It("should append to buffer", func() {
+		var localBuffer [10]byte
+		message := encoding.MessageQuery{
+			Source:  encoding.NewAddress(net.IPv4(1, 2, 3, 4), 1024),
+			QueryID: 7,
+			Name:    "version",
+			Payload: []byte("payload"),
+			Filter: []encoding.Address{
+				encoding.NewAddress(net.IPv4(5, 6, 7, 8), 1024),
+			},
+		}
+		buffer, _, err := message.AppendToBuffer(localBuffer[:0])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buffer).ToNot(BeNil())
+	})
+
+	It("should read from buffer", func() {
+		appendMessage := encoding.MessageQuery{
+			Source:  encoding.NewAddress(net.IPv4(1, 2, 3, 4), 1024),
+			QueryID: 7,
+			Name:    "version",
+			Payload: []byte("payload"),
+			Filter: []encoding.Address{
+				encoding.NewAddress(net.IPv4(5, 6, 7, 8), 1024),
+			},
+		}
+		buffer, appendN, err := appendMessage.AppendToBuffer(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buffer).ToNot(BeNil())
+
+		var readMessage encoding.MessageQuery
+		readN, err := readMessage.FromBuffer(buffer)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(appendN).To(Equal(readN))
+		Expect(appendMessage).To(Equal(readMessage))
+	})
+
+	It("should read from buffer without filter", func() {
+		appendMessage := encoding.MessageQuery{
+			Source:  encoding.NewAddress(net.IPv4(1, 2, 3, 4), 1024),
+			QueryID: 7,
+			Name:    "version",
+			Payload: []byte("payload"),
+		}
+		buffer, _, err := appendMessage.AppendToBuffer(nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		var readMessage encoding.MessageQuery
+		Expect(readMessage.FromBuffer(buffer)).Error().ToNot(HaveOccurred())
+		Expect(readMessage).To(Equal(appendMessage))
+	})
+
+	It("should match all addresses without filter", func() {
+		message := encoding.MessageQuery{}
+		Expect(message.Matches(encoding.NewAddress(net.IPv4(5, 6, 7, 8), 1024))).To(BeTrue())
+	})
+
+	It("should match only filtered addresses with filter", func() {
+		message := encoding.MessageQuery{
+			Filter: []encoding.Address{
+				encoding.NewAddress(net.IPv4(5, 6, 7, 8), 1024),
+			},
+		}
+		Expect(message.Matches(encoding.NewAddress(net.IPv4(5, 6, 7, 8), 1024))).To(BeTrue())
+		Expect(message.Matches(encoding.NewAddress(net.IPv4(5, 6, 7, 8), 1025))).To(BeFalse())
+	})
+
+	It("should fail to read from nil buffer", func() {
+		var readMessage encoding.MessageQuery
+		Expect(readMessage.FromBuffer(nil)).Error().To(HaveOccurred())
+	})
+
+	It("should fail to read from buffer which is too small", func() {
+		message := encoding.MessageQuery{
+			Source:  encoding.NewAddress(net.IPv4(1, 2, 3, 4), 1024),
+			QueryID: 7,
+			Name:    "version",
+			Payload: []byte("payload"),
+			Filter: []encoding.Address{
+				encoding.NewAddress(net.IPv4(5, 6, 7, 8), 1024),
+			},
+		}
+		buffer, _, err := message.AppendToBuffer(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buffer).ToNot(BeNil())
+
+		for i := len(buffer) - 1; i >= 0; i-- {
+			Expect(message.FromBuffer(buffer[:i])).Error().To(HaveOccurred())
+		}
+	})
+})
+
+func BenchmarkMessageQuery_AppendToBuffer(b *testing.B) {
+	message := encoding.MessageQuery{
+		Source:  encoding.NewAddress(net.IPv4(1, 2, 3, 4), 1024),
+		QueryID: 7,
+		Name:    "version",
+		Payload: []byte("payload"),
+		Filter: []encoding.Address{
+			encoding.NewAddress(net.IPv4(5, 6, 7, 8), 1024),
+		},
+	}
+	var buffer [1024]byte
+	for b.Loop() {
+		if _, _, err := message.AppendToBuffer(buffer[:0]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMessageQuery_FromBuffer(b *testing.B) {
+	message := encoding.MessageQuery{
+		Source:  encoding.NewAddress(net.IPv4(1, 2, 3, 4), 1024),
+		QueryID: 7,
+		Name:    "version",
+		Payload: []byte("payload"),
+		Filter: []encoding.Address{
+			encoding.NewAddress(net.IPv4(5, 6, 7, 8), 1024),
+		},
+	}
+	buffer, _, err := message.AppendToBuffer(nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for b.Loop() {
+		if _, err := message.FromBuffer(buffer); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
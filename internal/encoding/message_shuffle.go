@@ -0,0 +1,105 @@
+package encoding
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MessageShuffle exchanges a sample of known members through a random walk over the active views. The member at the
+// end of the walk answers with MessageShuffleReply and both members integrate the samples into their passive views.
+type MessageShuffle struct {
+	// Source is the member which forwarded the message.
+	Source Address
+
+	// Destination is the member which initiated the shuffle and receives the reply.
+	Destination Address
+
+	// TimeToLive is the number of hops the random walk still takes.
+	TimeToLive uint8
+
+	// Addresses is the sample of members destination knows about. Note that it is re-used when reading the message
+	// from a buffer.
+	Addresses []Address
+}
+
+// ToMessage converts the specific message into the general purpose message.
+func (m MessageShuffle) ToMessage() Message {
+	return Message{
+		Type:        MessageTypeShuffle,
+		Source:      m.Source,
+		Destination: m.Destination,
+		TimeToLive:  m.TimeToLive,
+		Addresses:   m.Addresses,
+	}
+}
+
+func (m MessageShuffle) String() string {
+	return fmt.Sprintf("Shuffle (by %s, for %s, time to live %d, %d addresses)", m.Source, m.Destination, m.TimeToLive, len(m.Addresses))
+}
+
+// AppendToBuffer appends the message to the provided buffer encoded for network transfer.
+// Returns the buffer with the data appended, the number of bytes appended and any error which occurred.
+func (m MessageShuffle) AppendToBuffer(buffer []byte) ([]byte, int, error) {
+	messageTypeBuffer, messageTypeN, err := AppendMessageTypeToBuffer(buffer, MessageTypeShuffle)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	sourceBuffer, sourceN, err := AppendAddressToBuffer(messageTypeBuffer, m.Source)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	destinationBuffer, destinationN, err := AppendAddressToBuffer(sourceBuffer, m.Destination)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	timeToLiveBuffer, timeToLiveN, err := AppendTimeToLiveToBuffer(destinationBuffer, m.TimeToLive)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	addressesBuffer, addressesN, err := AppendAddressesToBuffer(timeToLiveBuffer, m.Addresses)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	return addressesBuffer, messageTypeN + sourceN + destinationN + timeToLiveN + addressesN, nil
+}
+
+// FromBuffer reads the message from the provided buffer. The addresses slice of the message is re-used to reduce
+// memory allocations.
+// Returns the number of bytes read and any error which occurred.
+func (m *MessageShuffle) FromBuffer(buffer []byte) (int, error) {
+	messageType, messageTypeN, err := MessageTypeFromBuffer(buffer)
+	if err != nil {
+		return 0, err
+	}
+	if messageType != MessageTypeShuffle {
+		return 0, errors.New("invalid message type")
+	}
+
+	var sourceN, destinationN, timeToLiveN, addressesN int
+	m.Source, sourceN, err = AddressFromBuffer(buffer[messageTypeN:])
+	if err != nil {
+		return 0, err
+	}
+
+	m.Destination, destinationN, err = AddressFromBuffer(buffer[messageTypeN+sourceN:])
+	if err != nil {
+		return 0, err
+	}
+
+	m.TimeToLive, timeToLiveN, err = TimeToLiveFromBuffer(buffer[messageTypeN+sourceN+destinationN:])
+	if err != nil {
+		return 0, err
+	}
+
+	m.Addresses, addressesN, err = AddressesFromBuffer(buffer[messageTypeN+sourceN+destinationN+timeToLiveN:], m.Addresses[:0])
+	if err != nil {
+		return 0, err
+	}
+
+	return messageTypeN + sourceN + destinationN + timeToLiveN + addressesN, nil
+}
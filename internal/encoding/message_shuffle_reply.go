@@ -0,0 +1,76 @@
+package encoding
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MessageShuffleReply answers a MessageShuffle with a sample of the members the source knows about.
+type MessageShuffleReply struct {
+	// Source is the member which answers the shuffle.
+	Source Address
+
+	// Addresses is the sample of members source knows about. Note that it is re-used when reading the message from a
+	// buffer.
+	Addresses []Address
+}
+
+// ToMessage converts the specific message into the general purpose message.
+func (m MessageShuffleReply) ToMessage() Message {
+	return Message{
+		Type:      MessageTypeShuffleReply,
+		Source:    m.Source,
+		Addresses: m.Addresses,
+	}
+}
+
+func (m MessageShuffleReply) String() string {
+	return fmt.Sprintf("ShuffleReply (by %s, %d addresses)", m.Source, len(m.Addresses))
+}
+
+// AppendToBuffer appends the message to the provided buffer encoded for network transfer.
+// Returns the buffer with the data appended, the number of bytes appended and any error which occurred.
+func (m MessageShuffleReply) AppendToBuffer(buffer []byte) ([]byte, int, error) {
+	messageTypeBuffer, messageTypeN, err := AppendMessageTypeToBuffer(buffer, MessageTypeShuffleReply)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	sourceBuffer, sourceN, err := AppendAddressToBuffer(messageTypeBuffer, m.Source)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	addressesBuffer, addressesN, err := AppendAddressesToBuffer(sourceBuffer, m.Addresses)
+	if err != nil {
+		return buffer, 0, err
+	}
+
+	return addressesBuffer, messageTypeN + sourceN + addressesN, nil
+}
+
+// FromBuffer reads the message from the provided buffer. The addresses slice of the message is re-used to reduce
+// memory allocations.
+// Returns the number of bytes read and any error which occurred.
+func (m *MessageShuffleReply) FromBuffer(buffer []byte) (int, error) {
+	messageType, messageTypeN, err := MessageTypeFromBuffer(buffer)
+	if err != nil {
+		return 0, err
+	}
+	if messageType != MessageTypeShuffleReply {
+		return 0, errors.New("invalid message type")
+	}
+
+	var sourceN, addressesN int
+	m.Source, sourceN, err = AddressFromBuffer(buffer[messageTypeN:])
+	if err != nil {
+		return 0, err
+	}
+
+	m.Addresses, addressesN, err = AddressesFromBuffer(buffer[messageTypeN+sourceN:], m.Addresses[:0])
+	if err != nil {
+		return 0, err
+	}
+
+	return messageTypeN + sourceN + addressesN, nil
+}
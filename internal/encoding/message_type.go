@@ -15,6 +15,24 @@ const (
 	MessageTypeFaulty
 	MessageTypeListRequest
 	MessageTypeListResponse
+	MessageTypeQuery
+	MessageTypeQueryAck
+	MessageTypeQueryResponse
+	MessageTypeForceRemove
+	MessageTypeJoin
+	MessageTypeForwardJoin
+	MessageTypeNeighbor
+	MessageTypeNeighborReply
+	MessageTypeDisconnect
+	MessageTypeShuffle
+	MessageTypeShuffleReply
+	MessageTypeBroadcast
+	MessageTypeIHave
+	MessageTypeGraft
+	MessageTypePrune
+	MessageTypeDirectAckWithPayload
+	MessageTypeIndirectAckWithPayload
+	MessageTypeListRequestWithMembers
 )
 
 // AppendMessageTypeToBuffer appends the message type to the provided buffer encoded for network transfer.
@@ -53,6 +71,42 @@ func (t MessageType) String() string {
 		return "ListRequest"
 	case MessageTypeListResponse:
 		return "ListResponse"
+	case MessageTypeQuery:
+		return "Query"
+	case MessageTypeQueryAck:
+		return "QueryAck"
+	case MessageTypeQueryResponse:
+		return "QueryResponse"
+	case MessageTypeForceRemove:
+		return "ForceRemove"
+	case MessageTypeJoin:
+		return "Join"
+	case MessageTypeForwardJoin:
+		return "ForwardJoin"
+	case MessageTypeNeighbor:
+		return "Neighbor"
+	case MessageTypeNeighborReply:
+		return "NeighborReply"
+	case MessageTypeDisconnect:
+		return "Disconnect"
+	case MessageTypeShuffle:
+		return "Shuffle"
+	case MessageTypeShuffleReply:
+		return "ShuffleReply"
+	case MessageTypeBroadcast:
+		return "Broadcast"
+	case MessageTypeIHave:
+		return "IHave"
+	case MessageTypeGraft:
+		return "Graft"
+	case MessageTypePrune:
+		return "Prune"
+	case MessageTypeDirectAckWithPayload:
+		return "DirectAckWithPayload"
+	case MessageTypeIndirectAckWithPayload:
+		return "IndirectAckWithPayload"
+	case MessageTypeListRequestWithMembers:
+		return "ListRequestWithMembers"
 	default:
 		return "<unknown>"
 	}
@@ -0,0 +1,20 @@
+package encoding
+
+import (
+	"errors"
+)
+
+// AppendQueryIDToBuffer appends the query id to the provided buffer encoded for network transfer.
+// Returns the buffer with the data appended, the number of bytes appended and any error which occurred.
+func AppendQueryIDToBuffer(buffer []byte, queryID uint32) ([]byte, int, error) {
+	return Endian.AppendUint32(buffer, queryID), 4, nil
+}
+
+// QueryIDFromBuffer reads the query id from the provided buffer.
+// Returns the query id, the number of bytes read and any error which occurred.
+func QueryIDFromBuffer(buffer []byte) (uint32, int, error) {
+	if len(buffer) < 4 {
+		return 0, 0, errors.New("query id buffer too small")
+	}
+	return Endian.Uint32(buffer), 4, nil
+}
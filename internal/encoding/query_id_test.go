@@ -0,0 +1,29 @@
+package encoding_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/encoding"
+)
+
+var _ = Describe("QueryID", func() {
+	It("should read from buffer", func() {
+		buffer, appendN, err := encoding.AppendQueryIDToBuffer(nil, 0xdeadbeef)
+		Expect(err).ToNot(HaveOccurred())
+
+		queryID, readN, err := encoding.QueryIDFromBuffer(buffer)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(readN).To(Equal(appendN))
+		Expect(queryID).To(Equal(uint32(0xdeadbeef)))
+	})
+
+	It("should fail to read from buffer which is too small", func() {
+		buffer, _, err := encoding.AppendQueryIDToBuffer(nil, 7)
+		Expect(err).ToNot(HaveOccurred())
+
+		for i := len(buffer) - 1; i >= 0; i-- {
+			Expect(encoding.QueryIDFromBuffer(buffer[:i])).Error().To(HaveOccurred())
+		}
+	})
+})
@@ -0,0 +1,21 @@
+package encoding
+
+import (
+	"errors"
+)
+
+// AppendTimeToLiveToBuffer appends the time to live of a random walk to the provided buffer encoded for network
+// transfer.
+// Returns the buffer with the data appended, the number of bytes appended and any error which occurred.
+func AppendTimeToLiveToBuffer(buffer []byte, timeToLive uint8) ([]byte, int, error) {
+	return append(buffer, timeToLive), 1, nil
+}
+
+// TimeToLiveFromBuffer reads the time to live of a random walk from the provided buffer.
+// Returns the time to live, the number of bytes read and any error which occurred.
+func TimeToLiveFromBuffer(buffer []byte) (uint8, int, error) {
+	if len(buffer) < 1 {
+		return 0, 0, errors.New("time to live buffer too small")
+	}
+	return buffer[0], 1, nil
+}
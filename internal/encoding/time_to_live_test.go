@@ -0,0 +1,83 @@
+package encoding_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/encoding"
+)
+
+var _ = Describe("TimeToLive", func() {
+	It("should append to nil buffer", func() {
+		buffer, _, err := encoding.AppendTimeToLiveToBuffer(nil, 6)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buffer).ToNot(BeNil())
+	})
+
+	It("should append to buffer", func() {
+		var localBuffer [10]byte
+		buffer, _, err := encoding.AppendTimeToLiveToBuffer(localBuffer[:0], 6)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buffer).ToNot(BeNil())
+	})
+
+	DescribeTable("should append to buffer with valid times to live",
+		func(timeToLive int) {
+			buffer, _, err := encoding.AppendTimeToLiveToBuffer(nil, uint8(timeToLive))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(buffer).ToNot(BeNil())
+		},
+		Entry("zero", 0),
+		Entry("small positive", 6),
+		Entry("big positive", 255),
+	)
+
+	It("should read from buffer", func() {
+		buffer, appendN, err := encoding.AppendTimeToLiveToBuffer(nil, 6)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buffer).ToNot(BeNil())
+
+		readTimeToLive, readN, err := encoding.TimeToLiveFromBuffer(buffer)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(appendN).To(Equal(readN))
+		Expect(uint8(6)).To(Equal(readTimeToLive))
+	})
+
+	It("should fail to read from nil buffer", func() {
+		Expect(encoding.TimeToLiveFromBuffer(nil)).Error().To(HaveOccurred())
+	})
+
+	It("should fail to read from buffer which is too small", func() {
+		buffer, _, err := encoding.AppendTimeToLiveToBuffer(nil, 6)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buffer).ToNot(BeNil())
+
+		for i := len(buffer) - 1; i >= 0; i-- {
+			Expect(encoding.TimeToLiveFromBuffer(buffer[:i])).Error().To(HaveOccurred())
+		}
+	})
+})
+
+func BenchmarkAppendTimeToLiveToBuffer(b *testing.B) {
+	var buffer [6]byte
+	for b.Loop() {
+		if _, _, err := encoding.AppendTimeToLiveToBuffer(buffer[:0], 6); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTimeToLiveFromBuffer(b *testing.B) {
+	buffer, _, err := encoding.AppendTimeToLiveToBuffer(nil, 6)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for b.Loop() {
+		if _, _, err := encoding.TimeToLiveFromBuffer(buffer); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
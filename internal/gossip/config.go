@@ -1,5 +1,7 @@
 package gossip
 
+import "github.com/backbone81/membership/internal/encoding"
+
 // Config is the configuration for the gossip queue.
 type Config struct {
 	// MaxTransmissionCount is the maximum number of times messages in the gossip queue are transmitted before they are
@@ -9,6 +11,19 @@ type Config struct {
 	// PreAllocationCount is the number of elements the gossip queue should pre-allocate to reduce the number of grow
 	// events during runtime.
 	PreAllocationCount int
+
+	// MaxLength is the maximum number of messages the gossip queue holds. When the queue is full, the most transmitted
+	// messages are evicted to make room for new messages. Zero means that the queue is unbounded.
+	MaxLength int
+
+	// ProtectedAddress is the address whose messages are never evicted. The membership list protects its own address,
+	// which keeps the refutations of suspicions about itself in the queue. The queue might exceed the max length by
+	// that message.
+	ProtectedAddress encoding.Address
+
+	// Metrics holds the metrics collectors the gossip queue reports to. New metrics which are not registered anywhere
+	// are created when nil.
+	Metrics *Metrics
 }
 
 // DefaultConfig provides a default configuration for the gossip queue with sane defaults for most situations.
@@ -14,7 +14,8 @@ func ShouldReplaceExistingWithNew(existingMsg encoding.Message, newMsg encoding.
 	}
 
 	if newMsg.IncarnationNumber == existingMsg.IncarnationNumber &&
-		(newMsg.Type == encoding.MessageTypeAlive ||
+		(existingMsg.Type == encoding.MessageTypeForceRemove ||
+			newMsg.Type == encoding.MessageTypeAlive ||
 			newMsg.Type == encoding.MessageTypeSuspect && existingMsg.Type != encoding.MessageTypeAlive ||
 			newMsg.Type == encoding.MessageTypeFaulty && existingMsg.Type != encoding.MessageTypeAlive && existingMsg.Type != encoding.MessageTypeSuspect) {
 		// No need to overwrite with the same incarnation number and the wrong priorities.
@@ -2,55 +2,85 @@ package gossip
 
 import "github.com/prometheus/client_golang/prometheus"
 
-var (
-	MessagesAddedTotal = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Name: "membership_gossip_messages_added_total",
-			Help: "Total number of gossip messages added.",
-		},
-	)
-	MessagesOverwrittenTotal = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Name: "membership_gossip_messages_overwritten_total",
-			Help: "Total number of gossip messages overwritten due to higher precedence.",
-		},
-	)
-	MessagesRemovedTotal = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Name: "membership_gossip_messages_removed_total",
-			Help: "Total number of gossip messages removed after exceeding maximum transmission count.",
-		},
-	)
-	MessagesByTypeTotal = prometheus.NewCounterVec(
-		prometheus.CounterOpts{
-			Name: "membership_gossip_messages_by_type_total",
-			Help: "Total number of gossip messages added to the queue, labeled by message type.",
-		},
-		[]string{"type"},
-	)
-	QueueCapacityMessages = prometheus.NewGauge(
-		prometheus.GaugeOpts{
-			Name: "membership_gossip_queue_capacity_messages",
-			Help: "Current capacity of the gossip queue ring buffer, measured in messages.",
-		},
-	)
-	QueueGrowthsTotal = prometheus.NewCounter(
-		prometheus.CounterOpts{
-			Name: "membership_gossip_queue_growths_total",
-			Help: "Total number of times the ring buffer grew to accommodate more messages.",
-		},
-	)
-)
+// Metrics holds the metrics collectors of a gossip queue. Every gossip queue owns its own metrics, which allows for
+// running multiple gossip queues in the same process without mixing up their numbers.
+type Metrics struct {
+	MessagesAddedTotal       prometheus.Counter
+	MessagesOverwrittenTotal prometheus.Counter
+	MessagesRemovedTotal     prometheus.Counter
+	MessagesEvictedTotal     *prometheus.CounterVec
+	MessagesByTypeTotal      *prometheus.CounterVec
+	QueueCapacityMessages    prometheus.Gauge
+	QueueGrowthsTotal        prometheus.Counter
+}
+
+// NewMetrics creates new metrics collectors with the given const labels. The const labels might be nil.
+func NewMetrics(constLabels prometheus.Labels) *Metrics {
+	return &Metrics{
+		MessagesAddedTotal: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name:        "membership_gossip_messages_added_total",
+				Help:        "Total number of gossip messages added.",
+				ConstLabels: constLabels,
+			},
+		),
+		MessagesOverwrittenTotal: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name:        "membership_gossip_messages_overwritten_total",
+				Help:        "Total number of gossip messages overwritten due to higher precedence.",
+				ConstLabels: constLabels,
+			},
+		),
+		MessagesRemovedTotal: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name:        "membership_gossip_messages_removed_total",
+				Help:        "Total number of gossip messages removed after exceeding maximum transmission count.",
+				ConstLabels: constLabels,
+			},
+		),
+		MessagesEvictedTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "membership_gossip_messages_evicted_total",
+				Help:        "Total number of gossip messages evicted because the queue reached its maximum length, labeled by message type.",
+				ConstLabels: constLabels,
+			},
+			[]string{"type"},
+		),
+		MessagesByTypeTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "membership_gossip_messages_by_type_total",
+				Help:        "Total number of gossip messages added to the queue, labeled by message type.",
+				ConstLabels: constLabels,
+			},
+			[]string{"type"},
+		),
+		QueueCapacityMessages: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "membership_gossip_queue_capacity_messages",
+				Help:        "Current capacity of the gossip queue ring buffer, measured in messages.",
+				ConstLabels: constLabels,
+			},
+		),
+		QueueGrowthsTotal: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name:        "membership_gossip_queue_growths_total",
+				Help:        "Total number of times the ring buffer grew to accommodate more messages.",
+				ConstLabels: constLabels,
+			},
+		),
+	}
+}
 
-// RegisterMetrics registers all metrics collectors with the given prometheus registerer.
-func RegisterMetrics(registerer prometheus.Registerer) error {
+// Register registers all metrics collectors with the given prometheus registerer.
+func (m *Metrics) Register(registerer prometheus.Registerer) error {
 	metrics := []prometheus.Collector{
-		MessagesAddedTotal,
-		MessagesOverwrittenTotal,
-		MessagesRemovedTotal,
-		MessagesByTypeTotal,
-		QueueCapacityMessages,
-		QueueGrowthsTotal,
+		m.MessagesAddedTotal,
+		m.MessagesOverwrittenTotal,
+		m.MessagesRemovedTotal,
+		m.MessagesEvictedTotal,
+		m.MessagesByTypeTotal,
+		m.QueueCapacityMessages,
+		m.QueueGrowthsTotal,
 	}
 	for _, metric := range metrics {
 		if err := registerer.Register(metric); err != nil {
@@ -1,5 +1,7 @@
 package gossip
 
+import "github.com/backbone81/membership/internal/encoding"
+
 // Option is the function signature for all queue options to implement.
 type Option func(config *Config)
 
@@ -16,3 +18,22 @@ func WithPreAllocationCount(count int) Option {
 		config.PreAllocationCount = count
 	}
 }
+
+func WithMaxLength(length int) Option {
+	length = max(0, length)
+	return func(config *Config) {
+		config.MaxLength = length
+	}
+}
+
+func WithProtectedAddress(address encoding.Address) Option {
+	return func(config *Config) {
+		config.ProtectedAddress = address
+	}
+}
+
+func WithMetrics(metrics *Metrics) Option {
+	return func(config *Config) {
+		config.Metrics = metrics
+	}
+}
@@ -44,6 +44,11 @@ import (
 // at their location as long as possible. This means that we need to consider wrap-arounds at the end of the ring buffer
 // throughout our code. When the ring buffer is full, it can grow by copying the content over into a new buffer.
 //
+// The queue can be bounded with a max length. When a new message arrives at a full queue, the message at the tail is
+// evicted. This is the message which was transmitted the most, as the highest bucket sits at the tail. Messages about
+// the protected address are never evicted. When such a message is at the tail, it is moved to the first bucket instead,
+// and the message now sitting at the tail is evicted.
+//
 // This implementation results in the most common operations being O(1) and some less common situations to be
 // O(buckets) in a worst case scenario.
 //
@@ -75,6 +80,9 @@ type Queue struct {
 	// priorityIndex is the ring index which should be returned as the first element when iterating of all messages.
 	// This allows us to prioritize suspect and faulty messages when we are talking to that node right now.
 	priorityIndex int
+
+	// evictions is the number of messages evicted since the queue was created.
+	evictions int
 }
 
 // NewQueue creates a new gossip message queue.
@@ -83,7 +91,11 @@ func NewQueue(options ...Option) *Queue {
 	for _, option := range options {
 		option(&config)
 	}
-	QueueCapacityMessages.Set(float64(config.PreAllocationCount))
+	if config.Metrics == nil {
+		// Metrics which are not registered anywhere are simply never collected.
+		config.Metrics = NewMetrics(nil)
+	}
+	config.Metrics.QueueCapacityMessages.Set(float64(config.PreAllocationCount))
 	return &Queue{
 		config:         config,
 		ring:           make([]QueueEntry, config.PreAllocationCount),
@@ -115,6 +127,11 @@ func (q *Queue) Cap() int {
 	return len(q.ring)
 }
 
+// Evictions returns the number of messages evicted since the queue was created. Clear does not reset this number.
+func (q *Queue) Evictions() int {
+	return q.evictions
+}
+
 // Buckets returns the number of buckets available for elements.
 func (q *Queue) Buckets() int {
 	return len(q.bucketStarts)
@@ -168,11 +185,16 @@ func (q *Queue) Add(message encoding.Message) {
 		if entry.TransmissionCount != 0 {
 			_ = q.moveToFirstBucket(index)
 		}
-		MessagesOverwrittenTotal.Inc()
-		MessagesByTypeTotal.WithLabelValues(message.Type.String()).Inc()
+		q.config.Metrics.MessagesOverwrittenTotal.Inc()
+		q.config.Metrics.MessagesByTypeTotal.WithLabelValues(message.Type.String()).Inc()
 		return
 	}
 
+	// Make room for the new element when the queue is bounded.
+	if q.config.MaxLength > 0 && q.Len() >= q.config.MaxLength {
+		q.evict()
+	}
+
 	// Make sure we have space left in our ring buffer to add the new element.
 	if (q.head+1)%len(q.ring) == q.tail {
 		q.grow()
@@ -185,8 +207,8 @@ func (q *Queue) Add(message encoding.Message) {
 	}
 	q.indexByAddress[message.Destination] = q.head
 	q.head = (q.head + 1) % len(q.ring)
-	MessagesAddedTotal.Inc()
-	MessagesByTypeTotal.WithLabelValues(message.Type.String()).Inc()
+	q.config.Metrics.MessagesAddedTotal.Inc()
+	q.config.Metrics.MessagesByTypeTotal.WithLabelValues(message.Type.String()).Inc()
 }
 
 // Prioritize marks a message for the given address as priority. If such a message exists, it will always be
@@ -196,8 +218,9 @@ func (q *Queue) Prioritize(address encoding.Address) {
 	if found {
 		messageType := q.ring[index].Message.Type
 		if messageType == encoding.MessageTypeSuspect ||
-			messageType == encoding.MessageTypeFaulty {
-			// We are only prioritizing suspect or faulty messages. We do not have to tell the member that we know that
+			messageType == encoding.MessageTypeFaulty ||
+			messageType == encoding.MessageTypeForceRemove {
+			// We are only prioritizing suspect, faulty or force remove messages. We do not have to tell the member that we know that
 			// it is alive, for example.
 			q.priorityIndex = index
 			return
@@ -281,8 +304,52 @@ func (q *Queue) cleanupTail() {
 			q.priorityIndex = -1
 		}
 		q.tail = (q.tail + 1) % len(q.ring)
-		MessagesRemovedTotal.Inc()
+		q.config.Metrics.MessagesRemovedTotal.Inc()
+	}
+}
+
+// evict removes the most transmitted message from the queue. Messages about the protected address are never evicted.
+// Nothing is evicted when the queue only holds the message about the protected address.
+func (q *Queue) evict() {
+	if q.IsEmpty() {
+		return
 	}
+	if q.isProtected(q.tail) {
+		// Moving the protected message to the first bucket gives it a fresh start, which does no harm as refutations
+		// need to spread quickly anyway.
+		_ = q.moveToFirstBucket(q.tail)
+		if q.isProtected(q.tail) {
+			// The protected message is still at the tail, which means that all messages are in the first bucket. We
+			// can swap it with the next message without breaking the buckets.
+			if q.Len() == 1 {
+				return
+			}
+			q.swapElements(q.tail, (q.tail+1)%len(q.ring))
+		}
+	}
+
+	message := q.ring[q.tail].Message
+	delete(q.indexByAddress, message.Destination)
+	if q.priorityIndex == q.tail {
+		q.priorityIndex = -1
+	}
+	oldTail := q.tail
+	q.tail = (q.tail + 1) % len(q.ring)
+
+	// The bucket holding the evicted element and all empty buckets after it started at the old tail. They need to
+	// start at the new tail now.
+	for i := range q.bucketStarts {
+		if q.bucketStarts[i] == oldTail {
+			q.bucketStarts[i] = q.tail
+		}
+	}
+	q.evictions++
+	q.config.Metrics.MessagesEvictedTotal.WithLabelValues(message.Type.String()).Inc()
+}
+
+// isProtected reports if the element at the given index is a message about the protected address.
+func (q *Queue) isProtected(index int) bool {
+	return !q.config.ProtectedAddress.IsZero() && q.ring[index].Message.Destination.Equal(q.config.ProtectedAddress)
 }
 
 // grow increases the capacity of the ring buffer. Call this method when you need more space. Be aware that this is an
@@ -316,8 +383,8 @@ func (q *Queue) grow() {
 	q.head = q.Len()
 	q.tail = 0
 	q.ring = newRing
-	QueueCapacityMessages.Set(float64(len(newRing)))
-	QueueGrowthsTotal.Inc()
+	q.config.Metrics.QueueCapacityMessages.Set(float64(len(newRing)))
+	q.config.Metrics.QueueGrowthsTotal.Inc()
 }
 
 // adjustIndexAfterGrow returns the new index after the ring buffer was grown to a bigger size.
@@ -8,6 +8,7 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 
 	"github.com/backbone81/membership/internal/encoding"
 	"github.com/backbone81/membership/internal/gossip"
@@ -725,6 +726,94 @@ var _ = Describe("Queue", func() {
 				}.ToMessage(),
 				true,
 			),
+			Entry("Force remove with same incarnation number should overwrite alive",
+				encoding.MessageAlive{
+					Destination:       TestAddress,
+					IncarnationNumber: 2,
+				}.ToMessage(),
+				encoding.MessageForceRemove{
+					Source:            TestAddress2,
+					Destination:       TestAddress,
+					IncarnationNumber: 2,
+				}.ToMessage(),
+				true,
+			),
+			Entry("Force remove with same incarnation number should overwrite faulty",
+				encoding.MessageFaulty{
+					Source:            TestAddress2,
+					Destination:       TestAddress,
+					IncarnationNumber: 2,
+				}.ToMessage(),
+				encoding.MessageForceRemove{
+					Source:            TestAddress2,
+					Destination:       TestAddress,
+					IncarnationNumber: 2,
+				}.ToMessage(),
+				true,
+			),
+			Entry("Force remove with lower incarnation number should NOT overwrite faulty",
+				encoding.MessageFaulty{
+					Source:            TestAddress2,
+					Destination:       TestAddress,
+					IncarnationNumber: 2,
+				}.ToMessage(),
+				encoding.MessageForceRemove{
+					Source:            TestAddress2,
+					Destination:       TestAddress,
+					IncarnationNumber: 1,
+				}.ToMessage(),
+				false,
+			),
+			Entry("Alive with same incarnation number should NOT overwrite force remove",
+				encoding.MessageForceRemove{
+					Source:            TestAddress2,
+					Destination:       TestAddress,
+					IncarnationNumber: 2,
+				}.ToMessage(),
+				encoding.MessageAlive{
+					Destination:       TestAddress,
+					IncarnationNumber: 2,
+				}.ToMessage(),
+				false,
+			),
+			Entry("Faulty with same incarnation number should NOT overwrite force remove",
+				encoding.MessageForceRemove{
+					Source:            TestAddress2,
+					Destination:       TestAddress,
+					IncarnationNumber: 2,
+				}.ToMessage(),
+				encoding.MessageFaulty{
+					Source:            TestAddress2,
+					Destination:       TestAddress,
+					IncarnationNumber: 2,
+				}.ToMessage(),
+				false,
+			),
+			Entry("Force remove with same incarnation number should NOT overwrite force remove",
+				encoding.MessageForceRemove{
+					Source:            TestAddress2,
+					Destination:       TestAddress,
+					IncarnationNumber: 2,
+				}.ToMessage(),
+				encoding.MessageForceRemove{
+					Source:            TestAddress2,
+					Destination:       TestAddress,
+					IncarnationNumber: 2,
+				}.ToMessage(),
+				false,
+			),
+			Entry("Alive with bigger incarnation number should overwrite force remove",
+				encoding.MessageForceRemove{
+					Source:            TestAddress2,
+					Destination:       TestAddress,
+					IncarnationNumber: 2,
+				}.ToMessage(),
+				encoding.MessageAlive{
+					Destination:       TestAddress,
+					IncarnationNumber: 3,
+				}.ToMessage(),
+				true,
+			),
 		)
 	})
 
@@ -1085,6 +1174,175 @@ var _ = Describe("Queue", func() {
 		})
 	})
 
+	Context("MaxLength", func() {
+		addressOf := func(i int) encoding.Address {
+			return encoding.NewAddress(net.IPv4(1, 2, 3, 4), 1024+i)
+		}
+		aliveOf := func(i int) encoding.Message {
+			return encoding.MessageAlive{
+				Destination:       addressOf(i),
+				IncarnationNumber: 0,
+			}.ToMessage()
+		}
+		collect := func(queue *gossip.Queue) []encoding.Address {
+			var result []encoding.Address
+			queue.ForEach(func(message encoding.Message) bool {
+				result = append(result, message.Destination)
+				return true
+			})
+			return result
+		}
+
+		It("should enforce minimum max length of 0", func() {
+			queue := gossip.NewQueue(gossip.WithMaxLength(-1))
+
+			config := queue.Config()
+			Expect(config.MaxLength).To(Equal(0))
+		})
+
+		It("should not evict messages without max length", func() {
+			queue := gossip.NewQueue(gossip.WithPreAllocationCount(4))
+			for i := range 100 {
+				queue.Add(aliveOf(i))
+			}
+			Expect(queue.Len()).To(Equal(100))
+			Expect(queue.Evictions()).To(Equal(0))
+			Expect(queue.ValidateInternalState()).To(Succeed())
+		})
+
+		It("should evict the most transmitted message first", func() {
+			queue := gossip.NewQueue(gossip.WithMaxLength(3))
+			queue.Add(aliveOf(0))
+			queue.MarkTransmitted(1)
+			queue.Add(aliveOf(1))
+			queue.Add(aliveOf(2))
+			queue.MarkTransmitted(1)
+			Expect(queue.Len()).To(Equal(3))
+
+			queue.Add(aliveOf(3))
+			Expect(queue.Len()).To(Equal(3))
+			Expect(queue.Evictions()).To(Equal(1))
+			Expect(collect(queue)).To(ConsistOf(addressOf(1), addressOf(2), addressOf(3)))
+			Expect(queue.ValidateInternalState()).To(Succeed())
+
+			queue.Add(aliveOf(4))
+			Expect(queue.Len()).To(Equal(3))
+			Expect(queue.Evictions()).To(Equal(2))
+			Expect(collect(queue)).To(ConsistOf(addressOf(2), addressOf(3), addressOf(4)))
+			Expect(queue.ValidateInternalState()).To(Succeed())
+		})
+
+		It("should not evict when overwriting an existing message", func() {
+			queue := gossip.NewQueue(gossip.WithMaxLength(2))
+			queue.Add(aliveOf(0))
+			queue.Add(aliveOf(1))
+			queue.Add(encoding.MessageSuspect{
+				Destination:       addressOf(0),
+				IncarnationNumber: 0,
+			}.ToMessage())
+
+			Expect(queue.Len()).To(Equal(2))
+			Expect(queue.Evictions()).To(Equal(0))
+			Expect(queue.ValidateInternalState()).To(Succeed())
+		})
+
+		It("should clear the priority when the prioritized message is evicted", func() {
+			queue := gossip.NewQueue(gossip.WithMaxLength(2))
+			queue.Add(encoding.MessageSuspect{
+				Destination:       addressOf(0),
+				IncarnationNumber: 0,
+			}.ToMessage())
+			queue.Add(aliveOf(1))
+			queue.Prioritize(addressOf(0))
+			queue.Add(aliveOf(2))
+
+			Expect(collect(queue)).To(Equal([]encoding.Address{addressOf(1), addressOf(2)}))
+			Expect(queue.ValidateInternalState()).To(Succeed())
+		})
+
+		It("should never evict the message about the protected address", func() {
+			queue := gossip.NewQueue(
+				gossip.WithMaxLength(3),
+				gossip.WithProtectedAddress(addressOf(0)),
+			)
+			queue.Add(aliveOf(0))
+			queue.MarkTransmitted(1)
+			queue.Add(aliveOf(1))
+			queue.MarkTransmitted(2)
+			queue.Add(aliveOf(2))
+
+			for i := 3; i < 10; i++ {
+				queue.Add(aliveOf(i))
+				Expect(queue.Len()).To(Equal(3))
+				Expect(collect(queue)).To(ContainElement(addressOf(0)))
+				Expect(queue.ValidateInternalState()).To(Succeed())
+			}
+			Expect(queue.Evictions()).To(Equal(7))
+		})
+
+		It("should evict other messages when all messages are in the first bucket", func() {
+			queue := gossip.NewQueue(
+				gossip.WithMaxLength(2),
+				gossip.WithProtectedAddress(addressOf(0)),
+			)
+			queue.Add(aliveOf(0))
+			queue.Add(aliveOf(1))
+			queue.Add(aliveOf(2))
+
+			Expect(collect(queue)).To(ConsistOf(addressOf(0), addressOf(2)))
+			Expect(queue.ValidateInternalState()).To(Succeed())
+		})
+
+		It("should exceed the max length by the message about the protected address", func() {
+			queue := gossip.NewQueue(
+				gossip.WithMaxLength(1),
+				gossip.WithProtectedAddress(addressOf(0)),
+			)
+			queue.Add(aliveOf(0))
+			queue.Add(aliveOf(1))
+
+			Expect(collect(queue)).To(ConsistOf(addressOf(0), addressOf(1)))
+			Expect(queue.Evictions()).To(Equal(0))
+			Expect(queue.ValidateInternalState()).To(Succeed())
+		})
+
+		It("should report evictions to metrics", func() {
+			metrics := gossip.NewMetrics(nil)
+			queue := gossip.NewQueue(
+				gossip.WithMaxLength(1),
+				gossip.WithMetrics(metrics),
+			)
+			queue.Add(aliveOf(0))
+			queue.Add(aliveOf(1))
+
+			Expect(testutil.ToFloat64(metrics.MessagesEvictedTotal.WithLabelValues(encoding.MessageTypeAlive.String()))).To(Equal(1.0))
+		})
+
+		It("should maintain valid internal state under random operations", func() {
+			queue := gossip.NewQueue(
+				gossip.WithPreAllocationCount(4),
+				gossip.WithMaxLength(8),
+				gossip.WithProtectedAddress(addressOf(0)),
+			)
+			queue.Add(aliveOf(0))
+			for range 100_000 {
+				switch selection := rand.Intn(100); { //nolint:gosec // we do not need crypto/rand here
+				case selection < 50: // 50% of the time we add a message
+					queue.Add(encoding.MessageAlive{
+						Destination:       addressOf(1 + rand.Intn(32)), //nolint:gosec // we do not need crypto/rand here
+						IncarnationNumber: uint16(rand.Intn(5)),         //nolint:gosec // we do not need crypto/rand here
+					}.ToMessage())
+				case selection < 55: // 5% of the time we refute again
+					queue.Add(aliveOf(0))
+				case selection < 100: // 45% of the time we mark messages as transmitted
+					queue.MarkTransmitted(rand.Intn(3)) //nolint:gosec // we do not need crypto/rand here
+				}
+				Expect(queue.Len()).To(BeNumerically("<=", 8))
+				Expect(queue.ValidateInternalState()).To(Succeed())
+			}
+		})
+	})
+
 	It("should maintain valid internal state under random operations", func() {
 		// This test is a kind of monte carlo test. Creating random inputs and validating the internal state to be
 		// correct.
@@ -0,0 +1,4 @@
+// Package hashing provides the deterministic hash functions for placing keys on members. The hashes must be the same
+// on every member and across restarts, which rules out the randomly seeded hashes of the standard library. They do not
+// allocate any memory.
+package hashing
@@ -0,0 +1,62 @@
+package hashing
+
+import (
+	"github.com/backbone81/membership/internal/encoding"
+)
+
+const (
+	// fnvOffset64 is the offset basis of the 64-bit FNV-1a hash.
+	fnvOffset64 = 14695981039346656037
+
+	// fnvPrime64 is the prime of the 64-bit FNV-1a hash.
+	fnvPrime64 = 1099511628211
+)
+
+// String returns the hash of the given string.
+func String(s string) uint64 {
+	hash := uint64(fnvOffset64)
+	for i := range len(s) {
+		hash ^= uint64(s[i])
+		hash *= fnvPrime64
+	}
+	return Mix(hash)
+}
+
+// Address returns the hash of the given address.
+func Address(address encoding.Address) uint64 {
+	hash := uint64(fnvOffset64)
+	for _, b := range address {
+		hash ^= uint64(b)
+		hash *= fnvPrime64
+	}
+	return Mix(hash)
+}
+
+// Combine returns the hash of the two given hashes. The order of the hashes matters.
+func Combine(lhs uint64, rhs uint64) uint64 {
+	return Mix(lhs*fnvPrime64 ^ rhs)
+}
+
+// Mix scrambles the bits of the given value, so that every input bit affects every output bit. FNV-1a alone spreads
+// similar inputs like addresses which only differ in the last byte poorly. This is the finalizer of SplitMix64.
+func Mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// Jump returns the bucket in the range from 0 to buckets-1 for the given key hash. When the number of buckets grows by
+// one, only the keys moving to the new bucket change their bucket. This is the jump consistent hash by Lamping and
+// Veach (https://arxiv.org/abs/1406.2294). Returns -1 when there are no buckets.
+func Jump(key uint64, buckets int) int {
+	bucket, next := -1, 0
+	for next < buckets {
+		bucket = next
+		key = key*2862933555777941757 + 1
+		next = int(float64(bucket+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return bucket
+}
@@ -0,0 +1,42 @@
+package hashing_test
+
+import (
+	"math/bits"
+	"net"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/encoding"
+	"github.com/backbone81/membership/internal/hashing"
+)
+
+var _ = Describe("Hash", func() {
+	It("should be deterministic", func() {
+		// The hashes must never change, as members with different versions need to agree on them.
+		Expect(hashing.String("job")).To(Equal(hashing.String("job")))
+		Expect(hashing.String("")).To(Equal(uint64(0xf52a15e9a9b5e89b)))
+		Expect(hashing.String("job")).ToNot(Equal(hashing.String("jog")))
+	})
+
+	It("should spread similar addresses", func() {
+		lhs := hashing.Address(encoding.NewAddress(net.IPv4(10, 0, 0, 1), 3000))
+		rhs := hashing.Address(encoding.NewAddress(net.IPv4(10, 0, 0, 2), 3000))
+		Expect(bits.OnesCount64(lhs ^ rhs)).To(BeNumerically(">", 16))
+	})
+
+	It("should depend on the order of combined hashes", func() {
+		Expect(hashing.Combine(1, 2)).ToNot(Equal(hashing.Combine(2, 1)))
+	})
+
+	It("should only move keys to the new bucket with jump hashing", func() {
+		Expect(hashing.Jump(42, 0)).To(Equal(-1))
+		for key := range uint64(1000) {
+			keyHash := hashing.Mix(key)
+			Expect(hashing.Jump(keyHash, 1)).To(BeZero())
+			before := hashing.Jump(keyHash, 10)
+			after := hashing.Jump(keyHash, 11)
+			Expect(after == before || after == 10).To(BeTrue())
+		}
+	})
+})
@@ -0,0 +1,178 @@
+package health
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// Check is a function which verifies that some part of the application is working. It returns an error when the
+// application is broken. The context is canceled when the check exceeds the configured timeout.
+type Check func(ctx context.Context) error
+
+// namedCheck is a health check together with the name it was registered with.
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Checker runs all registered health checks on a schedule and reports the outcome to the target.
+//
+// Checker is safe for concurrent use by multiple goroutines. But you need to make sure that Shutdown is only called
+// after Startup and you should call Startup and Shutdown only once. Create a new Checker if you need to restart.
+type Checker struct {
+	logger    logr.Logger
+	config    Config
+	target    Target
+	waitGroup sync.WaitGroup
+	shutdown  chan struct{}
+
+	// mutex protects checks and healthy.
+	mutex   sync.Mutex
+	checks  []namedCheck
+	healthy bool
+}
+
+// New creates a new checker with the given configuration. Provide options to customize default config.
+func New(target Target, options ...Option) *Checker {
+	config := DefaultConfig
+	for _, option := range options {
+		option(&config)
+	}
+
+	if config.Metrics == nil {
+		// Metrics which are not registered anywhere are simply never collected.
+		config.Metrics = NewMetrics(nil)
+	}
+	config.Metrics.Healthy.Set(1)
+	return &Checker{
+		logger:   config.Logger,
+		config:   config,
+		target:   target,
+		shutdown: make(chan struct{}),
+		healthy:  true,
+	}
+}
+
+// Config returns the config of the checker.
+func (c *Checker) Config() Config {
+	return c.config
+}
+
+// Register adds the given health check. The check is executed with the next run. Registering a check with a name which
+// already exists replaces the existing check.
+func (c *Checker) Register(name string, check Check) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for i := range c.checks {
+		if c.checks[i].name == name {
+			c.checks[i].check = check
+			return
+		}
+	}
+	c.checks = append(c.checks, namedCheck{
+		name:  name,
+		check: check,
+	})
+}
+
+// Healthy reports if all health checks succeeded during the last run.
+func (c *Checker) Healthy() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.healthy
+}
+
+// Startup executes the checker. It will run the health checks until Shutdown is called.
+func (c *Checker) Startup() error {
+	c.logger.Info("Health checker startup")
+	c.waitGroup.Go(func() {
+		c.checkTask()
+	})
+	return nil
+}
+
+// Shutdown stops the checker. It will block until a currently running health check has completed.
+func (c *Checker) Shutdown() error {
+	c.logger.Info("Health checker shutdown")
+	close(c.shutdown)
+	c.waitGroup.Wait()
+	return nil
+}
+
+// checkTask periodically runs all health checks.
+func (c *Checker) checkTask() {
+	c.logger.Info("Health check background task started")
+	defer c.logger.Info("Health check background task finished")
+
+	ticker := time.NewTicker(c.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.shutdown:
+			return
+		case <-ticker.C:
+			if err := c.RunChecks(); err != nil {
+				c.logger.Error(err, "Reporting health to the membership list.")
+			}
+		}
+	}
+}
+
+// RunChecks executes all registered health checks once and reports the outcome to the target. The target is only
+// informed when the outcome changes, or when it failed to take the last change. All checks are executed, even when an
+// earlier check already failed, to keep the metrics for every check up to date.
+func (c *Checker) RunChecks() error {
+	// We work on a copy of the checks to not block registering new checks while running potentially slow checks.
+	c.mutex.Lock()
+	checks := slices.Clone(c.checks)
+	c.mutex.Unlock()
+
+	healthy := true
+	for _, check := range checks {
+		if err := c.runCheck(check.check); err != nil {
+			healthy = false
+			c.logger.Info("Health check failed", "check", check.name, "error", err.Error())
+			c.config.Metrics.CheckFailuresTotal.WithLabelValues(check.name).Inc()
+			c.config.Metrics.CheckStatus.WithLabelValues(check.name).Set(0)
+			continue
+		}
+		c.config.Metrics.CheckStatus.WithLabelValues(check.name).Set(1)
+	}
+
+	c.mutex.Lock()
+	changed := c.healthy != healthy
+	c.healthy = healthy
+	c.mutex.Unlock()
+
+	if healthy {
+		c.config.Metrics.Healthy.Set(1)
+	} else {
+		c.config.Metrics.Healthy.Set(0)
+	}
+	if !changed {
+		return nil
+	}
+	c.logger.Info("Health changed", "healthy", healthy)
+	if err := c.target.SetHealthy(healthy); err != nil {
+		// The target did not take the change. We report it again with the next run.
+		c.mutex.Lock()
+		c.healthy = !healthy
+		c.mutex.Unlock()
+		return err
+	}
+	return nil
+}
+
+// runCheck executes a single health check with the configured timeout.
+func (c *Checker) runCheck(check Check) error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.config.Timeout)
+	defer cancel()
+
+	return check(ctx)
+}
@@ -0,0 +1,138 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/health"
+)
+
+var _ = Describe("Checker", func() {
+	It("should be healthy without checks", func() {
+		var target TestTarget
+		checker := health.New(&target, health.WithLogger(GinkgoLogr))
+
+		Expect(checker.RunChecks()).To(Succeed())
+		Expect(checker.Healthy()).To(BeTrue())
+		Expect(target.Reports).To(BeEmpty())
+	})
+
+	It("should report only changes in health", func() {
+		var target TestTarget
+		checker := health.New(&target, health.WithLogger(GinkgoLogr))
+		var checkErr error
+		checker.Register("test", func(ctx context.Context) error {
+			return checkErr
+		})
+
+		Expect(checker.RunChecks()).To(Succeed())
+		Expect(target.Reports).To(BeEmpty())
+
+		checkErr = errors.New("broken")
+		Expect(checker.RunChecks()).To(Succeed())
+		Expect(checker.RunChecks()).To(Succeed())
+		Expect(checker.Healthy()).To(BeFalse())
+		Expect(target.Reports).To(Equal([]bool{false}))
+
+		checkErr = nil
+		Expect(checker.RunChecks()).To(Succeed())
+		Expect(checker.Healthy()).To(BeTrue())
+		Expect(target.Reports).To(Equal([]bool{false, true}))
+	})
+
+	It("should report a change again when the target failed to take it", func() {
+		var target TestTarget
+		checker := health.New(&target, health.WithLogger(GinkgoLogr))
+		checker.Register("test", func(ctx context.Context) error {
+			return errors.New("broken")
+		})
+
+		target.Err = errors.New("failed")
+		Expect(checker.RunChecks()).ToNot(Succeed())
+		Expect(target.Reports).To(Equal([]bool{false}))
+
+		target.Err = nil
+		Expect(checker.RunChecks()).To(Succeed())
+		Expect(checker.RunChecks()).To(Succeed())
+		Expect(target.Reports).To(Equal([]bool{false, false}))
+	})
+
+	It("should be unhealthy when any check fails", func() {
+		var target TestTarget
+		checker := health.New(&target, health.WithLogger(GinkgoLogr))
+		checker.Register("good", func(ctx context.Context) error {
+			return nil
+		})
+		checker.Register("bad", func(ctx context.Context) error {
+			return errors.New("broken")
+		})
+
+		Expect(checker.RunChecks()).To(Succeed())
+		Expect(checker.Healthy()).To(BeFalse())
+	})
+
+	It("should replace a check with the same name", func() {
+		var target TestTarget
+		checker := health.New(&target, health.WithLogger(GinkgoLogr))
+		checker.Register("test", func(ctx context.Context) error {
+			return errors.New("broken")
+		})
+		checker.Register("test", func(ctx context.Context) error {
+			return nil
+		})
+
+		Expect(checker.RunChecks()).To(Succeed())
+		Expect(checker.Healthy()).To(BeTrue())
+	})
+
+	It("should cancel checks which exceed the timeout", func() {
+		synctest.Test(testingT, func(t *testing.T) {
+			defer GinkgoRecover()
+
+			var target TestTarget
+			checker := health.New(
+				&target,
+				health.WithLogger(GinkgoLogr),
+				health.WithTimeout(time.Second),
+			)
+			checker.Register("slow", func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			})
+
+			Expect(checker.RunChecks()).To(Succeed())
+			Expect(checker.Healthy()).To(BeFalse())
+		})
+	})
+
+	It("should run checks on schedule", func() {
+		synctest.Test(testingT, func(t *testing.T) {
+			defer GinkgoRecover()
+
+			var target TestTarget
+			checker := health.New(
+				&target,
+				health.WithLogger(GinkgoLogr),
+				health.WithInterval(time.Second),
+			)
+			var runs int
+			checker.Register("test", func(ctx context.Context) error {
+				runs++
+				return nil
+			})
+			Expect(checker.Startup()).To(Succeed())
+
+			time.Sleep(10*time.Second + time.Millisecond)
+			Expect(checker.Shutdown()).To(Succeed())
+			synctest.Wait()
+
+			Expect(runs).To(Equal(10))
+		})
+	})
+})
@@ -0,0 +1,30 @@
+package health
+
+import (
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// Config is the configuration the checker is using.
+type Config struct {
+	// Logger is the Logger to use for outputting status information.
+	Logger logr.Logger
+
+	// Interval is the time between two runs of all health checks.
+	Interval time.Duration
+
+	// Timeout is the maximum time a single health check is allowed to take. A health check which does not complete in
+	// time is considered failed.
+	Timeout time.Duration
+
+	// Metrics holds the metrics collectors the checker reports to. New metrics which are not registered anywhere are
+	// created when nil.
+	Metrics *Metrics
+}
+
+// DefaultConfig provides a checker configuration with sane defaults for most situations.
+var DefaultConfig = Config{
+	Interval: 5 * time.Second,
+	Timeout:  1 * time.Second,
+}
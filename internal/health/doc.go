@@ -0,0 +1,5 @@
+// Package health provides functionality for running application health checks on a schedule. The outcome of the checks
+// is reported to the membership list, which then adjusts how this member announces itself to the other members. This
+// allows a member with a broken application to drop out of the cluster, even though it still responds to network
+// messages.
+package health
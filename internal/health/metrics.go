@@ -0,0 +1,55 @@
+package health
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// Metrics holds the metrics collectors of a checker. Every checker owns its own metrics, which allows for running
+// multiple checkers in the same process without mixing up their numbers.
+type Metrics struct {
+	CheckFailuresTotal *prometheus.CounterVec
+	CheckStatus        *prometheus.GaugeVec
+	Healthy            prometheus.Gauge
+}
+
+// NewMetrics creates new metrics collectors with the given const labels. The const labels might be nil.
+func NewMetrics(constLabels prometheus.Labels) *Metrics {
+	return &Metrics{
+		CheckFailuresTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "membership_health_check_failures_total",
+				Help:        "Total number of failed health checks.",
+				ConstLabels: constLabels,
+			},
+			[]string{"check"},
+		),
+		CheckStatus: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "membership_health_check_status",
+				Help:        "Outcome of the last run of a health check (1 for success, 0 for failure).",
+				ConstLabels: constLabels,
+			},
+			[]string{"check"},
+		),
+		Healthy: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "membership_health_healthy",
+				Help:        "Reports if all health checks succeeded during the last run (1 for healthy, 0 for unhealthy).",
+				ConstLabels: constLabels,
+			},
+		),
+	}
+}
+
+// Register registers all metrics collectors with the given prometheus registerer.
+func (m *Metrics) Register(registerer prometheus.Registerer) error {
+	metrics := []prometheus.Collector{
+		m.CheckFailuresTotal,
+		m.CheckStatus,
+		m.Healthy,
+	}
+	for _, metric := range metrics {
+		if err := registerer.Register(metric); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package health
+
+import (
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// Option is the function signature for all checker options to implement.
+type Option func(config *Config)
+
+// WithLogger sets the given logger for the checker.
+func WithLogger(logger logr.Logger) Option {
+	return func(config *Config) {
+		config.Logger = logger
+	}
+}
+
+// WithInterval sets the given interval between two runs of all health checks.
+func WithInterval(interval time.Duration) Option {
+	return func(config *Config) {
+		config.Interval = interval
+	}
+}
+
+// WithTimeout sets the given timeout for a single health check.
+func WithTimeout(timeout time.Duration) Option {
+	return func(config *Config) {
+		config.Timeout = timeout
+	}
+}
+
+// WithMetrics sets the given metrics collectors for the checker.
+func WithMetrics(metrics *Metrics) Option {
+	return func(config *Config) {
+		config.Metrics = metrics
+	}
+}
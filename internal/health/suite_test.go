@@ -0,0 +1,37 @@
+package health_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/health"
+)
+
+// As Ginkgo does not yet support testing/synctest, we need to capture t during test suite initialization and make it
+// available to our Ginkgo tests. Keep an eye on https://github.com/onsi/ginkgo/issues/1601 and remove this hack
+// when Ginkgo provides support for it.
+var testingT *testing.T
+
+func TestSuite(t *testing.T) {
+	testingT = t
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Health Suite")
+}
+
+// TestTarget provides a target implementation for testing the checker without a membership list.
+type TestTarget struct {
+	Reports []bool
+
+	// Err is returned by SetHealthy when not nil.
+	Err error
+}
+
+// TestTarget implements health.Target.
+var _ health.Target = (*TestTarget)(nil)
+
+func (t *TestTarget) SetHealthy(healthy bool) error {
+	t.Reports = append(t.Reports, healthy)
+	return t.Err
+}
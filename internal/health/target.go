@@ -0,0 +1,8 @@
+package health
+
+// Target is the interface which the membership list must implement to be informed about the outcome of the health
+// checks.
+type Target interface {
+	// SetHealthy reports if all health checks succeeded.
+	SetHealthy(healthy bool) error
+}
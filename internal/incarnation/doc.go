@@ -0,0 +1,4 @@
+// Package incarnation provides functionality for persisting the incarnation number of a member across restarts. This
+// allows a restarted member to resume with an incarnation number which is bigger than anything the cluster might still
+// remember about its previous life.
+package incarnation
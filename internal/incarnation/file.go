@@ -0,0 +1,67 @@
+package incarnation
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/backbone81/membership/internal/utility"
+)
+
+// File provides a store which persists the incarnation number in a file on disk. The file is always replaced
+// atomically by writing a temporary file first and renaming it over the existing file. That way a crash during a write
+// never leaves a corrupted file behind.
+//
+// File is not safe for concurrent use by multiple goroutines. Callers must serialize access to all methods. The
+// membership list only saves under its lock, and the incarnation number is resumed before the membership list is
+// created.
+type File struct {
+	path string
+}
+
+// File implements Store.
+var _ Store = (*File)(nil)
+
+// NewFile creates a new file store which persists the incarnation number to the given path. The directory of the path
+// must exist.
+func NewFile(path string) *File {
+	return &File{
+		path: path,
+	}
+}
+
+// Path returns the path of the file the incarnation number is persisted to.
+func (f *File) Path() string {
+	return f.path
+}
+
+// Load returns the incarnation number which was persisted last. Reports false when the file does not exist yet.
+func (f *File) Load() (uint16, bool, error) {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("reading incarnation number file: %w", err)
+	}
+
+	incarnationNumber, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 16)
+	if err != nil {
+		return 0, false, fmt.Errorf("parsing incarnation number file %q: %w", f.path, err)
+	}
+	return uint16(incarnationNumber), true, nil
+}
+
+// Save persists the given incarnation number. The content is synced to disk before the file is renamed over the
+// existing file, and the directory is synced afterward to make the rename durable.
+func (f *File) Save(incarnationNumber uint16) error {
+	data := strconv.AppendUint(nil, uint64(incarnationNumber), 10)
+	data = append(data, '\n')
+	if err := utility.WriteFileAtomic(f.path, data); err != nil {
+		return fmt.Errorf("saving incarnation number file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package incarnation_test
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/incarnation"
+)
+
+var _ = Describe("File", func() {
+	var path string
+
+	BeforeEach(func() {
+		path = filepath.Join(GinkgoT().TempDir(), "incarnation")
+	})
+
+	It("should report not found when the file does not exist", func() {
+		store := incarnation.NewFile(path)
+
+		incarnationNumber, found, err := store.Load()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeFalse())
+		Expect(incarnationNumber).To(Equal(uint16(0)))
+	})
+
+	It("should load what was saved", func() {
+		store := incarnation.NewFile(path)
+		Expect(store.Save(42)).To(Succeed())
+
+		incarnationNumber, found, err := incarnation.NewFile(path).Load()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeTrue())
+		Expect(incarnationNumber).To(Equal(uint16(42)))
+	})
+
+	It("should overwrite what was saved before", func() {
+		store := incarnation.NewFile(path)
+		Expect(store.Save(1)).To(Succeed())
+		Expect(store.Save(math.MaxUint16)).To(Succeed())
+
+		incarnationNumber, found, err := store.Load()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeTrue())
+		Expect(incarnationNumber).To(Equal(uint16(math.MaxUint16)))
+	})
+
+	It("should not leave temporary files behind", func() {
+		store := incarnation.NewFile(path)
+		Expect(store.Save(1)).To(Succeed())
+		Expect(store.Save(2)).To(Succeed())
+
+		entries, err := os.ReadDir(filepath.Dir(path))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(entries).To(HaveLen(1))
+		Expect(entries[0].Name()).To(Equal(filepath.Base(path)))
+	})
+
+	It("should fail to load a corrupted file", func() {
+		Expect(os.WriteFile(path, []byte("not a number"), 0o600)).To(Succeed())
+
+		Expect(incarnation.NewFile(path).Load()).Error().To(HaveOccurred())
+	})
+
+	It("should fail to load an out of range incarnation number", func() {
+		Expect(os.WriteFile(path, []byte("65536\n"), 0o600)).To(Succeed())
+
+		Expect(incarnation.NewFile(path).Load()).Error().To(HaveOccurred())
+	})
+
+	It("should fail to save into a missing directory", func() {
+		store := incarnation.NewFile(filepath.Join(path, "missing", "incarnation"))
+		Expect(store.Save(1)).ToNot(Succeed())
+	})
+})
@@ -0,0 +1,34 @@
+package incarnation
+
+// Memory provides a store which keeps the incarnation number in memory only. This is useful for tests, when we need to
+// check which incarnation number was persisted.
+type Memory struct {
+	// IncarnationNumber is the incarnation number which was saved last.
+	IncarnationNumber uint16
+
+	// Found reports if an incarnation number was saved.
+	Found bool
+
+	// SaveCount is the number of times Save was called.
+	SaveCount int
+
+	// SaveErr is returned by Save when not nil. The incarnation number is not saved in that case.
+	SaveErr error
+}
+
+// Memory implements Store.
+var _ Store = (*Memory)(nil)
+
+func (m *Memory) Load() (uint16, bool, error) {
+	return m.IncarnationNumber, m.Found, nil
+}
+
+func (m *Memory) Save(incarnationNumber uint16) error {
+	m.SaveCount++
+	if m.SaveErr != nil {
+		return m.SaveErr
+	}
+	m.IncarnationNumber = incarnationNumber
+	m.Found = true
+	return nil
+}
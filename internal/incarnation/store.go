@@ -0,0 +1,12 @@
+package incarnation
+
+// Store is the interface a store needs to implement for persisting the incarnation number of a member.
+type Store interface {
+	// Load returns the incarnation number which was persisted last. Reports false when no incarnation number was
+	// persisted yet.
+	Load() (uint16, bool, error)
+
+	// Save persists the given incarnation number durably. When Save returns without error, the incarnation number must
+	// survive a crash of the process or the machine.
+	Save(incarnationNumber uint16) error
+}
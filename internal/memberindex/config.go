@@ -0,0 +1,13 @@
+package memberindex
+
+// Config is the configuration for the member index.
+type Config struct {
+	// PreAllocationCount is the number of elements the member index should pre-allocate to reduce the number of grow
+	// events during runtime.
+	PreAllocationCount int
+}
+
+// DefaultConfig provides a default configuration for the member index with sane defaults for most situations.
+var DefaultConfig = Config{
+	PreAllocationCount: 64,
+}
@@ -0,0 +1,3 @@
+// Package memberindex provides an index to manage the alive and suspect members in an efficient way, even for clusters
+// with millions of members.
+package memberindex
@@ -0,0 +1,201 @@
+package memberindex
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/backbone81/membership/internal/encoding"
+)
+
+// Index is responsible for managing the alive and suspect members. It provides lookups by address and the order in
+// which members are probed with direct pings. Adding and removing members are O(1) operations.
+//
+// This implementation works like this:
+//
+// Slots:          [C A E | B D]
+// Next:                    ^
+// SlotByAddress:  {A: 1, B: 3, C: 0, D: 4, E: 2}
+//
+// Members are stored in a dense slot array without any specific order. A map from address to slot allows for O(1)
+// lookups. The slot array is the probe order at the same time: Next points to the next member to probe. All members
+// before Next were already probed in the current round, all members starting at Next still need to be probed. When
+// Next reaches the end of the slot array, the slot array is shuffled and the next round starts at the beginning.
+//
+// This gives us an upper bound for every member to be probed: A member is probed at the latest after two rounds
+// through all members. Adding and removing members must not break that guarantee. A member which still needs to be
+// probed must never be moved to the part which was already probed. Therefore:
+//
+// - A new member is placed at a random slot. When the slot is part of the already probed members, the member at Next
+// is moved to the end, the probed member from the random slot is moved to Next, and Next is advanced by one. Otherwise,
+// the member at the random slot is moved to the end.
+//
+// - When a member is removed from the already probed part, it is swapped with the last probed member and Next is moved
+// back by one. The removed member is then swapped with the last member in the slot array and dropped.
+//
+// Shuffling the slot array is O(n), but happens only once every n probes.
+//
+// Index is not safe for concurrent use by multiple goroutines. Callers must serialize access to all methods.
+type Index struct {
+	// config holds the current configuration of the index.
+	config Config
+
+	// slots holds the members in probe order.
+	slots []encoding.Member
+
+	// slotByAddress provides the index into slots for a given address.
+	slotByAddress map[encoding.Address]int
+
+	// next is the index into slots for the next member to probe.
+	next int
+}
+
+// NewIndex creates a new member index.
+func NewIndex(options ...Option) *Index {
+	config := DefaultConfig
+	for _, option := range options {
+		option(&config)
+	}
+	return &Index{
+		config:        config,
+		slots:         make([]encoding.Member, 0, config.PreAllocationCount),
+		slotByAddress: make(map[encoding.Address]int, config.PreAllocationCount),
+	}
+}
+
+// Len returns the number of members in the index.
+func (i *Index) Len() int {
+	return len(i.slots)
+}
+
+// Get returns the member with the given address. The returned pointer allows modifying the member in place, but must
+// not be used after the next call to Add, Remove, Next or Clear.
+func (i *Index) Get(address encoding.Address) (*encoding.Member, bool) {
+	slot, found := i.slotByAddress[address]
+	if !found {
+		return nil, false
+	}
+	return &i.slots[slot], true
+}
+
+// Contains reports if a member with the given address is part of the index.
+func (i *Index) Contains(address encoding.Address) bool {
+	_, found := i.slotByAddress[address]
+	return found
+}
+
+// Add adds the given member to the index. An existing member with the same address is replaced. Reports true if the
+// member was added and false if it was replaced.
+func (i *Index) Add(member encoding.Member) bool {
+	if slot, found := i.slotByAddress[member.Address]; found {
+		i.slots[slot] = member
+		return false
+	}
+
+	i.slots = append(i.slots, member)
+	last := len(i.slots) - 1
+	i.slotByAddress[member.Address] = last
+
+	slot := rand.Intn(len(i.slots)) //nolint:gosec // we do not need crypto/rand here
+	if slot < i.next {
+		// The new member lands in the part which was already probed. We must not move the member at next into that
+		// part, so we swap the new member in at next first and only then move it to its random slot.
+		i.swap(i.next, last)
+		i.swap(slot, i.next)
+		i.next++
+		return true
+	}
+	i.swap(slot, last)
+	return true
+}
+
+// Remove removes the member with the given address from the index. Reports false if no such member exists.
+func (i *Index) Remove(address encoding.Address) bool {
+	slot, found := i.slotByAddress[address]
+	if !found {
+		return false
+	}
+
+	if slot < i.next {
+		// The member is part of the already probed members. We shrink that part by one, to be able to replace the
+		// member with one which still needs to be probed.
+		i.next--
+		i.swap(slot, i.next)
+		slot = i.next
+	}
+	last := len(i.slots) - 1
+	i.swap(slot, last)
+	i.slots = i.slots[:last]
+	delete(i.slotByAddress, address)
+	return true
+}
+
+// Next returns the next member to probe. It returns nil when the index is empty. The returned pointer must not be
+// used after the next call to Add, Remove, Next or Clear.
+func (i *Index) Next() *encoding.Member {
+	if len(i.slots) == 0 {
+		return nil
+	}
+	if i.next >= len(i.slots) {
+		// When we moved beyond the end of the slots, re-shuffle and reset back to the start.
+		rand.Shuffle(len(i.slots), i.swap)
+		i.next = 0
+	}
+
+	member := &i.slots[i.next]
+	i.next++
+	return member
+}
+
+// Members returns all members in no specific order. The returned slice shares memory with the index. It must not be
+// modified and must only be used until the index is modified the next time.
+func (i *Index) Members() []encoding.Member {
+	return i.slots
+}
+
+// ForEachUpcoming executes the given function for all members which still need to be probed in the current round,
+// in the order they will be probed. Return false to abort the iteration.
+func (i *Index) ForEachUpcoming(fn func(encoding.Member) bool) {
+	for _, member := range i.slots[i.next:] {
+		if !fn(member) {
+			return
+		}
+	}
+}
+
+// Clear removes all members from the index.
+func (i *Index) Clear() {
+	i.slots = i.slots[:0]
+	clear(i.slotByAddress)
+	i.next = 0
+}
+
+// swap swaps the members at the given slots and keeps the slot lookup up to date.
+func (i *Index) swap(a int, b int) {
+	if a == b {
+		return
+	}
+	i.slots[a], i.slots[b] = i.slots[b], i.slots[a]
+	i.slotByAddress[i.slots[a].Address] = a
+	i.slotByAddress[i.slots[b].Address] = b
+}
+
+// ValidateInternalState reports if the internal state is valid.
+// This function is expensive and should not be called outside of tests.
+func (i *Index) ValidateInternalState() error {
+	if i.next < 0 || len(i.slots) < i.next {
+		return fmt.Errorf("next %d is out of bounds for %d slots", i.next, len(i.slots))
+	}
+	if len(i.slots) != len(i.slotByAddress) {
+		return fmt.Errorf("%d slots do not match %d entries in slot map", len(i.slots), len(i.slotByAddress))
+	}
+	for slot, member := range i.slots {
+		slot2, found := i.slotByAddress[member.Address]
+		if !found {
+			return fmt.Errorf("member %d could not be found in slot map", slot)
+		}
+		if slot2 != slot {
+			return fmt.Errorf("member %d has wrong slot in slot map", slot)
+		}
+	}
+	return nil
+}
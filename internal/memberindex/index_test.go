@@ -0,0 +1,205 @@
+package memberindex_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/encoding"
+	"github.com/backbone81/membership/internal/memberindex"
+)
+
+var _ = Describe("Index", func() {
+	It("should add members", func() {
+		index := memberindex.NewIndex()
+		Expect(index.Add(encoding.Member{Address: TestAddress})).To(BeTrue())
+		Expect(index.Add(encoding.Member{Address: TestAddress2})).To(BeTrue())
+		Expect(index.Len()).To(Equal(2))
+		Expect(index.Contains(TestAddress)).To(BeTrue())
+		Expect(index.Contains(TestAddress3)).To(BeFalse())
+		Expect(index.Members()).To(ConsistOf(
+			encoding.Member{Address: TestAddress},
+			encoding.Member{Address: TestAddress2},
+		))
+	})
+
+	It("should replace existing members", func() {
+		index := memberindex.NewIndex()
+		Expect(index.Add(encoding.Member{Address: TestAddress})).To(BeTrue())
+		Expect(index.Add(encoding.Member{Address: TestAddress, IncarnationNumber: 3})).To(BeFalse())
+		Expect(index.Len()).To(Equal(1))
+		member, found := index.Get(TestAddress)
+		Expect(found).To(BeTrue())
+		Expect(member.IncarnationNumber).To(Equal(uint16(3)))
+	})
+
+	It("should modify members in place", func() {
+		index := memberindex.NewIndex()
+		index.Add(encoding.Member{Address: TestAddress})
+		member, _ := index.Get(TestAddress)
+		member.State = encoding.MemberStateSuspect
+		member, _ = index.Get(TestAddress)
+		Expect(member.State).To(Equal(encoding.MemberStateSuspect))
+	})
+
+	It("should not get unknown members", func() {
+		index := memberindex.NewIndex()
+		member, found := index.Get(TestAddress)
+		Expect(found).To(BeFalse())
+		Expect(member).To(BeNil())
+	})
+
+	It("should remove members", func() {
+		index := memberindex.NewIndex()
+		index.Add(encoding.Member{Address: TestAddress})
+		index.Add(encoding.Member{Address: TestAddress2})
+		Expect(index.Remove(TestAddress)).To(BeTrue())
+		Expect(index.Remove(TestAddress)).To(BeFalse())
+		Expect(index.Len()).To(Equal(1))
+		Expect(index.Contains(TestAddress)).To(BeFalse())
+		Expect(index.Contains(TestAddress2)).To(BeTrue())
+	})
+
+	It("should return no next member when empty", func() {
+		index := memberindex.NewIndex()
+		Expect(index.Next()).To(BeNil())
+	})
+
+	It("should probe every member once per round", func() {
+		index := memberindex.NewIndex()
+		for i := range 100 {
+			index.Add(encoding.Member{Address: NewAddressForIndex(i)})
+		}
+		for range 3 {
+			probed := make(map[encoding.Address]int)
+			for range index.Len() {
+				probed[index.Next().Address]++
+			}
+			Expect(probed).To(HaveLen(100))
+		}
+	})
+
+	It("should list the upcoming members", func() {
+		index := memberindex.NewIndex()
+		index.Add(encoding.Member{Address: TestAddress})
+		index.Add(encoding.Member{Address: TestAddress2})
+		index.Add(encoding.Member{Address: TestAddress3})
+		first := index.Next().Address
+		var upcoming []encoding.Address
+		index.ForEachUpcoming(func(member encoding.Member) bool {
+			upcoming = append(upcoming, member.Address)
+			return true
+		})
+		Expect(upcoming).To(HaveLen(2))
+		Expect(upcoming).ToNot(ContainElement(first))
+	})
+
+	It("should clear all members", func() {
+		index := memberindex.NewIndex()
+		index.Add(encoding.Member{Address: TestAddress})
+		index.Next()
+		index.Clear()
+		Expect(index.Len()).To(Equal(0))
+		Expect(index.Contains(TestAddress)).To(BeFalse())
+		Expect(index.ValidateInternalState()).To(Succeed())
+	})
+
+	It("should probe every member within two rounds under random operations", func() {
+		// This test is a kind of monte carlo test. Members are added and removed at random while probing. Every member
+		// must be probed at the latest after two rounds through all members.
+		index := memberindex.NewIndex()
+		var addresses []encoding.Address
+		for i := range 64 {
+			addresses = append(addresses, NewAddressForIndex(i))
+		}
+
+		// probesSince counts the probes since the member was last probed or added.
+		probesSince := make(map[encoding.Address]int)
+		for range 100_000 {
+			switch selection := rand.Intn(100); { //nolint:gosec // we do not need crypto/rand here
+			case selection < 10: // 10% of the time we add a member
+				address := addresses[rand.Intn(len(addresses))] //nolint:gosec // we do not need crypto/rand here
+				if index.Add(encoding.Member{Address: address}) {
+					probesSince[address] = 0
+				}
+			case selection < 20: // 10% of the time we remove a member
+				address := addresses[rand.Intn(len(addresses))] //nolint:gosec // we do not need crypto/rand here
+				index.Remove(address)
+				delete(probesSince, address)
+			case selection < 100: // 80% of the time we probe a member
+				member := index.Next()
+				if member == nil {
+					continue
+				}
+				for address := range probesSince {
+					probesSince[address]++
+				}
+				probesSince[member.Address] = 0
+			}
+			Expect(index.ValidateInternalState()).To(Succeed())
+			for address, count := range probesSince {
+				Expect(count).To(BeNumerically("<", 2*len(addresses)), "member %s was not probed in time", address)
+			}
+		}
+	})
+
+	It("should not allocate memory when probing and looking up members", func() {
+		index := memberindex.NewIndex()
+		for i := range 1024 {
+			index.Add(encoding.Member{Address: NewAddressForIndex(i)})
+		}
+		Expect(testing.AllocsPerRun(10_000, func() {
+			member := index.Next()
+			index.Get(member.Address)
+		})).To(BeZero())
+	})
+})
+
+// BenchmarkIndex_AddRemove is measuring the time for adding and removing a member depending on the number of members
+// already there.
+func BenchmarkIndex_AddRemove(b *testing.B) {
+	for _, memberCount := range []int{1_000, 100_000, 1_000_000} {
+		index := createIndexWithMembers(memberCount)
+		b.Run(fmt.Sprintf("%d members", memberCount), func(b *testing.B) {
+			address := NewAddressForIndex(memberCount)
+			for range b.N {
+				index.Add(encoding.Member{Address: address})
+				index.Remove(address)
+			}
+		})
+	}
+}
+
+// BenchmarkIndex_Next is measuring the time for picking the next member to probe depending on the number of members.
+func BenchmarkIndex_Next(b *testing.B) {
+	for _, memberCount := range []int{1_000, 100_000, 1_000_000} {
+		index := createIndexWithMembers(memberCount)
+		b.Run(fmt.Sprintf("%d members", memberCount), func(b *testing.B) {
+			for range b.N {
+				index.Next()
+			}
+		})
+	}
+}
+
+// BenchmarkIndex_Join is measuring the time for a mass join of members into an empty index.
+func BenchmarkIndex_Join(b *testing.B) {
+	for _, memberCount := range []int{1_000, 100_000, 1_000_000} {
+		b.Run(fmt.Sprintf("%d members", memberCount), func(b *testing.B) {
+			for range b.N {
+				createIndexWithMembers(memberCount)
+			}
+		})
+	}
+}
+
+func createIndexWithMembers(memberCount int) *memberindex.Index {
+	index := memberindex.NewIndex()
+	for i := range memberCount {
+		index.Add(encoding.Member{Address: NewAddressForIndex(i)})
+	}
+	return index
+}
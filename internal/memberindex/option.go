@@ -0,0 +1,11 @@
+package memberindex
+
+// Option is the function signature for all index options to implement.
+type Option func(config *Config)
+
+func WithPreAllocationCount(count int) Option {
+	count = max(1, count)
+	return func(config *Config) {
+		config.PreAllocationCount = count
+	}
+}
@@ -0,0 +1,28 @@
+package memberindex_test
+
+import (
+	"net"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/encoding"
+)
+
+var (
+	TestAddress  = encoding.NewAddress(net.IPv4(1, 2, 3, 4), 1024)
+	TestAddress2 = encoding.NewAddress(net.IPv4(11, 12, 13, 14), 1024)
+	TestAddress3 = encoding.NewAddress(net.IPv4(21, 22, 23, 24), 1024)
+)
+
+func TestSuite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "MemberIndex Suite")
+}
+
+// NewAddressForIndex returns a unique address for every index, which allows for creating millions of members.
+func NewAddressForIndex(index int) encoding.Address {
+	ipBytes := encoding.Endian.AppendUint32(nil, uint32(index+1)) //nolint:gosec // overflow is not an issue
+	return encoding.NewAddress(net.IPv4(ipBytes[0], ipBytes[1], ipBytes[2], ipBytes[3]), 512)
+}
@@ -0,0 +1,121 @@
+package membership_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/encoding"
+	"github.com/backbone81/membership/internal/membership"
+	"github.com/backbone81/membership/internal/transport"
+)
+
+var _ = Describe("AckPayload", func() {
+	// ackPayload returns the payload of the last ack of the given member and fails when the member is unknown.
+	ackPayload := func(list *membership.List, address encoding.Address) []byte {
+		payload, found := list.AckPayload(address)
+		Expect(found).To(BeTrue())
+		return payload
+	}
+
+	It("should attach the payload to direct acks", func() {
+		var store transport.Store
+		list := newTestList(
+			membership.WithUDPClient(&store),
+			membership.WithAckPayloadCallback(func() []byte {
+				return []byte("load=42")
+			}),
+		)
+		Expect(DispatchDatagram(list, encoding.MessageDirectPing{
+			Source:         TestAddress2,
+			SequenceNumber: 7,
+		}.ToMessage())).To(Succeed())
+
+		Expect(store.Buffers).To(HaveLen(1))
+		var directAck encoding.MessageDirectAck
+		Expect(directAck.FromBuffer(store.Buffers[0])).Error().ToNot(HaveOccurred())
+		Expect(directAck.SequenceNumber).To(BeEquivalentTo(7))
+		Expect(directAck.Payload).To(Equal([]byte("load=42")))
+	})
+
+	It("should acknowledge without payload when the payload is too long", func() {
+		var store transport.Store
+		list := newTestList(
+			membership.WithUDPClient(&store),
+			membership.WithMaxAckPayloadLength(4),
+			membership.WithAckPayloadCallback(func() []byte {
+				return []byte("load=42")
+			}),
+		)
+		Expect(DispatchDatagram(list, encoding.MessageDirectPing{
+			Source:         TestAddress2,
+			SequenceNumber: 7,
+		}.ToMessage())).ToNot(Succeed())
+
+		Expect(store.Buffers).To(HaveLen(1))
+		var directAck encoding.MessageDirectAck
+		Expect(directAck.FromBuffer(store.Buffers[0])).Error().ToNot(HaveOccurred())
+		Expect(directAck.Payload).To(BeEmpty())
+	})
+
+	It("should record the payload of direct acks", func() {
+		list := newTestList(membership.WithBootstrapMembers([]encoding.Address{TestAddress2}))
+		payload, found := list.AckPayload(TestAddress2)
+		Expect(found).To(BeTrue())
+		Expect(payload).To(BeEmpty())
+
+		Expect(DispatchDatagram(list, encoding.MessageDirectAck{
+			Source:  TestAddress2,
+			Payload: []byte("load=42"),
+		}.ToMessage())).To(Succeed())
+		Expect(ackPayload(list, TestAddress2)).To(Equal([]byte("load=42")))
+
+		By("replacing the payload with the latest one")
+		Expect(DispatchDatagram(list, encoding.MessageDirectAck{
+			Source:  TestAddress2,
+			Payload: []byte("load=7"),
+		}.ToMessage())).To(Succeed())
+		Expect(ackPayload(list, TestAddress2)).To(Equal([]byte("load=7")))
+	})
+
+	It("should record the payload of indirect acks", func() {
+		list := newTestList(membership.WithBootstrapMembers([]encoding.Address{TestAddress2}))
+		Expect(DispatchDatagram(list, encoding.MessageIndirectAck{
+			Source:  TestAddress2,
+			Payload: []byte("ready"),
+		}.ToMessage())).To(Succeed())
+		Expect(ackPayload(list, TestAddress2)).To(Equal([]byte("ready")))
+	})
+
+	It("should not report a payload for unknown members", func() {
+		list := newTestList()
+		Expect(list.AckPayload(TestAddress2)).Error().To(BeFalse())
+	})
+
+	It("should relay the payload with the indirect ack", func() {
+		var store transport.Store
+		list := newTestList(membership.WithUDPClient(&store))
+		Expect(DispatchDatagram(list, encoding.MessageIndirectPing{
+			Source:         TestAddress2,
+			Destination:    TestAddress3,
+			SequenceNumber: 42,
+		}.ToMessage())).To(Succeed())
+		Expect(store.Buffers).To(HaveLen(1))
+		var directPing encoding.MessageDirectPing
+		Expect(directPing.FromBuffer(store.Buffers[0])).Error().ToNot(HaveOccurred())
+		store.Clear()
+
+		Expect(DispatchDatagram(list, encoding.MessageDirectAck{
+			Source:         TestAddress3,
+			SequenceNumber: directPing.SequenceNumber,
+			Payload:        []byte("load=42"),
+		}.ToMessage())).To(Succeed())
+		Expect(store.Addresses).To(Equal([]encoding.Address{TestAddress2}))
+		var indirectAck encoding.MessageIndirectAck
+		Expect(indirectAck.FromBuffer(store.Buffers[0])).Error().ToNot(HaveOccurred())
+		Expect(indirectAck).To(Equal(encoding.MessageIndirectAck{
+			Source:         TestAddress3,
+			SequenceNumber: 42,
+			Payload:        []byte("load=42"),
+		}))
+	})
+})
@@ -0,0 +1,41 @@
+package membership
+
+import (
+	"github.com/backbone81/membership/internal/admission"
+	"github.com/backbone81/membership/internal/encoding"
+)
+
+// admitMember reports if the unknown member with the given address may be added. The address is checked against the
+// configured networks first and is then handed to the admission callback for a veto. Rejected members are counted by
+// reason. The member which gossiped them is only logged, as its address would make for unbounded metric labels.
+func (l *List) admitMember(address encoding.Address) bool {
+	verdict := l.admissionFilter.Check(address)
+	reason := verdict.String()
+	var err error
+	if verdict == admission.VerdictAdmitted && l.config.AdmissionCallback != nil {
+		err = l.config.AdmissionCallback(address, l.gossipSource)
+		reason = "vetoed"
+	}
+	if verdict == admission.VerdictAdmitted && err == nil {
+		return true
+	}
+
+	l.config.Metrics.MembersRejectedTotal.WithLabelValues(reason).Inc()
+	logger := l.logger.V(1)
+	if logger.Enabled() {
+		// We only spend the memory allocation for interface boxing of the key value pairs when the log level would
+		// actually produce this log entry.
+		source := "unknown"
+		if !l.gossipSource.IsZero() {
+			source = l.gossipSource.String()
+		}
+		logger.Info(
+			"Rejected member",
+			"address", address,
+			"source", source,
+			"reason", reason,
+			"error", err,
+		)
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package membership_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/backbone81/membership/internal/admission"
+	"github.com/backbone81/membership/internal/encoding"
+	"github.com/backbone81/membership/internal/membership"
+)
+
+var _ = Describe("Admission", func() {
+	mustParseRules := func(texts ...string) []admission.Rule {
+		rules, err := admission.ParseRules(texts)
+		Expect(err).ToNot(HaveOccurred())
+		return rules
+	}
+
+	It("should only add members from allowed networks", func() {
+		list := newTestList(
+			membership.WithAllowedNetworks(mustParseRules("11.0.0.0/8")),
+		)
+		Expect(DispatchDatagram(list, encoding.MessageAlive{
+			Destination: TestAddress2,
+		}.ToMessage())).To(Succeed())
+		Expect(DispatchDatagram(list, encoding.MessageAlive{
+			Destination: TestAddress3,
+		}.ToMessage())).To(Succeed())
+		Expect(Collect(list)).To(Equal([]encoding.Address{TestAddress2}))
+	})
+
+	It("should not add members from denied networks", func() {
+		list := newTestList(
+			membership.WithDeniedNetworks(mustParseRules("21.0.0.0/8")),
+		)
+		Expect(DispatchDatagram(list, encoding.MessageSuspect{
+			Source:      TestAddress2,
+			Destination: TestAddress3,
+		}.ToMessage())).To(Succeed())
+		Expect(DispatchDatagram(list, encoding.MessageFaulty{
+			Source:      TestAddress2,
+			Destination: TestAddress3,
+		}.ToMessage())).To(Succeed())
+		Expect(list.Len()).To(Equal(0))
+		Expect(membership.DebugList(list).GetFaultyMembers()).To(BeEmpty())
+	})
+
+	It("should count rejected members by reason", func() {
+		metrics := membership.NewMetrics(nil)
+		list := newTestList(
+			membership.WithDeniedNetworks(mustParseRules("21.0.0.0/8")),
+			membership.WithMetrics(metrics),
+		)
+		Expect(DispatchDatagram(list, encoding.MessageAlive{
+			Destination: TestAddress3,
+		}.ToMessage())).To(Succeed())
+		Expect(testutil.ToFloat64(metrics.MembersRejectedTotal.WithLabelValues("denied"))).To(Equal(1.0))
+	})
+
+	It("should reject members from list responses", func() {
+		list := newTestList(
+			membership.WithDeniedNetworks(mustParseRules("21.0.0.0/8")),
+		)
+		Expect(DispatchDatagram(list, encoding.MessageListResponse{
+			Source: TestAddress2,
+			Members: []encoding.Member{
+				{Address: TestAddress2, State: encoding.MemberStateAlive},
+				{Address: TestAddress3, State: encoding.MemberStateAlive},
+			},
+		}.ToMessage())).To(Succeed())
+		Expect(Collect(list)).To(Equal([]encoding.Address{TestAddress2}))
+	})
+
+	It("should ask the admission callback with the member which gossiped", func() {
+		var sources []encoding.Address
+		list := newTestList(
+			membership.WithAdmissionCallback(func(address encoding.Address, source encoding.Address) error {
+				sources = append(sources, source)
+				if address.Equal(TestAddress3) {
+					return errors.New("vetoed")
+				}
+				return nil
+			}),
+		)
+		buffer, _, err := encoding.MessageDirectPing{
+			Source: TestAddress2,
+		}.AppendToBuffer(nil)
+		Expect(err).ToNot(HaveOccurred())
+		buffer, _, err = encoding.MessageAlive{
+			Destination: TestAddress3,
+		}.AppendToBuffer(buffer)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(list.DispatchDatagram(buffer)).To(Succeed())
+		Expect(list.Len()).To(Equal(0))
+		Expect(sources).To(Equal([]encoding.Address{TestAddress2}))
+
+		Expect(DispatchDatagram(list, encoding.MessageAlive{
+			Destination: TestAddress2,
+		}.ToMessage())).To(Succeed())
+		Expect(Collect(list)).To(Equal([]encoding.Address{TestAddress2}))
+	})
+
+	It("should keep configured bootstrap members", func() {
+		list := newTestList(
+			membership.WithBootstrapMembers([]encoding.Address{TestAddress3}),
+			membership.WithDeniedNetworks(mustParseRules("21.0.0.0/8")),
+		)
+		Expect(Collect(list)).To(Equal([]encoding.Address{TestAddress3}))
+	})
+})
@@ -0,0 +1,208 @@
+package membership
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/backbone81/membership/internal/encoding"
+)
+
+// Broadcasts follow Plumtree (https://asc.di.fct.unl.pt/~jleitao/pdf/srds07-leitao.pdf). Every member pushes a
+// broadcast eagerly over TCP to its eager push members and announces it lazily to all other members with an IHave
+// piggybacked as gossip. A member receiving a broadcast twice prunes the redundant link, which turns the eager push
+// links into a spanning tree over time. A member which only learns about a broadcast through an announcement grafts the
+// announcing member, which delivers the broadcast and adds the link back to the tree. Members leaving the member list
+// are replaced as eager push members right away, which repairs the tree before the next broadcast.
+
+// Broadcast disseminates the payload reliably to all members of the cluster. The payload is transferred over TCP and
+// may exceed the maximum datagram length. The caller is responsible for providing a broadcast id which is unique for
+// broadcasts of this member. The broadcast is not delivered to this member itself.
+func (l *List) Broadcast(broadcastID uint32, payload []byte) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	// The cache takes ownership of the payload, so we need our own copy.
+	payload = bytes.Clone(payload)
+	if !l.broadcastCache.Add(l.self, broadcastID, payload) {
+		return fmt.Errorf("broadcast id %d was already used", broadcastID)
+	}
+	return l.forwardBroadcast(encoding.MessageBroadcast{
+		Origin:      l.self,
+		BroadcastID: broadcastID,
+		Payload:     payload,
+	}, encoding.ZeroAddress)
+}
+
+// EagerPushMembers returns the addresses of the members broadcasts are pushed to eagerly.
+func (l *List) EagerPushMembers() []encoding.Address {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	result := make([]encoding.Address, 0, len(l.eagerPushMembers))
+	for address := range l.eagerPushMembers {
+		result = append(result, address)
+	}
+	return result
+}
+
+// forwardBroadcast pushes the broadcast to all eager push members except the given one and queues the announcement
+// for all other members.
+func (l *List) forwardBroadcast(message encoding.MessageBroadcast, exclude encoding.Address) error {
+	message.Source = l.self
+	var err error
+	l.broadcastBuffer, _, err = message.AppendToBuffer(l.broadcastBuffer[:0])
+	if err != nil {
+		return err
+	}
+
+	var joinedErr error
+	for address := range l.eagerPushMembers {
+		if address.Equal(exclude) {
+			continue
+		}
+		if err := l.config.TCPClient.Send(address, l.broadcastBuffer); err != nil {
+			joinedErr = errors.Join(joinedErr, err)
+		}
+	}
+	return errors.Join(joinedErr, l.announcementQueue.Add(encoding.MessageIHave{
+		Source:      l.self,
+		Origin:      message.Origin,
+		BroadcastID: message.BroadcastID,
+	}))
+}
+
+// eagerPushMemberLimit returns the number of eager push members we aim for. Grafts can add further eager push members
+// beyond that limit.
+func (l *List) eagerPushMemberLimit() int {
+	if l.config.PartialView {
+		// The active view is small enough to push along every link.
+		return l.config.ActiveViewSize
+	}
+	return l.config.EagerPushMemberCount
+}
+
+// addEagerPushMember makes the newly added member with the given address an eager push member, if we are below the
+// limit of eager push members.
+func (l *List) addEagerPushMember(address encoding.Address) {
+	if len(l.eagerPushMembers) >= l.eagerPushMemberLimit() {
+		return
+	}
+	l.eagerPushMembers[address] = struct{}{}
+}
+
+// setEagerPushMember makes the member with the given address an eager push member because it is part of the broadcast
+// tree. Addresses which are not in the member list are ignored, as they would never be removed again.
+func (l *List) setEagerPushMember(address encoding.Address) {
+	if !l.members.Contains(address) {
+		return
+	}
+	l.eagerPushMembers[address] = struct{}{}
+}
+
+// replaceEagerPushMember promotes a random lazy push member to eager push member, when the removed member with the
+// given address was an eager push member.
+func (l *List) replaceEagerPushMember(address encoding.Address) {
+	if _, found := l.eagerPushMembers[address]; !found {
+		return
+	}
+	delete(l.eagerPushMembers, address)
+
+	// Picking one member more than there are eager push members guarantees that at least one of them is a lazy push
+	// member, if there is such a member at all.
+	replaced := false
+	l.randomMemberPicker.Pick(len(l.eagerPushMembers)+1, l.members.Members(), func(member encoding.Member) {
+		if replaced {
+			return
+		}
+		if _, found := l.eagerPushMembers[member.Address]; found {
+			return
+		}
+		l.eagerPushMembers[member.Address] = struct{}{}
+		replaced = true
+	})
+}
+
+// graftMissingBroadcasts asks the announcing members for the broadcasts which did not arrive in time. The grafted
+// members become eager push members to repair the broadcast tree.
+func (l *List) graftMissingBroadcasts() error {
+	l.graftScratchSpace = l.missingBroadcasts.EndOfProtocolPeriod(l.config.BroadcastGraftTimeout, l.graftScratchSpace[:0])
+
+	var joinedErr error
+	for _, graft := range l.graftScratchSpace {
+		l.setEagerPushMember(graft.Destination)
+		if err := l.sendWithGossip(graft.Destination, encoding.MessageGraft{
+			Source:      l.self,
+			Origin:      graft.Origin,
+			BroadcastID: graft.BroadcastID,
+		}.ToMessage()); err != nil {
+			joinedErr = errors.Join(joinedErr, err)
+		}
+	}
+	return joinedErr
+}
+
+func (l *List) handleBroadcast(message encoding.MessageBroadcast) error {
+	if message.Origin.Equal(l.self) || l.broadcastCache.Contains(message.Origin, message.BroadcastID) {
+		// We already received this broadcast. The link to the source is redundant in the broadcast tree.
+		delete(l.eagerPushMembers, message.Source)
+		return l.sendWithGossip(message.Source, encoding.MessagePrune{
+			Source: l.self,
+		}.ToMessage())
+	}
+
+	logger := l.logger.V(2)
+	if logger.Enabled() {
+		// We only spend the memory allocation for interface boxing of the key value pairs when the log level would
+		// actually produce this log entry.
+		logger.Info(
+			"Received broadcast",
+			"source", message.Source,
+			"origin", message.Origin,
+			"broadcast-id", message.BroadcastID,
+		)
+	}
+
+	// The payload aliases the network buffer. The cache takes ownership of the payload, so we need our own copy.
+	message.Payload = bytes.Clone(message.Payload)
+	l.broadcastCache.Add(message.Origin, message.BroadcastID, message.Payload)
+	l.missingBroadcasts.Remove(message.Origin, message.BroadcastID)
+	l.setEagerPushMember(message.Source)
+
+	if l.config.BroadcastCallback != nil {
+		l.config.BroadcastCallback(message.Origin, message.Payload)
+	}
+	return l.forwardBroadcast(message, message.Source)
+}
+
+func (l *List) handleIHave(ihave encoding.MessageIHave) {
+	if ihave.Origin.Equal(l.self) || l.broadcastCache.Contains(ihave.Origin, ihave.BroadcastID) {
+		return
+	}
+	l.missingBroadcasts.Announce(ihave.Source, ihave.Origin, ihave.BroadcastID)
+}
+
+func (l *List) handleGraft(graft encoding.MessageGraft) error {
+	l.setEagerPushMember(graft.Source)
+
+	payload, found := l.broadcastCache.Get(graft.Origin, graft.BroadcastID)
+	if !found {
+		// The broadcast is too old. The link is part of the broadcast tree for future broadcasts nevertheless.
+		return nil
+	}
+	var err error
+	l.broadcastBuffer, _, err = encoding.MessageBroadcast{
+		Source:      l.self,
+		Origin:      graft.Origin,
+		BroadcastID: graft.BroadcastID,
+		Payload:     payload,
+	}.AppendToBuffer(l.broadcastBuffer[:0])
+	if err != nil {
+		return err
+	}
+	return l.config.TCPClient.Send(graft.Source, l.broadcastBuffer)
+}
+
+func (l *List) handlePrune(prune encoding.MessagePrune) {
+	delete(l.eagerPushMembers, prune.Source)
+}
@@ -0,0 +1,194 @@
+package membership_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/encoding"
+	"github.com/backbone81/membership/internal/membership"
+	"github.com/backbone81/membership/internal/transport"
+)
+
+var _ = Describe("Broadcast", func() {
+	// newBroadcastTestList returns a list with the given members. The list sends its TCP messages to tcpStore and its
+	// UDP messages to udpStore.
+	newBroadcastTestList := func(tcpStore *transport.Store, udpStore *transport.Store, members []encoding.Address, options ...membership.Option) *membership.List {
+		list := newTestList(append([]membership.Option{
+			membership.WithTCPClient(tcpStore),
+			membership.WithUDPClient(udpStore),
+		}, options...)...)
+		listMembers := make([]encoding.Member, 0, len(members))
+		for _, address := range members {
+			listMembers = append(listMembers, encoding.Member{
+				Address: address,
+				State:   encoding.MemberStateAlive,
+			})
+		}
+		membership.DebugList(list).SetMembers(listMembers)
+		membership.DebugList(list).ClearGossip()
+		return list
+	}
+
+	// parseBroadcast returns the broadcast which was sent with the given buffer.
+	parseBroadcast := func(buffer []byte) encoding.MessageBroadcast {
+		var message encoding.MessageBroadcast
+		Expect(message.FromBuffer(buffer)).Error().ToNot(HaveOccurred())
+		return message
+	}
+
+	It("should push broadcasts to the eager push members and announce them as gossip", func() {
+		var tcpStore, udpStore transport.Store
+		list := newBroadcastTestList(&tcpStore, &udpStore, []encoding.Address{TestAddress2})
+		Expect(list.Broadcast(7, []byte("payload"))).To(Succeed())
+		Expect(tcpStore.Addresses).To(Equal([]encoding.Address{TestAddress2}))
+		Expect(parseBroadcast(tcpStore.Buffers[0])).To(Equal(encoding.MessageBroadcast{
+			Source:      TestAddress,
+			Origin:      TestAddress,
+			BroadcastID: 7,
+			Payload:     []byte("payload"),
+		}))
+
+		Expect(list.DirectPing()).To(Succeed())
+		Expect(udpStore.Buffers).To(HaveLen(1))
+		var directPing encoding.MessageDirectPing
+		n, err := directPing.FromBuffer(udpStore.Buffers[0])
+		Expect(err).ToNot(HaveOccurred())
+		var ihave encoding.MessageIHave
+		Expect(ihave.FromBuffer(udpStore.Buffers[0][n:])).Error().ToNot(HaveOccurred())
+		Expect(ihave).To(Equal(encoding.MessageIHave{
+			Source:      TestAddress,
+			Origin:      TestAddress,
+			BroadcastID: 7,
+		}))
+	})
+
+	It("should limit the number of eager push members", func() {
+		var tcpStore, udpStore transport.Store
+		list := newBroadcastTestList(&tcpStore, &udpStore, []encoding.Address{TestAddress2, TestAddress3}, membership.WithEagerPushMemberCount(1))
+		Expect(list.EagerPushMembers()).To(HaveLen(1))
+		Expect(list.Broadcast(1, nil)).To(Succeed())
+		Expect(tcpStore.Addresses).To(HaveLen(1))
+	})
+
+	It("should reject broadcast ids which were already used", func() {
+		var tcpStore, udpStore transport.Store
+		list := newBroadcastTestList(&tcpStore, &udpStore, nil)
+		Expect(list.Broadcast(1, nil)).To(Succeed())
+		Expect(list.Broadcast(1, nil)).ToNot(Succeed())
+	})
+
+	It("should deliver and forward a broadcast only once", func() {
+		var tcpStore, udpStore transport.Store
+		var received [][]byte
+		list := newBroadcastTestList(&tcpStore, &udpStore, []encoding.Address{TestAddress2, TestAddress3},
+			membership.WithBroadcastCallback(func(origin encoding.Address, payload []byte) {
+				Expect(origin).To(Equal(TestAddress2))
+				received = append(received, payload)
+			}),
+		)
+		Expect(DispatchDatagram(list, encoding.MessageBroadcast{
+			Source:      TestAddress2,
+			Origin:      TestAddress2,
+			BroadcastID: 3,
+			Payload:     []byte("payload"),
+		}.ToMessage())).To(Succeed())
+		Expect(received).To(Equal([][]byte{[]byte("payload")}))
+		Expect(tcpStore.Addresses).To(Equal([]encoding.Address{TestAddress3}))
+		Expect(parseBroadcast(tcpStore.Buffers[0]).Source).To(Equal(TestAddress))
+
+		By("pruning the link which delivered the duplicate")
+		Expect(DispatchDatagram(list, encoding.MessageBroadcast{
+			Source:      TestAddress3,
+			Origin:      TestAddress2,
+			BroadcastID: 3,
+			Payload:     []byte("payload"),
+		}.ToMessage())).To(Succeed())
+		Expect(received).To(HaveLen(1))
+		Expect(list.EagerPushMembers()).To(Equal([]encoding.Address{TestAddress2}))
+		Expect(udpStore.Addresses).To(Equal([]encoding.Address{TestAddress3}))
+		var prune encoding.MessagePrune
+		Expect(prune.FromBuffer(udpStore.Buffers[0])).Error().ToNot(HaveOccurred())
+		Expect(prune.Source).To(Equal(TestAddress))
+	})
+
+	It("should stop pushing eagerly to members which pruned the link", func() {
+		var tcpStore, udpStore transport.Store
+		list := newBroadcastTestList(&tcpStore, &udpStore, []encoding.Address{TestAddress2})
+		Expect(DispatchDatagram(list, encoding.MessagePrune{
+			Source: TestAddress2,
+		}.ToMessage())).To(Succeed())
+		Expect(list.EagerPushMembers()).To(BeEmpty())
+		Expect(list.Broadcast(1, nil)).To(Succeed())
+		Expect(tcpStore.Addresses).To(BeEmpty())
+	})
+
+	It("should graft members which announced a missing broadcast", func() {
+		var tcpStore, udpStore transport.Store
+		list := newBroadcastTestList(&tcpStore, &udpStore, []encoding.Address{TestAddress2, TestAddress3}, membership.WithEagerPushMemberCount(1))
+		lazyMember := TestAddress2
+		if list.EagerPushMembers()[0] == TestAddress2 {
+			lazyMember = TestAddress3
+		}
+		Expect(DispatchDatagram(list, encoding.MessageIHave{
+			Source:      lazyMember,
+			Origin:      TestAddress2,
+			BroadcastID: 5,
+		}.ToMessage())).To(Succeed())
+		Expect(list.EndOfProtocolPeriod()).To(Succeed())
+		Expect(udpStore.Addresses).To(BeEmpty())
+		Expect(list.EndOfProtocolPeriod()).To(Succeed())
+		Expect(udpStore.Addresses).To(Equal([]encoding.Address{lazyMember}))
+		var graft encoding.MessageGraft
+		Expect(graft.FromBuffer(udpStore.Buffers[0])).Error().ToNot(HaveOccurred())
+		Expect(graft).To(Equal(encoding.MessageGraft{
+			Source:      TestAddress,
+			Origin:      TestAddress2,
+			BroadcastID: 5,
+		}))
+		Expect(list.EagerPushMembers()).To(ContainElement(lazyMember))
+	})
+
+	It("should not graft members for broadcasts which were received", func() {
+		var tcpStore, udpStore transport.Store
+		list := newBroadcastTestList(&tcpStore, &udpStore, []encoding.Address{TestAddress2, TestAddress3})
+		Expect(DispatchDatagram(list, encoding.MessageIHave{
+			Source:      TestAddress3,
+			Origin:      TestAddress2,
+			BroadcastID: 5,
+		}.ToMessage())).To(Succeed())
+		Expect(DispatchDatagram(list, encoding.MessageBroadcast{
+			Source:      TestAddress2,
+			Origin:      TestAddress2,
+			BroadcastID: 5,
+		}.ToMessage())).To(Succeed())
+		Expect(list.EndOfProtocolPeriod()).To(Succeed())
+		Expect(list.EndOfProtocolPeriod()).To(Succeed())
+		Expect(udpStore.Addresses).To(BeEmpty())
+	})
+
+	It("should answer grafts with the broadcast", func() {
+		var tcpStore, udpStore transport.Store
+		list := newBroadcastTestList(&tcpStore, &udpStore, []encoding.Address{TestAddress2})
+		Expect(list.Broadcast(9, []byte("payload"))).To(Succeed())
+		tcpStore.Clear()
+		Expect(DispatchDatagram(list, encoding.MessageGraft{
+			Source:      TestAddress2,
+			Origin:      TestAddress,
+			BroadcastID: 9,
+		}.ToMessage())).To(Succeed())
+		Expect(tcpStore.Addresses).To(Equal([]encoding.Address{TestAddress2}))
+		Expect(parseBroadcast(tcpStore.Buffers[0]).Payload).To(Equal([]byte("payload")))
+	})
+
+	It("should replace eager push members which were removed", func() {
+		var tcpStore, udpStore transport.Store
+		list := newBroadcastTestList(&tcpStore, &udpStore, []encoding.Address{TestAddress2, TestAddress3}, membership.WithEagerPushMemberCount(1))
+		eagerMember := list.EagerPushMembers()[0]
+		Expect(DispatchDatagram(list, encoding.MessageFaulty{
+			Source:      TestAddress,
+			Destination: eagerMember,
+		}.ToMessage())).To(Succeed())
+		Expect(list.EagerPushMembers()).To(HaveLen(1))
+		Expect(list.EagerPushMembers()).ToNot(ContainElement(eagerMember))
+	})
+})
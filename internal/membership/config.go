@@ -1,9 +1,18 @@
 package membership
 
 import (
+	"time"
+
 	"github.com/go-logr/logr"
 
+	"github.com/backbone81/membership/internal/admission"
 	"github.com/backbone81/membership/internal/encoding"
+	"github.com/backbone81/membership/internal/faultymember"
+	"github.com/backbone81/membership/internal/incarnation"
+	"github.com/backbone81/membership/internal/observer"
+	"github.com/backbone81/membership/internal/passiveview"
+	"github.com/backbone81/membership/internal/quorum"
+	"github.com/backbone81/membership/internal/reconnect"
 	"github.com/backbone81/membership/internal/roundtriptime"
 	"github.com/backbone81/membership/internal/transport"
 )
@@ -20,10 +29,12 @@ type Config struct {
 	// AdvertisedAddress is the address for contacting this member.
 	AdvertisedAddress encoding.Address
 
-	// UDPClient is the transport for sending unreliable UDP network messages.
+	// UDPClient is the transport for sending unreliable UDP network messages. It is called under the lock of the list.
+	// Wrap network clients with transport.Async, to not block the list on network I/O.
 	UDPClient transport.Transport
 
-	// TCPClient is the transport for sending reliable TCP network messages.
+	// TCPClient is the transport for sending reliable TCP network messages. It is called under the lock of the list.
+	// Wrap network clients with transport.Async, to not block the list on network I/O.
 	TCPClient transport.Transport
 
 	// MaxDatagramLengthSend is the maximum length in bytes we should not exceed for sending UDP network messages.
@@ -47,6 +58,70 @@ type Config struct {
 	// price when necessary.
 	MemberRemovedCallback func(address encoding.Address)
 
+	// MemberStateChangedCallback is the callback which is triggered when a member changes between alive and suspect.
+	// It is also triggered when a member is added as suspect, as members are otherwise considered alive when added.
+	// This callback executes under the lock of the membership list. The same restrictions as for MemberAddedCallback
+	// apply.
+	MemberStateChangedCallback func(address encoding.Address, state encoding.MemberState)
+
+	// MemberRoundTripTimeCallback is the callback which is triggered when the round trip time of a direct ping to a
+	// member was observed. This callback executes under the lock of the membership list. The same restrictions as for
+	// MemberAddedCallback apply.
+	MemberRoundTripTimeCallback func(address encoding.Address, roundTripTime time.Duration)
+
+	// QueryCallback is the callback which is triggered when a query matching this member was received. The payload and
+	// the filter of the query alias the network buffer and must be copied if they are used after the callback returns.
+	// This callback executes under the lock of the membership list. The same restrictions as for MemberAddedCallback
+	// apply.
+	QueryCallback func(query encoding.MessageQuery)
+
+	// QueryAckCallback is the callback which is triggered when a member acknowledged one of our queries. This callback
+	// executes under the lock of the membership list. The same restrictions as for MemberAddedCallback apply.
+	QueryAckCallback func(source encoding.Address, queryID uint32)
+
+	// QueryResponseCallback is the callback which is triggered when a member responded to one of our queries. The
+	// payload aliases the network buffer and must be copied if it is used after the callback returns. This callback
+	// executes under the lock of the membership list. The same restrictions as for MemberAddedCallback apply.
+	QueryResponseCallback func(source encoding.Address, queryID uint32, payload []byte)
+
+	// AdmissionCallback is the callback which is asked before an unknown member is added to the list. It receives the
+	// address of the member and the address of the member which gossiped it. Return an error to veto the member.
+	// This callback executes under the lock of the membership list. The same restrictions as for MemberAddedCallback
+	// apply.
+	AdmissionCallback func(address encoding.Address, source encoding.Address) error
+
+	// MergeCallback is the callback which is triggered when a full member list sync merged two partitions of the
+	// cluster. It is triggered once per sync instead of the individual MemberAddedCallback calls being the only hint.
+	// This callback executes under the lock of the membership list. The same restrictions as for MemberAddedCallback
+	// apply.
+	MergeCallback func(event MergeEvent)
+
+	// AckPayloadCallback is the callback which provides the application payload attached to every direct ack this
+	// member sends. This allows the pinging members to learn about live information like load or readiness without
+	// any additional network messages. The payload should be small, as it reduces the space for gossip. No payload is
+	// attached when nil. This callback executes under the lock of the membership list. The same restrictions as for
+	// MemberAddedCallback apply.
+	AckPayloadCallback func() []byte
+
+	// MaxAckPayloadLength is the maximum length in bytes of the payload provided by AckPayloadCallback. Acks are sent
+	// without payload when the payload is longer.
+	MaxAckPayloadLength int
+
+	// BroadcastCallback is the callback which is triggered when a broadcast of another member was received. Every
+	// broadcast is delivered only once. The payload is kept for answering grafts and must not be modified. This
+	// callback executes under the lock of the membership list. The same restrictions as for MemberAddedCallback apply.
+	BroadcastCallback func(origin encoding.Address, payload []byte)
+
+	// MinorityPartitionCallback is the callback which is triggered when this member ends up in a minority partition
+	// and when it regains quorum. Applications like a consensus layer can refuse writes or elections while in a
+	// minority partition. This callback executes under the lock of the membership list. The same restrictions as for
+	// MemberAddedCallback apply.
+	MinorityPartitionCallback func(minority bool)
+
+	// Observer is notified about protocol activity like pings, suspicions and gossip. It executes under the lock of the
+	// membership list. The same restrictions as for MemberAddedCallback apply. No observer is notified when nil.
+	Observer observer.Observer
+
 	// SafetyFactor is a multiplier which describes the safety margin for disseminating gossip and declaring a suspect
 	// as faulty. A factor of 1.0 wil return the minimal number of periods required in a perfect world. A factor of 2.0
 	// will double the number of periods. Small values between 2.0 and 4.0 should usually be a safe value.
@@ -87,18 +162,190 @@ type Config struct {
 	// the bootstrap members are static. In cases where bootstrap members are ephemeral, this might lead to unnecessary
 	// adds and removes and should better be disabled.
 	ReconnectBootstrapMembers bool
+
+	// ReconnectFaultyMemberCount is the number of faulty members which are probed with a direct ping at the end of
+	// every protocol period. A faulty member which responds is reachable again, and a full member list sync with it is
+	// started to merge the two partitions. No faulty members are probed when zero.
+	ReconnectFaultyMemberCount int
+
+	// ReconnectMinDelay is the number of protocol periods to wait before probing a faulty member or re-adding a
+	// bootstrap member again, after the first attempt did not succeed. The delay doubles with every failed attempt.
+	ReconnectMinDelay int
+
+	// ReconnectMaxDelay is the maximum number of protocol periods to wait between two reconnect attempts to the same
+	// member.
+	ReconnectMaxDelay int
+
+	// PushPullListSync reports if list requests carry our own member list. The recipient merges our member list into
+	// its own before responding with its member list. This heals the view of both sides in a single round trip, which
+	// speeds up convergence after network partitions and joins. As the request becomes as big as the response, it is
+	// sent over TCP instead of UDP and does not carry any gossip. Members running an older version do not understand
+	// requests with a member list.
+	PushPullListSync bool
+
+	// IncarnationNumber is the incarnation number this member starts with. A member which restarts should resume with
+	// an incarnation number bigger than the one it used before, to not be ignored or suspected because of gossip about
+	// its previous life.
+	IncarnationNumber uint16
+
+	// IncarnationStore is the store every new incarnation number is persisted to before it is gossiped. This allows
+	// a restarted member to resume from the persisted incarnation number. No incarnation number is persisted when nil.
+	IncarnationStore incarnation.Store
+
+	// JoinCandidates is a list of members which are contacted in addition to the bootstrap members when joining. In
+	// contrast to bootstrap members, join candidates are never reconnected when they drop from the membership list.
+	// This is used for members remembered from a previous snapshot which might not exist anymore.
+	JoinCandidates []encoding.Address
+
+	// SnapshotPath is the path of the file the known members are periodically written to. No snapshot is written
+	// when empty.
+	SnapshotPath string
+
+	// SnapshotInterval is the number of protocol periods between two snapshots.
+	SnapshotInterval int
+
+	// HealthPolicy describes how this member announces itself to the other members while its application is
+	// unhealthy.
+	HealthPolicy HealthPolicy
+
+	// ForceRemoveListRequestCount is the number of list requests a force removed member is kept as a tombstone. This
+	// should be considerably bigger than for faulty members, as force removed members are known to be gone for good.
+	ForceRemoveListRequestCount int
+
+	// AllowedNetworks is the list of networks unknown members must be part of to be added. Any member is allowed when
+	// empty.
+	AllowedNetworks []admission.Rule
+
+	// DeniedNetworks is the list of networks unknown members must not be part of to be added. Denied networks take
+	// precedence over allowed networks.
+	DeniedNetworks []admission.Rule
+
+	// MergeMinRevivedMembers is the minimum number of faulty members which need to be revived by a single full member
+	// list sync to consider the sync a merge of two partitions.
+	MergeMinRevivedMembers int
+
+	// Passive reports if this member follows the membership without being a member itself. A passive member learns
+	// about the members through list requests and gossip and answers pings, but never gossips about itself. As a result
+	// it never appears in the member list of other members and is never probed by them. A passive member does not
+	// declare other members as suspect or faulty either, it follows the gossip of the other members instead.
+	Passive bool
+
+	// MemberHistoryLength is the number of recent state transitions kept for every member. The history is intended
+	// for debugging and is not kept when zero.
+	MemberHistoryLength int
+
+	// PartialView reports if this member only keeps a partial view of the cluster, HyParView style. The member list
+	// is then the active view, which is small, symmetric and the only set of members failure detection runs over. The
+	// passive view holds a larger random sample of further members which replace active members when they fail.
+	PartialView bool
+
+	// ActiveViewSize is the maximum number of members in the active view when running in partial view mode.
+	ActiveViewSize int
+
+	// PassiveViewSize is the maximum number of members in the passive view when running in partial view mode.
+	PassiveViewSize int
+
+	// ActiveRandomWalkLength is the number of hops a join and a shuffle are forwarded through the active views.
+	ActiveRandomWalkLength int
+
+	// PassiveRandomWalkLength is the number of hops after which a forwarded join adds the joining member to the
+	// passive view. It is reduced to be smaller than ActiveRandomWalkLength.
+	PassiveRandomWalkLength int
+
+	// ShuffleActiveCount is the number of members of the active view which are sent with every shuffle.
+	ShuffleActiveCount int
+
+	// ShufflePassiveCount is the number of members of the passive view which are sent with every shuffle.
+	ShufflePassiveCount int
+
+	// FailureDetector selects how members are declared suspect.
+	FailureDetector FailureDetector
+
+	// PhiThreshold is the suspicion level above which the phi accrual failure detector declares a member as suspect.
+	PhiThreshold float64
+
+	// PhiMinStdDeviation is the minimum standard deviation of inter-arrival times the phi accrual failure detector
+	// assumes. It prevents suspicion from rising too fast for members which are heard from very regularly.
+	PhiMinStdDeviation time.Duration
+
+	// PhiSmoothing is the weight of the latest inter-arrival time in the moving mean and variance of the phi accrual
+	// failure detector. Bigger values adapt faster to changing network conditions, smaller values are more stable.
+	PhiSmoothing float64
+
+	// Clock returns the current time the phi accrual failure detector measures inter-arrival times with. Simulations
+	// replace it to run protocol periods faster than in real time.
+	Clock func() time.Time
+
+	// EagerPushMemberCount is the number of members a broadcast is pushed to eagerly. All other members only learn
+	// about the broadcast through announcements piggybacked as gossip. In partial view mode, broadcasts are pushed to
+	// the whole active view instead.
+	EagerPushMemberCount int
+
+	// BroadcastGraftTimeout is the number of protocol periods to wait for a broadcast which was announced to us, before
+	// asking the announcing member for it.
+	BroadcastGraftTimeout int
+
+	// ExpectedClusterSize is the number of members including ourselves the operator expects in the cluster. Quorum is
+	// calculated against this size instead of the largest cluster size observed recently when bigger than zero.
+	ExpectedClusterSize int
+
+	// QuorumFraction is the fraction of the reference cluster size our partition needs to exceed to have quorum. A
+	// fraction of 0.5 requires a strict majority.
+	QuorumFraction float64
+
+	// QuorumWindow is the number of protocol periods the largest observed cluster size is remembered as reference for
+	// quorum. The cluster needs to shrink below quorum within that window to be considered partitioned.
+	QuorumWindow int
+
+	// MaxGossipQueueLength is the maximum number of gossip messages waiting for dissemination. When the gossip queue
+	// is full, the most transmitted messages are evicted. Our own refutations are never evicted. A member which evicted
+	// gossip requests the member list at the end of the protocol period to make up for the lost gossip. Zero means
+	// that the gossip queue is unbounded.
+	MaxGossipQueueLength int
+
+	// EvictionListRequestInterval is the minimum number of protocol periods between two member list requests which
+	// make up for evicted gossip. Evictions in between are made up for by the next member list request. This keeps an
+	// overloaded gossip queue from causing a full member list sync every protocol period.
+	EvictionListRequestInterval int
+
+	// Metrics holds the metrics collectors the membership list reports to. New metrics which are not registered
+	// anywhere are created when nil.
+	Metrics *Metrics
 }
 
 // DefaultConfig provides a default configuration which should work for most use-cases.
 var DefaultConfig = Config{
-	MaxDatagramLengthSend:     512,
-	SafetyFactor:              3,
-	ShutdownMemberCount:       3,
-	DirectPingMemberCount:     1,
-	MinDirectPingMemberCount:  1,
-	MaxDirectPingMemberCount:  16,
-	IndirectPingMemberCount:   3,
-	PendingPingPreAllocation:  16,
-	MemberPreAllocation:       128,
-	ReconnectBootstrapMembers: true,
+	MaxDatagramLengthSend:       512,
+	SafetyFactor:                3,
+	ShutdownMemberCount:         3,
+	DirectPingMemberCount:       1,
+	MinDirectPingMemberCount:    1,
+	MaxDirectPingMemberCount:    16,
+	IndirectPingMemberCount:     3,
+	PendingPingPreAllocation:    16,
+	MemberPreAllocation:         128,
+	ReconnectBootstrapMembers:   true,
+	ReconnectFaultyMemberCount:  1,
+	ReconnectMinDelay:           reconnect.DefaultConfig.MinDelay,
+	ReconnectMaxDelay:           reconnect.DefaultConfig.MaxDelay,
+	SnapshotInterval:            60,
+	HealthPolicy:                HealthPolicyStopAcking,
+	ForceRemoveListRequestCount: 10 * faultymember.DefaultConfig.MaxListRequestCount,
+	MergeMinRevivedMembers:      3,
+	MaxAckPayloadLength:         64,
+	ActiveViewSize:              5,
+	PassiveViewSize:             passiveview.DefaultConfig.MaxLength,
+	ActiveRandomWalkLength:      6,
+	PassiveRandomWalkLength:     3,
+	ShuffleActiveCount:          3,
+	ShufflePassiveCount:         4,
+	PhiThreshold:                8,
+	PhiMinStdDeviation:          500 * time.Millisecond,
+	PhiSmoothing:                0.1,
+	Clock:                       time.Now,
+	EagerPushMemberCount:        4,
+	BroadcastGraftTimeout:       2,
+	QuorumFraction:              quorum.DefaultConfig.Fraction,
+	QuorumWindow:                quorum.DefaultConfig.Window,
+	EvictionListRequestInterval: 10,
 }
@@ -29,10 +29,10 @@ func (l *DebugListWrapper) WriteInternalDebugState(writer io.Writer) error {
 		return err
 	}
 
-	if _, err := fmt.Fprintf(writer, "Members (%d)\n", len(l.members)); err != nil {
+	if _, err := fmt.Fprintf(writer, "Members (%d)\n", l.members.Len()); err != nil {
 		return err
 	}
-	for _, member := range l.members {
+	for _, member := range l.sortedMembers() {
 		if _, err := fmt.Fprintf(writer, "  - %s\n", member.Address); err != nil {
 			return err
 		}
@@ -41,10 +41,13 @@ func (l *DebugListWrapper) WriteInternalDebugState(writer io.Writer) error {
 	if _, err := fmt.Fprintf(writer, "Next Direct Pings\n"); err != nil {
 		return err
 	}
-	for i := l.nextRandomIndex; i < len(l.randomIndexes); i++ {
-		if _, err := fmt.Fprintf(writer, "  - %s\n", l.members[l.randomIndexes[i]].Address); err != nil {
-			return err
-		}
+	var err error
+	l.members.ForEachUpcoming(func(member encoding.Member) bool {
+		_, err = fmt.Fprintf(writer, "  - %s\n", member.Address)
+		return err == nil
+	})
+	if err != nil {
+		return err
 	}
 
 	if _, err := fmt.Fprintf(writer, "Gossip (%d)\n", l.gossipQueue.Len()); err != nil {
@@ -65,7 +68,7 @@ func (l *DebugListWrapper) GetMembers() []encoding.Member {
 	l.mutex.Lock()
 	defer l.mutex.Unlock()
 
-	return l.members
+	return l.sortedMembers()
 }
 
 func (l *DebugListWrapper) GetFaultyMembers() []encoding.Member {
@@ -83,10 +86,13 @@ func (l *DebugListWrapper) GetFaultyMembers() []encoding.Member {
 func (l *DebugListWrapper) SetMembers(members []encoding.Member) {
 	l.mutex.Lock()
 	defer l.mutex.Unlock()
+	defer l.publishView()
 
-	l.members = l.members[:0]
-	l.randomIndexes = l.randomIndexes[:0]
-	l.nextRandomIndex = 0
+	l.members.Clear()
+	clear(l.suspectSince)
+	clear(l.lifecycles)
+	clear(l.eagerPushMembers)
+	l.viewRebuild = true
 
 	for _, member := range members {
 		l.addMember(member)
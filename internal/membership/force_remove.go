@@ -0,0 +1,116 @@
+package membership
+
+import (
+	"errors"
+
+	"github.com/backbone81/membership/internal/encoding"
+	"github.com/backbone81/membership/internal/utility"
+)
+
+// ForceRemove removes the member with the given address from the whole cluster. This is meant for members which are
+// known to be permanently gone, like a terminated virtual machine. The member is declared faulty right away with an
+// incarnation number bigger than any incarnation number we know for it. It is kept as a tombstone longer than a
+// normal faulty member and is never reconnected as a bootstrap member during that time.
+//
+// A member which is still alive and receives the gossip about being force removed refutes it like any other gossip
+// about being faulty.
+func (l *List) ForceRemove(address encoding.Address) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	defer l.publishView()
+
+	if address.Equal(l.self) {
+		return errors.New("cannot force remove ourselves, use a graceful shutdown instead")
+	}
+
+	incarnationNumber := l.knownIncarnationNumber(address)
+	l.handleForceRemove(encoding.MessageForceRemove{
+		Source:            l.self,
+		Destination:       address,
+		IncarnationNumber: incarnationNumber + 1,
+	})
+	return nil
+}
+
+// knownIncarnationNumber returns the biggest incarnation number we know for the member with the given address.
+func (l *List) knownIncarnationNumber(address encoding.Address) uint16 {
+	var incarnationNumber uint16
+	if member, found := l.members.Get(address); found {
+		incarnationNumber = utility.IncarnationMax(incarnationNumber, member.IncarnationNumber)
+	}
+	if faultyMember, found := l.faultyMembers.Get(address); found {
+		incarnationNumber = utility.IncarnationMax(incarnationNumber, faultyMember.IncarnationNumber)
+	}
+	if forceRemovedMember, found := l.forceRemovedMembers.Get(address); found {
+		incarnationNumber = utility.IncarnationMax(incarnationNumber, forceRemovedMember.IncarnationNumber)
+	}
+	return incarnationNumber
+}
+
+func (l *List) handleForceRemove(forceRemove encoding.MessageForceRemove) {
+	logger := l.logger.V(3)
+	if logger.Enabled() {
+		// We only spend the memory allocation for interface boxing of the key value pairs when the log level would
+		// actually produce this log entry.
+		logger.Info(
+			"Received gossip about force remove",
+			"source", forceRemove.Source,
+			"destination", forceRemove.Destination,
+			"incarnation-number", forceRemove.IncarnationNumber,
+		)
+	}
+	if forceRemove.Destination.Equal(l.self) {
+		// We are alive, so this is a mistake. We refute like for any other gossip about being faulty.
+		l.handleFaultyForSelf(encoding.MessageFaulty{
+			Source:            forceRemove.Source,
+			Destination:       forceRemove.Destination,
+			IncarnationNumber: forceRemove.IncarnationNumber,
+		})
+		return
+	}
+	if utility.IncarnationLessThan(forceRemove.IncarnationNumber, l.knownIncarnationNumber(forceRemove.Destination)) {
+		// The member refuted the force remove already.
+		return
+	}
+	if forceRemovedMember, found := l.forceRemovedMembers.Get(forceRemove.Destination); found &&
+		forceRemovedMember.IncarnationNumber == forceRemove.IncarnationNumber {
+		// We already know about this force remove.
+		return
+	}
+
+	l.logger.Info(
+		"Member force removed",
+		"source", forceRemove.Source,
+		"destination", forceRemove.Destination,
+		"incarnation-number", forceRemove.IncarnationNumber,
+	)
+	l.config.Metrics.MemberStateTransitionsTotal.WithLabelValues("force_removed").Inc()
+
+	forceRemovedMember := encoding.Member{
+		Address:           forceRemove.Destination,
+		State:             encoding.MemberStateFaulty,
+		IncarnationNumber: forceRemove.IncarnationNumber,
+	}
+	l.faultyMembers.Add(forceRemovedMember)
+	l.forceRemovedMembers.Add(forceRemovedMember)
+	l.gossipQueue.Add(forceRemove.ToMessage())
+	delete(l.suspectCounters, forceRemove.Destination)
+	l.passiveView.Remove(forceRemove.Destination)
+	l.removeMember(forceRemove.Destination) // must always happen last
+}
+
+// handleForceRemovedMembers reports if gossip about a member with the given address and incarnation number must be
+// ignored, because the member was force removed. Gossip with a bigger incarnation number means that the member
+// refuted the force remove, which lifts the force remove.
+func (l *List) handleForceRemovedMembers(address encoding.Address, incarnationNumber uint16) bool {
+	forceRemovedMember, found := l.forceRemovedMembers.Get(address)
+	if !found {
+		return false
+	}
+	if !utility.IncarnationLessThan(forceRemovedMember.IncarnationNumber, incarnationNumber) {
+		// The gossip is older than the force remove.
+		return true
+	}
+	l.forceRemovedMembers.Remove(address)
+	return false
+}
@@ -0,0 +1,164 @@
+package membership_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/encoding"
+	"github.com/backbone81/membership/internal/faultymember"
+	"github.com/backbone81/membership/internal/membership"
+	"github.com/backbone81/membership/internal/transport"
+)
+
+var _ = Describe("ForceRemove", func() {
+	// expireFaultyMembers sends enough list requests to drop all faulty members which were not force removed.
+	expireFaultyMembers := func(list *membership.List) {
+		for range faultymember.DefaultConfig.MaxListRequestCount + 1 {
+			Expect(DispatchDatagram(list, encoding.MessageListRequest{
+				Source: TestAddress3,
+			}.ToMessage())).To(Succeed())
+		}
+	}
+
+	It("should remove the member and gossip with a bumped incarnation number", func() {
+		var removed []encoding.Address
+		list := newTestList(
+			membership.WithMemberRemovedCallback(func(address encoding.Address) {
+				removed = append(removed, address)
+			}),
+		)
+		Expect(DispatchDatagram(list, encoding.MessageAlive{
+			Destination:       TestAddress2,
+			IncarnationNumber: 5,
+		}.ToMessage())).To(Succeed())
+		debugList := membership.DebugList(list)
+		debugList.ClearGossip()
+
+		Expect(list.ForceRemove(TestAddress2)).To(Succeed())
+		Expect(list.Len()).To(Equal(0))
+		Expect(removed).To(Equal([]encoding.Address{TestAddress2}))
+		Expect(debugList.GetFaultyMembers()).To(ConsistOf(encoding.Member{
+			Address:           TestAddress2,
+			State:             encoding.MemberStateFaulty,
+			IncarnationNumber: 6,
+		}))
+		Expect(debugList.GetGossip().Len()).To(Equal(1))
+		Expect(GetFromQueueByIndex(debugList.GetGossip(), 0)).To(Equal(encoding.MessageForceRemove{
+			Source:            TestAddress,
+			Destination:       TestAddress2,
+			IncarnationNumber: 6,
+		}.ToMessage()))
+	})
+
+	It("should not force remove ourselves", func() {
+		list := newTestList()
+		Expect(list.ForceRemove(TestAddress)).ToNot(Succeed())
+	})
+
+	It("should ignore a force remove which was refuted already", func() {
+		list := newTestList()
+		Expect(DispatchDatagram(list, encoding.MessageAlive{
+			Destination:       TestAddress2,
+			IncarnationNumber: 5,
+		}.ToMessage())).To(Succeed())
+		Expect(DispatchDatagram(list, encoding.MessageForceRemove{
+			Source:            TestAddress3,
+			Destination:       TestAddress2,
+			IncarnationNumber: 4,
+		}.ToMessage())).To(Succeed())
+		Expect(list.Len()).To(Equal(1))
+	})
+
+	It("should refute a force remove about ourselves", func() {
+		list := newTestList(membership.WithIncarnationNumber(3))
+		debugList := membership.DebugList(list)
+		debugList.ClearGossip()
+		Expect(DispatchDatagram(list, encoding.MessageForceRemove{
+			Source:            TestAddress2,
+			Destination:       TestAddress,
+			IncarnationNumber: 4,
+		}.ToMessage())).To(Succeed())
+		Expect(GetFromQueueByIndex(debugList.GetGossip(), 0)).To(Equal(encoding.MessageAlive{
+			Destination:       TestAddress,
+			IncarnationNumber: 5,
+		}.ToMessage()))
+	})
+
+	It("should ignore outdated gossip after the faulty member expired", func() {
+		list := newTestList()
+		Expect(DispatchDatagram(list, encoding.MessageForceRemove{
+			Source:            TestAddress3,
+			Destination:       TestAddress2,
+			IncarnationNumber: 4,
+		}.ToMessage())).To(Succeed())
+		expireFaultyMembers(list)
+		Expect(membership.DebugList(list).GetFaultyMembers()).To(BeEmpty())
+
+		Expect(DispatchDatagram(list, encoding.MessageAlive{
+			Destination:       TestAddress2,
+			IncarnationNumber: 4,
+		}.ToMessage())).To(Succeed())
+		Expect(DispatchDatagram(list, encoding.MessageSuspect{
+			Source:            TestAddress3,
+			Destination:       TestAddress2,
+			IncarnationNumber: 4,
+		}.ToMessage())).To(Succeed())
+		Expect(list.Len()).To(Equal(0))
+
+		Expect(DispatchDatagram(list, encoding.MessageAlive{
+			Destination:       TestAddress2,
+			IncarnationNumber: 5,
+		}.ToMessage())).To(Succeed())
+		Expect(Collect(list)).To(Equal([]encoding.Address{TestAddress2}))
+	})
+
+	It("should carry force removed members in list syncs after the faulty member expired", func() {
+		var store transport.Store
+		list := newTestList(membership.WithTCPClient(&store))
+		Expect(DispatchDatagram(list, encoding.MessageForceRemove{
+			Source:            TestAddress3,
+			Destination:       TestAddress2,
+			IncarnationNumber: 4,
+		}.ToMessage())).To(Succeed())
+		expireFaultyMembers(list)
+
+		var listResponse encoding.MessageListResponse
+		Expect(listResponse.FromBuffer(store.Buffers[len(store.Buffers)-1])).Error().ToNot(HaveOccurred())
+		Expect(listResponse.Members).To(Equal([]encoding.Member{
+			{
+				Address:           TestAddress2,
+				State:             encoding.MemberStateForceRemoved,
+				IncarnationNumber: 4,
+			},
+		}))
+	})
+
+	It("should take over force removed members from list syncs", func() {
+		list := newTestList()
+		Expect(DispatchDatagram(list, encoding.MessageListResponse{
+			Source: TestAddress3,
+			Members: []encoding.Member{
+				{
+					Address:           TestAddress2,
+					State:             encoding.MemberStateForceRemoved,
+					IncarnationNumber: 4,
+				},
+			},
+		}.ToMessage())).To(Succeed())
+		expireFaultyMembers(list)
+
+		Expect(DispatchDatagram(list, encoding.MessageAlive{
+			Destination:       TestAddress2,
+			IncarnationNumber: 4,
+		}.ToMessage())).To(Succeed())
+		Expect(list.Len()).To(Equal(0))
+	})
+
+	It("should not reconnect a force removed bootstrap member", func() {
+		list := newTestList(membership.WithBootstrapMembers([]encoding.Address{TestAddress2}))
+		Expect(list.ForceRemove(TestAddress2)).To(Succeed())
+		expireFaultyMembers(list)
+		Expect(list.EndOfProtocolPeriod()).To(Succeed())
+		Expect(list.Len()).To(Equal(0))
+	})
+})
@@ -0,0 +1,112 @@
+package membership
+
+import (
+	"github.com/backbone81/membership/internal/encoding"
+	"github.com/backbone81/membership/internal/utility"
+)
+
+// HealthPolicy describes how this member announces itself to the other members while its application is unhealthy.
+type HealthPolicy int
+
+const (
+	// HealthPolicyStopAcking stops acknowledging direct pings. The other members detect this member as failed through
+	// the normal failure detection.
+	HealthPolicyStopAcking HealthPolicy = iota
+
+	// HealthPolicyDegraded keeps acknowledging direct pings, but gossips this member as suspect. The other members see
+	// this member as suspect right away and declare it faulty after the suspicion timeout, unless it recovers before.
+	HealthPolicyDegraded
+
+	// HealthPolicyLeave stops acknowledging direct pings and broadcasts a graceful leave like during shutdown. The
+	// other members remove this member right away.
+	HealthPolicyLeave
+)
+
+// String returns a human-readable representation of the health policy.
+func (p HealthPolicy) String() string {
+	switch p {
+	case HealthPolicyStopAcking:
+		return "stop-acking"
+	case HealthPolicyDegraded:
+		return "degraded"
+	case HealthPolicyLeave:
+		return "leave"
+	default:
+		return "unknown"
+	}
+}
+
+// Healthy reports if the application of this member is healthy.
+func (l *List) Healthy() bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.healthy
+}
+
+// SetHealthy informs the membership list about the health of the application. When the application becomes unhealthy,
+// this member announces itself according to the configured health policy. While unhealthy, gossip about this member
+// being suspect or faulty is not refuted. When the application recovers, this member refutes with a new incarnation
+// number and rejoins automatically.
+func (l *List) SetHealthy(healthy bool) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.healthy == healthy {
+		return nil
+	}
+	l.healthy = healthy
+	if l.config.Passive {
+		// We are not part of the member list of other members. There is nothing to announce.
+		return nil
+	}
+
+	if healthy {
+		if err := l.refuteAfterRecovery(); err != nil {
+			// We stay unhealthy from the point of view of the membership list, which makes the next health update
+			// try again.
+			l.healthy = false
+			return err
+		}
+		return nil
+	}
+
+	l.logger.Info(
+		"Application unhealthy",
+		"policy", l.config.HealthPolicy.String(),
+	)
+	l.unhealthyIncarnationNumber = l.incarnationNumber
+	l.config.Metrics.MemberStateTransitionsTotal.WithLabelValues("unhealthy").Inc()
+	switch l.config.HealthPolicy {
+	case HealthPolicyDegraded:
+		l.gossipQueue.Add(encoding.MessageSuspect{
+			Source:            l.self,
+			Destination:       l.self,
+			IncarnationNumber: l.incarnationNumber,
+		}.ToMessage())
+	case HealthPolicyLeave:
+		return l.broadcastFaultyForSelf()
+	default:
+		// Not acknowledging direct pings is all we need to do.
+	}
+	return nil
+}
+
+// refuteAfterRecovery gossips ourselves as alive with an incarnation number which is bigger than everything the other
+// members might have seen while we were unhealthy.
+func (l *List) refuteAfterRecovery() error {
+	if err := l.setIncarnationNumber(utility.IncarnationMax(l.incarnationNumber+1, l.unhealthyIncarnationNumber+1)); err != nil {
+		return err
+	}
+	l.gossipQueue.Add(encoding.MessageAlive{
+		Destination:       l.self,
+		IncarnationNumber: l.incarnationNumber,
+	}.ToMessage())
+
+	l.logger.Info(
+		"Application recovered",
+		"incarnation-number", l.incarnationNumber,
+	)
+	l.config.Metrics.MemberStateTransitionsTotal.WithLabelValues("recovered").Inc()
+	return nil
+}
@@ -0,0 +1,153 @@
+package membership_test
+
+import (
+	"errors"
+	"net"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/encoding"
+	"github.com/backbone81/membership/internal/incarnation"
+	"github.com/backbone81/membership/internal/membership"
+	"github.com/backbone81/membership/internal/transport"
+)
+
+var _ = Describe("Health", func() {
+	It("should be healthy initially", func() {
+		list := newTestList()
+		Expect(list.Healthy()).To(BeTrue())
+	})
+
+	It("should stop acking direct pings while unhealthy", func() {
+		var store transport.Store
+		list := newTestList(
+			membership.WithUDPClient(&store),
+			membership.WithHealthPolicy(membership.HealthPolicyStopAcking),
+		)
+		Expect(list.SetHealthy(false)).To(Succeed())
+		Expect(list.Healthy()).To(BeFalse())
+
+		Expect(DispatchDatagram(list, encoding.MessageDirectPing{
+			Source:         TestAddress2,
+			SequenceNumber: 42,
+		}.ToMessage())).To(Succeed())
+		Expect(store.Buffers).To(BeEmpty())
+
+		Expect(list.SetHealthy(true)).To(Succeed())
+		Expect(DispatchDatagram(list, encoding.MessageDirectPing{
+			Source:         TestAddress2,
+			SequenceNumber: 43,
+		}.ToMessage())).To(Succeed())
+		Expect(store.Buffers).To(HaveLen(1))
+	})
+
+	It("should keep acking direct pings and gossip suspect about self while degraded", func() {
+		var store transport.Store
+		list := newTestList(
+			membership.WithUDPClient(&store),
+			membership.WithHealthPolicy(membership.HealthPolicyDegraded),
+			membership.WithIncarnationNumber(3),
+		)
+		debugList := membership.DebugList(list)
+		debugList.ClearGossip()
+		Expect(list.SetHealthy(false)).To(Succeed())
+
+		Expect(debugList.GetGossip().Len()).To(Equal(1))
+		msg := GetFromQueueByIndex(debugList.GetGossip(), 0)
+		Expect(msg.Type).To(Equal(encoding.MessageTypeSuspect))
+		Expect(msg.Destination).To(Equal(TestAddress))
+		Expect(msg.IncarnationNumber).To(Equal(uint16(3)))
+
+		Expect(DispatchDatagram(list, encoding.MessageDirectPing{
+			Source:         TestAddress2,
+			SequenceNumber: 42,
+		}.ToMessage())).To(Succeed())
+		Expect(store.Buffers).To(HaveLen(1))
+	})
+
+	It("should broadcast faulty about self when leaving", func() {
+		var store transport.Store
+		list := newTestList(
+			membership.WithUDPClient(&store),
+			membership.WithHealthPolicy(membership.HealthPolicyLeave),
+			membership.WithBootstrapMember(encoding.NewAddress(net.IPv4(255, 255, 255, 255), 1)),
+		)
+		Expect(list.SetHealthy(false)).To(Succeed())
+
+		Expect(store.Buffers).To(HaveLen(1))
+		var faultyMsg encoding.MessageFaulty
+		Expect(faultyMsg.FromBuffer(store.Buffers[0])).Error().ToNot(HaveOccurred())
+		Expect(faultyMsg.Destination).To(Equal(TestAddress))
+	})
+
+	It("should not refute suspect about self while unhealthy", func() {
+		list := newTestList(
+			membership.WithIncarnationNumber(3),
+		)
+		debugList := membership.DebugList(list)
+		debugList.ClearGossip()
+		Expect(list.SetHealthy(false)).To(Succeed())
+
+		Expect(DispatchDatagram(list, encoding.MessageSuspect{
+			Source:            TestAddress2,
+			Destination:       TestAddress,
+			IncarnationNumber: 5,
+		}.ToMessage())).To(Succeed())
+		Expect(debugList.GetGossip().Len()).To(Equal(0))
+	})
+
+	It("should refute with a bigger incarnation number after recovery", func() {
+		list := newTestList(
+			membership.WithIncarnationNumber(3),
+		)
+		debugList := membership.DebugList(list)
+		debugList.ClearGossip()
+		Expect(list.SetHealthy(false)).To(Succeed())
+
+		Expect(DispatchDatagram(list, encoding.MessageFaulty{
+			Source:            TestAddress2,
+			Destination:       TestAddress,
+			IncarnationNumber: 5,
+		}.ToMessage())).To(Succeed())
+		Expect(list.SetHealthy(true)).To(Succeed())
+
+		Expect(debugList.GetGossip().Len()).To(Equal(1))
+		msg := GetFromQueueByIndex(debugList.GetGossip(), 0)
+		Expect(msg.Type).To(Equal(encoding.MessageTypeAlive))
+		Expect(msg.Destination).To(Equal(TestAddress))
+		Expect(msg.IncarnationNumber).To(Equal(uint16(6)))
+	})
+
+	It("should stay unhealthy when persisting the incarnation number fails on recovery", func() {
+		store := incarnation.Memory{
+			SaveErr: errors.New("disk full"),
+		}
+		list := newTestList(
+			membership.WithIncarnationNumber(3),
+			membership.WithIncarnationStore(&store),
+		)
+		debugList := membership.DebugList(list)
+		debugList.ClearGossip()
+		Expect(list.SetHealthy(false)).To(Succeed())
+
+		Expect(list.SetHealthy(true)).ToNot(Succeed())
+		Expect(list.Healthy()).To(BeFalse())
+		Expect(debugList.GetGossip().IsEmpty()).To(BeTrue())
+
+		store.SaveErr = nil
+		Expect(list.SetHealthy(true)).To(Succeed())
+		Expect(list.Healthy()).To(BeTrue())
+		Expect(store.IncarnationNumber).To(Equal(uint16(4)))
+		Expect(GetFromQueueByIndex(debugList.GetGossip(), 0).IncarnationNumber).To(Equal(uint16(4)))
+	})
+
+	It("should do nothing when health does not change", func() {
+		list := newTestList()
+		debugList := membership.DebugList(list)
+		debugList.ClearGossip()
+
+		Expect(list.SetHealthy(true)).To(Succeed())
+		Expect(debugList.GetGossip().Len()).To(Equal(0))
+	})
+})
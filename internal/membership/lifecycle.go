@@ -0,0 +1,189 @@
+package membership
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/backbone81/membership/internal/encoding"
+)
+
+// MemberLifecycle provides timing information about a member which is alive or suspect. It is a copy, which does not
+// change when the member changes.
+type MemberLifecycle struct {
+	// JoinedAt is the point in time the member was added to the member list.
+	JoinedAt time.Time
+
+	// LastStateChange is the point in time the member was added or changed between alive and suspect the last time.
+	LastStateChange time.Time
+
+	// LastAckReceived is the point in time we received an ack from the member the last time, either directly or
+	// through an indirect ping. It is the zero value when no ack was received yet.
+	LastAckReceived time.Time
+
+	// LastGossipAbout is the point in time we received gossip about the member the last time. It is the zero value
+	// when no gossip was received yet.
+	LastGossipAbout time.Time
+
+	// History holds the most recent state transitions of the member, oldest first. It is empty unless a member
+	// history length is configured.
+	History []StateTransition
+}
+
+// lastSeen returns the most recent point in time we heard from or about the member.
+func (m *MemberLifecycle) lastSeen() time.Time {
+	lastSeen := m.LastStateChange
+	if m.LastAckReceived.After(lastSeen) {
+		lastSeen = m.LastAckReceived
+	}
+	if m.LastGossipAbout.After(lastSeen) {
+		lastSeen = m.LastGossipAbout
+	}
+	return lastSeen
+}
+
+// StateTransition describes a single change of the state of a member.
+type StateTransition struct {
+	// Timestamp is the point in time the state changed.
+	Timestamp time.Time
+
+	// State is the state the member changed to.
+	State encoding.MemberState
+
+	// IncarnationNumber is the incarnation number of the member at the time of the state change.
+	IncarnationNumber uint16
+}
+
+// lifecycle provides the bookkeeping of timing information for a single member. It is kept separate from the members
+// to not grow the member index, which needs to stay small for millions of members.
+type lifecycle struct {
+	// timestamps holds the timestamps of the member. Its history is always nil, the history is kept in the ring buffer
+	// below.
+	timestamps MemberLifecycle
+
+	// history is the ring buffer of the most recent state transitions. Its capacity is the configured member history
+	// length and it is allocated once when the lifecycle is created.
+	history []StateTransition
+
+	// historyNext is the index in history to write the next state transition to, once the ring buffer is full.
+	historyNext int
+
+	// ackPayload is the application payload of the last ack received from the member. Its memory is re-used for every
+	// ack to not allocate memory for every ack.
+	ackPayload []byte
+
+	// arrivals holds the inter-arrival statistics of the member for the phi accrual failure detector.
+	arrivals arrivals
+}
+
+// MemberLifecycle returns the timing information about the member with the given address. Reports false if the
+// address is not an alive or suspect member.
+func (l *List) MemberLifecycle(address encoding.Address) (MemberLifecycle, bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	memberLifecycle, found := l.lifecycles[address]
+	if !found {
+		return MemberLifecycle{}, false
+	}
+	result := memberLifecycle.timestamps
+	if len(memberLifecycle.history) > 0 {
+		result.History = make([]StateTransition, 0, len(memberLifecycle.history))
+		result.History = append(result.History, memberLifecycle.history[memberLifecycle.historyNext:]...)
+		result.History = append(result.History, memberLifecycle.history[:memberLifecycle.historyNext]...)
+	}
+	return result, true
+}
+
+// AckPayload returns a copy of the application payload the member with the given address attached to the last ack we
+// received from it. The payload is empty when no ack was received yet or when the member does not attach any payload.
+// Reports false if the address is not an alive or suspect member.
+func (l *List) AckPayload(address encoding.Address) ([]byte, bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	memberLifecycle, found := l.lifecycles[address]
+	if !found {
+		return nil, false
+	}
+	return bytes.Clone(memberLifecycle.ackPayload), true
+}
+
+// lifecycleJoined starts the lifecycle of the given member which was just added. Lifecycles of members which were
+// removed are re-used to reduce memory allocations.
+func (l *List) lifecycleJoined(member encoding.Member) {
+	var memberLifecycle *lifecycle
+	if last := len(l.lifecycleFreeList) - 1; last >= 0 {
+		memberLifecycle = l.lifecycleFreeList[last]
+		l.lifecycleFreeList = l.lifecycleFreeList[:last]
+		*memberLifecycle = lifecycle{
+			history:    memberLifecycle.history[:0],
+			ackPayload: memberLifecycle.ackPayload[:0],
+		}
+	} else {
+		memberLifecycle = &lifecycle{
+			history: make([]StateTransition, 0, l.config.MemberHistoryLength),
+		}
+	}
+	now := time.Now()
+	memberLifecycle.timestamps.JoinedAt = now
+	memberLifecycle.arrivals.last = l.config.Clock()
+	l.lifecycles[member.Address] = memberLifecycle
+
+	// Members are considered alive when added. A member which is added as suspect records its change to suspect
+	// separately.
+	l.lifecycleStateChanged(encoding.Member{
+		Address:           member.Address,
+		State:             encoding.MemberStateAlive,
+		IncarnationNumber: member.IncarnationNumber,
+	}, now)
+}
+
+// lifecycleStateChanged records the state change of the given member.
+func (l *List) lifecycleStateChanged(member encoding.Member, now time.Time) {
+	memberLifecycle, found := l.lifecycles[member.Address]
+	if !found {
+		return
+	}
+	memberLifecycle.timestamps.LastStateChange = now
+	if cap(memberLifecycle.history) == 0 {
+		return
+	}
+	transition := StateTransition{
+		Timestamp:         now,
+		State:             member.State,
+		IncarnationNumber: member.IncarnationNumber,
+	}
+	if len(memberLifecycle.history) < cap(memberLifecycle.history) {
+		memberLifecycle.history = append(memberLifecycle.history, transition)
+		return
+	}
+	memberLifecycle.history[memberLifecycle.historyNext] = transition
+	memberLifecycle.historyNext = (memberLifecycle.historyNext + 1) % len(memberLifecycle.history)
+}
+
+// lifecycleAckReceived records that we received an ack with the given payload from the member with the given address.
+// The payload is copied, as it shares memory with the network buffer.
+func (l *List) lifecycleAckReceived(address encoding.Address, payload []byte) {
+	if memberLifecycle, found := l.lifecycles[address]; found {
+		memberLifecycle.timestamps.LastAckReceived = time.Now()
+		memberLifecycle.ackPayload = append(memberLifecycle.ackPayload[:0], payload...)
+		memberLifecycle.arrivals.add(l.config.Clock(), l.config.PhiSmoothing)
+	}
+}
+
+// lifecycleGossipReceived records that we received gossip about the member with the given address.
+func (l *List) lifecycleGossipReceived(address encoding.Address) {
+	if memberLifecycle, found := l.lifecycles[address]; found {
+		memberLifecycle.timestamps.LastGossipAbout = time.Now()
+	}
+}
+
+// lifecycleRemoved ends the lifecycle of the member with the given address. The lifecycle is kept for re-use.
+func (l *List) lifecycleRemoved(address encoding.Address) {
+	memberLifecycle, found := l.lifecycles[address]
+	if !found {
+		return
+	}
+	delete(l.lifecycles, address)
+	l.lifecycleFreeList = append(l.lifecycleFreeList, memberLifecycle)
+}
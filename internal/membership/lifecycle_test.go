@@ -0,0 +1,116 @@
+package membership_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/encoding"
+	"github.com/backbone81/membership/internal/membership"
+)
+
+var _ = Describe("MemberLifecycle", func() {
+	It("should not report unknown members", func() {
+		list := newTestList()
+		Expect(list.MemberLifecycle(TestAddress2)).Error().To(BeFalse())
+	})
+
+	It("should record when the member joined", func() {
+		list := newTestList()
+		Expect(DispatchDatagram(list, encoding.MessageAlive{
+			Destination: TestAddress2,
+		}.ToMessage())).To(Succeed())
+
+		lifecycle, found := list.MemberLifecycle(TestAddress2)
+		Expect(found).To(BeTrue())
+		Expect(lifecycle.JoinedAt).ToNot(BeZero())
+		Expect(lifecycle.LastStateChange).To(Equal(lifecycle.JoinedAt))
+		Expect(lifecycle.LastAckReceived).To(BeZero())
+		Expect(lifecycle.History).To(BeEmpty())
+	})
+
+	It("should record state changes", func() {
+		list := newTestList()
+		Expect(DispatchDatagram(list, encoding.MessageAlive{
+			Destination: TestAddress2,
+		}.ToMessage())).To(Succeed())
+		joined, _ := list.MemberLifecycle(TestAddress2)
+
+		Expect(DispatchDatagram(list, encoding.MessageSuspect{
+			Source:      TestAddress3,
+			Destination: TestAddress2,
+		}.ToMessage())).To(Succeed())
+		suspected, _ := list.MemberLifecycle(TestAddress2)
+		Expect(suspected.JoinedAt).To(Equal(joined.JoinedAt))
+		Expect(suspected.LastStateChange).To(BeTemporally(">=", joined.LastStateChange))
+		Expect(suspected.LastGossipAbout).To(BeTemporally(">=", joined.JoinedAt))
+	})
+
+	It("should record acks", func() {
+		list := newTestList(membership.WithBootstrapMembers([]encoding.Address{TestAddress2}))
+		Expect(list.DirectPing()).To(Succeed())
+		pendingPings := membership.DebugList(list).GetPendingDirectPings()
+		Expect(pendingPings).To(HaveLen(1))
+
+		Expect(DispatchDatagram(list, encoding.MessageDirectAck{
+			Source:         TestAddress2,
+			SequenceNumber: pendingPings[0].MessageDirectPing.SequenceNumber,
+		}.ToMessage())).To(Succeed())
+		lifecycle, _ := list.MemberLifecycle(TestAddress2)
+		Expect(lifecycle.LastAckReceived).To(BeTemporally(">=", lifecycle.JoinedAt))
+	})
+
+	It("should forget members which were removed", func() {
+		list := newTestList()
+		Expect(DispatchDatagram(list, encoding.MessageAlive{
+			Destination: TestAddress2,
+		}.ToMessage())).To(Succeed())
+		joined, _ := list.MemberLifecycle(TestAddress2)
+		Expect(DispatchDatagram(list, encoding.MessageFaulty{
+			Source:      TestAddress3,
+			Destination: TestAddress2,
+		}.ToMessage())).To(Succeed())
+		Expect(list.MemberLifecycle(TestAddress2)).Error().To(BeFalse())
+
+		By("starting over when the member rejoins")
+		Expect(DispatchDatagram(list, encoding.MessageAlive{
+			Destination:       TestAddress2,
+			IncarnationNumber: 1,
+		}.ToMessage())).To(Succeed())
+		lifecycle, found := list.MemberLifecycle(TestAddress2)
+		Expect(found).To(BeTrue())
+		Expect(lifecycle.JoinedAt).To(BeTemporally(">=", joined.JoinedAt))
+		Expect(lifecycle.LastGossipAbout).To(BeZero())
+	})
+
+	It("should keep a bounded history of state transitions", func() {
+		list := newTestList(membership.WithMemberHistoryLength(3))
+		Expect(DispatchDatagram(list, encoding.MessageAlive{
+			Destination: TestAddress2,
+		}.ToMessage())).To(Succeed())
+		for incarnationNumber := range uint16(3) {
+			Expect(DispatchDatagram(list, encoding.MessageSuspect{
+				Source:            TestAddress3,
+				Destination:       TestAddress2,
+				IncarnationNumber: incarnationNumber,
+			}.ToMessage())).To(Succeed())
+			Expect(DispatchDatagram(list, encoding.MessageAlive{
+				Destination:       TestAddress2,
+				IncarnationNumber: incarnationNumber + 1,
+			}.ToMessage())).To(Succeed())
+		}
+
+		lifecycle, _ := list.MemberLifecycle(TestAddress2)
+		var states []encoding.MemberState
+		var incarnationNumbers []uint16
+		for _, transition := range lifecycle.History {
+			states = append(states, transition.State)
+			incarnationNumbers = append(incarnationNumbers, transition.IncarnationNumber)
+		}
+		Expect(states).To(Equal([]encoding.MemberState{
+			encoding.MemberStateAlive,
+			encoding.MemberStateSuspect,
+			encoding.MemberStateAlive,
+		}))
+		Expect(incarnationNumbers).To(Equal([]uint16{2, 2, 3}))
+	})
+})
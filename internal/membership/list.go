@@ -4,17 +4,25 @@ import (
 	"errors"
 	"fmt"
 	"math"
-	"math/rand"
 	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-logr/logr"
 
+	"github.com/backbone81/membership/internal/admission"
+	"github.com/backbone81/membership/internal/broadcast"
 	"github.com/backbone81/membership/internal/encoding"
 	"github.com/backbone81/membership/internal/faultymember"
 	"github.com/backbone81/membership/internal/gossip"
+	"github.com/backbone81/membership/internal/memberindex"
+	"github.com/backbone81/membership/internal/passiveview"
+	"github.com/backbone81/membership/internal/query"
+	"github.com/backbone81/membership/internal/quorum"
 	"github.com/backbone81/membership/internal/randmember"
+	"github.com/backbone81/membership/internal/reconnect"
+	"github.com/backbone81/membership/internal/snapshot"
 	"github.com/backbone81/membership/internal/utility"
 )
 
@@ -48,29 +56,102 @@ type List struct {
 	// utility.IncarnationMax when dealing with incarnation numbers to correctly deal with wrap-around events.
 	incarnationNumber uint16
 
-	// members holds the list of members which are known to be alive or suspect. This list always needs to be sorted
-	// by address to allow for binary searches in this list. It can contain thousands of elements in big clusters.
-	members []encoding.Member
+	// healthy reports if the application health checks succeeded. While unhealthy, this member announces itself
+	// according to the configured health policy and does not refute any gossip about itself being suspect or faulty.
+	healthy bool
+
+	// unhealthyIncarnationNumber is the biggest incarnation number of any suspect or faulty gossip about ourselves
+	// which we did not refute while being unhealthy. On recovery, we refute with an incarnation number bigger than
+	// that.
+	unhealthyIncarnationNumber uint16
+
+	// members holds the members which are known to be alive or suspect. It provides lookups by address and the order
+	// in which members are picked for direct pings. It can contain millions of elements in big clusters.
+	members *memberindex.Index
+
+	// view is the latest view of members published for lock-free readers.
+	view atomic.Pointer[View]
+
+	// viewVersion is the version of the latest view published.
+	viewVersion uint64
+
+	// viewChunks holds the chunks of members the next view is published with. The slice itself is never shared, but
+	// the chunks are shared with the latest view until they are modified.
+	viewChunks [][]encoding.Member
+
+	// viewOwned reports for every chunk in viewChunks if it was copied since the latest view was published. Only those
+	// chunks can be modified in place. The space is re-used to reduce memory allocations.
+	viewOwned []bool
+
+	// viewLength is the number of members in viewChunks.
+	viewLength int
+
+	// viewChanges holds the addresses of the members which changed since the latest view was published.
+	viewChanges []encoding.Address
+
+	// viewRebuild reports if the next view needs to be built from all members instead of only the changed ones.
+	viewRebuild bool
 
 	// faultyMembers holds the list of members which were declared faulty. This is important for a full memberlist
 	// sync to allow information about faulty members to be transported.
 	faultyMembers *faultymember.List
 
-	// randomIndexes holds alist of random indexes into members. randomIndexes always has the same length as members and
-	// every index only occurs once. This helps in having an upper bound on picking random members for direct pings.
-	// If direct pings were truly random, there would be a slight chance that some member would never be picked as a
-	// direct ping target by any other member. By shuffling all available members and working through that list before
-	// shuffling again, we have a guarantee that in the works case after two sweeps through the member list each member
-	// was picked at least once.
-	randomIndexes []int
+	// forceRemovedMembers holds the list of members which were force removed. They are kept around longer than faulty
+	// members, to not re-add them through outdated gossip or bootstrap member reconnects.
+	forceRemovedMembers *faultymember.List
+
+	// admissionFilter decides with static network rules which unknown members may be added.
+	admissionFilter admission.Filter
 
-	// nextRandomIndex is the index into randomIndexes which describes the next random member to pick for direct pings.
-	// This index is always increasing and wraps around at the end of randomIndexes - triggering a re-shuffle.
-	nextRandomIndex int
+	// gossipSource is the member which sent the datagram currently being dispatched. It is reported as the source of
+	// rejected members.
+	gossipSource encoding.Address
 
 	// gossipQueue provides the priority queue for gossip messages to piggyback on pings and acks.
 	gossipQueue *gossip.Queue
 
+	// gossipEvictions is the number of messages the gossip queue evicted until the last list request which made up
+	// for them.
+	gossipEvictions int
+
+	// nextEvictionListRequest is the protocol period from which on the next list request for making up for evicted
+	// gossip is allowed.
+	nextEvictionListRequest int
+
+	// queryQueue holds the queries which still need to be piggybacked on pings and acks.
+	queryQueue *query.Queue
+
+	// queryHistory remembers the queries seen recently, to process and relay every query only once.
+	queryHistory *query.History
+
+	// queryFilterScratchSpace is temporary space for processing query messages. The space is re-used to reduce memory
+	// allocations.
+	queryFilterScratchSpace []encoding.Address
+
+	// eagerPushMembers holds the members broadcasts are pushed to eagerly. Together with the eager push members of all
+	// other members, they form the broadcast tree. All other members are lazy push members, which only receive
+	// announcements.
+	eagerPushMembers map[encoding.Address]struct{}
+
+	// broadcastCache remembers the broadcasts received recently, to deliver every broadcast only once and to answer
+	// grafts.
+	broadcastCache *broadcast.Cache
+
+	// missingBroadcasts keeps track of the broadcasts which were announced to us, but which we did not receive yet.
+	missingBroadcasts *broadcast.Missing
+
+	// announcementQueue holds the announcements of received broadcasts which still need to be piggybacked on pings and
+	// acks.
+	announcementQueue *broadcast.Queue
+
+	// graftScratchSpace is temporary space for collecting the grafts which are due at the end of a protocol period.
+	// The space is re-used to reduce memory allocations.
+	graftScratchSpace []broadcast.Graft
+
+	// broadcastBuffer is the buffer to write broadcasts into. It is separate from datagramBuffer, because broadcasts
+	// can be considerably bigger than a datagram.
+	broadcastBuffer []byte
+
 	// datagramBuffer is the buffer to write network messages into. We re-use the same buffer for every network message
 	// to reduce the amount of memory allocations happening. As access to this buffer is serialized on the top level,
 	// we do not need more than one buffer as we cannot have more than one network message at the same time.
@@ -98,6 +179,50 @@ type List struct {
 	// randomMemberPicker provides functionality for picking unique random members.
 	randomMemberPicker *randmember.Picker
 
+	// mergeScratchSpace is temporary space for collecting the members which joined our member list during a full
+	// member list sync. The space is re-used to reduce memory allocations.
+	mergeScratchSpace []encoding.Address
+
+	// syncScratchSpace is temporary space for collecting the members which are exchanged during a full member list
+	// sync. The space is re-used to reduce memory allocations.
+	syncScratchSpace []encoding.Member
+
+	// reconnectBackoff spreads out the reconnect attempts for bootstrap members and faulty members which did not
+	// respond.
+	reconnectBackoff *reconnect.Backoff
+
+	// quorumTracker keeps track of the cluster size to detect that we ended up in a minority partition.
+	quorumTracker *quorum.Tracker
+
+	// pendingReconnectProbes provides information about direct pings which were sent to faulty members to find out if
+	// they are reachable again. They end at the end of the current protocol period. This list will usually only contain
+	// a handful of elements and does not require special ordering.
+	pendingReconnectProbes []PendingDirectPing
+
+	// reconnectScratchSpace is temporary space for collecting the faulty members which are due for a reconnect probe.
+	// The space is re-used to reduce memory allocations.
+	reconnectScratchSpace []encoding.Member
+
+	// reconnectedBootstraps holds the bootstrap members which were re-added after dropping from the member list
+	// and did not respond to our list request yet.
+	reconnectedBootstraps map[encoding.Address]struct{}
+
+	// passiveView holds the members which replace failed members of the active view in partial view mode. The member
+	// list is the active view in that mode.
+	passiveView *passiveview.View
+
+	// pendingNeighborRequests holds the members we sent a join or neighbor request to in the current protocol period
+	// and which did not answer yet.
+	pendingNeighborRequests []encoding.Address
+
+	// shuffleScratchSpace is temporary space for processing shuffle messages. The space is re-used to reduce memory
+	// allocations.
+	shuffleScratchSpace []encoding.Address
+
+	// shuffleSampleScratchSpace is temporary space for collecting the sample of members we send with a shuffle. The
+	// space is re-used to reduce memory allocations.
+	shuffleSampleScratchSpace []encoding.Address
+
 	// listResponseScratchSpace is temporary space for processing list response messages. The space is re-used to reduce
 	// memory allocations.
 	listResponseScratchSpace []encoding.Member
@@ -114,6 +239,35 @@ type List struct {
 	// suspectCounters keeps track of the number of protocol periods a given member is a suspect. This helps in
 	// efficiently processing suspect members even in very large clusters.
 	suspectCounters map[encoding.Address]int
+
+	// suspectSince keeps track of the time a given member became suspect. It is used for measuring how long
+	// suspicions last.
+	suspectSince map[encoding.Address]time.Time
+
+	// lifecycles holds the timing information of every alive and suspect member.
+	lifecycles map[encoding.Address]*lifecycle
+
+	// lifecycleFreeList holds the lifecycles of removed members for re-use. This keeps members joining and leaving
+	// over and over again free of memory allocations.
+	lifecycleFreeList []*lifecycle
+
+	// protocolPeriod is the number of protocol periods since the list was created. It spaces out the member list
+	// requests which make up for evicted gossip.
+	protocolPeriod int
+
+	// snapshotPeriodCounter is the number of protocol periods since the last snapshot was written.
+	snapshotPeriodCounter int
+
+	// snapshotMutex serializes writing snapshots. It is separate from the list mutex, because we do not want to block
+	// the protocol while writing to disk.
+	snapshotMutex sync.Mutex
+
+	// snapshot is the snapshot which is re-used between writes to reduce memory allocations. Protected by
+	// snapshotMutex.
+	snapshot snapshot.Snapshot
+
+	// snapshotBuffer is the buffer which is re-used for encoding snapshots. Protected by snapshotMutex.
+	snapshotBuffer []byte
 }
 
 // NewList creates a new membership list.
@@ -133,6 +287,14 @@ func NewList(options ...Option) *List {
 		panic("you must provide a round trip time tracker")
 	}
 
+	if config.Metrics == nil {
+		// Metrics which are not registered anywhere are simply never collected.
+		config.Metrics = NewMetrics(nil)
+	}
+	if config.Clock == nil {
+		config.Clock = time.Now
+	}
+
 	if config.MaxDirectPingMemberCount < config.MinDirectPingMemberCount {
 		// The maximum is smaller than the minimum. Adjust the minimum to match the maximum.
 		config.MinDirectPingMemberCount = config.MaxDirectPingMemberCount
@@ -145,35 +307,89 @@ func NewList(options ...Option) *List {
 		// The default is bigger than the maximum. Adjust the default to match the maximum.
 		config.DirectPingMemberCount = config.MaxDirectPingMemberCount
 	}
+	if config.ActiveRandomWalkLength <= config.PassiveRandomWalkLength {
+		// The passive random walk is not shorter than the active random walk. Adjust it to end one hop earlier.
+		config.PassiveRandomWalkLength = max(0, config.ActiveRandomWalkLength-1)
+	}
 
 	newList := List{
 		config:                   config,
 		logger:                   config.Logger,
 		self:                     config.AdvertisedAddress,
-		gossipQueue:              gossip.NewQueue(),
+		incarnationNumber:        config.IncarnationNumber,
+		healthy:                  true,
+		admissionFilter:          admission.Filter{Allow: config.AllowedNetworks, Deny: config.DeniedNetworks},
+		queryQueue:               query.NewQueue(),
+		queryHistory:             query.NewHistory(),
+		eagerPushMembers:         make(map[encoding.Address]struct{}, config.EagerPushMemberCount),
+		broadcastCache:           broadcast.NewCache(),
+		missingBroadcasts:        broadcast.NewMissing(),
+		announcementQueue:        broadcast.NewQueue(),
 		datagramBuffer:           make([]byte, 0, config.MaxDatagramLengthSend),
-		members:                  make([]encoding.Member, 0, config.MemberPreAllocation),
+		members:                  memberindex.NewIndex(memberindex.WithPreAllocationCount(config.MemberPreAllocation)),
 		faultyMembers:            faultymember.NewList(faultymember.WithPreAllocationCount(config.MemberPreAllocation)),
+		forceRemovedMembers:      faultymember.NewList(faultymember.WithMaxListRequestCount(config.ForceRemoveListRequestCount)),
 		listResponseScratchSpace: make([]encoding.Member, 0, config.MemberPreAllocation),
 		pendingDirectPings:       make([]PendingDirectPing, 0, config.PendingPingPreAllocation),
 		pendingDirectPingsNext:   make([]PendingDirectPing, 0, config.PendingPingPreAllocation),
 		pendingIndirectPings:     make([]PendingIndirectPing, 0, config.PendingPingPreAllocation),
 		randomMemberPicker:       randmember.NewPicker(),
 		suspectCounters:          make(map[encoding.Address]int, config.MemberPreAllocation),
+		suspectSince:             make(map[encoding.Address]time.Time, config.MemberPreAllocation),
+		lifecycles:               make(map[encoding.Address]*lifecycle, config.MemberPreAllocation),
+		reconnectedBootstraps:    make(map[encoding.Address]struct{}, len(config.BootstrapMembers)),
+		passiveView:              passiveview.NewView(passiveview.WithMaxLength(config.PassiveViewSize)),
+		reconnectBackoff: reconnect.NewBackoff(
+			reconnect.WithMinDelay(config.ReconnectMinDelay),
+			reconnect.WithMaxDelay(config.ReconnectMaxDelay),
+		),
+		quorumTracker: quorum.NewTracker(
+			quorum.WithExpectedSize(config.ExpectedClusterSize),
+			quorum.WithFraction(config.QuorumFraction),
+			quorum.WithWindow(config.QuorumWindow),
+		),
+		gossipQueue: gossip.NewQueue(
+			gossip.WithMaxLength(config.MaxGossipQueueLength),
+			gossip.WithProtectedAddress(config.AdvertisedAddress),
+			gossip.WithMetrics(config.Metrics.Gossip),
+		),
+	}
+
+	if !config.Passive {
+		// We need to gossip our own alive. Otherwise, nobody will pick us up into their own member list.
+		newList.gossipQueue.Add(encoding.MessageAlive{
+			Destination:       config.AdvertisedAddress,
+			IncarnationNumber: config.IncarnationNumber,
+		}.ToMessage())
 	}
-
-	// We need to gossip our own alive. Otherwise, nobody will pick us up into their own member list.
-	newList.gossipQueue.Add(encoding.MessageAlive{
-		Destination:       config.AdvertisedAddress,
-		IncarnationNumber: 0,
-	}.ToMessage())
-	for _, initialMember := range config.BootstrapMembers {
-		newList.addMember(encoding.Member{
-			Address:           initialMember,
-			State:             encoding.MemberStateAlive,
-			IncarnationNumber: 0,
-		})
+	if config.PartialView {
+		// Bootstrap members are contacted with a join at the end of the first protocol period. Join candidates are
+		// potential neighbors.
+		for _, joinCandidate := range config.JoinCandidates {
+			if !joinCandidate.Equal(newList.self) {
+				newList.passiveView.Add(joinCandidate)
+			}
+		}
+	} else {
+		for _, initialMember := range config.BootstrapMembers {
+			newList.addMember(encoding.Member{
+				Address:           initialMember,
+				State:             encoding.MemberStateAlive,
+				IncarnationNumber: 0,
+			})
+		}
+		for _, joinCandidate := range config.JoinCandidates {
+			newList.addMember(encoding.Member{
+				Address:           joinCandidate,
+				State:             encoding.MemberStateAlive,
+				IncarnationNumber: 0,
+			})
+		}
 	}
+
+	// Readers expect a view to be available right away, even when we start without any members.
+	newList.viewRebuild = true
+	newList.publishView()
 	return &newList
 }
 
@@ -185,32 +401,28 @@ func (l *List) Config() Config {
 	return l.config
 }
 
-// Len returns the number of members which are currently alive or suspect.
+// Len returns the number of members which are currently alive or suspect. It reads the latest view and does not take
+// the lock of the membership list.
 func (l *List) Len() int {
-	l.mutex.Lock()
-	defer l.mutex.Unlock()
-
-	return len(l.members)
+	return l.View().Len()
 }
 
 // ForEach executes the given function for all address of all members stored in the list. The members are sorted by
 // address ascending. Return false to abort the iteration.
 //
-// While iterating over all members, the internal mutex is locked. Do not execute lengthy operations while iterating
-// over all members, as that will block processing of network messages. In addition, do not call any other method
-// of List during iteration, as that will cause a deadlock. Create your own copy of the member list and work on that
-// list if you need to execute long-running operations or need to call methods on List.
+// The iteration works on the latest view and does not take the lock of the membership list. Lengthy operations during
+// the iteration do not block processing of network messages, and other methods of List can be called. Changes to the
+// membership list during the iteration are not observed.
 //
 // Note that we are explicitly not providing a range over function type for iterating over all member addresses, because
 // that would cause memory allocations for the range over for loop, as it needs to introduce state which is allocated
 // on the heap. The solution with ForEach is less nice, but it allows for zero allocations.
 func (l *List) ForEach(fn func(encoding.Address) bool) {
-	l.mutex.Lock()
-	defer l.mutex.Unlock()
-
-	for _, member := range l.members {
-		if !fn(member.Address) {
-			return
+	for _, chunk := range l.View().chunks {
+		for _, member := range chunk {
+			if !fn(member.Address) {
+				return
+			}
 		}
 	}
 }
@@ -222,14 +434,16 @@ func (l *List) DirectPing() error {
 
 	// As we are supporting to directly ping multiple members, we need to make sure that we are not exceeding the
 	// current member count. Otherwise, we would ping the same member multiple times in the same protocol period.
-	for range min(len(l.members), l.config.DirectPingMemberCount) {
+	for range min(l.members.Len(), l.config.DirectPingMemberCount) {
 		directPing := encoding.MessageDirectPing{
 			Source:         l.self,
 			SequenceNumber: l.nextSequenceNumber,
 		}
 		l.nextSequenceNumber++
 
-		destination := l.getNextMember().Address
+		// The member index picks members randomly, but with an upper bound to prevent some members never being picked
+		// for a direct ping. In the worst case, a member is picked after two iterations of the member list.
+		destination := l.members.Next().Address
 		logger := l.logger.V(1)
 		if logger.Enabled() {
 			// We only spend the memory allocation for interface boxing of the key value pairs when the log level would
@@ -249,29 +463,13 @@ func (l *List) DirectPing() error {
 		if err := l.sendWithGossip(destination, directPing.ToMessage()); err != nil {
 			return err
 		}
+		if l.config.Observer != nil {
+			l.config.Observer.DirectPingSent(destination, directPing.SequenceNumber)
+		}
 	}
 	return nil
 }
 
-// getNextMember returns the next member to pick for a direct ping. This method ensures that the next member is selected
-// randomly but with an upper bound to prevent some members never being picket for a direct ping. This is done by having
-// a list of all indexes into the member list and shuffling that index list. Then we can move through that list one by
-// one and re-shuffle once we reach the end of the list. This ensures that in the worst case a member is picked after
-// two iterations of the member list.
-func (l *List) getNextMember() *encoding.Member {
-	if l.nextRandomIndex >= len(l.randomIndexes) {
-		// When we moved beyond the end of the list, re-shuffle the indices and reset back to the start of the list.
-		rand.Shuffle(len(l.randomIndexes), func(i, j int) {
-			l.randomIndexes[i], l.randomIndexes[j] = l.randomIndexes[j], l.randomIndexes[i]
-		})
-		l.nextRandomIndex = 0
-	}
-
-	randomIndex := l.nextRandomIndex
-	l.nextRandomIndex++
-	return &l.members[l.randomIndexes[randomIndex]]
-}
-
 // sendWithGossip sends the given network message to the given address, It fills up the remaining space in the datagram
 // with gossip from the gossip queue.
 func (l *List) sendWithGossip(address encoding.Address, message encoding.Message) error {
@@ -284,6 +482,15 @@ func (l *List) sendWithGossip(address encoding.Address, message encoding.Message
 		return err
 	}
 
+	// Queries go first, as they are rare and the initiator is waiting for responses. Queries are limited in size to
+	// always leave room for the message they are piggybacked on.
+	l.datagramBuffer = l.queryQueue.AppendToBuffer(l.datagramBuffer, l.config.MaxDatagramLengthSend)
+
+	// Announcements of broadcasts are next, as members waiting for a broadcast can only graft after they learned about
+	// it. Announcements are small and only gossiped a few times.
+	l.datagramBuffer = l.announcementQueue.AppendToBuffer(l.datagramBuffer, l.config.MaxDatagramLengthSend)
+	datagramN = len(l.datagramBuffer)
+
 	// Make sure that we send gossip about our destination first, to allow quicker refutation of suspects.
 	l.gossipQueue.Prioritize(address)
 
@@ -310,6 +517,9 @@ func (l *List) sendWithGossip(address encoding.Address, message encoding.Message
 		return err
 	}
 	l.gossipQueue.MarkTransmitted(gossipAdded)
+	if l.config.Observer != nil && gossipAdded > 0 {
+		l.config.Observer.GossipSent(address, gossipAdded)
+	}
 
 	if message.Type == encoding.MessageTypeDirectPing {
 		l.directPingGossipCount += gossipAdded
@@ -329,7 +539,7 @@ func (l *List) IndirectPing() error {
 	defer l.mutex.Unlock()
 
 	// An indirect ping only makes sense whe we have at least two members.
-	if len(l.members) < 2 {
+	if l.members.Len() < 2 {
 		return nil
 	}
 
@@ -355,7 +565,7 @@ func (l *List) IndirectPing() error {
 
 		// Send the indirect pings to the indirect ping members and join up all errors which might occur.
 		logger := l.logger.V(1)
-		l.randomMemberPicker.PickWithout(l.config.IndirectPingMemberCount, l.members, directPing.Destination, func(member encoding.Member) {
+		l.randomMemberPicker.PickWithout(l.config.IndirectPingMemberCount, l.members.Members(), directPing.Destination, func(member encoding.Member) {
 			if logger.Enabled() {
 				// We only spend the memory allocation for interface boxing of the key value pairs when the log level
 				// would actually produce this log entry.
@@ -382,12 +592,33 @@ func (l *List) IndirectPing() error {
 // EndOfProtocolPeriod is the last step in the SWIM protocol where we check which pings went unanswered, and we declare
 // as suspect or faulty which needs declaring.
 func (l *List) EndOfProtocolPeriod() error {
+	snapshotDue, err := l.endOfProtocolPeriod()
+	if snapshotDue {
+		// We write the snapshot after releasing the list lock, because writing to disk is slow and would otherwise
+		// block network processing.
+		err = errors.Join(err, l.WriteSnapshot())
+	}
+	return err
+}
+
+// endOfProtocolPeriod does the work of EndOfProtocolPeriod under the list lock. Reports if a snapshot is due.
+func (l *List) endOfProtocolPeriod() (bool, error) {
 	l.mutex.Lock()
 	defer l.mutex.Unlock()
+	defer l.publishView()
 
 	// Adjust the gossip queue to the potentially changed cluster size. Either keep gossip longer because of bigger
 	// cluster or keep gossip shorter, because of smaller cluster.
 	l.gossipQueue.SetMaxTransmissionCount(l.requiredDisseminationPeriods())
+	l.queryQueue.SetMaxTransmissionCount(l.requiredDisseminationPeriods())
+	l.announcementQueue.SetMaxTransmissionCount(l.requiredDisseminationPeriods())
+
+	// Queries are remembered twice as long as they are disseminated, to not process late copies a second time.
+	l.queryHistory.EndOfProtocolPeriod(2 * l.requiredDisseminationPeriods())
+
+	// Broadcasts are remembered twice as long as they are announced, to answer grafts of members which learned about
+	// them late.
+	l.broadcastCache.EndOfProtocolPeriod(2 * l.requiredDisseminationPeriods())
 
 	// We first process failed pings which lead to suspect declarations, and then mark suspects as faulty. This allows
 	// us to declare suspect and faulty within the same protocol period, if needed. This is helpful for tests and
@@ -395,14 +626,76 @@ func (l *List) EndOfProtocolPeriod() error {
 	l.processFailedPings()
 	l.markSuspectsAsFaulty()
 	l.adjustDirectPingMemberCount()
+	broadcastErr := l.graftMissingBroadcasts()
+
+	l.config.Metrics.MembersByState.WithLabelValues("alive").Set(float64(l.members.Len() - len(l.suspectCounters)))
+	l.config.Metrics.MembersByState.WithLabelValues("suspect").Set(float64(len(l.suspectCounters)))
+	l.config.Metrics.MembersByState.WithLabelValues("faulty").Set(float64(l.faultyMembers.Len()))
+	l.updateQuorum()
 
-	MembersByState.WithLabelValues("alive").Set(float64(len(l.members) - len(l.suspectCounters)))
-	MembersByState.WithLabelValues("suspect").Set(float64(len(l.suspectCounters)))
-	MembersByState.WithLabelValues("faulty").Set(float64(l.faultyMembers.Len()))
+	l.protocolPeriod++
+
+	snapshotDue := false
+	if l.config.SnapshotPath != "" {
+		l.snapshotPeriodCounter++
+		if l.snapshotPeriodCounter >= l.config.SnapshotInterval {
+			l.snapshotPeriodCounter = 0
+			snapshotDue = true
+		}
+	}
+
+	return snapshotDue, errors.Join(broadcastErr, l.compensateGossipEvictions(), l.reconnect())
+}
 
-	if err := l.reconnectBootstrapMembers(); err != nil {
+// WriteSnapshot writes all alive and suspect members as well as the tombstones of faulty members to the configured
+// snapshot file. The file is replaced atomically. Does nothing when no snapshot path is configured.
+//
+// The members are copied under the list lock, but the file is written after releasing the lock. That way the protocol
+// is not blocked by disk I/O.
+func (l *List) WriteSnapshot() error {
+	if l.config.SnapshotPath == "" {
+		return nil
+	}
+
+	l.snapshotMutex.Lock()
+	defer l.snapshotMutex.Unlock()
+
+	l.mutex.Lock()
+	l.snapshot.Timestamp = time.Now()
+	l.snapshot.Members = l.snapshot.Members[:0]
+	for _, member := range l.members.Members() {
+		snapshotMember := snapshot.Member{
+			Member: member,
+		}
+		if memberLifecycle, found := l.lifecycles[member.Address]; found {
+			snapshotMember.LastSeen = memberLifecycle.timestamps.lastSeen()
+		}
+		l.snapshot.Members = append(l.snapshot.Members, snapshotMember)
+	}
+	l.faultyMembers.ForEach(func(member encoding.Member) bool {
+		l.snapshot.Members = append(l.snapshot.Members, snapshot.Member{
+			Member: member,
+		})
+		return true
+	})
+	l.mutex.Unlock()
+
+	buffer, err := snapshot.WriteFile(l.config.SnapshotPath, &l.snapshot, l.snapshotBuffer)
+	l.snapshotBuffer = buffer
+	if err != nil {
 		return err
 	}
+
+	logger := l.logger.V(1)
+	if logger.Enabled() {
+		// We only spend the memory allocation for interface boxing of the key value pairs when the log level would
+		// actually produce this log entry.
+		logger.Info(
+			"Snapshot written",
+			"path", l.config.SnapshotPath,
+			"members", len(l.snapshot.Members),
+		)
+	}
 	return nil
 }
 
@@ -410,46 +703,35 @@ func (l *List) EndOfProtocolPeriod() error {
 // messages and for declaring as suspect as faulty. Note that a safety factor of 0 will always lead to 0 periods
 // causing instant suspect and faulty declarations.
 func (l *List) requiredDisseminationPeriods() int {
-	return int(math.Ceil(utility.DisseminationPeriods(l.config.SafetyFactor, len(l.members))))
+	return int(math.Ceil(utility.DisseminationPeriods(l.config.SafetyFactor, l.members.Len())))
 }
 
 // processFailedPings loops through all pending direct pings, marks members as suspect which did not answer to pings and
-// adds a gossip message about that suspect message.
+// adds a gossip message about that suspect message. With the phi accrual failure detector, a failed ping only raises
+// suspicion when phi exceeds the threshold. Failed pings of a passive member never raise suspicion.
 func (l *List) processFailedPings() {
 	for _, pendingDirectPings := range l.pendingDirectPings {
-		memberIndex, found := slices.BinarySearchFunc(
-			l.members,
-			encoding.Member{Address: pendingDirectPings.Destination},
-			encoding.CompareMember,
-		)
+		if l.config.Passive {
+			// A passive member does not influence the cluster. It learns about failed members through the gossip of
+			// the other members.
+			break
+		}
+		if l.config.FailureDetector == FailureDetectorPhiAccrual &&
+			!l.suspectedByPhiAccrual(pendingDirectPings.Destination) {
+			continue
+		}
+		member, found := l.members.Get(pendingDirectPings.Destination)
 		if !found {
 			// We probably got a faulty message by some other member while we were waiting for our ping to succeed.
 			// Nothing to do here.
 			continue
 		}
 
-		member := &l.members[memberIndex]
 		if member.State == encoding.MemberStateSuspect {
 			// The member is already suspect. Nothing to do.
 			continue
 		}
-
-		l.logger.Info(
-			"Member declared as suspect",
-			"source", l.self,
-			"destination", member.Address,
-			"incarnation-number", member.IncarnationNumber,
-		)
-		MemberStateTransitionsTotal.WithLabelValues("declared_suspect").Inc()
-
-		// We need to mark the member as suspect and gossip about it.
-		member.State = encoding.MemberStateSuspect
-		l.suspectCounters[member.Address] = 0
-		l.gossipQueue.Add(encoding.MessageSuspect{
-			Source:            l.self,
-			Destination:       member.Address,
-			IncarnationNumber: member.IncarnationNumber,
-		}.ToMessage())
+		l.declareSuspect(member)
 	}
 
 	// We swap the pending direct pings of the current protocol period with the pending direct pings of the next
@@ -458,10 +740,37 @@ func (l *List) processFailedPings() {
 	l.pendingDirectPings, l.pendingDirectPingsNext = l.pendingDirectPingsNext, l.pendingDirectPings[:0]
 
 	// As indirect pings always happen with a direct ping not being satisfied before, we can clear the indirect pings
-	// without any further actions, as those actions have already been taken on the pending direct pings.
+	// without any further actions, as those actions have already been taken on the pending direct pings. We only need
+	// to report them as failed.
+	if l.config.Observer != nil {
+		for _, pendingIndirectPing := range l.pendingIndirectPings {
+			l.config.Observer.IndirectPingCompleted(pendingIndirectPing.MessageIndirectPing.Destination, false)
+		}
+	}
 	l.pendingIndirectPings = l.pendingIndirectPings[:0]
 }
 
+// declareSuspect marks the given alive member as suspect and gossips about it.
+func (l *List) declareSuspect(member *encoding.Member) {
+	l.logger.Info(
+		"Member declared as suspect",
+		"source", l.self,
+		"destination", member.Address,
+		"incarnation-number", member.IncarnationNumber,
+	)
+	l.config.Metrics.MemberStateTransitionsTotal.WithLabelValues("declared_suspect").Inc()
+
+	// We need to mark the member as suspect and gossip about it.
+	member.State = encoding.MemberStateSuspect
+	l.memberStateChanged(*member)
+	l.suspectCounters[member.Address] = 0
+	l.gossipQueue.Add(encoding.MessageSuspect{
+		Source:            l.self,
+		Destination:       member.Address,
+		IncarnationNumber: member.IncarnationNumber,
+	}.ToMessage())
+}
+
 // markSuspectsAsFaulty loops through all members and increases the suspect counter on each suspect. It declares members
 // as faulty if they exceeded the suspicion threshold.
 func (l *List) markSuspectsAsFaulty() {
@@ -475,7 +784,7 @@ func (l *List) markSuspectsAsFaulty() {
 			continue
 		}
 
-		index, found := slices.BinarySearchFunc(l.members, encoding.Member{Address: address}, encoding.CompareMember)
+		member, found := l.members.Get(address)
 		if !found {
 			l.logger.Error(
 				errors.New("the suspect member could not be found - this should never happen and is a strong indication of a logic error"),
@@ -485,66 +794,28 @@ func (l *List) markSuspectsAsFaulty() {
 			continue
 		}
 
-		member := &l.members[index]
 		l.logger.Info(
 			"Member declared as faulty",
 			"source", l.self,
 			"destination", member.Address,
 			"incarnation-number", member.IncarnationNumber,
 		)
-		MemberStateTransitionsTotal.WithLabelValues("declared_faulty").Inc()
+		l.config.Metrics.MemberStateTransitionsTotal.WithLabelValues("declared_faulty").Inc()
 
 		member.State = encoding.MemberStateFaulty
 		delete(l.suspectCounters, address)
 		l.faultyMembers.Add(*member)
-		l.gossipQueue.Add(encoding.MessageFaulty{
-			Source:            l.self,
-			Destination:       member.Address,
-			IncarnationNumber: member.IncarnationNumber,
-		}.ToMessage())
-		l.removeMemberByIndex(index) // must always happen last to keep the member alive during this method
-	}
-}
-
-func (l *List) reconnectBootstrapMembers() error {
-	if !l.config.ReconnectBootstrapMembers {
-		return nil
-	}
-
-	var joinedErr error
-	for _, bootstrapMember := range l.config.BootstrapMembers {
-		if _, found := slices.BinarySearchFunc(
-			l.members,
-			encoding.Member{Address: bootstrapMember},
-			encoding.CompareMember,
-		); found {
-			// The bootstrap member is part of the members list. No need to re-add it.
-			continue
-		}
-		if _, found := l.faultyMembers.Get(bootstrapMember); found {
-			// The bootstrap member is part of the faulty members list. No need to re-add it.
-			continue
-		}
-
-		// The bootstrap member is not in members or faulty members. Re-add it to try to fix a network split.
-		l.logger.Info("Re-adding bootstrap member",
-			"destination", bootstrapMember,
-		)
-		l.addMember(encoding.Member{
-			Address:           bootstrapMember,
-			State:             encoding.MemberStateAlive,
-			IncarnationNumber: 0,
-		})
-
-		// We also request the full member list immediately to try and consolidate the two partitions as quickly as
-		// possible.
-		if err := l.sendWithGossip(bootstrapMember, encoding.MessageListRequest{
-			Source: l.self,
-		}.ToMessage()); err != nil {
-			joinedErr = errors.Join(joinedErr, err)
+		if !l.config.Passive {
+			// A passive member only follows the suspicion of the other members, it does not tell them about the
+			// outcome.
+			l.gossipQueue.Add(encoding.MessageFaulty{
+				Source:            l.self,
+				Destination:       member.Address,
+				IncarnationNumber: member.IncarnationNumber,
+			}.ToMessage())
 		}
+		l.removeMember(address) // must always happen last to keep the member alive during this method
 	}
-	return joinedErr
 }
 
 func (l *List) adjustDirectPingMemberCount() {
@@ -581,18 +852,25 @@ func (l *List) adjustDirectPingMemberCount() {
 // a full list of all alive, suspect and faulty members. The request is sent as a standard datagram with gossip, while
 // the response is returned as TCP message. This operation can be expensive in time and space and should be executed
 // at a much lower frequency compared to the standard SWIM actions.
+//
+// In partial view mode, RequestList shuffles the passive view instead, as there is no full member list to sync.
 func (l *List) RequestList() error {
 	l.mutex.Lock()
 	defer l.mutex.Unlock()
 
-	listRequest := encoding.MessageListRequest{
-		Source: l.self,
-	}.ToMessage()
+	return l.requestList()
+}
+
+// requestList requests the member list from a random member. The caller must hold the lock.
+func (l *List) requestList() error {
+	if l.config.PartialView {
+		return l.shuffle()
+	}
 
 	logger := l.logger.V(1)
 
 	var joinedErr error
-	l.randomMemberPicker.Pick(1, l.members, func(member encoding.Member) {
+	l.randomMemberPicker.Pick(1, l.members.Members(), func(member encoding.Member) {
 		if logger.Enabled() {
 			// We only spend the memory allocation for interface boxing of the key value pairs when the log level would
 			// actually produce this log entry.
@@ -601,13 +879,104 @@ func (l *List) RequestList() error {
 				"destination", member.Address,
 			)
 		}
-		if err := l.sendWithGossip(member.Address, listRequest); err != nil {
+		if err := l.sendListRequest(member.Address); err != nil {
 			joinedErr = errors.Join(joinedErr, err)
 		}
 	})
 	return joinedErr
 }
 
+// compensateGossipEvictions requests the member list from a random member when the gossip queue evicted messages
+// during the last protocol period. Evicted gossip might never reach some members, but a member list sync transports
+// the same information about alive, suspect and faulty members. With push-pull list sync, the other member also learns
+// about what we evicted. Without it, the other members make up for it with their own list requests. In partial view
+// mode, there is no member list to sync.
+//
+// A list request is made at most once per eviction list request interval. A single full member list sync makes up for
+// all evictions since the last one.
+func (l *List) compensateGossipEvictions() error {
+	evictions := l.gossipQueue.Evictions()
+	if evictions == l.gossipEvictions {
+		return nil
+	}
+	if l.config.PartialView {
+		l.gossipEvictions = evictions
+		return nil
+	}
+	if l.protocolPeriod < l.nextEvictionListRequest {
+		return nil
+	}
+	evicted := evictions - l.gossipEvictions
+	l.gossipEvictions = evictions
+	l.nextEvictionListRequest = l.protocolPeriod + l.config.EvictionListRequestInterval
+
+	l.logger.Info(
+		"Requesting member list to compensate for evicted gossip",
+		"evicted", evicted,
+	)
+	return l.requestList()
+}
+
+// sendListRequest sends a list request to the given address. Without push-pull list sync, the request is a small
+// datagram with gossip attached. With push-pull list sync, our own member list is attached to the request, which is
+// then sent over TCP like a list response.
+func (l *List) sendListRequest(address encoding.Address) error {
+	if !l.config.PushPullListSync {
+		return l.sendWithGossip(address, encoding.MessageListRequest{
+			Source: l.self,
+		}.ToMessage())
+	}
+
+	// We add ourselves to the members we push. That way the recipient learns about us within the same round trip,
+	// which helps new-joiners and members on the other side of a healed network partition.
+	members := l.syncMembers()
+	if !l.config.Passive {
+		members = append(members, encoding.Member{
+			Address:           l.self,
+			State:             encoding.MemberStateAlive,
+			IncarnationNumber: l.incarnationNumber,
+		})
+	}
+	listRequest := encoding.MessageListRequest{
+		Source:  l.self,
+		Members: members,
+	}
+	buffer, _, err := listRequest.AppendToBuffer(l.datagramBuffer[:0])
+	if err != nil {
+		return err
+	}
+
+	if err := l.config.TCPClient.Send(address, buffer); err != nil {
+		return err
+	}
+	return nil
+}
+
+// syncMembers returns all alive, suspect, faulty and force removed members which are exchanged with other members
+// during a full member list sync. Note that the returned slice is the sync scratch space. It must only be used until
+// syncMembers is called the next time.
+func (l *List) syncMembers() []encoding.Member {
+	members := append(l.syncScratchSpace[:0], l.members.Members()...)
+	l.faultyMembers.ForEach(func(member encoding.Member) bool {
+		if _, found := l.forceRemovedMembers.Get(member.Address); found {
+			// Force removed members are added below with their own state.
+			return true
+		}
+		members = append(members, member)
+		return true
+	})
+
+	// Force removed members go last. Members running an older version stop merging at the first unknown
+	// member state, which allows them to still merge all other members.
+	l.forceRemovedMembers.ForEach(func(member encoding.Member) bool {
+		member.State = encoding.MemberStateForceRemoved
+		members = append(members, member)
+		return true
+	})
+	l.syncScratchSpace = members
+	return members
+}
+
 // BroadcastShutdown is picking some members at random and sends those a faulty message about itself. This helps in
 // disseminating graceful shutdowns a lot quicker than waiting for a ping to fail and then to wait through a suspect
 // timeout.
@@ -615,6 +984,15 @@ func (l *List) BroadcastShutdown() error {
 	l.mutex.Lock()
 	defer l.mutex.Unlock()
 
+	if l.config.Passive {
+		// Nobody knows about us, so there is nobody to inform.
+		return nil
+	}
+	return l.broadcastFaultyForSelf()
+}
+
+// broadcastFaultyForSelf sends a faulty message about ourselves to some members picked at random.
+func (l *List) broadcastFaultyForSelf() error {
 	faultyMessage := encoding.MessageFaulty{
 		Source:            l.self,
 		Destination:       l.self,
@@ -623,7 +1001,7 @@ func (l *List) BroadcastShutdown() error {
 	logger := l.logger.V(1)
 
 	var joinedErr error
-	l.randomMemberPicker.Pick(l.config.ShutdownMemberCount, l.members, func(member encoding.Member) {
+	l.randomMemberPicker.Pick(l.config.ShutdownMemberCount, l.members.Members(), func(member encoding.Member) {
 		if logger.Enabled() {
 			// We only spend the memory allocation for interface boxing of the key value pairs when the log level would
 			// actually produce this log entry.
@@ -653,87 +1031,81 @@ func (l *List) addMember(member encoding.Member) {
 		"incarnation-number", member.IncarnationNumber,
 	)
 
-	// Insert the new member at the correct ordered location. Remember the index for later.
-	memberIndex, found := slices.BinarySearchFunc(
-		l.members,
-		member,
-		encoding.CompareMember,
-	)
-	if found {
-		// Update the existing member. Note that we do not count this towards the add member metric. Otherwise, the
+	l.viewChanged(member.Address)
+	if !l.members.Add(member) {
+		// We updated the existing member. Note that we do not count this towards the add member metric. Otherwise, the
 		// number of members could not be calculated by subtracting remove member metric from add member metric.
-		l.members[memberIndex] = member
 		return
 	}
-	l.members = slices.Insert(l.members, memberIndex, member)
-
-	// Fix the current indices to account for the inserted member.
-	for i := range l.randomIndexes {
-		if l.randomIndexes[i] < memberIndex {
-			continue
-		}
-		l.randomIndexes[i]++
-	}
-
-	// We pick a random location to insert the new member into the random indexes slice. We need to add +1 to the length
-	// of that slice to allow for appending at the end.
-	insertIndex := rand.Intn(len(l.randomIndexes) + 1) //nolint:gosec // we do not need crypto/rand here
-	l.randomIndexes = slices.Insert(l.randomIndexes, insertIndex, memberIndex)
-	if insertIndex <= l.nextRandomIndex {
-		// The new member index was inserted before or at the next random index. We therefore move the next random index
-		// forward by one to not have the same member be picked twice in a row.
-		l.nextRandomIndex++
-	}
+	l.lifecycleJoined(member)
+	l.addEagerPushMember(member.Address)
 
 	// Trigger the callback if set.
 	if l.config.MemberAddedCallback != nil {
 		l.config.MemberAddedCallback(member.Address)
 	}
-	MemberStateTransitionsTotal.WithLabelValues("added").Inc()
+	if member.State == encoding.MemberStateSuspect {
+		// Members are considered alive when added. We need to report when this is not the case.
+		l.memberStateChanged(member)
+	}
+	l.config.Metrics.MemberStateTransitionsTotal.WithLabelValues("added").Inc()
 }
 
-// removeMemberByAddress removes the member with the given address from the list of members. Updating the relevant
-// bookkeeping at the same time.
-func (l *List) removeMemberByAddress(address encoding.Address) {
-	index, found := slices.BinarySearchFunc(
-		l.members,
-		encoding.Member{Address: address},
-		encoding.CompareMember,
-	)
+// memberStateChanged triggers the callback and the observer for a member changing between alive and suspect if set.
+func (l *List) memberStateChanged(member encoding.Member) {
+	l.viewChanged(member.Address)
+	if member.State == encoding.MemberStateSuspect {
+		l.suspectSince[member.Address] = time.Now()
+	} else {
+		l.suspicionEnded(member.Address)
+	}
+	l.lifecycleStateChanged(member, time.Now())
+	if l.config.MemberStateChangedCallback != nil {
+		l.config.MemberStateChangedCallback(member.Address, member.State)
+	}
+	if l.config.Observer != nil {
+		if member.State == encoding.MemberStateSuspect {
+			l.config.Observer.SuspicionRaised(member.Address, member.IncarnationNumber)
+		} else {
+			l.config.Observer.SuspicionRefuted(member.Address, member.IncarnationNumber)
+		}
+	}
+}
+
+// suspicionEnded records how long the member with the given address was suspect, if it was suspect at all.
+func (l *List) suspicionEnded(address encoding.Address) {
+	since, found := l.suspectSince[address]
 	if !found {
 		return
 	}
-	l.removeMemberByIndex(index)
+	l.config.Metrics.SuspicionDurationSeconds.Observe(time.Since(since).Seconds())
+	delete(l.suspectSince, address)
 }
 
-// removeMemberByIndex removes the member with the given index from the list of members. Updating the relevant
-// bookkeeping at the same time.
-func (l *List) removeMemberByIndex(index int) {
+// removeMember removes the member with the given address from the list of members. Updating the relevant bookkeeping
+// at the same time.
+func (l *List) removeMember(address encoding.Address) {
+	member, found := l.members.Get(address)
+	if !found {
+		return
+	}
 	l.logger.Info(
 		"Member removed",
-		"address", l.members[index].Address,
-		"incarnation-number", l.members[index].IncarnationNumber,
+		"address", member.Address,
+		"incarnation-number", member.IncarnationNumber,
 	)
 
 	// Trigger the callback if set.
 	if l.config.MemberRemovedCallback != nil {
-		l.config.MemberRemovedCallback(l.members[index].Address)
+		l.config.MemberRemovedCallback(member.Address)
 	}
 
-	// If we remove the element from the slice, all indexes after that element need to be decremented by one.
-	var randomIndex int
-	for i := range l.randomIndexes {
-		if index < l.randomIndexes[i] {
-			l.randomIndexes[i]--
-		}
-		if index == l.randomIndexes[i] {
-			randomIndex = i
-		}
-	}
-
-	l.members = slices.Delete(l.members, index, index+1)
-	l.randomIndexes = slices.Delete(l.randomIndexes, randomIndex, randomIndex+1)
-	MemberStateTransitionsTotal.WithLabelValues("removed").Inc()
+	l.suspicionEnded(address)
+	l.lifecycleRemoved(address)
+	l.members.Remove(address)
+	l.replaceEagerPushMember(address)
+	l.viewChanged(address)
+	l.config.Metrics.MemberStateTransitionsTotal.WithLabelValues("removed").Inc()
 }
 
 // DispatchDatagram is the entrypoint which processes messages received by other members. The buffer provided as
@@ -744,8 +1116,13 @@ func (l *List) removeMemberByIndex(index int) {
 func (l *List) DispatchDatagram(buffer []byte) error {
 	l.mutex.Lock()
 	defer l.mutex.Unlock()
+	defer l.publishView()
+
+	// Gossip is piggybacked on other messages. We remember who sent us the datagram to report it for rejected members.
+	l.gossipSource = encoding.ZeroAddress
 
 	var joinedErr error
+	var gossipReceived int
 	for len(buffer) > 0 {
 		messageType, _, err := encoding.MessageTypeFromBuffer(buffer)
 		if err != nil {
@@ -754,87 +1131,107 @@ func (l *List) DispatchDatagram(buffer []byte) error {
 
 		switch messageType {
 		case encoding.MessageTypeDirectPing:
-			MessagesReceivedTotal.WithLabelValues("direct_ping").Inc()
+			l.config.Metrics.MessagesReceivedTotal.WithLabelValues("direct_ping").Inc()
 			var message encoding.MessageDirectPing
 			n, err := message.FromBuffer(buffer)
 			if err != nil {
 				return err
 			}
 			buffer = buffer[n:]
+			l.gossipSource = message.Source
 			if err := l.handleDirectPing(message); err != nil {
 				joinedErr = errors.Join(joinedErr, err)
 			}
-		case encoding.MessageTypeDirectAck:
-			MessagesReceivedTotal.WithLabelValues("direct_ack").Inc()
+		case encoding.MessageTypeDirectAck, encoding.MessageTypeDirectAckWithPayload:
+			l.config.Metrics.MessagesReceivedTotal.WithLabelValues("direct_ack").Inc()
 			var message encoding.MessageDirectAck
 			n, err := message.FromBuffer(buffer)
 			if err != nil {
 				return err
 			}
 			buffer = buffer[n:]
+			l.gossipSource = message.Source
 			if err := l.handleDirectAck(message); err != nil {
 				joinedErr = errors.Join(joinedErr, err)
 			}
 		case encoding.MessageTypeIndirectPing:
-			MessagesReceivedTotal.WithLabelValues("indirect_ping").Inc()
+			l.config.Metrics.MessagesReceivedTotal.WithLabelValues("indirect_ping").Inc()
 			var message encoding.MessageIndirectPing
 			n, err := message.FromBuffer(buffer)
 			if err != nil {
 				return err
 			}
 			buffer = buffer[n:]
+			l.gossipSource = message.Source
 			if err := l.handleIndirectPing(message); err != nil {
 				joinedErr = errors.Join(joinedErr, err)
 			}
-		case encoding.MessageTypeIndirectAck:
-			MessagesReceivedTotal.WithLabelValues("indirect_ack").Inc()
+		case encoding.MessageTypeIndirectAck, encoding.MessageTypeIndirectAckWithPayload:
+			l.config.Metrics.MessagesReceivedTotal.WithLabelValues("indirect_ack").Inc()
 			var message encoding.MessageIndirectAck
 			n, err := message.FromBuffer(buffer)
 			if err != nil {
 				return err
 			}
 			buffer = buffer[n:]
+			l.gossipSource = message.Source
 			l.handleIndirectAck(message)
 		case encoding.MessageTypeSuspect:
-			MessagesReceivedTotal.WithLabelValues("suspect").Inc()
+			l.config.Metrics.MessagesReceivedTotal.WithLabelValues("suspect").Inc()
 			var message encoding.MessageSuspect
 			n, err := message.FromBuffer(buffer)
 			if err != nil {
 				return err
 			}
 			buffer = buffer[n:]
+			gossipReceived++
 			l.handleSuspect(message)
 		case encoding.MessageTypeAlive:
-			MessagesReceivedTotal.WithLabelValues("alive").Inc()
+			l.config.Metrics.MessagesReceivedTotal.WithLabelValues("alive").Inc()
 			var message encoding.MessageAlive
 			n, err := message.FromBuffer(buffer)
 			if err != nil {
 				return err
 			}
 			buffer = buffer[n:]
+			gossipReceived++
 			l.handleAlive(message)
 		case encoding.MessageTypeFaulty:
-			MessagesReceivedTotal.WithLabelValues("faulty").Inc()
+			l.config.Metrics.MessagesReceivedTotal.WithLabelValues("faulty").Inc()
 			var message encoding.MessageFaulty
 			n, err := message.FromBuffer(buffer)
 			if err != nil {
 				return err
 			}
 			buffer = buffer[n:]
+			gossipReceived++
 			l.handleFaulty(message)
-		case encoding.MessageTypeListRequest:
-			MessagesReceivedTotal.WithLabelValues("list_request").Inc()
+		case encoding.MessageTypeForceRemove:
+			l.config.Metrics.MessagesReceivedTotal.WithLabelValues("force_remove").Inc()
+			var message encoding.MessageForceRemove
+			n, err := message.FromBuffer(buffer)
+			if err != nil {
+				return err
+			}
+			buffer = buffer[n:]
+			gossipReceived++
+			l.handleForceRemove(message)
+		case encoding.MessageTypeListRequest, encoding.MessageTypeListRequestWithMembers:
+			l.config.Metrics.MessagesReceivedTotal.WithLabelValues("list_request").Inc()
 			var message encoding.MessageListRequest
+			message.Members = l.listResponseScratchSpace
 			n, err := message.FromBuffer(buffer)
+			l.listResponseScratchSpace = message.Members
 			if err != nil {
 				return err
 			}
 			buffer = buffer[n:]
+			l.gossipSource = message.Source
 			if err := l.handleListRequest(message); err != nil {
 				joinedErr = errors.Join(joinedErr, err)
 			}
 		case encoding.MessageTypeListResponse:
-			MessagesReceivedTotal.WithLabelValues("list_response").Inc()
+			l.config.Metrics.MessagesReceivedTotal.WithLabelValues("list_response").Inc()
 			var message encoding.MessageListResponse
 			message.Members = l.listResponseScratchSpace
 			n, err := message.FromBuffer(buffer)
@@ -843,16 +1240,190 @@ func (l *List) DispatchDatagram(buffer []byte) error {
 				return err
 			}
 			buffer = buffer[n:]
+			l.gossipSource = message.Source
 			if err := l.handleListResponse(message); err != nil {
 				joinedErr = errors.Join(joinedErr, err)
 			}
+		case encoding.MessageTypeQuery:
+			l.config.Metrics.MessagesReceivedTotal.WithLabelValues("query").Inc()
+			var message encoding.MessageQuery
+			message.Filter = l.queryFilterScratchSpace
+			n, err := message.FromBuffer(buffer)
+			l.queryFilterScratchSpace = message.Filter
+			if err != nil {
+				return err
+			}
+			buffer = buffer[n:]
+			l.gossipSource = message.Source
+			if err := l.handleQuery(message); err != nil {
+				joinedErr = errors.Join(joinedErr, err)
+			}
+		case encoding.MessageTypeQueryAck:
+			l.config.Metrics.MessagesReceivedTotal.WithLabelValues("query_ack").Inc()
+			var message encoding.MessageQueryAck
+			n, err := message.FromBuffer(buffer)
+			if err != nil {
+				return err
+			}
+			buffer = buffer[n:]
+			l.gossipSource = message.Source
+			l.handleQueryAck(message)
+		case encoding.MessageTypeQueryResponse:
+			l.config.Metrics.MessagesReceivedTotal.WithLabelValues("query_response").Inc()
+			var message encoding.MessageQueryResponse
+			n, err := message.FromBuffer(buffer)
+			if err != nil {
+				return err
+			}
+			buffer = buffer[n:]
+			l.gossipSource = message.Source
+			l.handleQueryResponse(message)
+		case encoding.MessageTypeJoin:
+			l.config.Metrics.MessagesReceivedTotal.WithLabelValues("join").Inc()
+			var message encoding.MessageJoin
+			n, err := message.FromBuffer(buffer)
+			if err != nil {
+				return err
+			}
+			buffer = buffer[n:]
+			l.gossipSource = message.Source
+			if err := l.handleJoin(message); err != nil {
+				joinedErr = errors.Join(joinedErr, err)
+			}
+		case encoding.MessageTypeForwardJoin:
+			l.config.Metrics.MessagesReceivedTotal.WithLabelValues("forward_join").Inc()
+			var message encoding.MessageForwardJoin
+			n, err := message.FromBuffer(buffer)
+			if err != nil {
+				return err
+			}
+			buffer = buffer[n:]
+			l.gossipSource = message.Source
+			if err := l.handleForwardJoin(message); err != nil {
+				joinedErr = errors.Join(joinedErr, err)
+			}
+		case encoding.MessageTypeNeighbor:
+			l.config.Metrics.MessagesReceivedTotal.WithLabelValues("neighbor").Inc()
+			var message encoding.MessageNeighbor
+			n, err := message.FromBuffer(buffer)
+			if err != nil {
+				return err
+			}
+			buffer = buffer[n:]
+			l.gossipSource = message.Source
+			if err := l.handleNeighbor(message); err != nil {
+				joinedErr = errors.Join(joinedErr, err)
+			}
+		case encoding.MessageTypeNeighborReply:
+			l.config.Metrics.MessagesReceivedTotal.WithLabelValues("neighbor_reply").Inc()
+			var message encoding.MessageNeighborReply
+			n, err := message.FromBuffer(buffer)
+			if err != nil {
+				return err
+			}
+			buffer = buffer[n:]
+			l.gossipSource = message.Source
+			if err := l.handleNeighborReply(message); err != nil {
+				joinedErr = errors.Join(joinedErr, err)
+			}
+		case encoding.MessageTypeDisconnect:
+			l.config.Metrics.MessagesReceivedTotal.WithLabelValues("disconnect").Inc()
+			var message encoding.MessageDisconnect
+			n, err := message.FromBuffer(buffer)
+			if err != nil {
+				return err
+			}
+			buffer = buffer[n:]
+			l.gossipSource = message.Source
+			l.handleDisconnect(message)
+		case encoding.MessageTypeShuffle:
+			l.config.Metrics.MessagesReceivedTotal.WithLabelValues("shuffle").Inc()
+			var message encoding.MessageShuffle
+			message.Addresses = l.shuffleScratchSpace
+			n, err := message.FromBuffer(buffer)
+			l.shuffleScratchSpace = message.Addresses
+			if err != nil {
+				return err
+			}
+			buffer = buffer[n:]
+			l.gossipSource = message.Source
+			if err := l.handleShuffle(message); err != nil {
+				joinedErr = errors.Join(joinedErr, err)
+			}
+		case encoding.MessageTypeShuffleReply:
+			l.config.Metrics.MessagesReceivedTotal.WithLabelValues("shuffle_reply").Inc()
+			var message encoding.MessageShuffleReply
+			message.Addresses = l.shuffleScratchSpace
+			n, err := message.FromBuffer(buffer)
+			l.shuffleScratchSpace = message.Addresses
+			if err != nil {
+				return err
+			}
+			buffer = buffer[n:]
+			l.gossipSource = message.Source
+			l.handleShuffleReply(message)
+		case encoding.MessageTypeBroadcast:
+			l.config.Metrics.MessagesReceivedTotal.WithLabelValues("broadcast").Inc()
+			var message encoding.MessageBroadcast
+			n, err := message.FromBuffer(buffer)
+			if err != nil {
+				return err
+			}
+			buffer = buffer[n:]
+			l.gossipSource = message.Source
+			if err := l.handleBroadcast(message); err != nil {
+				joinedErr = errors.Join(joinedErr, err)
+			}
+		case encoding.MessageTypeIHave:
+			l.config.Metrics.MessagesReceivedTotal.WithLabelValues("ihave").Inc()
+			var message encoding.MessageIHave
+			n, err := message.FromBuffer(buffer)
+			if err != nil {
+				return err
+			}
+			buffer = buffer[n:]
+			l.gossipSource = message.Source
+			l.handleIHave(message)
+		case encoding.MessageTypeGraft:
+			l.config.Metrics.MessagesReceivedTotal.WithLabelValues("graft").Inc()
+			var message encoding.MessageGraft
+			n, err := message.FromBuffer(buffer)
+			if err != nil {
+				return err
+			}
+			buffer = buffer[n:]
+			l.gossipSource = message.Source
+			if err := l.handleGraft(message); err != nil {
+				joinedErr = errors.Join(joinedErr, err)
+			}
+		case encoding.MessageTypePrune:
+			l.config.Metrics.MessagesReceivedTotal.WithLabelValues("prune").Inc()
+			var message encoding.MessagePrune
+			n, err := message.FromBuffer(buffer)
+			if err != nil {
+				return err
+			}
+			buffer = buffer[n:]
+			l.gossipSource = message.Source
+			l.handlePrune(message)
 		default:
+			// We do not know the length of a message we do not understand, so we cannot continue with the messages
+			// following it. This happens with messages introduced by newer versions during a rolling upgrade. We drop
+			// the rest of the datagram but keep what we processed so far.
 			l.logger.Error(
 				fmt.Errorf("unknown message type %d", messageType),
 				"The network message has an unknown message type.",
 			)
+			buffer = nil
 		}
 	}
+	if l.config.Observer != nil && gossipReceived > 0 {
+		l.config.Observer.GossipReceived(l.gossipSource, gossipReceived)
+	}
+	if !l.gossipSource.IsZero() {
+		// Every datagram a member sends us is a sign of life.
+		l.recordArrival(l.gossipSource)
+	}
 	return joinedErr
 }
 
@@ -867,14 +1438,27 @@ func (l *List) handleDirectPing(directPing encoding.MessageDirectPing) error {
 			"sequence-number", directPing.SequenceNumber,
 		)
 	}
+	if !l.healthy && l.config.HealthPolicy != HealthPolicyDegraded {
+		// We are unhealthy and want the other members to detect us as failed. We therefore stay silent.
+		return nil
+	}
 	directAck := encoding.MessageDirectAck{
 		Source:         l.self,
 		SequenceNumber: directPing.SequenceNumber,
 	}
+	var payloadErr error
+	if l.config.AckPayloadCallback != nil {
+		directAck.Payload = l.config.AckPayloadCallback()
+		if len(directAck.Payload) > l.config.MaxAckPayloadLength {
+			// We still need to acknowledge the ping, otherwise we would be suspected for a misbehaving callback.
+			payloadErr = fmt.Errorf("ack payload of %d bytes exceeds the maximum of %d bytes", len(directAck.Payload), l.config.MaxAckPayloadLength)
+			directAck.Payload = nil
+		}
+	}
 	if err := l.sendWithGossip(directPing.Source, directAck.ToMessage()); err != nil {
-		return err
+		return errors.Join(payloadErr, err)
 	}
-	return nil
+	return payloadErr
 }
 
 func (l *List) handleDirectAck(directAck encoding.MessageDirectAck) error {
@@ -888,7 +1472,11 @@ func (l *List) handleDirectAck(directAck encoding.MessageDirectAck) error {
 			"sequence-number", directAck.SequenceNumber,
 		)
 	}
+	l.lifecycleAckReceived(directAck.Source, directAck.Payload)
 	l.handleDirectAckForPendingIndirectPings(directAck)
+	if err := l.handleDirectAckForReconnectProbes(directAck); err != nil {
+		return err
+	}
 	var err error
 	l.pendingDirectPings, err = l.handleDirectAckForPendingDirectPings(l.pendingDirectPings, directAck)
 	if err != nil {
@@ -914,8 +1502,19 @@ func (l *List) handleDirectAckForPendingDirectPings(pendingDirectPings []Pending
 	pendingDirectPing := pendingDirectPings[pendingDirectPingIndex]
 	pendingDirectPings = utility.SwapDelete(pendingDirectPings, pendingDirectPingIndex)
 
+	// A member which responds is reachable. Any earlier reconnect attempts do not count against it anymore.
+	l.reconnectBackoff.Succeeded(pendingDirectPing.Destination)
+
 	// We note down the round trip time for the direct ping.
-	l.config.RoundTripTimeTracker.AddObserved(time.Since(pendingDirectPing.Timestamp))
+	roundTripTime := time.Since(pendingDirectPing.Timestamp)
+	l.config.RoundTripTimeTracker.AddObserved(roundTripTime)
+	l.config.Metrics.RoundTripTimeSeconds.Observe(roundTripTime.Seconds())
+	if l.config.MemberRoundTripTimeCallback != nil {
+		l.config.MemberRoundTripTimeCallback(pendingDirectPing.Destination, roundTripTime)
+	}
+	if l.config.Observer != nil {
+		l.config.Observer.DirectAckReceived(pendingDirectPing.Destination, roundTripTime)
+	}
 
 	if pendingDirectPing.MessageIndirectPing.IsZero() {
 		// The direct ping was NOT done in a response to a request for an indirect ping, so we are done here.
@@ -925,6 +1524,7 @@ func (l *List) handleDirectAckForPendingDirectPings(pendingDirectPings []Pending
 	indirectAck := encoding.MessageIndirectAck{
 		Source:         directAck.Source,
 		SequenceNumber: pendingDirectPing.MessageIndirectPing.SequenceNumber,
+		Payload:        directAck.Payload,
 	}
 	if err := l.sendWithGossip(pendingDirectPing.MessageIndirectPing.Source, indirectAck.ToMessage()); err != nil {
 		return pendingDirectPings, err
@@ -941,6 +1541,9 @@ func (l *List) handleDirectAckForPendingIndirectPings(directAck encoding.Message
 	if pendingIndirectPingIndex == -1 {
 		return
 	}
+	if l.config.Observer != nil {
+		l.config.Observer.IndirectPingCompleted(directAck.Source, true)
+	}
 	l.pendingIndirectPings = utility.SwapDelete(l.pendingIndirectPings, pendingIndirectPingIndex)
 }
 
@@ -972,6 +1575,9 @@ func (l *List) handleIndirectPing(indirectPing encoding.MessageIndirectPing) err
 	if err := l.sendWithGossip(indirectPing.Destination, directPing.ToMessage()); err != nil {
 		return err
 	}
+	if l.config.Observer != nil {
+		l.config.Observer.DirectPingSent(indirectPing.Destination, directPing.SequenceNumber)
+	}
 	return nil
 }
 
@@ -986,6 +1592,7 @@ func (l *List) handleIndirectAck(indirectAck encoding.MessageIndirectAck) {
 			"sequence-number", indirectAck.SequenceNumber,
 		)
 	}
+	l.lifecycleAckReceived(indirectAck.Source, indirectAck.Payload)
 	l.handleIndirectAckForPendingDirectPings(indirectAck)
 	l.handleIndirectAckForPendingIndirectPings(indirectAck)
 }
@@ -1019,6 +1626,9 @@ func (l *List) handleIndirectAckForPendingIndirectPings(indirectAck encoding.Mes
 	l.config.RoundTripTimeTracker.AddObserved(observedRoundTrip)
 	l.config.RoundTripTimeTracker.AddObserved(observedRoundTrip)
 
+	if l.config.Observer != nil {
+		l.config.Observer.IndirectPingCompleted(indirectAck.Source, true)
+	}
 	l.pendingIndirectPings = utility.SwapDelete(l.pendingIndirectPings, pendingIndirectPingIndex)
 }
 
@@ -1034,9 +1644,17 @@ func (l *List) handleSuspect(suspect encoding.MessageSuspect) {
 			"incarnation-number", suspect.IncarnationNumber,
 		)
 	}
+	l.lifecycleGossipReceived(suspect.Destination)
 	if l.handleSuspectForSelf(suspect) {
 		return
 	}
+	if l.handleForceRemovedMembers(suspect.Destination, suspect.IncarnationNumber) {
+		return
+	}
+	if l.config.PartialView && !l.members.Contains(suspect.Destination) {
+		// We only keep track of suspects in our active view.
+		return
+	}
 	if l.handleSuspectForFaultyMembers(suspect) {
 		return
 	}
@@ -1050,15 +1668,30 @@ func (l *List) handleSuspectForSelf(suspect encoding.MessageSuspect) bool {
 	if !suspect.Destination.Equal(l.self) {
 		return false
 	}
+	if l.config.Passive {
+		// We are not part of the member list of other members. There is nothing to refute.
+		return true
+	}
 
 	if utility.IncarnationLessThan(suspect.IncarnationNumber, l.incarnationNumber) {
 		// We have a more up-to-date state than the gossip. Nothing to do.
 		return true
 	}
 
+	if !l.healthy {
+		// We must not refute while being unhealthy. We remember the incarnation number to refute once we recover.
+		l.unhealthyIncarnationNumber = utility.IncarnationMax(l.unhealthyIncarnationNumber, suspect.IncarnationNumber)
+		return true
+	}
+
 	// We need to refute the suspect about ourselves. Add a new alive message to gossip.
 	// Also make sure that our incarnation number is bigger than before.
-	l.incarnationNumber = utility.IncarnationMax(l.incarnationNumber+1, suspect.IncarnationNumber+1)
+	if err := l.setIncarnationNumber(utility.IncarnationMax(l.incarnationNumber+1, suspect.IncarnationNumber+1)); err != nil {
+		// We do not refute with an incarnation number we might use again after a restart. The next gossip about us
+		// gives us another chance.
+		l.logger.Error(err, "Refuting gossip about self")
+		return true
+	}
 	l.gossipQueue.Add(encoding.MessageAlive{
 		Destination:       l.self,
 		IncarnationNumber: l.incarnationNumber,
@@ -1068,10 +1701,27 @@ func (l *List) handleSuspectForSelf(suspect encoding.MessageSuspect) bool {
 		"Refuted gossip about being suspect",
 		"incarnation-number", l.incarnationNumber,
 	)
-	MemberStateTransitionsTotal.WithLabelValues("refuted_suspect").Inc()
+	l.config.Metrics.MemberStateTransitionsTotal.WithLabelValues("refuted_suspect").Inc()
+	if l.config.Observer != nil {
+		l.config.Observer.SuspicionRefuted(l.self, l.incarnationNumber)
+	}
 	return true
 }
 
+// setIncarnationNumber persists the given incarnation number to the incarnation store, if one is configured, and uses it
+// from then on. Persisting must happen before the new incarnation number is gossiped, to make sure that we never resume
+// with an incarnation number which the cluster has already seen. When persisting fails, the incarnation number is left
+// unchanged and the caller must not gossip the new one.
+func (l *List) setIncarnationNumber(incarnationNumber uint16) error {
+	if l.config.IncarnationStore != nil {
+		if err := l.config.IncarnationStore.Save(incarnationNumber); err != nil {
+			return fmt.Errorf("persisting incarnation number %d: %w", incarnationNumber, err)
+		}
+	}
+	l.incarnationNumber = incarnationNumber
+	return nil
+}
+
 func (l *List) handleSuspectForFaultyMembers(suspect encoding.MessageSuspect) bool {
 	faultyMember, found := l.faultyMembers.Get(suspect.Destination)
 	if !found {
@@ -1097,23 +1747,21 @@ func (l *List) handleSuspectForFaultyMembers(suspect encoding.MessageSuspect) bo
 }
 
 func (l *List) handleSuspectForMembers(suspect encoding.MessageSuspect) bool {
-	memberIndex, found := slices.BinarySearchFunc(
-		l.members,
-		encoding.Member{Address: suspect.Destination},
-		encoding.CompareMember,
-	)
+	member, found := l.members.Get(suspect.Destination)
 	if !found {
 		// The member is not part of our members list. Nothing to do.
 		return false
 	}
-	member := &l.members[memberIndex]
 
 	if utility.IncarnationLessThan(suspect.IncarnationNumber, member.IncarnationNumber) {
 		// We have more up-to-date information about this member.
 		return true
 	}
 
-	member.IncarnationNumber = suspect.IncarnationNumber
+	if member.IncarnationNumber != suspect.IncarnationNumber {
+		member.IncarnationNumber = suspect.IncarnationNumber
+		l.viewChanged(member.Address)
+	}
 	if member.State == encoding.MemberStateSuspect {
 		// We already know about this member being suspect. Nothing to do.
 		return true
@@ -1121,12 +1769,17 @@ func (l *List) handleSuspectForMembers(suspect encoding.MessageSuspect) bool {
 
 	// This information is new to us, we need to make sure to gossip about it.
 	member.State = encoding.MemberStateSuspect
+	l.memberStateChanged(*member)
 	l.suspectCounters[suspect.Destination] = 0
 	l.gossipQueue.Add(suspect.ToMessage())
 	return true
 }
 
 func (l *List) handleSuspectForUnknown(suspect encoding.MessageSuspect) {
+	if !l.admitMember(suspect.Destination) {
+		return
+	}
+
 	// We don't know about this member yet. Add it to our member list and gossip about it.
 	l.addMember(encoding.Member{
 		Address:           suspect.Destination,
@@ -1148,9 +1801,18 @@ func (l *List) handleAlive(alive encoding.MessageAlive) {
 			"incarnation-number", alive.IncarnationNumber,
 		)
 	}
+	l.lifecycleGossipReceived(alive.Destination)
+	l.recordArrival(alive.Destination)
 	if l.handleAliveForSelf(alive) {
 		return
 	}
+	if l.handleForceRemovedMembers(alive.Destination, alive.IncarnationNumber) {
+		return
+	}
+	if l.config.PartialView && !l.members.Contains(alive.Destination) {
+		l.handleAliveForPartialView(alive)
+		return
+	}
 	if l.handleAliveForFaultyMembers(alive) {
 		return
 	}
@@ -1164,6 +1826,10 @@ func (l *List) handleAliveForSelf(alive encoding.MessageAlive) bool {
 	if !alive.Destination.Equal(l.self) {
 		return false
 	}
+	if l.config.Passive {
+		// We are not part of the member list of other members. There is nothing to refute.
+		return true
+	}
 
 	if alive.IncarnationNumber == l.incarnationNumber ||
 		utility.IncarnationLessThan(alive.IncarnationNumber, l.incarnationNumber) {
@@ -1173,7 +1839,12 @@ func (l *List) handleAliveForSelf(alive encoding.MessageAlive) bool {
 
 	// We need to update the incarnation number about ourselves. Add a new alive message to gossip.
 	// Also make sure that our incarnation number is bigger than before.
-	l.incarnationNumber = utility.IncarnationMax(l.incarnationNumber+1, alive.IncarnationNumber+1)
+	if err := l.setIncarnationNumber(utility.IncarnationMax(l.incarnationNumber+1, alive.IncarnationNumber+1)); err != nil {
+		// We do not refute with an incarnation number we might use again after a restart. The next gossip about us
+		// gives us another chance.
+		l.logger.Error(err, "Refuting gossip about self")
+		return true
+	}
 	l.gossipQueue.Add(encoding.MessageAlive{
 		Destination:       l.self,
 		IncarnationNumber: l.incarnationNumber,
@@ -1183,7 +1854,7 @@ func (l *List) handleAliveForSelf(alive encoding.MessageAlive) bool {
 		"Refuted gossip about being alive",
 		"incarnation-number", l.incarnationNumber,
 	)
-	MemberStateTransitionsTotal.WithLabelValues("refuted_alive").Inc()
+	l.config.Metrics.MemberStateTransitionsTotal.WithLabelValues("refuted_alive").Inc()
 	return true
 }
 
@@ -1211,16 +1882,11 @@ func (l *List) handleAliveForFaultyMembers(alive encoding.MessageAlive) bool {
 }
 
 func (l *List) handleAliveForMembers(alive encoding.MessageAlive) bool {
-	memberIndex, found := slices.BinarySearchFunc(
-		l.members,
-		encoding.Member{Address: alive.Destination},
-		encoding.CompareMember,
-	)
+	member, found := l.members.Get(alive.Destination)
 	if !found {
 		// The member is not part of our members list. Nothing to do.
 		return false
 	}
-	member := &l.members[memberIndex]
 
 	if !utility.IncarnationLessThan(member.IncarnationNumber, alive.IncarnationNumber) {
 		// We have more up-to-date information about this member.
@@ -1228,6 +1894,7 @@ func (l *List) handleAliveForMembers(alive encoding.MessageAlive) bool {
 	}
 
 	member.IncarnationNumber = alive.IncarnationNumber
+	l.viewChanged(member.Address)
 	if member.State == encoding.MemberStateAlive {
 		// We already know about this member being alive. Nothing to do.
 		return true
@@ -1235,12 +1902,17 @@ func (l *List) handleAliveForMembers(alive encoding.MessageAlive) bool {
 
 	// This information is new to us, we need to make sure to gossip about it.
 	member.State = encoding.MemberStateAlive
+	l.memberStateChanged(*member)
 	delete(l.suspectCounters, member.Address)
 	l.gossipQueue.Add(alive.ToMessage())
 	return true
 }
 
 func (l *List) handleAliveForUnknown(alive encoding.MessageAlive) {
+	if !l.admitMember(alive.Destination) {
+		return
+	}
+
 	// We don't know about this member yet. Add it to our member list and gossip about it.
 	l.addMember(encoding.Member{
 		Address:           alive.Destination,
@@ -1262,9 +1934,12 @@ func (l *List) handleFaulty(faulty encoding.MessageFaulty) {
 			"incarnation-number", faulty.IncarnationNumber,
 		)
 	}
+	l.lifecycleGossipReceived(faulty.Destination)
 	if l.handleFaultyForSelf(faulty) {
 		return
 	}
+	// A faulty member is no replacement candidate for the active view.
+	l.passiveView.Remove(faulty.Destination)
 	if l.handleFaultyForFaultyMembers(faulty) {
 		return
 	}
@@ -1278,15 +1953,30 @@ func (l *List) handleFaultyForSelf(faulty encoding.MessageFaulty) bool {
 	if !faulty.Destination.Equal(l.self) {
 		return false
 	}
+	if l.config.Passive {
+		// We are not part of the member list of other members. There is nothing to refute.
+		return true
+	}
 
 	if utility.IncarnationLessThan(faulty.IncarnationNumber, l.incarnationNumber) {
 		// We have a more up-to-date state than the gossip. Nothing to do.
 		return true
 	}
 
+	if !l.healthy {
+		// We must not refute while being unhealthy. We remember the incarnation number to refute once we recover.
+		l.unhealthyIncarnationNumber = utility.IncarnationMax(l.unhealthyIncarnationNumber, faulty.IncarnationNumber)
+		return true
+	}
+
 	// We need to re-join. Add a new alive message to gossip.
 	// Also make sure that our incarnation number is bigger than before.
-	l.incarnationNumber = utility.IncarnationMax(l.incarnationNumber+1, faulty.IncarnationNumber+1)
+	if err := l.setIncarnationNumber(utility.IncarnationMax(l.incarnationNumber+1, faulty.IncarnationNumber+1)); err != nil {
+		// We do not refute with an incarnation number we might use again after a restart. The next gossip about us
+		// gives us another chance.
+		l.logger.Error(err, "Refuting gossip about self")
+		return true
+	}
 	l.gossipQueue.Add(encoding.MessageAlive{
 		Destination:       l.self,
 		IncarnationNumber: l.incarnationNumber,
@@ -1296,7 +1986,7 @@ func (l *List) handleFaultyForSelf(faulty encoding.MessageFaulty) bool {
 		"Refuted gossip about being faulty",
 		"incarnation-number", l.incarnationNumber,
 	)
-	MemberStateTransitionsTotal.WithLabelValues("refuted_faulty").Inc()
+	l.config.Metrics.MemberStateTransitionsTotal.WithLabelValues("refuted_faulty").Inc()
 	return true
 }
 
@@ -1319,16 +2009,11 @@ func (l *List) handleFaultyForFaultyMembers(faulty encoding.MessageFaulty) bool
 }
 
 func (l *List) handleFaultyForMembers(faulty encoding.MessageFaulty) bool {
-	memberIndex, found := slices.BinarySearchFunc(
-		l.members,
-		encoding.Member{Address: faulty.Destination},
-		encoding.CompareMember,
-	)
+	member, found := l.members.Get(faulty.Destination)
 	if !found {
 		// The member is not part of our member list. Nothing to do.
 		return false
 	}
-	member := &l.members[memberIndex]
 
 	if utility.IncarnationLessThan(faulty.IncarnationNumber, member.IncarnationNumber) {
 		// We have more up-to-date information about this member.
@@ -1341,11 +2026,15 @@ func (l *List) handleFaultyForMembers(faulty encoding.MessageFaulty) bool {
 	delete(l.suspectCounters, member.Address)
 	l.faultyMembers.Add(*member)
 	l.gossipQueue.Add(faulty.ToMessage())
-	l.removeMemberByAddress(faulty.Destination) // must always happen last to keep the member alive during this method
+	l.removeMember(faulty.Destination) // must always happen last to keep the member alive during this method
 	return true
 }
 
 func (l *List) handleFaultyForUnknown(faulty encoding.MessageFaulty) {
+	if !l.admitMember(faulty.Destination) {
+		return
+	}
+
 	// We don't know about this member yet. Add it to our faulty member list and gossip about it.
 	faultyMember := encoding.Member{
 		Address:           faulty.Destination,
@@ -1364,15 +2053,19 @@ func (l *List) handleListRequest(listRequest encoding.MessageListRequest) error
 		logger.Info(
 			"Received list request",
 			"source", listRequest.Source,
+			"pushed-members", len(listRequest.Members),
 		)
 	}
 
-	members := l.members
-	l.faultyMembers.ForEach(func(member encoding.Member) bool {
-		members = append(members, member)
-		return true
-	})
+	// With a push-pull list sync, the source sent its own member list along. We merge it into our own member list
+	// first, to heal our view in the same round trip as the view of the source.
+	if err := l.mergeMembers(listRequest.Source, listRequest.Members); err != nil {
+		return err
+	}
+
+	members := l.syncMembers()
 	l.faultyMembers.ListRequestObserved()
+	l.forceRemovedMembers.ListRequestObserved()
 
 	listResponse := encoding.MessageListResponse{
 		Source:  l.self,
@@ -1399,7 +2092,19 @@ func (l *List) handleListResponse(listResponse encoding.MessageListResponse) err
 			"source", listResponse.Source,
 		)
 	}
-	for _, member := range listResponse.Members {
+	return l.mergeMembers(listResponse.Source, listResponse.Members)
+}
+
+// mergeMembers merges the given members as reported by source into our own member list. This is done by treating every
+// member as gossip about that member.
+func (l *List) mergeMembers(source encoding.Address, members []encoding.Member) error {
+	localPartitionSize := l.members.Len() + 1
+	l.mergeScratchSpace = l.mergeScratchSpace[:0]
+	revivedCount := 0
+	for _, member := range members {
+		wasMember := l.members.Contains(member.Address)
+		_, wasFaulty := l.faultyMembers.Get(member.Address)
+
 		switch member.State {
 		case encoding.MemberStateAlive:
 			l.handleAlive(encoding.MessageAlive{
@@ -1408,19 +2113,37 @@ func (l *List) handleListResponse(listResponse encoding.MessageListResponse) err
 			})
 		case encoding.MemberStateSuspect:
 			l.handleSuspect(encoding.MessageSuspect{
-				Source:            listResponse.Source,
+				Source:            source,
 				Destination:       member.Address,
 				IncarnationNumber: member.IncarnationNumber,
 			})
 		case encoding.MemberStateFaulty:
 			l.handleFaulty(encoding.MessageFaulty{
-				Source:            listResponse.Source,
+				Source:            source,
+				Destination:       member.Address,
+				IncarnationNumber: member.IncarnationNumber,
+			})
+		case encoding.MemberStateForceRemoved:
+			l.handleForceRemove(encoding.MessageForceRemove{
+				Source:            source,
 				Destination:       member.Address,
 				IncarnationNumber: member.IncarnationNumber,
 			})
 		default:
 			return fmt.Errorf("unknown member state: %v", member.State)
 		}
+
+		if wasMember {
+			continue
+		}
+		if !l.members.Contains(member.Address) {
+			continue
+		}
+		l.mergeScratchSpace = append(l.mergeScratchSpace, member.Address)
+		if wasFaulty {
+			revivedCount++
+		}
 	}
+	l.detectMerge(source, localPartitionSize, revivedCount)
 	return nil
 }
@@ -1,17 +1,23 @@
 package membership_test
 
 import (
+	"errors"
 	"fmt"
 	"math"
+	"math/rand/v2"
 	"net"
+	"path/filepath"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	"github.com/backbone81/membership/internal/encoding"
+	"github.com/backbone81/membership/internal/incarnation"
 	"github.com/backbone81/membership/internal/membership"
 	"github.com/backbone81/membership/internal/roundtriptime"
+	"github.com/backbone81/membership/internal/snapshot"
 	"github.com/backbone81/membership/internal/transport"
 	"github.com/backbone81/membership/internal/utility"
 )
@@ -129,6 +135,34 @@ var _ = Describe("List", func() {
 			Expect(config.MaxDatagramLengthSend).To(Equal(1))
 		})
 
+		It("should apply WithPushPullListSync option", func() {
+			list := newTestList(
+				membership.WithPushPullListSync(true),
+			)
+
+			config := list.Config()
+			Expect(config.PushPullListSync).To(BeTrue())
+		})
+
+		It("should process join candidates as alive", func() {
+			joinCandidates := []encoding.Address{
+				encoding.NewAddress(net.IPv4(255, 255, 255, 255), 1),
+				encoding.NewAddress(net.IPv4(255, 255, 255, 255), 2),
+			}
+
+			list := newTestList(
+				membership.WithJoinCandidates(joinCandidates),
+			)
+			debugList := membership.DebugList(list)
+
+			members := debugList.GetMembers()
+			Expect(members).To(HaveLen(2))
+			for i, member := range members {
+				Expect(member.Address).To(Equal(joinCandidates[i]))
+				Expect(member.State).To(Equal(encoding.MemberStateAlive))
+			}
+		})
+
 		It("should process bootstrap members as alive", func() {
 			bootstrap := []encoding.Address{
 				encoding.NewAddress(net.IPv4(255, 255, 255, 255), 1),
@@ -253,6 +287,21 @@ var _ = Describe("List", func() {
 			Expect(msg.Destination).To(Equal(TestAddress))
 			Expect(msg.IncarnationNumber).To(Equal(uint16(0)))
 		})
+
+		It("should initialize with gossip about self with the configured incarnation number", func() {
+			list := newTestList(
+				membership.WithIncarnationNumber(7),
+			)
+			debugList := membership.DebugList(list)
+
+			gossipQueue := debugList.GetGossip()
+			Expect(gossipQueue.Len()).To(Equal(1))
+
+			msg := GetFromQueueByIndex(debugList.GetGossip(), 0)
+			Expect(msg.Type).To(Equal(encoding.MessageTypeAlive))
+			Expect(msg.Destination).To(Equal(TestAddress))
+			Expect(msg.IncarnationNumber).To(Equal(uint16(7)))
+		})
 	})
 
 	Context("All", func() {
@@ -912,6 +961,158 @@ var _ = Describe("List", func() {
 			By("Verify that list request was sent")
 			Expect(store.Buffers).To(BeEmpty())
 		})
+
+		It("should write a snapshot after the snapshot interval", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "snapshot")
+			list := newTestList(
+				membership.WithSnapshotPath(path),
+				membership.WithSnapshotInterval(2),
+			)
+			Expect(DispatchDatagram(list, encoding.MessageAlive{
+				Destination:       TestAddress2,
+				IncarnationNumber: 1,
+			}.ToMessage())).To(Succeed())
+			debugList := membership.DebugList(list)
+			debugList.SetFaultyMembers([]encoding.Member{
+				{
+					Address:           TestAddress3,
+					State:             encoding.MemberStateFaulty,
+					IncarnationNumber: 2,
+				},
+			})
+
+			Expect(list.EndOfProtocolPeriod()).To(Succeed())
+			Expect(path).ToNot(BeAnExistingFile())
+
+			Expect(list.EndOfProtocolPeriod()).To(Succeed())
+			readSnapshot, err := snapshot.ReadFile(path)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(readSnapshot.Members).To(ConsistOf(
+				And(
+					HaveField("Member", encoding.Member{
+						Address:           TestAddress2,
+						State:             encoding.MemberStateAlive,
+						IncarnationNumber: 1,
+					}),
+					HaveField("LastSeen", Not(BeZero())),
+				),
+				snapshot.Member{
+					Member: encoding.Member{
+						Address:           TestAddress3,
+						State:             encoding.MemberStateFaulty,
+						IncarnationNumber: 2,
+					},
+				},
+			))
+		})
+
+		It("should request the member list after gossip was evicted", func() {
+			var store transport.Store
+			list := newTestList(
+				membership.WithUDPClient(&store),
+				membership.WithMaxGossipQueueLength(2),
+			)
+			debugList := membership.DebugList(list)
+			debugList.ClearGossip()
+
+			By("Receiving more alive messages than the gossip queue holds")
+			for i := range 3 {
+				Expect(DispatchDatagram(list, encoding.MessageAlive{
+					Destination:       encoding.NewAddress(net.IPv4(255, 255, 255, 255), 1+i),
+					IncarnationNumber: 0,
+				}.ToMessage())).To(Succeed())
+			}
+			Expect(debugList.GetGossip().Len()).To(Equal(2))
+			Expect(debugList.GetGossip().Evictions()).To(Equal(1))
+			store.Clear()
+
+			By("Verify that list request was sent")
+			Expect(list.EndOfProtocolPeriod()).To(Succeed())
+			Expect(store.Buffers).To(HaveLen(1))
+			var msg encoding.MessageListRequest
+			Expect(msg.FromBuffer(store.Buffers[0])).Error().ToNot(HaveOccurred())
+
+			By("Verify that no further list request is sent without further evictions")
+			store.Clear()
+			Expect(list.EndOfProtocolPeriod()).To(Succeed())
+			Expect(store.Buffers).To(BeEmpty())
+		})
+
+		It("should request the member list for evicted gossip at most once per interval", func() {
+			var store transport.Store
+			list := newTestList(
+				membership.WithUDPClient(&store),
+				membership.WithMaxGossipQueueLength(1),
+				membership.WithEvictionListRequestInterval(3),
+			)
+			membership.DebugList(list).ClearGossip()
+
+			// evictAndEndProtocolPeriod evicts gossip and reports if a list request was sent at the end of the protocol
+			// period.
+			nextPort := 1
+			evictAndEndProtocolPeriod := func() bool {
+				for range 2 {
+					Expect(DispatchDatagram(list, encoding.MessageAlive{
+						Destination:       encoding.NewAddress(net.IPv4(255, 255, 255, 255), nextPort),
+						IncarnationNumber: 0,
+					}.ToMessage())).To(Succeed())
+					nextPort++
+				}
+				store.Clear()
+				Expect(list.EndOfProtocolPeriod()).To(Succeed())
+				for _, buffer := range store.Buffers {
+					var msg encoding.MessageListRequest
+					if _, err := msg.FromBuffer(buffer); err == nil {
+						return true
+					}
+				}
+				return false
+			}
+
+			Expect(evictAndEndProtocolPeriod()).To(BeTrue())
+			Expect(evictAndEndProtocolPeriod()).To(BeFalse())
+			Expect(evictAndEndProtocolPeriod()).To(BeFalse())
+			Expect(evictAndEndProtocolPeriod()).To(BeTrue())
+		})
+
+		It("should never evict the refutation of a suspicion about self", func() {
+			list := newTestList(
+				membership.WithMaxGossipQueueLength(2),
+			)
+			debugList := membership.DebugList(list)
+			debugList.ClearGossip()
+
+			By("Refuting a suspicion about self")
+			Expect(DispatchDatagram(list, encoding.MessageSuspect{
+				Source:            TestAddress2,
+				Destination:       TestAddress,
+				IncarnationNumber: 0,
+			}.ToMessage())).To(Succeed())
+
+			By("Receiving more alive messages than the gossip queue holds")
+			for i := range 5 {
+				Expect(DispatchDatagram(list, encoding.MessageAlive{
+					Destination:       encoding.NewAddress(net.IPv4(255, 255, 255, 255), 1+i),
+					IncarnationNumber: 0,
+				}.ToMessage())).To(Succeed())
+			}
+
+			var gossipAddresses []encoding.Address
+			debugList.GetGossip().ForEach(func(message encoding.Message) bool {
+				gossipAddresses = append(gossipAddresses, message.Destination)
+				return true
+			})
+			Expect(gossipAddresses).To(HaveLen(2))
+			Expect(gossipAddresses).To(ContainElement(TestAddress))
+		})
+
+		It("should not write a snapshot without a snapshot path", func() {
+			list := newTestList(
+				membership.WithSnapshotInterval(1),
+			)
+			Expect(list.EndOfProtocolPeriod()).To(Succeed())
+			Expect(list.WriteSnapshot()).To(Succeed())
+		})
 	})
 
 	Context("ListRequestObserved", func() {
@@ -1208,6 +1409,38 @@ var _ = Describe("List", func() {
 			var msg encoding.MessageListRequest
 			Expect(msg.FromBuffer(store.Buffers[0])).Error().ToNot(HaveOccurred())
 		})
+		It("should push own member list over TCP with push-pull list sync", func() {
+			var udpStore transport.Store
+			var tcpStore transport.Store
+			bootstrapMembers := []encoding.Address{
+				encoding.NewAddress(net.IPv4(255, 255, 255, 255), 1),
+				encoding.NewAddress(net.IPv4(255, 255, 255, 255), 2),
+			}
+			list := newTestList(
+				membership.WithUDPClient(&udpStore),
+				membership.WithTCPClient(&tcpStore),
+				membership.WithBootstrapMembers(bootstrapMembers),
+				membership.WithPushPullListSync(true),
+			)
+
+			By("Executing list request")
+			Expect(list.RequestList()).To(Succeed())
+
+			By("Verifying network message sent over TCP only")
+			Expect(udpStore.Addresses).To(BeEmpty())
+			Expect(tcpStore.Addresses).To(HaveLen(1))
+			Expect(bootstrapMembers).To(ContainElement(tcpStore.Addresses[0]))
+
+			By("Verifying message is ListRequest with all members and self")
+			var msg encoding.MessageListRequest
+			Expect(msg.FromBuffer(tcpStore.Buffers[0])).Error().ToNot(HaveOccurred())
+			Expect(msg.Source).To(Equal(TestAddress))
+			var pushedAddresses []encoding.Address
+			for _, member := range msg.Members {
+				pushedAddresses = append(pushedAddresses, member.Address)
+			}
+			Expect(pushedAddresses).To(ConsistOf(bootstrapMembers[0], bootstrapMembers[1], TestAddress))
+		})
 	})
 
 	Context("BroadcastShutdown", func() {
@@ -1274,6 +1507,28 @@ var _ = Describe("List", func() {
 		})
 	})
 
+	Context("DispatchDatagram", func() {
+		It("should drop the rest of the datagram after an unknown message type", func() {
+			list := newTestList()
+			buffer, _, err := encoding.MessageAlive{
+				Destination: TestAddress2,
+			}.AppendToBuffer(nil)
+			Expect(err).ToNot(HaveOccurred())
+			buffer = append(buffer, 0xff, 1, 2, 3)
+			buffer, _, err = encoding.MessageAlive{
+				Destination: TestAddress3,
+			}.AppendToBuffer(buffer)
+			Expect(err).ToNot(HaveOccurred())
+
+			done := make(chan error)
+			go func() {
+				done <- list.DispatchDatagram(buffer)
+			}()
+			Eventually(done).Should(Receive(Succeed()))
+			Expect(Collect(list)).To(Equal([]encoding.Address{TestAddress2}))
+		})
+	})
+
 	Context("handleDirectPing", func() {
 		It("should send direct ack when receiving direct ping", func() {
 			var store transport.Store
@@ -1343,6 +1598,28 @@ var _ = Describe("List", func() {
 			Expect(debugList.GetPendingDirectPings()).To(BeEmpty())
 		})
 
+		It("should invoke member round trip time callback when receiving matching ack", func() {
+			bootstrapMember := encoding.NewAddress(net.IPv4(255, 255, 255, 255), 1)
+			var reportedAddresses []encoding.Address
+			list := newTestList(
+				membership.WithBootstrapMember(bootstrapMember),
+				membership.WithMemberRoundTripTimeCallback(func(address encoding.Address, roundTripTime time.Duration) {
+					reportedAddresses = append(reportedAddresses, address)
+				}),
+			)
+			debugList := membership.DebugList(list)
+
+			Expect(list.DirectPing()).To(Succeed())
+			pendingPings := debugList.GetPendingDirectPings()
+			Expect(pendingPings).To(HaveLen(1))
+
+			Expect(DispatchDatagram(list, encoding.MessageDirectAck{
+				Source:         pendingPings[0].Destination,
+				SequenceNumber: pendingPings[0].MessageDirectPing.SequenceNumber,
+			}.ToMessage())).To(Succeed())
+			Expect(reportedAddresses).To(Equal([]encoding.Address{bootstrapMember}))
+		})
+
 		It("should ignore ack with non-matching sequence number", func() {
 			var store transport.Store
 			bootstrapMembers := []encoding.Address{
@@ -1822,6 +2099,63 @@ var _ = Describe("List", func() {
 			}.ToMessage()))
 		})
 
+		It("should persist the incarnation number when refuting suspect about self", func() {
+			var store incarnation.Memory
+			list := newTestList(
+				membership.WithIncarnationNumber(3),
+				membership.WithIncarnationStore(&store),
+			)
+
+			By("Receiving a suspect message with an outdated incarnation number")
+			Expect(DispatchDatagram(list, encoding.MessageSuspect{
+				Source:            TestAddress2,
+				Destination:       TestAddress,
+				IncarnationNumber: 2,
+			}.ToMessage())).To(Succeed())
+			Expect(store.SaveCount).To(Equal(0))
+
+			By("Receiving a suspect message about our current incarnation number")
+			Expect(DispatchDatagram(list, encoding.MessageSuspect{
+				Source:            TestAddress2,
+				Destination:       TestAddress,
+				IncarnationNumber: 3,
+			}.ToMessage())).To(Succeed())
+			Expect(store.SaveCount).To(Equal(1))
+			Expect(store.Found).To(BeTrue())
+			Expect(store.IncarnationNumber).To(Equal(uint16(4)))
+		})
+
+		It("should not refute suspect about self when persisting the incarnation number fails", func() {
+			store := incarnation.Memory{
+				SaveErr: errors.New("disk full"),
+			}
+			list := newTestList(
+				membership.WithIncarnationNumber(3),
+				membership.WithIncarnationStore(&store),
+			)
+			debugList := membership.DebugList(list)
+			debugList.ClearGossip()
+
+			By("Receiving a suspect message while the store fails")
+			suspect := encoding.MessageSuspect{
+				Source:            TestAddress2,
+				Destination:       TestAddress,
+				IncarnationNumber: 3,
+			}.ToMessage()
+			Expect(DispatchDatagram(list, suspect)).To(Succeed())
+			Expect(store.SaveCount).To(Equal(1))
+			Expect(debugList.GetGossip().IsEmpty()).To(BeTrue())
+
+			By("Receiving the suspect message again after the store recovered")
+			store.SaveErr = nil
+			Expect(DispatchDatagram(list, suspect)).To(Succeed())
+			Expect(store.IncarnationNumber).To(Equal(uint16(4)))
+			Expect(GetFromQueueByIndex(debugList.GetGossip(), 0)).To(Equal(encoding.MessageAlive{
+				Destination:       TestAddress,
+				IncarnationNumber: 4,
+			}.ToMessage()))
+		})
+
 		It("should invoke member added callback when suspect", func() {
 			var addedCount int
 			list := newTestList(
@@ -1840,6 +2174,48 @@ var _ = Describe("List", func() {
 			Expect(addedCount).To(Equal(1))
 		})
 
+		It("should invoke member state changed callback when adding a suspect", func() {
+			var states []encoding.MemberState
+			list := newTestList(
+				membership.WithMemberStateChangedCallback(func(address encoding.Address, state encoding.MemberState) {
+					Expect(address).To(Equal(TestAddress2))
+					states = append(states, state)
+				}),
+			)
+
+			Expect(DispatchDatagram(list, encoding.MessageSuspect{
+				Destination:       TestAddress2,
+				IncarnationNumber: 5,
+			}.ToMessage())).To(Succeed())
+			Expect(states).To(Equal([]encoding.MemberState{encoding.MemberStateSuspect}))
+		})
+
+		It("should invoke member state changed callback when an alive member becomes suspect and alive again", func() {
+			var states []encoding.MemberState
+			list := newTestList(
+				membership.WithMemberStateChangedCallback(func(address encoding.Address, state encoding.MemberState) {
+					Expect(address).To(Equal(TestAddress2))
+					states = append(states, state)
+				}),
+			)
+
+			Expect(DispatchDatagram(list, encoding.MessageAlive{
+				Destination:       TestAddress2,
+				IncarnationNumber: 5,
+			}.ToMessage())).To(Succeed())
+			Expect(states).To(BeEmpty())
+
+			Expect(DispatchDatagram(list, encoding.MessageSuspect{
+				Destination:       TestAddress2,
+				IncarnationNumber: 5,
+			}.ToMessage())).To(Succeed())
+			Expect(DispatchDatagram(list, encoding.MessageAlive{
+				Destination:       TestAddress2,
+				IncarnationNumber: 6,
+			}.ToMessage())).To(Succeed())
+			Expect(states).To(Equal([]encoding.MemberState{encoding.MemberStateSuspect, encoding.MemberStateAlive}))
+		})
+
 		DescribeTable("Gossip should update the memberlist correctly",
 			func(beforeMembers []encoding.Member, beforeFaultyMembers []encoding.Member, message encoding.Message, afterMembers []encoding.Member, afterFaultyMembers []encoding.Member) {
 				list := membership.NewList(
@@ -2510,6 +2886,50 @@ var _ = Describe("List", func() {
 			Expect(responseMsg.Members).To(HaveLen(2))
 		})
 
+		It("should merge members pushed with the list request", func() {
+			var store transport.Store
+			list := newTestList(
+				membership.WithTCPClient(&store),
+			)
+			debugList := membership.DebugList(list)
+
+			By("Sending list request with members")
+			sourceAddr := encoding.NewAddress(net.IPv4(255, 255, 255, 255), 4)
+			pushedMembers := []encoding.Member{
+				{
+					Address:           sourceAddr,
+					State:             encoding.MemberStateAlive,
+					IncarnationNumber: 1,
+				},
+				{
+					Address:           encoding.NewAddress(net.IPv4(255, 255, 255, 255), 1),
+					State:             encoding.MemberStateSuspect,
+					IncarnationNumber: 2,
+				},
+				{
+					Address:           encoding.NewAddress(net.IPv4(255, 255, 255, 255), 2),
+					State:             encoding.MemberStateFaulty,
+					IncarnationNumber: 3,
+				},
+			}
+			Expect(DispatchDatagram(list, encoding.MessageListRequest{
+				Source:  sourceAddr,
+				Members: pushedMembers,
+			}.ToMessage())).To(Succeed())
+
+			By("Verifying pushed members merged")
+			Expect(list.Len()).To(Equal(2))
+			Expect(debugList.GetMembers()).To(ConsistOf(pushedMembers[0], pushedMembers[1]))
+			Expect(debugList.GetFaultyMembers()).To(ConsistOf(pushedMembers[2]))
+
+			By("Verifying response contains the merged members")
+			Expect(store.Addresses).To(HaveLen(1))
+			Expect(store.Addresses[0]).To(Equal(sourceAddr))
+			var responseMsg encoding.MessageListResponse
+			Expect(responseMsg.FromBuffer(store.Buffers[0])).Error().ToNot(HaveOccurred())
+			Expect(responseMsg.Members).To(ConsistOf(pushedMembers))
+		})
+
 		It("should handle TCP send errors gracefully", func() {
 			list := newTestList(
 				membership.WithTCPClient(&transport.Error{}),
@@ -2880,6 +3300,44 @@ func BenchmarkList_handleListResponse(b *testing.B) {
 	}
 }
 
+func BenchmarkList_MassJoin(b *testing.B) {
+	const memberCount = 100_000
+
+	// Members join in random order, which spreads the changes over the whole member list.
+	buffers := make([][]byte, 0, memberCount)
+	for i := range memberCount {
+		messageAlive := encoding.MessageAlive{
+			Destination:       encoding.NewAddress(net.IPv4(10, byte(i>>16), byte(i>>8), byte(i)), 1024),
+			IncarnationNumber: 0,
+		}
+		buffer, _, err := messageAlive.AppendToBuffer(nil)
+		if err != nil {
+			b.Fatal(err)
+		}
+		buffers = append(buffers, buffer)
+	}
+	random := rand.New(rand.NewPCG(0, 0))
+	random.Shuffle(len(buffers), func(i, j int) {
+		buffers[i], buffers[j] = buffers[j], buffers[i]
+	})
+
+	for b.Loop() {
+		list := membership.NewList(
+			membership.WithUDPClient(&transport.Discard{}),
+			membership.WithTCPClient(&transport.Discard{}),
+			membership.WithRoundTripTimeTracker(roundtriptime.NewTracker()),
+		)
+		for _, buffer := range buffers {
+			if err := list.DispatchDatagram(buffer); err != nil {
+				b.Fatal(err)
+			}
+		}
+		if list.Len() != memberCount {
+			b.Fatal("member count does not match expected value")
+		}
+	}
+}
+
 func dispatchDatagramWithMembers(b *testing.B, message encoding.Message) {
 	buffer, _, err := message.AppendToBuffer(nil)
 	if err != nil {
@@ -0,0 +1,63 @@
+package membership
+
+import (
+	"slices"
+
+	"github.com/backbone81/membership/internal/encoding"
+)
+
+// MergeEvent describes two partitions of the cluster merging again after a full member list sync.
+type MergeEvent struct {
+	// Source is the member of the other partition we synced with.
+	Source encoding.Address
+
+	// Members holds the members which joined our member list through the sync. This includes members which were
+	// declared faulty before and members which were unknown.
+	Members []encoding.Address
+
+	// RevivedCount is the number of members which were declared faulty before the sync.
+	RevivedCount int
+
+	// LocalPartitionSize is the number of members in our partition before the sync, including ourselves.
+	LocalPartitionSize int
+
+	// BootstrapReconnected reports if the sync was with a bootstrap member which we re-added after it dropped from
+	// our member list.
+	BootstrapReconnected bool
+}
+
+// detectMerge checks if the full member list sync with source which just finished is a merge of two partitions. This
+// is the case when many faulty members were revived at once, or when source is a bootstrap member we re-added. The
+// members which joined through the sync are expected in mergeScratchSpace.
+func (l *List) detectMerge(source encoding.Address, localPartitionSize int, revivedCount int) {
+	_, bootstrapReconnected := l.reconnectedBootstraps[source]
+	delete(l.reconnectedBootstraps, source)
+
+	if len(l.mergeScratchSpace) == 0 {
+		// Nothing changed for us, the partitions were not separated.
+		return
+	}
+	if !bootstrapReconnected && revivedCount < l.config.MergeMinRevivedMembers {
+		// This looks like the regular churn of a cluster.
+		return
+	}
+
+	l.logger.Info(
+		"Partitions merged",
+		"source", source,
+		"members", len(l.mergeScratchSpace),
+		"revived", revivedCount,
+		"local-partition-size", localPartitionSize,
+		"bootstrap-reconnected", bootstrapReconnected,
+	)
+	l.config.Metrics.MergesTotal.Inc()
+	if l.config.MergeCallback != nil {
+		l.config.MergeCallback(MergeEvent{
+			Source:               source,
+			Members:              slices.Clone(l.mergeScratchSpace),
+			RevivedCount:         revivedCount,
+			LocalPartitionSize:   localPartitionSize,
+			BootstrapReconnected: bootstrapReconnected,
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package membership_test
+
+import (
+	"net"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/encoding"
+	"github.com/backbone81/membership/internal/faultymember"
+	"github.com/backbone81/membership/internal/membership"
+)
+
+var _ = Describe("Merge", func() {
+	partitionAddresses := []encoding.Address{
+		encoding.NewAddress(net.IPv4(31, 0, 0, 1), 1024),
+		encoding.NewAddress(net.IPv4(31, 0, 0, 2), 1024),
+		encoding.NewAddress(net.IPv4(31, 0, 0, 3), 1024),
+	}
+
+	// declareFaulty adds the given addresses as faulty members.
+	declareFaulty := func(list *membership.List, addresses ...encoding.Address) {
+		for _, address := range addresses {
+			Expect(DispatchDatagram(list, encoding.MessageFaulty{
+				Source:      TestAddress3,
+				Destination: address,
+			}.ToMessage())).To(Succeed())
+		}
+	}
+
+	// listResponse returns a list response from source with the given addresses as alive members.
+	listResponse := func(source encoding.Address, addresses ...encoding.Address) encoding.Message {
+		members := make([]encoding.Member, 0, len(addresses))
+		for _, address := range addresses {
+			members = append(members, encoding.Member{
+				Address:           address,
+				State:             encoding.MemberStateAlive,
+				IncarnationNumber: 1,
+			})
+		}
+		return encoding.MessageListResponse{
+			Source:  source,
+			Members: members,
+		}.ToMessage()
+	}
+
+	It("should report a merge when many faulty members are revived at once", func() {
+		var events []membership.MergeEvent
+		list := newTestList(
+			membership.WithMergeCallback(func(event membership.MergeEvent) {
+				events = append(events, event)
+			}),
+		)
+		Expect(DispatchDatagram(list, encoding.MessageAlive{
+			Destination: TestAddress2,
+		}.ToMessage())).To(Succeed())
+		declareFaulty(list, partitionAddresses...)
+
+		Expect(DispatchDatagram(list, listResponse(TestAddress2, append(partitionAddresses, TestAddress3)...))).To(Succeed())
+		Expect(events).To(Equal([]membership.MergeEvent{
+			{
+				Source:             TestAddress2,
+				Members:            append(partitionAddresses, TestAddress3),
+				RevivedCount:       3,
+				LocalPartitionSize: 2,
+			},
+		}))
+	})
+
+	It("should not report a merge for a few revived members", func() {
+		var events []membership.MergeEvent
+		list := newTestList(
+			membership.WithMergeCallback(func(event membership.MergeEvent) {
+				events = append(events, event)
+			}),
+		)
+		declareFaulty(list, partitionAddresses[:2]...)
+		Expect(DispatchDatagram(list, listResponse(TestAddress2, append(partitionAddresses, TestAddress3)...))).To(Succeed())
+		Expect(events).To(BeEmpty())
+		Expect(list.Len()).To(Equal(4))
+	})
+
+	It("should report a merge when a reconnected bootstrap member responds", func() {
+		var events []membership.MergeEvent
+		list := newTestList(
+			membership.WithBootstrapMembers([]encoding.Address{TestAddress2}),
+			membership.WithMergeCallback(func(event membership.MergeEvent) {
+				events = append(events, event)
+			}),
+		)
+		declareFaulty(list, TestAddress2)
+		for range faultymember.DefaultConfig.MaxListRequestCount + 1 {
+			Expect(DispatchDatagram(list, encoding.MessageListRequest{
+				Source: TestAddress3,
+			}.ToMessage())).To(Succeed())
+		}
+		Expect(list.EndOfProtocolPeriod()).To(Succeed())
+		Expect(Collect(list)).To(Equal([]encoding.Address{TestAddress2}))
+
+		Expect(DispatchDatagram(list, listResponse(TestAddress2, TestAddress2, TestAddress3))).To(Succeed())
+		Expect(events).To(Equal([]membership.MergeEvent{
+			{
+				Source:               TestAddress2,
+				Members:              []encoding.Address{TestAddress3},
+				LocalPartitionSize:   2,
+				BootstrapReconnected: true,
+			},
+		}))
+
+		By("not reporting a merge for the following syncs")
+		Expect(DispatchDatagram(list, listResponse(TestAddress2, partitionAddresses[0]))).To(Succeed())
+		Expect(events).To(HaveLen(1))
+	})
+})
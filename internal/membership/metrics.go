@@ -1,42 +1,148 @@
 package membership
 
-import "github.com/prometheus/client_golang/prometheus"
+import (
+	"github.com/prometheus/client_golang/prometheus"
 
-var (
-	MembersByState = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Name: "membership_list_members",
-			Help: "Current number of members by state (alive, suspect, faulty).",
-		},
-		[]string{"state"},
-	)
-	MemberStateTransitionsTotal = prometheus.NewCounterVec(
-		prometheus.CounterOpts{
-			Name: "membership_list_member_state_transitions_total",
-			Help: "Total number of member state transitions.",
-		},
-		[]string{"transition"},
-	)
-	MessagesReceivedTotal = prometheus.NewCounterVec(
-		prometheus.CounterOpts{
-			Name: "membership_list_messages_received_total",
-			Help: "Total number of member state transitions.",
-		},
-		[]string{"type"},
-	)
+	"github.com/backbone81/membership/internal/gossip"
 )
 
-// RegisterMetrics registers all metrics collectors with the given prometheus registerer.
-func RegisterMetrics(registerer prometheus.Registerer) error {
+// Metrics holds the metrics collectors of a membership list. Every membership list owns its own metrics, which allows
+// for running multiple membership lists in the same process without mixing up their numbers.
+type Metrics struct {
+	MembersByState              *prometheus.GaugeVec
+	MemberStateTransitionsTotal *prometheus.CounterVec
+	MembersRejectedTotal        *prometheus.CounterVec
+	MergesTotal                 prometheus.Counter
+	MessagesReceivedTotal       *prometheus.CounterVec
+	RoundTripTimeSeconds        prometheus.Histogram
+	SuspicionDurationSeconds    prometheus.Histogram
+	ReconnectProbesTotal        prometheus.Counter
+	ReconnectProbesAckedTotal   prometheus.Counter
+	PassiveViewMembers          prometheus.Gauge
+	MinorityPartition           prometheus.Gauge
+	QuorumReferenceMembers      prometheus.Gauge
+
+	// Gossip holds the metrics of the gossip queue owned by the membership list.
+	Gossip *gossip.Metrics
+}
+
+// NewMetrics creates new metrics collectors with the given const labels. The const labels might be nil.
+func NewMetrics(constLabels prometheus.Labels) *Metrics {
+	return &Metrics{
+		MembersByState: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "membership_list_members",
+				Help:        "Current number of members by state (alive, suspect, faulty).",
+				ConstLabels: constLabels,
+			},
+			[]string{"state"},
+		),
+		MemberStateTransitionsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "membership_list_member_state_transitions_total",
+				Help:        "Total number of member state transitions.",
+				ConstLabels: constLabels,
+			},
+			[]string{"transition"},
+		),
+		MembersRejectedTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "membership_list_members_rejected_total",
+				Help:        "Total number of unknown members which were not admitted by reason.",
+				ConstLabels: constLabels,
+			},
+			[]string{"reason"},
+		),
+		MergesTotal: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name:        "membership_list_merges_total",
+				Help:        "Total number of partitions merged through a full member list sync.",
+				ConstLabels: constLabels,
+			},
+		),
+		MessagesReceivedTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "membership_list_messages_received_total",
+				Help:        "Total number of network messages received by type.",
+				ConstLabels: constLabels,
+			},
+			[]string{"type"},
+		),
+		RoundTripTimeSeconds: prometheus.NewHistogram(
+			prometheus.HistogramOpts{
+				Name:        "membership_list_round_trip_time_seconds",
+				Help:        "Round trip time of direct pings in seconds.",
+				ConstLabels: constLabels,
+				Buckets:     prometheus.ExponentialBuckets(0.001, 2, 12),
+			},
+		),
+		SuspicionDurationSeconds: prometheus.NewHistogram(
+			prometheus.HistogramOpts{
+				Name:        "membership_list_suspicion_duration_seconds",
+				Help:        "Time members were suspect before they were refuted or removed in seconds.",
+				ConstLabels: constLabels,
+				Buckets:     prometheus.ExponentialBuckets(0.5, 2, 10),
+			},
+		),
+		ReconnectProbesTotal: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name:        "membership_list_reconnect_probes_total",
+				Help:        "Total number of direct pings sent to faulty members to find out if they are reachable again.",
+				ConstLabels: constLabels,
+			},
+		),
+		ReconnectProbesAckedTotal: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name:        "membership_list_reconnect_probes_acked_total",
+				Help:        "Total number of direct pings to faulty members which were acknowledged.",
+				ConstLabels: constLabels,
+			},
+		),
+		PassiveViewMembers: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "membership_list_passive_view_members",
+				Help:        "Current number of members in the passive view when running in partial view mode.",
+				ConstLabels: constLabels,
+			},
+		),
+		MinorityPartition: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "membership_list_minority_partition",
+				Help:        "Whether this member is in a minority partition which lost quorum (1) or not (0).",
+				ConstLabels: constLabels,
+			},
+		),
+		QuorumReferenceMembers: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "membership_list_quorum_reference_members",
+				Help:        "Current cluster size quorum is calculated against.",
+				ConstLabels: constLabels,
+			},
+		),
+		Gossip: gossip.NewMetrics(constLabels),
+	}
+}
+
+// Register registers all metrics collectors with the given prometheus registerer.
+func (m *Metrics) Register(registerer prometheus.Registerer) error {
 	metrics := []prometheus.Collector{
-		MembersByState,
-		MemberStateTransitionsTotal,
-		MessagesReceivedTotal,
+		m.MembersByState,
+		m.MemberStateTransitionsTotal,
+		m.MembersRejectedTotal,
+		m.MergesTotal,
+		m.MessagesReceivedTotal,
+		m.RoundTripTimeSeconds,
+		m.SuspicionDurationSeconds,
+		m.ReconnectProbesTotal,
+		m.ReconnectProbesAckedTotal,
+		m.PassiveViewMembers,
+		m.MinorityPartition,
+		m.QuorumReferenceMembers,
 	}
 	for _, metric := range metrics {
 		if err := registerer.Register(metric); err != nil {
 			return err
 		}
 	}
-	return nil
+	return m.Gossip.Register(registerer)
 }
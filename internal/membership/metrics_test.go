@@ -0,0 +1,69 @@
+package membership_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/backbone81/membership/internal/encoding"
+	"github.com/backbone81/membership/internal/membership"
+)
+
+var _ = Describe("Metrics", func() {
+	It("should register the metrics of multiple lists with different const labels", func() {
+		registry := prometheus.NewRegistry()
+		Expect(membership.NewMetrics(prometheus.Labels{"cluster": "a"}).Register(registry)).To(Succeed())
+		Expect(membership.NewMetrics(prometheus.Labels{"cluster": "b"}).Register(registry)).To(Succeed())
+	})
+
+	It("should not mix up the metrics of multiple lists", func() {
+		metrics1 := membership.NewMetrics(nil)
+		metrics2 := membership.NewMetrics(nil)
+		list := newTestList(membership.WithMetrics(metrics1))
+		newTestList(membership.WithMetrics(metrics2))
+
+		Expect(DispatchDatagram(list, encoding.MessageAlive{
+			Destination: TestAddress2,
+		}.ToMessage())).To(Succeed())
+		Expect(testutil.ToFloat64(metrics1.MemberStateTransitionsTotal.WithLabelValues("added"))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(metrics2.MemberStateTransitionsTotal.WithLabelValues("added"))).To(Equal(0.0))
+	})
+
+	It("should observe the round trip time of direct pings", func() {
+		metrics := membership.NewMetrics(nil)
+		list := newTestList(
+			membership.WithMetrics(metrics),
+			membership.WithBootstrapMembers([]encoding.Address{TestAddress2}),
+		)
+		Expect(list.DirectPing()).To(Succeed())
+		Expect(DispatchDatagram(list, encoding.MessageDirectAck{
+			Source: TestAddress2,
+		}.ToMessage())).To(Succeed())
+		Expect(histogramSampleCount(metrics.RoundTripTimeSeconds)).To(Equal(uint64(1)))
+	})
+
+	It("should observe the duration of suspicions", func() {
+		metrics := membership.NewMetrics(nil)
+		list := newTestList(membership.WithMetrics(metrics))
+		Expect(DispatchDatagram(list, encoding.MessageSuspect{
+			Source:      TestAddress3,
+			Destination: TestAddress2,
+		}.ToMessage())).To(Succeed())
+		Expect(histogramSampleCount(metrics.SuspicionDurationSeconds)).To(BeZero())
+
+		Expect(DispatchDatagram(list, encoding.MessageAlive{
+			Destination:       TestAddress2,
+			IncarnationNumber: 1,
+		}.ToMessage())).To(Succeed())
+		Expect(histogramSampleCount(metrics.SuspicionDurationSeconds)).To(Equal(uint64(1)))
+	})
+})
+
+// histogramSampleCount returns the number of observations of the given histogram.
+func histogramSampleCount(histogram prometheus.Histogram) uint64 {
+	var metric dto.Metric
+	Expect(histogram.Write(&metric)).To(Succeed())
+	return metric.GetHistogram().GetSampleCount()
+}
@@ -0,0 +1,113 @@
+package membership_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/encoding"
+	"github.com/backbone81/membership/internal/membership"
+	"github.com/backbone81/membership/internal/observer"
+)
+
+var _ = Describe("Observer", func() {
+	It("should report pings, acks and gossip", func() {
+		var testObserver TestObserver
+		list := newTestList(membership.WithObserver(&testObserver))
+		Expect(DispatchDatagram(list, encoding.MessageAlive{
+			Destination: TestAddress2,
+		}.ToMessage())).To(Succeed())
+		Expect(testObserver.GossipReceivedCount).To(Equal(1))
+
+		Expect(list.DirectPing()).To(Succeed())
+		Expect(testObserver.DirectPingsSent).To(Equal([]encoding.Address{TestAddress2}))
+		Expect(testObserver.GossipSentCount).To(BeNumerically(">", 0))
+
+		Expect(DispatchDatagram(list, encoding.MessageDirectAck{
+			Source:         TestAddress2,
+			SequenceNumber: testObserver.LastSequenceNumber,
+		}.ToMessage())).To(Succeed())
+		Expect(testObserver.DirectAcksReceived).To(Equal([]encoding.Address{TestAddress2}))
+	})
+
+	It("should report failed indirect pings and suspicions", func() {
+		var testObserver TestObserver
+		list := newTestList(membership.WithObserver(&testObserver))
+		Expect(DispatchDatagram(list, encoding.MessageAlive{
+			Destination: TestAddress2,
+		}.ToMessage())).To(Succeed())
+		Expect(DispatchDatagram(list, encoding.MessageAlive{
+			Destination: TestAddress3,
+		}.ToMessage())).To(Succeed())
+
+		Expect(list.DirectPing()).To(Succeed())
+		Expect(testObserver.DirectPingsSent).To(HaveLen(1))
+		destination := testObserver.DirectPingsSent[0]
+		Expect(list.IndirectPing()).To(Succeed())
+		Expect(list.EndOfProtocolPeriod()).To(Succeed())
+		Expect(testObserver.IndirectPingsFailed).To(Equal([]encoding.Address{destination}))
+		Expect(testObserver.SuspicionsRaised).To(Equal([]encoding.Address{destination}))
+
+		Expect(DispatchDatagram(list, encoding.MessageAlive{
+			Destination:       destination,
+			IncarnationNumber: 1,
+		}.ToMessage())).To(Succeed())
+		Expect(testObserver.SuspicionsRefuted).To(Equal([]encoding.Address{destination}))
+	})
+
+	It("should report refuting a suspicion about ourselves", func() {
+		var testObserver TestObserver
+		list := newTestList(membership.WithObserver(&testObserver))
+		Expect(DispatchDatagram(list, encoding.MessageSuspect{
+			Source:      TestAddress2,
+			Destination: TestAddress,
+		}.ToMessage())).To(Succeed())
+		Expect(testObserver.SuspicionsRefuted).To(Equal([]encoding.Address{TestAddress}))
+	})
+})
+
+// TestObserver records the events it is notified about.
+type TestObserver struct {
+	observer.Nop
+
+	DirectPingsSent     []encoding.Address
+	LastSequenceNumber  uint16
+	DirectAcksReceived  []encoding.Address
+	IndirectPingsFailed []encoding.Address
+	SuspicionsRaised    []encoding.Address
+	SuspicionsRefuted   []encoding.Address
+	GossipSentCount     int
+	GossipReceivedCount int
+}
+
+func (o *TestObserver) DirectPingSent(destination encoding.Address, sequenceNumber uint16) {
+	o.DirectPingsSent = append(o.DirectPingsSent, destination)
+	o.LastSequenceNumber = sequenceNumber
+}
+
+func (o *TestObserver) DirectAckReceived(source encoding.Address, _ time.Duration) {
+	o.DirectAcksReceived = append(o.DirectAcksReceived, source)
+}
+
+func (o *TestObserver) IndirectPingCompleted(destination encoding.Address, success bool) {
+	if !success {
+		o.IndirectPingsFailed = append(o.IndirectPingsFailed, destination)
+	}
+}
+
+func (o *TestObserver) SuspicionRaised(address encoding.Address, _ uint16) {
+	o.SuspicionsRaised = append(o.SuspicionsRaised, address)
+}
+
+func (o *TestObserver) SuspicionRefuted(address encoding.Address, _ uint16) {
+	o.SuspicionsRefuted = append(o.SuspicionsRefuted, address)
+}
+
+func (o *TestObserver) GossipSent(_ encoding.Address, count int) {
+	o.GossipSentCount += count
+}
+
+func (o *TestObserver) GossipReceived(_ encoding.Address, count int) {
+	o.GossipReceivedCount += count
+}
@@ -1,9 +1,14 @@
 package membership
 
 import (
+	"time"
+
 	"github.com/go-logr/logr"
 
+	"github.com/backbone81/membership/internal/admission"
 	"github.com/backbone81/membership/internal/encoding"
+	"github.com/backbone81/membership/internal/incarnation"
+	"github.com/backbone81/membership/internal/observer"
 	"github.com/backbone81/membership/internal/roundtriptime"
 	"github.com/backbone81/membership/internal/transport"
 )
@@ -66,6 +71,78 @@ func WithMemberRemovedCallback(memberRemovedCallback func(address encoding.Addre
 	}
 }
 
+func WithMemberStateChangedCallback(memberStateChangedCallback func(address encoding.Address, state encoding.MemberState)) Option {
+	return func(config *Config) {
+		config.MemberStateChangedCallback = memberStateChangedCallback
+	}
+}
+
+func WithMemberRoundTripTimeCallback(memberRoundTripTimeCallback func(address encoding.Address, roundTripTime time.Duration)) Option {
+	return func(config *Config) {
+		config.MemberRoundTripTimeCallback = memberRoundTripTimeCallback
+	}
+}
+
+func WithQueryCallback(queryCallback func(query encoding.MessageQuery)) Option {
+	return func(config *Config) {
+		config.QueryCallback = queryCallback
+	}
+}
+
+func WithQueryAckCallback(queryAckCallback func(source encoding.Address, queryID uint32)) Option {
+	return func(config *Config) {
+		config.QueryAckCallback = queryAckCallback
+	}
+}
+
+func WithQueryResponseCallback(queryResponseCallback func(source encoding.Address, queryID uint32, payload []byte)) Option {
+	return func(config *Config) {
+		config.QueryResponseCallback = queryResponseCallback
+	}
+}
+
+func WithAdmissionCallback(admissionCallback func(address encoding.Address, source encoding.Address) error) Option {
+	return func(config *Config) {
+		config.AdmissionCallback = admissionCallback
+	}
+}
+
+func WithMergeCallback(mergeCallback func(event MergeEvent)) Option {
+	return func(config *Config) {
+		config.MergeCallback = mergeCallback
+	}
+}
+
+func WithAckPayloadCallback(ackPayloadCallback func() []byte) Option {
+	return func(config *Config) {
+		config.AckPayloadCallback = ackPayloadCallback
+	}
+}
+
+func WithMaxAckPayloadLength(length int) Option {
+	return func(config *Config) {
+		config.MaxAckPayloadLength = max(0, length)
+	}
+}
+
+func WithBroadcastCallback(broadcastCallback func(origin encoding.Address, payload []byte)) Option {
+	return func(config *Config) {
+		config.BroadcastCallback = broadcastCallback
+	}
+}
+
+func WithMinorityPartitionCallback(minorityPartitionCallback func(minority bool)) Option {
+	return func(config *Config) {
+		config.MinorityPartitionCallback = minorityPartitionCallback
+	}
+}
+
+func WithObserver(observer observer.Observer) Option {
+	return func(config *Config) {
+		config.Observer = observer
+	}
+}
+
 func WithSafetyFactor(safetyFactor float64) Option {
 	return func(config *Config) {
 		config.SafetyFactor = max(0, safetyFactor)
@@ -125,3 +202,219 @@ func WithReconnectBootstrapMembers(reconnect bool) Option {
 		config.ReconnectBootstrapMembers = reconnect
 	}
 }
+
+func WithReconnectFaultyMemberCount(memberCount int) Option {
+	return func(config *Config) {
+		config.ReconnectFaultyMemberCount = max(0, memberCount)
+	}
+}
+
+func WithReconnectMinDelay(periods int) Option {
+	return func(config *Config) {
+		config.ReconnectMinDelay = max(1, periods)
+	}
+}
+
+func WithReconnectMaxDelay(periods int) Option {
+	return func(config *Config) {
+		config.ReconnectMaxDelay = max(1, periods)
+	}
+}
+
+func WithPushPullListSync(pushPull bool) Option {
+	return func(config *Config) {
+		config.PushPullListSync = pushPull
+	}
+}
+
+func WithIncarnationNumber(incarnationNumber uint16) Option {
+	return func(config *Config) {
+		config.IncarnationNumber = incarnationNumber
+	}
+}
+
+func WithIncarnationStore(store incarnation.Store) Option {
+	return func(config *Config) {
+		config.IncarnationStore = store
+	}
+}
+
+func WithJoinCandidates(addresses []encoding.Address) Option {
+	return func(config *Config) {
+		config.JoinCandidates = append(config.JoinCandidates, addresses...)
+	}
+}
+
+func WithSnapshotPath(path string) Option {
+	return func(config *Config) {
+		config.SnapshotPath = path
+	}
+}
+
+func WithSnapshotInterval(periods int) Option {
+	return func(config *Config) {
+		config.SnapshotInterval = max(1, periods)
+	}
+}
+
+func WithHealthPolicy(policy HealthPolicy) Option {
+	return func(config *Config) {
+		config.HealthPolicy = policy
+	}
+}
+
+func WithForceRemoveListRequestCount(count int) Option {
+	return func(config *Config) {
+		config.ForceRemoveListRequestCount = max(1, count)
+	}
+}
+
+func WithAllowedNetworks(rules []admission.Rule) Option {
+	return func(config *Config) {
+		config.AllowedNetworks = append(config.AllowedNetworks, rules...)
+	}
+}
+
+func WithDeniedNetworks(rules []admission.Rule) Option {
+	return func(config *Config) {
+		config.DeniedNetworks = append(config.DeniedNetworks, rules...)
+	}
+}
+
+func WithMergeMinRevivedMembers(count int) Option {
+	return func(config *Config) {
+		config.MergeMinRevivedMembers = max(1, count)
+	}
+}
+
+func WithPassive(passive bool) Option {
+	return func(config *Config) {
+		config.Passive = passive
+	}
+}
+
+func WithMemberHistoryLength(length int) Option {
+	return func(config *Config) {
+		config.MemberHistoryLength = max(0, length)
+	}
+}
+
+func WithPartialView(partialView bool) Option {
+	return func(config *Config) {
+		config.PartialView = partialView
+	}
+}
+
+func WithActiveViewSize(size int) Option {
+	return func(config *Config) {
+		config.ActiveViewSize = max(1, size)
+	}
+}
+
+func WithPassiveViewSize(size int) Option {
+	return func(config *Config) {
+		config.PassiveViewSize = max(1, size)
+	}
+}
+
+func WithActiveRandomWalkLength(length int) Option {
+	return func(config *Config) {
+		config.ActiveRandomWalkLength = max(0, length)
+	}
+}
+
+func WithPassiveRandomWalkLength(length int) Option {
+	return func(config *Config) {
+		config.PassiveRandomWalkLength = max(0, length)
+	}
+}
+
+func WithShuffleActiveCount(count int) Option {
+	return func(config *Config) {
+		config.ShuffleActiveCount = max(0, count)
+	}
+}
+
+func WithShufflePassiveCount(count int) Option {
+	return func(config *Config) {
+		config.ShufflePassiveCount = max(0, count)
+	}
+}
+
+func WithFailureDetector(detector FailureDetector) Option {
+	return func(config *Config) {
+		config.FailureDetector = detector
+	}
+}
+
+func WithPhiThreshold(threshold float64) Option {
+	return func(config *Config) {
+		config.PhiThreshold = max(0, threshold)
+	}
+}
+
+func WithPhiMinStdDeviation(stdDeviation time.Duration) Option {
+	return func(config *Config) {
+		config.PhiMinStdDeviation = max(10*time.Millisecond, stdDeviation)
+	}
+}
+
+func WithPhiSmoothing(smoothing float64) Option {
+	return func(config *Config) {
+		config.PhiSmoothing = min(1, max(0.01, smoothing))
+	}
+}
+
+func WithClock(clock func() time.Time) Option {
+	return func(config *Config) {
+		config.Clock = clock
+	}
+}
+
+func WithEagerPushMemberCount(count int) Option {
+	return func(config *Config) {
+		config.EagerPushMemberCount = max(1, count)
+	}
+}
+
+func WithBroadcastGraftTimeout(periods int) Option {
+	return func(config *Config) {
+		config.BroadcastGraftTimeout = max(1, periods)
+	}
+}
+
+func WithExpectedClusterSize(size int) Option {
+	return func(config *Config) {
+		config.ExpectedClusterSize = max(0, size)
+	}
+}
+
+func WithQuorumFraction(fraction float64) Option {
+	return func(config *Config) {
+		config.QuorumFraction = min(1, max(0, fraction))
+	}
+}
+
+func WithQuorumWindow(periods int) Option {
+	return func(config *Config) {
+		config.QuorumWindow = max(1, periods)
+	}
+}
+
+func WithMaxGossipQueueLength(length int) Option {
+	return func(config *Config) {
+		config.MaxGossipQueueLength = max(0, length)
+	}
+}
+
+func WithEvictionListRequestInterval(periods int) Option {
+	return func(config *Config) {
+		config.EvictionListRequestInterval = max(1, periods)
+	}
+}
+
+func WithMetrics(metrics *Metrics) Option {
+	return func(config *Config) {
+		config.Metrics = metrics
+	}
+}
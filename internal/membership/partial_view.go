@@ -0,0 +1,499 @@
+package membership
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/backbone81/membership/internal/encoding"
+	"github.com/backbone81/membership/internal/utility"
+)
+
+// The partial view mode follows HyParView (https://asc.di.fct.unl.pt/~jleitao/pdf/dsn07-leitao.pdf). The member list
+// is the active view. It is small and symmetric, which means that two members are either in each other's active view
+// or in neither. All SWIM activity like pings and gossip happens over the active view only. The passive view is a
+// larger random sample of further members, which replace active members when they fail or leave. Members join through
+// a random walk over the active views and periodically exchange samples of their views through shuffles, which keeps
+// the views random and the overlay connected.
+
+// PassiveView returns the addresses of the passive view. The passive view is always empty when not running in partial
+// view mode.
+func (l *List) PassiveView() []encoding.Address {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	result := make([]encoding.Address, 0, l.passiveView.Len())
+	l.passiveView.ForEach(func(address encoding.Address) bool {
+		result = append(result, address)
+		return true
+	})
+	return result
+}
+
+// maintainPartialView fills up the active view at the end of every protocol period. A member without any active
+// members joins the cluster through a bootstrap member. Missing active members are replaced with members from the
+// passive view.
+func (l *List) maintainPartialView() error {
+	// Neighbor requests from the previous protocol period which were not answered failed. The member is most likely
+	// gone, so we do not keep it as a replacement candidate.
+	for _, address := range l.pendingNeighborRequests {
+		l.passiveView.Remove(address)
+	}
+	l.pendingNeighborRequests = l.pendingNeighborRequests[:0]
+	l.config.Metrics.PassiveViewMembers.Set(float64(l.passiveView.Len()))
+
+	if l.members.Len() >= l.config.ActiveViewSize {
+		return nil
+	}
+
+	// Neighbor requests go first, as they only consider pending neighbor requests, not pending joins.
+	var joinedErr error
+	if err := l.requestNeighbor(); err != nil {
+		joinedErr = errors.Join(joinedErr, err)
+	}
+	if l.members.Len() == 0 {
+		if err := l.joinThroughBootstrapMember(); err != nil {
+			joinedErr = errors.Join(joinedErr, err)
+		}
+	}
+	return joinedErr
+}
+
+// joinThroughBootstrapMember sends a join to the first bootstrap member which is due for a reconnect attempt.
+func (l *List) joinThroughBootstrapMember() error {
+	for _, bootstrapMember := range l.config.BootstrapMembers {
+		if bootstrapMember.Equal(l.self) {
+			continue
+		}
+		if _, found := l.forceRemovedMembers.Get(bootstrapMember); found {
+			// The bootstrap member was force removed. We must not join through it.
+			continue
+		}
+		if !l.reconnectBackoff.Due(bootstrapMember) {
+			// The bootstrap member did not respond to earlier attempts. We wait a little longer before trying again.
+			continue
+		}
+
+		l.logger.Info("Joining through bootstrap member",
+			"destination", bootstrapMember,
+		)
+		l.reconnectBackoff.Attempted(bootstrapMember)
+		l.pendingNeighborRequests = append(l.pendingNeighborRequests, bootstrapMember)
+		return l.sendPartialViewMessage(bootstrapMember, encoding.MessageJoin{
+			Source: l.self,
+		}.ToMessage())
+	}
+	return nil
+}
+
+// requestNeighbor asks a random member of the passive view to become part of our active view. Only one request is
+// pending at any time.
+func (l *List) requestNeighbor() error {
+	if len(l.pendingNeighborRequests) > 0 {
+		return nil
+	}
+	address, found := l.passiveView.Random()
+	if !found {
+		return nil
+	}
+
+	logger := l.logger.V(1)
+	if logger.Enabled() {
+		// We only spend the memory allocation for interface boxing of the key value pairs when the log level would
+		// actually produce this log entry.
+		logger.Info(
+			"Requesting neighbor",
+			"destination", address,
+		)
+	}
+	l.pendingNeighborRequests = append(l.pendingNeighborRequests, address)
+	return l.sendPartialViewMessage(address, encoding.MessageNeighbor{
+		Source: l.self,
+
+		// A member without any active members is cut off from the cluster. The request must not be rejected then.
+		HighPriority: l.members.Len() == 0,
+	}.ToMessage())
+}
+
+// shuffle sends a sample of our active and passive view on a random walk through the active views. This replaces the
+// full member list sync in partial view mode.
+func (l *List) shuffle() error {
+	var destination encoding.Address
+	l.randomMemberPicker.Pick(1, l.members.Members(), func(member encoding.Member) {
+		destination = member.Address
+	})
+	if destination.IsZero() {
+		return nil
+	}
+
+	l.shuffleSampleScratchSpace = append(l.shuffleSampleScratchSpace[:0], l.self)
+	l.randomMemberPicker.PickWithout(l.config.ShuffleActiveCount, l.members.Members(), destination, func(member encoding.Member) {
+		l.shuffleSampleScratchSpace = append(l.shuffleSampleScratchSpace, member.Address)
+	})
+	l.shuffleSampleScratchSpace = l.passiveView.Sample(l.config.ShufflePassiveCount, l.shuffleSampleScratchSpace)
+
+	logger := l.logger.V(1)
+	if logger.Enabled() {
+		// We only spend the memory allocation for interface boxing of the key value pairs when the log level would
+		// actually produce this log entry.
+		logger.Info(
+			"Shuffling",
+			"destination", destination,
+			"addresses", len(l.shuffleSampleScratchSpace),
+		)
+	}
+	return l.sendPartialViewMessage(destination, encoding.MessageShuffle{
+		Source:      l.self,
+		Destination: l.self,
+		TimeToLive:  walkLength(l.config.ActiveRandomWalkLength),
+		Addresses:   l.shuffleSampleScratchSpace,
+	}.ToMessage())
+}
+
+// sendPartialViewMessage sends the given message to the given address without any gossip attached. The messages of the
+// partial view protocol are often exchanged with members which are not part of the active view. Those must not
+// receive our gossip, as gossip is kept within the active view.
+func (l *List) sendPartialViewMessage(address encoding.Address, message encoding.Message) error {
+	var err error
+	l.datagramBuffer, _, err = message.AppendToBuffer(l.datagramBuffer[:0])
+	if err != nil {
+		return err
+	}
+	return l.config.UDPClient.Send(address, l.datagramBuffer)
+}
+
+// addToActiveView adds the member with the given address to the active view. A random active member is dropped to make
+// room when the active view is full.
+func (l *List) addToActiveView(address encoding.Address) error {
+	if address.Equal(l.self) || l.members.Contains(address) {
+		return nil
+	}
+
+	var err error
+	if l.members.Len() >= l.config.ActiveViewSize {
+		err = l.dropRandomActiveMember(address)
+	}
+
+	// The member talked to us or was vouched for by a member which did. Earlier failures no longer apply.
+	l.faultyMembers.Remove(address)
+	l.passiveView.Remove(address)
+	l.addMember(encoding.Member{
+		Address:           address,
+		State:             encoding.MemberStateAlive,
+		IncarnationNumber: 0,
+	})
+	return err
+}
+
+// dropRandomActiveMember moves a random member of the active view other than exclude to the passive view. The member
+// is informed about it, to keep the active views symmetric.
+func (l *List) dropRandomActiveMember(exclude encoding.Address) error {
+	var dropped encoding.Address
+	l.randomMemberPicker.PickWithout(1, l.members.Members(), exclude, func(member encoding.Member) {
+		dropped = member.Address
+	})
+	if dropped.IsZero() {
+		return nil
+	}
+
+	logger := l.logger.V(1)
+	if logger.Enabled() {
+		// We only spend the memory allocation for interface boxing of the key value pairs when the log level would
+		// actually produce this log entry.
+		logger.Info(
+			"Disconnecting from active member",
+			"destination", dropped,
+		)
+	}
+	l.moveToPassiveView(dropped)
+	return l.sendPartialViewMessage(dropped, encoding.MessageDisconnect{
+		Source: l.self,
+	}.ToMessage())
+}
+
+// moveToPassiveView removes the member with the given address from the active view and adds it to the passive view.
+func (l *List) moveToPassiveView(address encoding.Address) {
+	delete(l.suspectCounters, address)
+	l.removeMember(address)
+	l.passiveView.Add(address)
+}
+
+// randomActiveMember returns a random member of the active view which is neither exclude1 nor exclude2. Returns the
+// zero address if there is no such member.
+func (l *List) randomActiveMember(exclude1 encoding.Address, exclude2 encoding.Address) encoding.Address {
+	var result encoding.Address
+	// Picking three members guarantees that at least one of them is not excluded, if there is such a member at all.
+	l.randomMemberPicker.Pick(3, l.members.Members(), func(member encoding.Member) {
+		if !result.IsZero() || member.Address.Equal(exclude1) || member.Address.Equal(exclude2) {
+			return
+		}
+		result = member.Address
+	})
+	return result
+}
+
+// admitToPartialView reports if the member with the given address may become part of the active or passive view.
+func (l *List) admitToPartialView(address encoding.Address) bool {
+	if address.Equal(l.self) {
+		return false
+	}
+	if _, found := l.forceRemovedMembers.Get(address); found {
+		return false
+	}
+	return l.admitMember(address)
+}
+
+// integrateIntoPassiveView adds the given addresses received through a shuffle to the passive view. Members we already
+// know about through other means are skipped.
+func (l *List) integrateIntoPassiveView(addresses []encoding.Address) {
+	for _, address := range addresses {
+		if l.members.Contains(address) || l.passiveView.Contains(address) {
+			continue
+		}
+		if _, found := l.faultyMembers.Get(address); found {
+			// We know the member as faulty. The shuffle might carry outdated information.
+			continue
+		}
+		if !l.admitToPartialView(address) {
+			continue
+		}
+		l.passiveView.Add(address)
+	}
+}
+
+func (l *List) handleJoin(join encoding.MessageJoin) error {
+	logger := l.logger.V(2)
+	if logger.Enabled() {
+		// We only spend the memory allocation for interface boxing of the key value pairs when the log level would
+		// actually produce this log entry.
+		logger.Info(
+			"Received join",
+			"source", join.Source,
+		)
+	}
+	if !l.config.PartialView || !l.admitToPartialView(join.Source) {
+		return nil
+	}
+
+	joinedErr := l.addToActiveView(join.Source)
+	if err := l.sendPartialViewMessage(join.Source, encoding.MessageNeighborReply{
+		Source:   l.self,
+		Accepted: true,
+	}.ToMessage()); err != nil {
+		joinedErr = errors.Join(joinedErr, err)
+	}
+
+	// We announce the new member through random walks starting at every other member of our active view. That way the
+	// new member ends up in the active and passive views of random members all over the cluster.
+	forwardJoin := encoding.MessageForwardJoin{
+		Source:      l.self,
+		Destination: join.Source,
+		TimeToLive:  walkLength(l.config.ActiveRandomWalkLength),
+	}.ToMessage()
+	for _, member := range l.members.Members() {
+		if member.Address.Equal(join.Source) {
+			continue
+		}
+		if err := l.sendPartialViewMessage(member.Address, forwardJoin); err != nil {
+			joinedErr = errors.Join(joinedErr, err)
+		}
+	}
+	return joinedErr
+}
+
+func (l *List) handleForwardJoin(forwardJoin encoding.MessageForwardJoin) error {
+	logger := l.logger.V(2)
+	if logger.Enabled() {
+		// We only spend the memory allocation for interface boxing of the key value pairs when the log level would
+		// actually produce this log entry.
+		logger.Info(
+			"Received forward join",
+			"source", forwardJoin.Source,
+			"destination", forwardJoin.Destination,
+			"time-to-live", forwardJoin.TimeToLive,
+		)
+	}
+	if !l.config.PartialView || l.members.Contains(forwardJoin.Destination) {
+		return nil
+	}
+	if !l.admitToPartialView(forwardJoin.Destination) {
+		return nil
+	}
+
+	if forwardJoin.TimeToLive == 0 || l.members.Len() <= 1 {
+		// The random walk ends here.
+		return l.acceptForwardJoin(forwardJoin.Destination)
+	}
+	if int(forwardJoin.TimeToLive) == l.config.PassiveRandomWalkLength {
+		l.passiveView.Add(forwardJoin.Destination)
+	}
+
+	next := l.randomActiveMember(forwardJoin.Source, forwardJoin.Destination)
+	if next.IsZero() {
+		// There is nobody left to forward to. The random walk ends here.
+		return l.acceptForwardJoin(forwardJoin.Destination)
+	}
+	forwardJoin.Source = l.self
+	forwardJoin.TimeToLive--
+	return l.sendPartialViewMessage(next, forwardJoin.ToMessage())
+}
+
+// acceptForwardJoin adds the joining member with the given address to the active view at the end of a random walk.
+// The joining member is asked to add us to its active view in turn.
+func (l *List) acceptForwardJoin(address encoding.Address) error {
+	joinedErr := l.addToActiveView(address)
+	if err := l.sendPartialViewMessage(address, encoding.MessageNeighbor{
+		Source:       l.self,
+		HighPriority: true,
+	}.ToMessage()); err != nil {
+		joinedErr = errors.Join(joinedErr, err)
+	}
+	return joinedErr
+}
+
+func (l *List) handleNeighbor(neighbor encoding.MessageNeighbor) error {
+	logger := l.logger.V(2)
+	if logger.Enabled() {
+		// We only spend the memory allocation for interface boxing of the key value pairs when the log level would
+		// actually produce this log entry.
+		logger.Info(
+			"Received neighbor request",
+			"source", neighbor.Source,
+			"high-priority", neighbor.HighPriority,
+		)
+	}
+	if !l.config.PartialView {
+		return nil
+	}
+
+	var joinedErr error
+	accepted := l.members.Contains(neighbor.Source)
+	if !accepted &&
+		(neighbor.HighPriority || l.members.Len() < l.config.ActiveViewSize) &&
+		l.admitToPartialView(neighbor.Source) {
+		joinedErr = l.addToActiveView(neighbor.Source)
+		accepted = true
+	}
+	if err := l.sendPartialViewMessage(neighbor.Source, encoding.MessageNeighborReply{
+		Source:   l.self,
+		Accepted: accepted,
+	}.ToMessage()); err != nil {
+		joinedErr = errors.Join(joinedErr, err)
+	}
+	return joinedErr
+}
+
+func (l *List) handleNeighborReply(neighborReply encoding.MessageNeighborReply) error {
+	logger := l.logger.V(2)
+	if logger.Enabled() {
+		// We only spend the memory allocation for interface boxing of the key value pairs when the log level would
+		// actually produce this log entry.
+		logger.Info(
+			"Received neighbor reply",
+			"source", neighborReply.Source,
+			"accepted", neighborReply.Accepted,
+		)
+	}
+	pendingIndex := slices.IndexFunc(l.pendingNeighborRequests, neighborReply.Source.Equal)
+	if !l.config.PartialView || pendingIndex == -1 {
+		// We did not ask for it. The member might already be part of our active view, because the neighbor request was
+		// sent at the end of a forwarded join.
+		return nil
+	}
+	l.pendingNeighborRequests = utility.SwapDelete(l.pendingNeighborRequests, pendingIndex)
+	l.reconnectBackoff.Succeeded(neighborReply.Source)
+	if !neighborReply.Accepted {
+		// The member stays in the passive view. We try again with a different member in the next protocol period.
+		return nil
+	}
+	return l.addToActiveView(neighborReply.Source)
+}
+
+func (l *List) handleDisconnect(disconnect encoding.MessageDisconnect) {
+	logger := l.logger.V(2)
+	if logger.Enabled() {
+		// We only spend the memory allocation for interface boxing of the key value pairs when the log level would
+		// actually produce this log entry.
+		logger.Info(
+			"Received disconnect",
+			"source", disconnect.Source,
+		)
+	}
+	if !l.config.PartialView || !l.members.Contains(disconnect.Source) {
+		return
+	}
+	l.moveToPassiveView(disconnect.Source)
+}
+
+func (l *List) handleShuffle(shuffle encoding.MessageShuffle) error {
+	logger := l.logger.V(2)
+	if logger.Enabled() {
+		// We only spend the memory allocation for interface boxing of the key value pairs when the log level would
+		// actually produce this log entry.
+		logger.Info(
+			"Received shuffle",
+			"source", shuffle.Source,
+			"destination", shuffle.Destination,
+			"time-to-live", shuffle.TimeToLive,
+		)
+	}
+	if !l.config.PartialView || shuffle.Destination.Equal(l.self) {
+		return nil
+	}
+
+	if shuffle.TimeToLive > 0 {
+		if next := l.randomActiveMember(shuffle.Source, shuffle.Destination); !next.IsZero() {
+			shuffle.Source = l.self
+			shuffle.TimeToLive--
+			return l.sendPartialViewMessage(next, shuffle.ToMessage())
+		}
+	}
+
+	// The random walk ends here. We answer with a sample of the same size of our passive view, before integrating the
+	// received sample. Otherwise, we might send parts of the received sample right back.
+	l.shuffleSampleScratchSpace = l.passiveView.Sample(len(shuffle.Addresses), l.shuffleSampleScratchSpace[:0])
+	err := l.sendPartialViewMessage(shuffle.Destination, encoding.MessageShuffleReply{
+		Source:    l.self,
+		Addresses: l.shuffleSampleScratchSpace,
+	}.ToMessage())
+	l.integrateIntoPassiveView(shuffle.Addresses)
+	return err
+}
+
+func (l *List) handleShuffleReply(shuffleReply encoding.MessageShuffleReply) {
+	logger := l.logger.V(2)
+	if logger.Enabled() {
+		// We only spend the memory allocation for interface boxing of the key value pairs when the log level would
+		// actually produce this log entry.
+		logger.Info(
+			"Received shuffle reply",
+			"source", shuffleReply.Source,
+			"addresses", len(shuffleReply.Addresses),
+		)
+	}
+	if !l.config.PartialView {
+		return
+	}
+	l.integrateIntoPassiveView(shuffleReply.Addresses)
+}
+
+// handleAliveForPartialView processes gossip about an alive member which is not part of our active view. The member is
+// not added to the active view, as only the partial view protocol manages the active view. It is added to the passive
+// view instead. The gossip is not relayed, as we have no state to tell new gossip from gossip we relayed before.
+func (l *List) handleAliveForPartialView(alive encoding.MessageAlive) {
+	if faultyMember, found := l.faultyMembers.Get(alive.Destination); found {
+		if !utility.IncarnationLessThan(faultyMember.IncarnationNumber, alive.IncarnationNumber) {
+			// We have more up-to-date information about this member.
+			return
+		}
+		l.faultyMembers.Remove(alive.Destination)
+	}
+	if l.passiveView.Contains(alive.Destination) || !l.admitToPartialView(alive.Destination) {
+		return
+	}
+	l.passiveView.Add(alive.Destination)
+}
+
+// walkLength converts the given random walk length into a time to live.
+func walkLength(length int) uint8 {
+	return uint8(min(length, 255)) //nolint:gosec // already checked before
+}
@@ -0,0 +1,260 @@
+package membership_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/encoding"
+	"github.com/backbone81/membership/internal/membership"
+	"github.com/backbone81/membership/internal/transport"
+)
+
+var _ = Describe("PartialView", func() {
+	// newPartialViewTestList returns a list in partial view mode with the given members in its active view. The list
+	// sends its UDP messages to the given store.
+	newPartialViewTestList := func(store *transport.Store, members []encoding.Address, options ...membership.Option) *membership.List {
+		list := newTestList(append([]membership.Option{
+			membership.WithUDPClient(store),
+			membership.WithPartialView(true),
+		}, options...)...)
+		activeMembers := make([]encoding.Member, 0, len(members))
+		for _, address := range members {
+			activeMembers = append(activeMembers, encoding.Member{
+				Address: address,
+				State:   encoding.MemberStateAlive,
+			})
+		}
+		membership.DebugList(list).SetMembers(activeMembers)
+		store.Clear()
+		return list
+	}
+
+	// parseNeighborReply returns the neighbor reply which was sent with the given buffer.
+	parseNeighborReply := func(buffer []byte) encoding.MessageNeighborReply {
+		var neighborReply encoding.MessageNeighborReply
+		Expect(neighborReply.FromBuffer(buffer)).Error().ToNot(HaveOccurred())
+		return neighborReply
+	}
+
+	It("should keep the passive random walk shorter than the active random walk", func() {
+		list := newTestList(
+			membership.WithActiveRandomWalkLength(3),
+			membership.WithPassiveRandomWalkLength(5),
+		)
+		Expect(list.Config().PassiveRandomWalkLength).To(Equal(2))
+	})
+
+	It("should not add bootstrap members to the active view", func() {
+		list := newTestList(
+			membership.WithPartialView(true),
+			membership.WithBootstrapMembers([]encoding.Address{TestAddress2}),
+		)
+		Expect(list.Len()).To(BeZero())
+	})
+
+	It("should join through a bootstrap member", func() {
+		var store transport.Store
+		list := newTestList(
+			membership.WithUDPClient(&store),
+			membership.WithPartialView(true),
+			membership.WithBootstrapMembers([]encoding.Address{TestAddress2}),
+		)
+		Expect(list.EndOfProtocolPeriod()).To(Succeed())
+		Expect(store.Addresses).To(Equal([]encoding.Address{TestAddress2}))
+		var join encoding.MessageJoin
+		Expect(join.FromBuffer(store.Buffers[0])).Error().ToNot(HaveOccurred())
+		Expect(join.Source).To(Equal(TestAddress))
+
+		By("adding the bootstrap member when it accepts")
+		Expect(DispatchDatagram(list, encoding.MessageNeighborReply{
+			Source:   TestAddress2,
+			Accepted: true,
+		}.ToMessage())).To(Succeed())
+		Expect(Collect(list)).To(Equal([]encoding.Address{TestAddress2}))
+	})
+
+	It("should add joining members and forward the join", func() {
+		var store transport.Store
+		list := newPartialViewTestList(&store, []encoding.Address{TestAddress2})
+		Expect(DispatchDatagram(list, encoding.MessageJoin{
+			Source: TestAddress3,
+		}.ToMessage())).To(Succeed())
+		Expect(Collect(list)).To(ConsistOf(TestAddress2, TestAddress3))
+		Expect(store.Addresses).To(Equal([]encoding.Address{TestAddress3, TestAddress2}))
+		Expect(parseNeighborReply(store.Buffers[0]).Accepted).To(BeTrue())
+
+		var forwardJoin encoding.MessageForwardJoin
+		Expect(forwardJoin.FromBuffer(store.Buffers[1])).Error().ToNot(HaveOccurred())
+		Expect(forwardJoin).To(Equal(encoding.MessageForwardJoin{
+			Source:      TestAddress,
+			Destination: TestAddress3,
+			TimeToLive:  6,
+		}))
+	})
+
+	It("should accept forwarded joins at the end of the random walk", func() {
+		var store transport.Store
+		list := newPartialViewTestList(&store, []encoding.Address{TestAddress2})
+		Expect(DispatchDatagram(list, encoding.MessageForwardJoin{
+			Source:      TestAddress2,
+			Destination: TestAddress3,
+			TimeToLive:  0,
+		}.ToMessage())).To(Succeed())
+		Expect(Collect(list)).To(ConsistOf(TestAddress2, TestAddress3))
+		Expect(store.Addresses).To(Equal([]encoding.Address{TestAddress3}))
+
+		var neighbor encoding.MessageNeighbor
+		Expect(neighbor.FromBuffer(store.Buffers[0])).Error().ToNot(HaveOccurred())
+		Expect(neighbor.HighPriority).To(BeTrue())
+	})
+
+	It("should reject neighbor requests with low priority when the active view is full", func() {
+		var store transport.Store
+		list := newPartialViewTestList(&store, []encoding.Address{TestAddress2}, membership.WithActiveViewSize(1))
+		Expect(DispatchDatagram(list, encoding.MessageNeighbor{
+			Source: TestAddress3,
+		}.ToMessage())).To(Succeed())
+		Expect(Collect(list)).To(Equal([]encoding.Address{TestAddress2}))
+		Expect(store.Addresses).To(Equal([]encoding.Address{TestAddress3}))
+		Expect(parseNeighborReply(store.Buffers[0]).Accepted).To(BeFalse())
+	})
+
+	It("should accept neighbor requests with high priority when the active view is full", func() {
+		var store transport.Store
+		list := newPartialViewTestList(&store, []encoding.Address{TestAddress2}, membership.WithActiveViewSize(1))
+		Expect(DispatchDatagram(list, encoding.MessageNeighbor{
+			Source:       TestAddress3,
+			HighPriority: true,
+		}.ToMessage())).To(Succeed())
+		Expect(Collect(list)).To(Equal([]encoding.Address{TestAddress3}))
+		Expect(list.PassiveView()).To(Equal([]encoding.Address{TestAddress2}))
+
+		By("informing the dropped member")
+		Expect(store.Addresses).To(Equal([]encoding.Address{TestAddress2, TestAddress3}))
+		var disconnect encoding.MessageDisconnect
+		Expect(disconnect.FromBuffer(store.Buffers[0])).Error().ToNot(HaveOccurred())
+		Expect(disconnect.Source).To(Equal(TestAddress))
+		Expect(parseNeighborReply(store.Buffers[1]).Accepted).To(BeTrue())
+	})
+
+	It("should move disconnecting members to the passive view", func() {
+		var store transport.Store
+		list := newPartialViewTestList(&store, []encoding.Address{TestAddress2})
+		Expect(DispatchDatagram(list, encoding.MessageDisconnect{
+			Source: TestAddress2,
+		}.ToMessage())).To(Succeed())
+		Expect(list.Len()).To(BeZero())
+		Expect(list.PassiveView()).To(Equal([]encoding.Address{TestAddress2}))
+	})
+
+	It("should add members from alive gossip to the passive view", func() {
+		var store transport.Store
+		list := newPartialViewTestList(&store, []encoding.Address{TestAddress2})
+		Expect(DispatchDatagram(list, encoding.MessageAlive{
+			Destination: TestAddress3,
+		}.ToMessage())).To(Succeed())
+		Expect(Collect(list)).To(Equal([]encoding.Address{TestAddress2}))
+		Expect(list.PassiveView()).To(Equal([]encoding.Address{TestAddress3}))
+
+		By("removing them again with faulty gossip")
+		Expect(DispatchDatagram(list, encoding.MessageFaulty{
+			Source:      TestAddress2,
+			Destination: TestAddress3,
+		}.ToMessage())).To(Succeed())
+		Expect(list.PassiveView()).To(BeEmpty())
+	})
+
+	It("should ignore suspect gossip about members outside of the active view", func() {
+		var store transport.Store
+		list := newPartialViewTestList(&store, []encoding.Address{TestAddress2})
+		membership.DebugList(list).ClearGossip()
+		Expect(DispatchDatagram(list, encoding.MessageSuspect{
+			Source:      TestAddress2,
+			Destination: TestAddress3,
+		}.ToMessage())).To(Succeed())
+		Expect(Collect(list)).To(Equal([]encoding.Address{TestAddress2}))
+		Expect(membership.DebugList(list).GetGossip().Len()).To(BeZero())
+	})
+
+	It("should replace missing active members from the passive view", func() {
+		var store transport.Store
+		list := newPartialViewTestList(&store, []encoding.Address{TestAddress2})
+		Expect(DispatchDatagram(list, encoding.MessageAlive{
+			Destination: TestAddress3,
+		}.ToMessage())).To(Succeed())
+		Expect(list.EndOfProtocolPeriod()).To(Succeed())
+		Expect(store.Addresses).To(Equal([]encoding.Address{TestAddress3}))
+		var neighbor encoding.MessageNeighbor
+		Expect(neighbor.FromBuffer(store.Buffers[0])).Error().ToNot(HaveOccurred())
+		Expect(neighbor.HighPriority).To(BeFalse())
+
+		Expect(DispatchDatagram(list, encoding.MessageNeighborReply{
+			Source:   TestAddress3,
+			Accepted: true,
+		}.ToMessage())).To(Succeed())
+		Expect(Collect(list)).To(ConsistOf(TestAddress2, TestAddress3))
+		Expect(list.PassiveView()).To(BeEmpty())
+	})
+
+	It("should drop passive members which do not answer neighbor requests", func() {
+		var store transport.Store
+		list := newPartialViewTestList(&store, []encoding.Address{TestAddress2})
+		Expect(DispatchDatagram(list, encoding.MessageAlive{
+			Destination: TestAddress3,
+		}.ToMessage())).To(Succeed())
+		Expect(list.EndOfProtocolPeriod()).To(Succeed())
+		Expect(list.EndOfProtocolPeriod()).To(Succeed())
+		Expect(list.PassiveView()).To(BeEmpty())
+	})
+
+	It("should ignore neighbor replies which were not requested", func() {
+		var store transport.Store
+		list := newPartialViewTestList(&store, []encoding.Address{TestAddress2})
+		Expect(DispatchDatagram(list, encoding.MessageNeighborReply{
+			Source:   TestAddress3,
+			Accepted: true,
+		}.ToMessage())).To(Succeed())
+		Expect(Collect(list)).To(Equal([]encoding.Address{TestAddress2}))
+	})
+
+	It("should shuffle instead of requesting the member list", func() {
+		var store transport.Store
+		list := newPartialViewTestList(&store, []encoding.Address{TestAddress2})
+		Expect(list.RequestList()).To(Succeed())
+		Expect(store.Addresses).To(Equal([]encoding.Address{TestAddress2}))
+		var shuffle encoding.MessageShuffle
+		Expect(shuffle.FromBuffer(store.Buffers[0])).Error().ToNot(HaveOccurred())
+		Expect(shuffle).To(Equal(encoding.MessageShuffle{
+			Source:      TestAddress,
+			Destination: TestAddress,
+			TimeToLive:  6,
+			Addresses:   []encoding.Address{TestAddress},
+		}))
+	})
+
+	It("should answer shuffles at the end of the random walk", func() {
+		var store transport.Store
+		list := newPartialViewTestList(&store, []encoding.Address{TestAddress2})
+		Expect(DispatchDatagram(list, encoding.MessageShuffle{
+			Source:      TestAddress2,
+			Destination: TestAddress2,
+			TimeToLive:  6,
+			Addresses:   []encoding.Address{TestAddress2, TestAddress3},
+		}.ToMessage())).To(Succeed())
+		Expect(store.Addresses).To(Equal([]encoding.Address{TestAddress2}))
+		var shuffleReply encoding.MessageShuffleReply
+		Expect(shuffleReply.FromBuffer(store.Buffers[0])).Error().ToNot(HaveOccurred())
+		Expect(shuffleReply.Source).To(Equal(TestAddress))
+		Expect(list.PassiveView()).To(Equal([]encoding.Address{TestAddress3}))
+	})
+
+	It("should integrate shuffle replies into the passive view", func() {
+		var store transport.Store
+		list := newPartialViewTestList(&store, []encoding.Address{TestAddress2})
+		Expect(DispatchDatagram(list, encoding.MessageShuffleReply{
+			Source:    TestAddress2,
+			Addresses: []encoding.Address{TestAddress, TestAddress2, TestAddress3},
+		}.ToMessage())).To(Succeed())
+		Expect(list.PassiveView()).To(Equal([]encoding.Address{TestAddress3}))
+	})
+})
@@ -0,0 +1,134 @@
+package membership_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/encoding"
+	"github.com/backbone81/membership/internal/membership"
+	"github.com/backbone81/membership/internal/transport"
+)
+
+var _ = Describe("Passive", func() {
+	It("should not gossip its own alive", func() {
+		list := newTestList(membership.WithPassive(true))
+		Expect(membership.DebugList(list).GetGossip().Len()).To(BeZero())
+	})
+
+	It("should not refute gossip about itself", func() {
+		list := newTestList(membership.WithPassive(true))
+		Expect(DispatchDatagram(list, encoding.MessageSuspect{
+			Source:      TestAddress2,
+			Destination: TestAddress,
+		}.ToMessage())).To(Succeed())
+		Expect(DispatchDatagram(list, encoding.MessageFaulty{
+			Source:      TestAddress2,
+			Destination: TestAddress,
+		}.ToMessage())).To(Succeed())
+		Expect(DispatchDatagram(list, encoding.MessageAlive{
+			Destination:       TestAddress,
+			IncarnationNumber: 5,
+		}.ToMessage())).To(Succeed())
+		Expect(membership.DebugList(list).GetGossip().Len()).To(BeZero())
+	})
+
+	It("should not announce health changes", func() {
+		list := newTestList(
+			membership.WithPassive(true),
+			membership.WithHealthPolicy(membership.HealthPolicyDegraded),
+		)
+		Expect(list.SetHealthy(false)).To(Succeed())
+		Expect(list.SetHealthy(true)).To(Succeed())
+		Expect(membership.DebugList(list).GetGossip().Len()).To(BeZero())
+	})
+
+	It("should not declare members as suspect for failed pings", func() {
+		list := newTestList(
+			membership.WithPassive(true),
+			membership.WithBootstrapMembers([]encoding.Address{TestAddress2}),
+		)
+		membership.DebugList(list).ClearGossip()
+		Expect(list.DirectPing()).To(Succeed())
+		Expect(list.EndOfProtocolPeriod()).To(Succeed())
+		Expect(membership.DebugList(list).GetMembers()).To(ConsistOf(encoding.Member{
+			Address: TestAddress2,
+			State:   encoding.MemberStateAlive,
+		}))
+		Expect(membership.DebugList(list).GetGossip().Len()).To(BeZero())
+	})
+
+	It("should not gossip about members it declares as faulty", func() {
+		list := newTestList(
+			membership.WithPassive(true),
+			membership.WithBootstrapMembers([]encoding.Address{TestAddress2}),
+			membership.WithSafetyFactor(0),
+		)
+		Expect(DispatchDatagram(list, encoding.MessageSuspect{
+			Source:      TestAddress3,
+			Destination: TestAddress2,
+		}.ToMessage())).To(Succeed())
+		membership.DebugList(list).ClearGossip()
+		Expect(list.EndOfProtocolPeriod()).To(Succeed())
+		Expect(list.Len()).To(BeZero())
+		Expect(membership.DebugList(list).GetGossip().Len()).To(BeZero())
+	})
+
+	It("should answer pings", func() {
+		var store transport.Store
+		list := newTestList(
+			membership.WithPassive(true),
+			membership.WithUDPClient(&store),
+		)
+		Expect(DispatchDatagram(list, encoding.MessageDirectPing{
+			Source:         TestAddress2,
+			SequenceNumber: 7,
+		}.ToMessage())).To(Succeed())
+		Expect(store.Addresses).To(Equal([]encoding.Address{TestAddress2}))
+	})
+
+	It("should learn about members through list responses", func() {
+		list := newTestList(
+			membership.WithPassive(true),
+			membership.WithBootstrapMembers([]encoding.Address{TestAddress2}),
+		)
+		Expect(DispatchDatagram(list, encoding.MessageListResponse{
+			Source: TestAddress2,
+			Members: []encoding.Member{
+				{
+					Address: TestAddress3,
+					State:   encoding.MemberStateAlive,
+				},
+			},
+		}.ToMessage())).To(Succeed())
+		Expect(Collect(list)).To(ConsistOf(TestAddress2, TestAddress3))
+	})
+
+	It("should not push itself with list requests", func() {
+		var store transport.Store
+		list := newTestList(
+			membership.WithPassive(true),
+			membership.WithPushPullListSync(true),
+			membership.WithTCPClient(&store),
+			membership.WithBootstrapMembers([]encoding.Address{TestAddress2}),
+		)
+		Expect(list.RequestList()).To(Succeed())
+		Expect(store.Buffers).To(HaveLen(1))
+		var listRequest encoding.MessageListRequest
+		Expect(listRequest.FromBuffer(store.Buffers[0])).Error().ToNot(HaveOccurred())
+		Expect(listRequest.Members).To(ConsistOf(encoding.Member{
+			Address: TestAddress2,
+			State:   encoding.MemberStateAlive,
+		}))
+	})
+
+	It("should not broadcast its shutdown", func() {
+		var store transport.Store
+		list := newTestList(
+			membership.WithPassive(true),
+			membership.WithUDPClient(&store),
+			membership.WithBootstrapMembers([]encoding.Address{TestAddress2}),
+		)
+		Expect(list.BroadcastShutdown()).To(Succeed())
+		Expect(store.Buffers).To(BeEmpty())
+	})
+})
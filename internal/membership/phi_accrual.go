@@ -0,0 +1,137 @@
+package membership
+
+import (
+	"math"
+	"time"
+
+	"github.com/backbone81/membership/internal/encoding"
+)
+
+// FailureDetector selects how members are declared suspect.
+type FailureDetector int
+
+const (
+	// FailureDetectorSWIM declares a member suspect when it did not answer a direct ping and all indirect pings within
+	// a protocol period.
+	FailureDetectorSWIM FailureDetector = iota
+
+	// FailureDetectorPhiAccrual declares a member suspect when it did not answer a direct ping and the time since we
+	// last heard from it is unlikely given the inter-arrival times we observed before. Until enough inter-arrival times
+	// were observed, it falls back to FailureDetectorSWIM.
+	FailureDetectorPhiAccrual
+)
+
+// String returns a human-readable representation of the failure detector.
+func (d FailureDetector) String() string {
+	switch d {
+	case FailureDetectorSWIM:
+		return "swim"
+	case FailureDetectorPhiAccrual:
+		return "phi-accrual"
+	default:
+		return "unknown"
+	}
+}
+
+// phiMinIntervals is the number of inter-arrival times which need to be observed before phi is calculated for a
+// member. Fewer samples do not provide a meaningful distribution.
+const phiMinIntervals = 3
+
+// phiMinInterval is the shortest inter-arrival time which is observed. Arrivals which are closer together count once.
+// This keeps multiple signs of life within the same datagram, like the datagram itself and the ack it carries, from
+// distorting the inter-arrival times.
+const phiMinInterval = time.Millisecond
+
+// The phi accrual failure detector follows Hayashibara et al. (https://doi.org/10.1109/RELDIS.2004.1353004). Every
+// ack of a member, every datagram a member sends us and every alive gossip about a member counts as an arrival. The
+// inter-arrival times are measured with the monotonic clock, as signs of life arrive at any time within a protocol
+// period. Instead of a window of samples, we keep an exponentially weighted moving mean and variance of the
+// inter-arrival times, which needs constant memory per member even in clusters with millions of members. Phi is the
+// negative decimal logarithm of the probability that the next arrival is still to come, assuming normally distributed
+// inter-arrival times. A phi of 1 means a 10% chance of a false suspicion, a phi of 2 a 1% chance and so on.
+//
+// Phi is only evaluated for members which did not answer a direct ping within the protocol period. All other members
+// were heard from just now, which keeps the cost per protocol period independent of the number of members.
+
+// arrivals holds the inter-arrival statistics of a single member.
+type arrivals struct {
+	// last is the point in time of the last arrival.
+	last time.Time
+
+	// intervals is the number of inter-arrival times observed, capped at phiMinIntervals.
+	intervals int
+
+	// mean is the moving mean of the inter-arrival times in seconds.
+	mean float64
+
+	// variance is the moving variance of the inter-arrival times in seconds squared.
+	variance float64
+}
+
+// add records an arrival at the given point in time. Arrivals which are closer than phiMinInterval to the last arrival
+// count once.
+func (a *arrivals) add(now time.Time, smoothing float64) {
+	elapsed := now.Sub(a.last)
+	if elapsed < phiMinInterval {
+		return
+	}
+	interval := elapsed.Seconds()
+	a.last = now
+	if a.intervals == 0 {
+		a.mean = interval
+		a.variance = 0
+		a.intervals++
+		return
+	}
+	a.intervals = min(a.intervals+1, phiMinIntervals)
+
+	// See "Incremental calculation of weighted mean and variance" by Tony Finch.
+	diff := interval - a.mean
+	increment := smoothing * diff
+	a.mean += increment
+	a.variance = (1 - smoothing) * (a.variance + diff*increment)
+}
+
+// phi returns the suspicion level of the member at the given point in time. Reports false when not enough arrivals
+// were observed yet.
+func (a *arrivals) phi(now time.Time, minStdDeviation time.Duration) (float64, bool) {
+	if a.intervals < phiMinIntervals {
+		return 0, false
+	}
+	stdDeviation := max(math.Sqrt(a.variance), minStdDeviation.Seconds())
+	y := (now.Sub(a.last).Seconds() - a.mean) / stdDeviation
+	return -math.Log10(0.5 * math.Erfc(y/math.Sqrt2)), true
+}
+
+// Phi returns the suspicion level of the phi accrual failure detector for the member with the given address. Phi is
+// tracked independent of the configured failure detector, which allows for comparing both detectors. Reports false if
+// the address is not an alive or suspect member or if not enough arrivals were observed for that member yet.
+func (l *List) Phi(address encoding.Address) (float64, bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	memberLifecycle, found := l.lifecycles[address]
+	if !found {
+		return 0, false
+	}
+	return memberLifecycle.arrivals.phi(l.config.Clock(), l.config.PhiMinStdDeviation)
+}
+
+// recordArrival records that we heard from the member with the given address just now.
+func (l *List) recordArrival(address encoding.Address) {
+	if memberLifecycle, found := l.lifecycles[address]; found {
+		memberLifecycle.arrivals.add(l.config.Clock(), l.config.PhiSmoothing)
+	}
+}
+
+// suspectedByPhiAccrual reports whether the member with the given address, which did not answer a direct ping, is to
+// be declared as suspect. This is the case when phi exceeds the threshold. When not enough arrivals were observed for
+// the member yet, the failed ping alone raises suspicion like with the SWIM failure detector.
+func (l *List) suspectedByPhiAccrual(address encoding.Address) bool {
+	memberLifecycle, found := l.lifecycles[address]
+	if !found {
+		return true
+	}
+	phi, ok := memberLifecycle.arrivals.phi(l.config.Clock(), l.config.PhiMinStdDeviation)
+	return !ok || phi > l.config.PhiThreshold
+}
@@ -0,0 +1,172 @@
+package membership_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/encoding"
+	"github.com/backbone81/membership/internal/membership"
+)
+
+var _ = Describe("Phi Accrual", func() {
+	// now is the point in time the lists of the tests see. Every protocol period advances it by one second.
+	var now time.Time
+
+	BeforeEach(func() {
+		now = time.Unix(0, 0)
+	})
+
+	// newPhiTestList returns a list with TestAddress2 as its only member.
+	newPhiTestList := func(options ...membership.Option) *membership.List {
+		options = append(options, membership.WithClock(func() time.Time {
+			return now
+		}))
+		list := newTestList(options...)
+		membership.DebugList(list).SetMembers([]encoding.Member{
+			{
+				Address: TestAddress2,
+				State:   encoding.MemberStateAlive,
+			},
+		})
+		membership.DebugList(list).ClearGossip()
+		return list
+	}
+
+	// runProtocolPeriod runs a protocol period of one second. When heard is true, the list receives a datagram from
+	// TestAddress2 within that protocol period.
+	runProtocolPeriod := func(list *membership.List, heard bool) {
+		if heard {
+			Expect(DispatchDatagram(list, encoding.MessageDirectPing{
+				Source: TestAddress2,
+			}.ToMessage())).To(Succeed())
+		}
+		Expect(list.EndOfProtocolPeriod()).To(Succeed())
+		now = now.Add(time.Second)
+	}
+
+	stateOf := func(list *membership.List) encoding.MemberState {
+		members := membership.DebugList(list).GetMembers()
+		Expect(members).To(HaveLen(1))
+		return members[0].State
+	}
+
+	It("should not report phi before enough arrivals were observed", func() {
+		list := newPhiTestList()
+		_, ok := list.Phi(TestAddress2)
+		Expect(ok).To(BeFalse())
+
+		runProtocolPeriod(list, true)
+		runProtocolPeriod(list, true)
+		_, ok = list.Phi(TestAddress2)
+		Expect(ok).To(BeFalse())
+
+		runProtocolPeriod(list, true)
+		runProtocolPeriod(list, true)
+		_, ok = list.Phi(TestAddress2)
+		Expect(ok).To(BeTrue())
+	})
+
+	It("should not report phi for unknown members", func() {
+		list := newPhiTestList()
+		_, ok := list.Phi(TestAddress3)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should increase phi while the member is not heard from", func() {
+		list := newPhiTestList()
+		for range 10 {
+			runProtocolPeriod(list, true)
+		}
+		phi, ok := list.Phi(TestAddress2)
+		Expect(ok).To(BeTrue())
+		Expect(phi).To(BeNumerically("<", 1))
+
+		for range 5 {
+			runProtocolPeriod(list, false)
+			nextPhi, ok := list.Phi(TestAddress2)
+			Expect(ok).To(BeTrue())
+			Expect(nextPhi).To(BeNumerically(">", phi))
+			phi = nextPhi
+		}
+		Expect(phi).To(BeNumerically(">", 8))
+	})
+
+	It("should declare members suspect by phi instead of failed pings", func() {
+		list := newPhiTestList(membership.WithFailureDetector(membership.FailureDetectorPhiAccrual))
+		for range 10 {
+			runProtocolPeriod(list, true)
+		}
+		for range 10 {
+			// The direct pings are never answered.
+			Expect(list.DirectPing()).To(Succeed())
+			runProtocolPeriod(list, true)
+		}
+		Expect(stateOf(list)).To(Equal(encoding.MemberStateAlive))
+
+		silentPeriods := 0
+		for stateOf(list) == encoding.MemberStateAlive && silentPeriods < 10 {
+			Expect(list.DirectPing()).To(Succeed())
+			runProtocolPeriod(list, false)
+			silentPeriods++
+		}
+		Expect(silentPeriods).To(BeNumerically(">", 1))
+		Expect(stateOf(list)).To(Equal(encoding.MemberStateSuspect))
+		gossip := membership.DebugList(list).GetGossip()
+		Expect(gossip.Len()).To(Equal(1))
+		Expect(GetFromQueueByIndex(gossip, 0).Type).To(Equal(encoding.MessageTypeSuspect))
+	})
+
+	It("should declare members suspect by failed pings before enough arrivals were observed", func() {
+		list := newPhiTestList(membership.WithFailureDetector(membership.FailureDetectorPhiAccrual))
+		runProtocolPeriod(list, true)
+		Expect(list.DirectPing()).To(Succeed())
+		runProtocolPeriod(list, true)
+		Expect(stateOf(list)).To(Equal(encoding.MemberStateSuspect))
+	})
+
+	It("should not declare members suspect by phi which were not pinged", func() {
+		list := newPhiTestList(membership.WithFailureDetector(membership.FailureDetectorPhiAccrual))
+		for range 10 {
+			runProtocolPeriod(list, true)
+		}
+		for range 10 {
+			runProtocolPeriod(list, false)
+		}
+		phi, ok := list.Phi(TestAddress2)
+		Expect(ok).To(BeTrue())
+		Expect(phi).To(BeNumerically(">", 8))
+		Expect(stateOf(list)).To(Equal(encoding.MemberStateAlive))
+	})
+
+	It("should count arrivals at the same point in time once", func() {
+		list := newPhiTestList()
+		for range 4 {
+			Expect(DispatchDatagram(list, encoding.MessageDirectPing{
+				Source: TestAddress2,
+			}.ToMessage())).To(Succeed())
+			Expect(DispatchDatagram(list, encoding.MessageDirectPing{
+				Source: TestAddress2,
+			}.ToMessage())).To(Succeed())
+			runProtocolPeriod(list, false)
+		}
+		phi, ok := list.Phi(TestAddress2)
+		Expect(ok).To(BeTrue())
+		Expect(phi).To(BeNumerically("<", 1))
+	})
+
+	It("should not declare members suspect by phi with the SWIM failure detector", func() {
+		list := newPhiTestList()
+		for range 10 {
+			runProtocolPeriod(list, true)
+		}
+		for range 10 {
+			runProtocolPeriod(list, false)
+		}
+		phi, ok := list.Phi(TestAddress2)
+		Expect(ok).To(BeTrue())
+		Expect(phi).To(BeNumerically(">", 8))
+		Expect(stateOf(list)).To(Equal(encoding.MemberStateAlive))
+	})
+})
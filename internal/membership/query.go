@@ -0,0 +1,118 @@
+package membership
+
+import (
+	"fmt"
+
+	"github.com/backbone81/membership/internal/encoding"
+)
+
+// queryReservedLength is the number of bytes in a datagram which are reserved for the message a query is piggybacked
+// on. It covers the biggest message sent with gossip.
+const queryReservedLength = 32
+
+// Query disseminates a query to all members of the cluster. The query piggybacks on the gossip of pings and acks. Every
+// member which matches the filter acknowledges the query and hands it over to the QueryCallback. An empty filter
+// matches all members.
+//
+// The caller is responsible for providing a query id which is unique for queries of this member. The query is not
+// delivered to this member itself.
+func (l *List) Query(queryID uint32, name string, payload []byte, filter []encoding.Address) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	query := encoding.MessageQuery{
+		Source:  l.self,
+		QueryID: queryID,
+		Name:    name,
+		Payload: payload,
+		Filter:  filter,
+	}
+	buffer, _, err := query.AppendToBuffer(l.datagramBuffer[:0])
+	if err != nil {
+		return err
+	}
+	if maxLength := l.config.MaxDatagramLengthSend - queryReservedLength; len(buffer) > maxLength {
+		return fmt.Errorf("query with %d bytes exceeds the maximum of %d bytes", len(buffer), maxLength)
+	}
+
+	l.queryHistory.Add(l.self, queryID)
+	return l.queryQueue.Add(query)
+}
+
+// RespondToQuery sends the response to a query directly to the member which started the query. Small responses are
+// sent over UDP, responses which exceed the maximum datagram length are sent over TCP.
+func (l *List) RespondToQuery(source encoding.Address, queryID uint32, payload []byte) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	queryResponse := encoding.MessageQueryResponse{
+		Source:  l.self,
+		QueryID: queryID,
+		Payload: payload,
+	}
+	var err error
+	l.datagramBuffer, _, err = queryResponse.AppendToBuffer(l.datagramBuffer[:0])
+	if err != nil {
+		return err
+	}
+
+	if len(l.datagramBuffer) > l.config.MaxDatagramLengthSend {
+		return l.config.TCPClient.Send(source, l.datagramBuffer)
+	}
+	return l.config.UDPClient.Send(source, l.datagramBuffer)
+}
+
+func (l *List) handleQuery(query encoding.MessageQuery) error {
+	if query.Source == l.self {
+		// Our own query came back to us.
+		return nil
+	}
+	if !l.queryHistory.Add(query.Source, query.QueryID) {
+		// We already processed this query.
+		return nil
+	}
+
+	logger := l.logger.V(2)
+	if logger.Enabled() {
+		// We only spend the memory allocation for interface boxing of the key value pairs when the log level would
+		// actually produce this log entry.
+		logger.Info(
+			"Received query",
+			"source", query.Source,
+			"query-id", query.QueryID,
+			"name", query.Name,
+		)
+	}
+
+	// Relay the query to the other members.
+	if err := l.queryQueue.Add(query); err != nil {
+		return err
+	}
+
+	if !query.Matches(l.self) {
+		return nil
+	}
+	queryAck := encoding.MessageQueryAck{
+		Source:  l.self,
+		QueryID: query.QueryID,
+	}
+	if err := l.sendWithGossip(query.Source, queryAck.ToMessage()); err != nil {
+		return err
+	}
+	if l.config.QueryCallback != nil {
+		l.config.QueryCallback(query)
+	}
+	return nil
+}
+
+func (l *List) handleQueryAck(queryAck encoding.MessageQueryAck) {
+	if l.config.QueryAckCallback != nil {
+		l.config.QueryAckCallback(queryAck.Source, queryAck.QueryID)
+	}
+}
+
+func (l *List) handleQueryResponse(queryResponse encoding.MessageQueryResponse) {
+	if l.config.QueryResponseCallback != nil {
+		l.config.QueryResponseCallback(queryResponse.Source, queryResponse.QueryID, queryResponse.Payload)
+	}
+}
@@ -0,0 +1,154 @@
+package membership_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/encoding"
+	"github.com/backbone81/membership/internal/membership"
+	"github.com/backbone81/membership/internal/transport"
+)
+
+var _ = Describe("Query", func() {
+	It("should piggyback queries on direct pings", func() {
+		var store transport.Store
+		list := newTestList(
+			membership.WithUDPClient(&store),
+			membership.WithBootstrapMembers([]encoding.Address{TestAddress2}),
+		)
+		Expect(list.Query(7, "test", []byte("payload"), nil)).To(Succeed())
+		Expect(list.DirectPing()).To(Succeed())
+		Expect(store.Buffers).To(HaveLen(1))
+
+		buffer := store.Buffers[0]
+		var directPing encoding.MessageDirectPing
+		n, err := directPing.FromBuffer(buffer)
+		Expect(err).ToNot(HaveOccurred())
+		var query encoding.MessageQuery
+		Expect(query.FromBuffer(buffer[n:])).Error().ToNot(HaveOccurred())
+		Expect(query.Source).To(Equal(TestAddress))
+		Expect(query.QueryID).To(Equal(uint32(7)))
+		Expect(query.Name).To(Equal("test"))
+		Expect(query.Payload).To(Equal([]byte("payload")))
+	})
+
+	It("should reject queries which do not fit into a datagram", func() {
+		list := newTestList()
+		Expect(list.Query(1, "test", make([]byte, membership.DefaultConfig.MaxDatagramLengthSend), nil)).ToNot(Succeed())
+	})
+
+	It("should acknowledge, process and relay a matching query only once", func() {
+		var store transport.Store
+		var received []encoding.MessageQuery
+		list := newTestList(
+			membership.WithUDPClient(&store),
+			membership.WithBootstrapMembers([]encoding.Address{TestAddress3}),
+			membership.WithQueryCallback(func(query encoding.MessageQuery) {
+				received = append(received, query)
+			}),
+		)
+		query := encoding.MessageQuery{
+			Source:  TestAddress2,
+			QueryID: 3,
+			Name:    "test",
+			Filter:  []encoding.Address{TestAddress},
+		}
+		Expect(DispatchDatagram(list, query.ToMessage())).To(Succeed())
+		Expect(DispatchDatagram(list, query.ToMessage())).To(Succeed())
+		Expect(received).To(HaveLen(1))
+		Expect(received[0].Source).To(Equal(TestAddress2))
+		Expect(received[0].QueryID).To(Equal(uint32(3)))
+
+		Expect(store.Addresses).To(Equal([]encoding.Address{TestAddress2}))
+		var queryAck encoding.MessageQueryAck
+		Expect(queryAck.FromBuffer(store.Buffers[0])).Error().ToNot(HaveOccurred())
+		Expect(queryAck.Source).To(Equal(TestAddress))
+		Expect(queryAck.QueryID).To(Equal(uint32(3)))
+
+		store.Clear()
+		Expect(list.DirectPing()).To(Succeed())
+		var directPing encoding.MessageDirectPing
+		n, err := directPing.FromBuffer(store.Buffers[0])
+		Expect(err).ToNot(HaveOccurred())
+		var relayed encoding.MessageQuery
+		Expect(relayed.FromBuffer(store.Buffers[0][n:])).Error().ToNot(HaveOccurred())
+		Expect(relayed.Source).To(Equal(TestAddress2))
+		Expect(relayed.QueryID).To(Equal(uint32(3)))
+	})
+
+	It("should relay but not process a query which does not match", func() {
+		var store transport.Store
+		var received int
+		list := newTestList(
+			membership.WithUDPClient(&store),
+			membership.WithQueryCallback(func(query encoding.MessageQuery) {
+				received++
+			}),
+		)
+		Expect(DispatchDatagram(list, encoding.MessageQuery{
+			Source:  TestAddress2,
+			QueryID: 3,
+			Filter:  []encoding.Address{TestAddress3},
+		}.ToMessage())).To(Succeed())
+		Expect(received).To(Equal(0))
+		Expect(store.Buffers).To(BeEmpty())
+	})
+
+	It("should ignore its own queries", func() {
+		var received int
+		list := newTestList(
+			membership.WithQueryCallback(func(query encoding.MessageQuery) {
+				received++
+			}),
+		)
+		Expect(DispatchDatagram(list, encoding.MessageQuery{
+			Source:  TestAddress,
+			QueryID: 3,
+		}.ToMessage())).To(Succeed())
+		Expect(received).To(Equal(0))
+	})
+
+	It("should report acks and responses", func() {
+		var acks []encoding.Address
+		var payloads []string
+		list := newTestList(
+			membership.WithQueryAckCallback(func(source encoding.Address, queryID uint32) {
+				acks = append(acks, source)
+			}),
+			membership.WithQueryResponseCallback(func(source encoding.Address, queryID uint32, payload []byte) {
+				payloads = append(payloads, string(payload))
+			}),
+		)
+		Expect(DispatchDatagram(list, encoding.MessageQueryAck{
+			Source:  TestAddress2,
+			QueryID: 3,
+		}.ToMessage())).To(Succeed())
+		Expect(DispatchDatagram(list, encoding.MessageQueryResponse{
+			Source:  TestAddress2,
+			QueryID: 3,
+			Payload: []byte("answer"),
+		}.ToMessage())).To(Succeed())
+		Expect(acks).To(Equal([]encoding.Address{TestAddress2}))
+		Expect(payloads).To(Equal([]string{"answer"}))
+	})
+
+	It("should send small responses over UDP and big responses over TCP", func() {
+		var udpStore, tcpStore transport.Store
+		list := newTestList(
+			membership.WithUDPClient(&udpStore),
+			membership.WithTCPClient(&tcpStore),
+		)
+		Expect(list.RespondToQuery(TestAddress2, 3, []byte("small"))).To(Succeed())
+		Expect(udpStore.Addresses).To(Equal([]encoding.Address{TestAddress2}))
+		Expect(tcpStore.Addresses).To(BeEmpty())
+
+		Expect(list.RespondToQuery(TestAddress2, 4, make([]byte, 1024))).To(Succeed())
+		Expect(udpStore.Addresses).To(HaveLen(1))
+		Expect(tcpStore.Addresses).To(Equal([]encoding.Address{TestAddress2}))
+
+		var queryResponse encoding.MessageQueryResponse
+		Expect(queryResponse.FromBuffer(tcpStore.Buffers[0])).Error().ToNot(HaveOccurred())
+		Expect(queryResponse.QueryID).To(Equal(uint32(4)))
+		Expect(queryResponse.Payload).To(HaveLen(1024))
+	})
+})
@@ -0,0 +1,52 @@
+package membership
+
+// MinorityPartition reports if this member is in a minority partition. This is the case when the cluster size dropped
+// to the quorum fraction of the reference size or below. Applications like a consensus layer can use this to refuse
+// writes or elections. This member is never in a minority partition when running in partial view mode, as the member
+// list does not reflect the cluster size in that mode.
+func (l *List) MinorityPartition() bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.quorumTracker.Minority()
+}
+
+// updateQuorum records the current cluster size with the quorum tracker and notifies about changes of the minority
+// partition state.
+func (l *List) updateQuorum() {
+	if l.config.PartialView {
+		// The active view is a small sample of the cluster and says nothing about the cluster size.
+		return
+	}
+
+	clusterSize := l.members.Len()
+	if !l.config.Passive {
+		// We are part of the cluster ourselves.
+		clusterSize++
+	}
+	changed := l.quorumTracker.EndOfProtocolPeriod(clusterSize)
+	l.config.Metrics.QuorumReferenceMembers.Set(float64(l.quorumTracker.ReferenceSize()))
+	if !changed {
+		return
+	}
+
+	minority := l.quorumTracker.Minority()
+	if minority {
+		l.logger.Info(
+			"Lost quorum, this member is in a minority partition",
+			"cluster-size", clusterSize,
+			"reference-size", l.quorumTracker.ReferenceSize(),
+		)
+		l.config.Metrics.MinorityPartition.Set(1)
+	} else {
+		l.logger.Info(
+			"Regained quorum",
+			"cluster-size", clusterSize,
+			"reference-size", l.quorumTracker.ReferenceSize(),
+		)
+		l.config.Metrics.MinorityPartition.Set(0)
+	}
+	if l.config.MinorityPartitionCallback != nil {
+		l.config.MinorityPartitionCallback(minority)
+	}
+}
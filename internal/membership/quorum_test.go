@@ -0,0 +1,70 @@
+package membership_test
+
+import (
+	"net"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/encoding"
+	"github.com/backbone81/membership/internal/membership"
+)
+
+var _ = Describe("Quorum", func() {
+	TestAddress4 := encoding.NewAddress(net.IPv4(31, 32, 33, 34), 1024)
+
+	It("should report a minority partition when half of the cluster is gone", func() {
+		var notifications []bool
+		list := newTestList(membership.WithMinorityPartitionCallback(func(minority bool) {
+			notifications = append(notifications, minority)
+		}))
+		membership.DebugList(list).SetMembers([]encoding.Member{
+			{Address: TestAddress2, State: encoding.MemberStateAlive},
+			{Address: TestAddress3, State: encoding.MemberStateAlive},
+			{Address: TestAddress4, State: encoding.MemberStateAlive},
+		})
+		Expect(list.EndOfProtocolPeriod()).To(Succeed())
+		Expect(list.MinorityPartition()).To(BeFalse())
+
+		By("losing one member")
+		Expect(DispatchDatagram(list, encoding.MessageFaulty{
+			Source:      TestAddress2,
+			Destination: TestAddress3,
+		}.ToMessage())).To(Succeed())
+		Expect(list.EndOfProtocolPeriod()).To(Succeed())
+		Expect(list.MinorityPartition()).To(BeFalse())
+
+		By("losing another member")
+		Expect(DispatchDatagram(list, encoding.MessageFaulty{
+			Source:      TestAddress2,
+			Destination: TestAddress4,
+		}.ToMessage())).To(Succeed())
+		Expect(list.EndOfProtocolPeriod()).To(Succeed())
+		Expect(list.MinorityPartition()).To(BeTrue())
+		Expect(notifications).To(Equal([]bool{true}))
+
+		By("getting a member back")
+		Expect(DispatchDatagram(list, encoding.MessageAlive{
+			Destination:       TestAddress3,
+			IncarnationNumber: 1,
+		}.ToMessage())).To(Succeed())
+		Expect(list.EndOfProtocolPeriod()).To(Succeed())
+		Expect(list.MinorityPartition()).To(BeFalse())
+		Expect(notifications).To(Equal([]bool{true, false}))
+	})
+
+	It("should report a minority partition against the expected cluster size", func() {
+		list := newTestList(membership.WithExpectedClusterSize(5))
+		membership.DebugList(list).SetMembers([]encoding.Member{
+			{Address: TestAddress2, State: encoding.MemberStateAlive},
+		})
+		Expect(list.EndOfProtocolPeriod()).To(Succeed())
+		Expect(list.MinorityPartition()).To(BeTrue())
+	})
+
+	It("should not report a minority partition in partial view mode", func() {
+		list := newTestList(membership.WithPartialView(true), membership.WithExpectedClusterSize(5))
+		Expect(list.EndOfProtocolPeriod()).To(Succeed())
+		Expect(list.MinorityPartition()).To(BeFalse())
+	})
+})
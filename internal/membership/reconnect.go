@@ -0,0 +1,181 @@
+package membership
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/backbone81/membership/internal/encoding"
+	"github.com/backbone81/membership/internal/utility"
+)
+
+// reconnect tries to heal network partitions by contacting members which dropped from the member list. Bootstrap
+// members are re-added to the member list, while a sample of faulty members is probed with a direct ping. Every address
+// is subject to exponential backoff, to not contact unreachable members over and over again.
+func (l *List) reconnect() error {
+	// Probes from the previous protocol period which were not acknowledged failed. The backoff was already applied when
+	// the probe was sent.
+	l.pendingReconnectProbes = l.pendingReconnectProbes[:0]
+
+	l.reconnectBackoff.EndOfProtocolPeriod()
+	l.reconnectBackoff.Prune(func(address encoding.Address) bool {
+		// We only need to remember addresses we might still reconnect to.
+		if _, found := l.faultyMembers.Get(address); found {
+			return true
+		}
+		return l.members.Contains(address) || slices.ContainsFunc(l.config.BootstrapMembers, address.Equal)
+	})
+
+	if l.config.PartialView {
+		// Members return to the active view through the passive view or by joining through a bootstrap member again.
+		return l.maintainPartialView()
+	}
+	return errors.Join(
+		l.reconnectBootstrapMembers(),
+		l.probeFaultyMembers(),
+	)
+}
+
+func (l *List) reconnectBootstrapMembers() error {
+	if !l.config.ReconnectBootstrapMembers {
+		return nil
+	}
+
+	var joinedErr error
+	for _, bootstrapMember := range l.config.BootstrapMembers {
+		if l.members.Contains(bootstrapMember) {
+			// The bootstrap member is part of the members list. No need to re-add it.
+			continue
+		}
+		if _, found := l.faultyMembers.Get(bootstrapMember); found {
+			// The bootstrap member is part of the faulty members list. No need to re-add it, it is probed like all the
+			// other faulty members.
+			continue
+		}
+		if _, found := l.forceRemovedMembers.Get(bootstrapMember); found {
+			// The bootstrap member was force removed. We must not re-add it.
+			continue
+		}
+		if !l.reconnectBackoff.Due(bootstrapMember) {
+			// The bootstrap member did not respond to earlier attempts. We wait a little longer before trying again.
+			continue
+		}
+
+		// The bootstrap member is not in members or faulty members. Re-add it to try to fix a network split.
+		l.logger.Info("Re-adding bootstrap member",
+			"destination", bootstrapMember,
+		)
+		l.addMember(encoding.Member{
+			Address:           bootstrapMember,
+			State:             encoding.MemberStateAlive,
+			IncarnationNumber: 0,
+		})
+		l.reconnectedBootstraps[bootstrapMember] = struct{}{}
+		l.reconnectBackoff.Attempted(bootstrapMember)
+
+		// We also request the full member list immediately to try and consolidate the two partitions as quickly as
+		// possible.
+		if err := l.sendListRequest(bootstrapMember); err != nil {
+			joinedErr = errors.Join(joinedErr, err)
+		}
+	}
+	return joinedErr
+}
+
+// probeFaultyMembers sends a direct ping to a random sample of faulty members which are due for a reconnect attempt.
+// The faulty member did not necessarily crash, it might just have been on the other side of a network partition.
+// Force removed members are never probed.
+func (l *List) probeFaultyMembers() error {
+	if l.config.ReconnectFaultyMemberCount == 0 {
+		return nil
+	}
+
+	l.reconnectScratchSpace = l.reconnectScratchSpace[:0]
+	l.faultyMembers.ForEach(func(member encoding.Member) bool {
+		if _, found := l.forceRemovedMembers.Get(member.Address); found {
+			// The member was force removed. It is gone for good and we must not reconnect to it.
+			return true
+		}
+		if l.reconnectBackoff.Due(member.Address) {
+			l.reconnectScratchSpace = append(l.reconnectScratchSpace, member)
+		}
+		return true
+	})
+
+	var joinedErr error
+	l.randomMemberPicker.Pick(l.config.ReconnectFaultyMemberCount, l.reconnectScratchSpace, func(member encoding.Member) {
+		l.reconnectBackoff.Attempted(member.Address)
+		if err := l.sendReconnectProbe(member); err != nil {
+			joinedErr = errors.Join(joinedErr, err)
+		}
+	})
+	return joinedErr
+}
+
+// sendReconnectProbe sends a direct ping to the given faulty member. The ping is preceded by the faulty message about
+// the member. A member which is still running refutes the faulty message before processing the ping, and the alive
+// message of the refutation is then piggybacked on the direct ack. No other gossip is attached, as the member most
+// likely does not know about most of our members anyway.
+func (l *List) sendReconnectProbe(member encoding.Member) error {
+	directPing := encoding.MessageDirectPing{
+		Source:         l.self,
+		SequenceNumber: l.nextSequenceNumber,
+	}
+	l.nextSequenceNumber++
+
+	logger := l.logger.V(1)
+	if logger.Enabled() {
+		// We only spend the memory allocation for interface boxing of the key value pairs when the log level would
+		// actually produce this log entry.
+		logger.Info(
+			"Probing faulty member",
+			"destination", member.Address,
+			"sequence-number", directPing.SequenceNumber,
+		)
+	}
+
+	var err error
+	l.datagramBuffer, _, err = encoding.MessageFaulty{
+		Source:            l.self,
+		Destination:       member.Address,
+		IncarnationNumber: member.IncarnationNumber,
+	}.AppendToBuffer(l.datagramBuffer[:0])
+	if err != nil {
+		return err
+	}
+	l.datagramBuffer, _, err = directPing.AppendToBuffer(l.datagramBuffer)
+	if err != nil {
+		return err
+	}
+
+	l.pendingReconnectProbes = append(l.pendingReconnectProbes, PendingDirectPing{
+		Timestamp:         time.Now(),
+		Destination:       member.Address,
+		MessageDirectPing: directPing,
+	})
+	if err := l.config.UDPClient.Send(member.Address, l.datagramBuffer); err != nil {
+		return err
+	}
+	l.config.Metrics.ReconnectProbesTotal.Inc()
+	return nil
+}
+
+// handleDirectAckForReconnectProbes processes direct acks to our reconnect probes. A faulty member which responds is
+// reachable again, so we exchange member lists with it to merge the two partitions.
+func (l *List) handleDirectAckForReconnectProbes(directAck encoding.MessageDirectAck) error {
+	pendingProbeIndex := slices.IndexFunc(l.pendingReconnectProbes, func(record PendingDirectPing) bool {
+		return record.MessageDirectPing.SequenceNumber == directAck.SequenceNumber &&
+			record.Destination.Equal(directAck.Source)
+	})
+	if pendingProbeIndex == -1 {
+		return nil
+	}
+	l.pendingReconnectProbes = utility.SwapDelete(l.pendingReconnectProbes, pendingProbeIndex)
+	l.reconnectBackoff.Succeeded(directAck.Source)
+
+	l.logger.Info("Faulty member is reachable again",
+		"destination", directAck.Source,
+	)
+	l.config.Metrics.ReconnectProbesAckedTotal.Inc()
+	return l.sendListRequest(directAck.Source)
+}
@@ -0,0 +1,141 @@
+package membership_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/encoding"
+	"github.com/backbone81/membership/internal/membership"
+	"github.com/backbone81/membership/internal/transport"
+)
+
+var _ = Describe("Reconnect", func() {
+	// newFaultyTestList returns a list with TestAddress2 as a faulty member. The list sends its UDP messages to the
+	// given store.
+	newFaultyTestList := func(store *transport.Store, options ...membership.Option) *membership.List {
+		list := newTestList(append([]membership.Option{membership.WithUDPClient(store)}, options...)...)
+		membership.DebugList(list).SetFaultyMembers([]encoding.Member{
+			{
+				Address:           TestAddress2,
+				State:             encoding.MemberStateFaulty,
+				IncarnationNumber: 3,
+			},
+		})
+		return list
+	}
+
+	// parseProbe returns the direct ping of the probe which was sent last.
+	parseProbe := func(store *transport.Store) encoding.MessageDirectPing {
+		buffer := store.Buffers[len(store.Buffers)-1]
+		var faulty encoding.MessageFaulty
+		n, err := faulty.FromBuffer(buffer)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(faulty).To(Equal(encoding.MessageFaulty{
+			Source:            TestAddress,
+			Destination:       TestAddress2,
+			IncarnationNumber: 3,
+		}))
+		var directPing encoding.MessageDirectPing
+		Expect(directPing.FromBuffer(buffer[n:])).Error().ToNot(HaveOccurred())
+		return directPing
+	}
+
+	It("should probe faulty members", func() {
+		var store transport.Store
+		list := newFaultyTestList(&store)
+		Expect(list.EndOfProtocolPeriod()).To(Succeed())
+		Expect(store.Addresses).To(Equal([]encoding.Address{TestAddress2}))
+		Expect(parseProbe(&store).Source).To(Equal(TestAddress))
+	})
+
+	It("should not probe faulty members when disabled", func() {
+		var store transport.Store
+		list := newFaultyTestList(&store, membership.WithReconnectFaultyMemberCount(0))
+		Expect(list.EndOfProtocolPeriod()).To(Succeed())
+		Expect(store.Addresses).To(BeEmpty())
+	})
+
+	It("should not probe force removed members", func() {
+		var store transport.Store
+		list := newFaultyTestList(&store)
+		Expect(list.ForceRemove(TestAddress2)).To(Succeed())
+		Expect(list.EndOfProtocolPeriod()).To(Succeed())
+		Expect(store.Addresses).To(BeEmpty())
+	})
+
+	It("should back off from faulty members which do not respond", func() {
+		var store transport.Store
+		list := newFaultyTestList(&store)
+		for range 31 {
+			Expect(list.EndOfProtocolPeriod()).To(Succeed())
+		}
+		// Without jitter, the probes happen in the periods 1, 2, 4, 8 and 16. Jitter might delay the last one beyond the
+		// periods we observe.
+		Expect(len(store.Addresses)).To(And(
+			BeNumerically(">=", 4),
+			BeNumerically("<=", 5),
+		))
+	})
+
+	It("should request the member list of faulty members which respond", func() {
+		var store transport.Store
+		list := newFaultyTestList(&store)
+		Expect(list.EndOfProtocolPeriod()).To(Succeed())
+		directPing := parseProbe(&store)
+		store.Clear()
+
+		Expect(DispatchDatagram(list, encoding.MessageDirectAck{
+			Source:         TestAddress2,
+			SequenceNumber: directPing.SequenceNumber,
+		}.ToMessage())).To(Succeed())
+		Expect(store.Addresses).To(Equal([]encoding.Address{TestAddress2}))
+		var listRequest encoding.MessageListRequest
+		Expect(listRequest.FromBuffer(store.Buffers[0])).Error().ToNot(HaveOccurred())
+		Expect(listRequest.Source).To(Equal(TestAddress))
+
+		By("reviving the member through its refutation")
+		Expect(DispatchDatagram(list, encoding.MessageAlive{
+			Destination:       TestAddress2,
+			IncarnationNumber: 4,
+		}.ToMessage())).To(Succeed())
+		Expect(Collect(list)).To(Equal([]encoding.Address{TestAddress2}))
+	})
+
+	It("should ignore acks for unknown probes", func() {
+		var store transport.Store
+		list := newFaultyTestList(&store)
+		Expect(list.EndOfProtocolPeriod()).To(Succeed())
+		directPing := parseProbe(&store)
+		store.Clear()
+
+		Expect(DispatchDatagram(list, encoding.MessageDirectAck{
+			Source:         TestAddress2,
+			SequenceNumber: directPing.SequenceNumber + 1,
+		}.ToMessage())).To(Succeed())
+		Expect(store.Addresses).To(BeEmpty())
+	})
+
+	It("should back off from bootstrap members which do not respond", func() {
+		var store transport.Store
+		list := newTestList(
+			membership.WithUDPClient(&store),
+			membership.WithBootstrapMembers([]encoding.Address{TestAddress2}),
+			membership.WithReconnectMinDelay(4),
+		)
+		debugList := membership.DebugList(list)
+
+		By("re-adding the bootstrap member right away the first time")
+		debugList.SetMembers(nil)
+		Expect(list.EndOfProtocolPeriod()).To(Succeed())
+		Expect(list.Len()).To(Equal(1))
+
+		By("waiting before re-adding the bootstrap member the second time")
+		debugList.SetMembers(nil)
+		Expect(list.EndOfProtocolPeriod()).To(Succeed())
+		Expect(list.Len()).To(Equal(0))
+		for range 6 {
+			Expect(list.EndOfProtocolPeriod()).To(Succeed())
+		}
+		Expect(list.Len()).To(Equal(1))
+	})
+})
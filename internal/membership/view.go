@@ -0,0 +1,218 @@
+package membership
+
+import (
+	"slices"
+
+	"github.com/backbone81/membership/internal/encoding"
+)
+
+// viewChunkLength is the maximum number of members in a single chunk of a view. Views share all chunks which did not
+// change with the view published before. This keeps the cost of publishing a view after a change independent of the
+// number of members, which would otherwise make mass joins quadratic.
+const viewChunkLength = 256
+
+// View is an immutable snapshot of the members which were alive or suspect at some point in time. Views are published
+// by the membership list after every batch of changes and can be read without taking the lock of the membership list.
+// A view never changes after it was published. Get the latest view from List.View to observe newer changes.
+type View struct {
+	// version is incremented with every view published by the same membership list. A bigger version is a newer view.
+	version uint64
+
+	// chunks holds the members sorted by address, split into chunks of at most viewChunkLength members. No chunk is
+	// empty. Chunks are shared between views and must never be modified after the view was published.
+	chunks [][]encoding.Member
+
+	// length is the number of members over all chunks.
+	length int
+}
+
+// Version returns the version of this view. Every view published by the same membership list has a bigger version
+// than the views before.
+func (v *View) Version() uint64 {
+	return v.version
+}
+
+// Len returns the number of members which were alive or suspect.
+func (v *View) Len() int {
+	return v.length
+}
+
+// ForEach executes the given function for all members of the view. The members are sorted by address ascending.
+// Return false to abort the iteration.
+func (v *View) ForEach(fn func(encoding.Member) bool) {
+	for _, chunk := range v.chunks {
+		for _, member := range chunk {
+			if !fn(member) {
+				return
+			}
+		}
+	}
+}
+
+// Get returns the member with the given address. Reports false if the address is not a member of the view.
+func (v *View) Get(address encoding.Address) (encoding.Member, bool) {
+	chunkIndex := viewChunkIndex(v.chunks, address)
+	if chunkIndex == len(v.chunks) {
+		return encoding.Member{}, false
+	}
+	chunk := v.chunks[chunkIndex]
+	index, found := slices.BinarySearchFunc(chunk, encoding.Member{Address: address}, encoding.CompareMember)
+	if !found {
+		return encoding.Member{}, false
+	}
+	return chunk[index], true
+}
+
+// viewChunkIndex returns the index of the chunk the given address belongs to. This is the first chunk with a last
+// member not smaller than the address. Returns the number of chunks when the address is bigger than all members.
+func viewChunkIndex(chunks [][]encoding.Member, address encoding.Address) int {
+	index, _ := slices.BinarySearchFunc(chunks, address, func(chunk []encoding.Member, address encoding.Address) int {
+		return encoding.CompareAddress(chunk[len(chunk)-1].Address, address)
+	})
+	return index
+}
+
+// View returns the latest view of the members. It does not take the lock of the membership list and never blocks
+// network processing.
+func (l *List) View() *View {
+	return l.view.Load()
+}
+
+// publishView publishes the current members as a new view, if the members changed since the last view was published.
+// Must be called with the lock of the membership list held. Only the chunks with changed members are copied, all other
+// chunks are shared with the view published before. Message processing without membership changes does not publish
+// anything, which keeps it free of memory allocations.
+func (l *List) publishView() {
+	if !l.viewRebuild && len(l.viewChanges) == 0 {
+		return
+	}
+	if l.viewRebuild {
+		l.rebuildViewChunks(l.sortedMembers())
+	} else {
+		l.applyViewChanges()
+	}
+	l.viewRebuild = false
+	l.viewChanges = l.viewChanges[:0]
+	l.viewVersion++
+	l.view.Store(&View{
+		version: l.viewVersion,
+		chunks:  slices.Clone(l.viewChunks),
+		length:  l.viewLength,
+	})
+}
+
+// viewChanged records that the member with the given address was added, changed or removed. The change is picked up
+// by the next view published.
+func (l *List) viewChanged(address encoding.Address) {
+	l.viewChanges = append(l.viewChanges, address)
+}
+
+// applyViewChanges updates the chunks of the view with the current state of all members which changed since the last
+// view was published. Chunks which are shared with published views are copied before they are modified.
+func (l *List) applyViewChanges() {
+	// None of the chunks is owned by us right after publishing, they are all shared with the latest view.
+	l.viewOwned = slices.Grow(l.viewOwned[:0], len(l.viewChunks))[:len(l.viewChunks)]
+	clear(l.viewOwned)
+
+	for _, address := range l.viewChanges {
+		member, found := l.members.Get(address)
+		if found {
+			l.upsertViewMember(*member)
+		} else {
+			l.removeViewMember(address)
+		}
+	}
+
+	if len(l.viewChunks) > 1 && l.viewLength < len(l.viewChunks)*viewChunkLength/4 {
+		// Many members were removed, leaving mostly empty chunks behind. We pack the members into full chunks again to
+		// keep the number of chunks proportional to the number of members.
+		l.rebuildViewChunks(slices.Concat(l.viewChunks...))
+	}
+}
+
+// upsertViewMember adds the given member to the chunks of the view or updates the existing member.
+func (l *List) upsertViewMember(member encoding.Member) {
+	if len(l.viewChunks) == 0 {
+		l.viewChunks = append(l.viewChunks, nil)
+		l.viewOwned = append(l.viewOwned, false)
+	}
+	chunkIndex := 0
+	if len(l.viewChunks[0]) > 0 {
+		// Members bigger than all others go into the last chunk.
+		chunkIndex = min(viewChunkIndex(l.viewChunks, member.Address), len(l.viewChunks)-1)
+	}
+	chunk := l.ownViewChunk(chunkIndex)
+	index, found := slices.BinarySearchFunc(chunk, member, encoding.CompareMember)
+	if found {
+		chunk[index] = member
+		return
+	}
+	chunk = slices.Insert(chunk, index, member)
+	l.viewLength++
+	if len(chunk) <= viewChunkLength {
+		l.viewChunks[chunkIndex] = chunk
+		return
+	}
+
+	// The chunk is full, we split it in half.
+	half := len(chunk) / 2
+	upperHalf := make([]encoding.Member, len(chunk)-half, viewChunkLength+1)
+	copy(upperHalf, chunk[half:])
+	l.viewChunks[chunkIndex] = chunk[:half]
+	l.viewChunks = slices.Insert(l.viewChunks, chunkIndex+1, upperHalf)
+	l.viewOwned = slices.Insert(l.viewOwned, chunkIndex+1, true)
+}
+
+// removeViewMember removes the member with the given address from the chunks of the view, if it is there.
+func (l *List) removeViewMember(address encoding.Address) {
+	chunkIndex := viewChunkIndex(l.viewChunks, address)
+	if chunkIndex == len(l.viewChunks) {
+		return
+	}
+	index, found := slices.BinarySearchFunc(
+		l.viewChunks[chunkIndex],
+		encoding.Member{Address: address},
+		encoding.CompareMember,
+	)
+	if !found {
+		return
+	}
+	l.viewLength--
+	if len(l.viewChunks[chunkIndex]) == 1 {
+		// Chunks are never empty.
+		l.viewChunks = slices.Delete(l.viewChunks, chunkIndex, chunkIndex+1)
+		l.viewOwned = slices.Delete(l.viewOwned, chunkIndex, chunkIndex+1)
+		return
+	}
+	l.viewChunks[chunkIndex] = slices.Delete(l.ownViewChunk(chunkIndex), index, index+1)
+}
+
+// ownViewChunk returns the chunk with the given index for modification. The chunk is copied first, unless it was
+// already copied since the latest view was published. The copy has room for one more member than a full chunk holds,
+// which allows for inserting before splitting without growing the chunk.
+func (l *List) ownViewChunk(chunkIndex int) []encoding.Member {
+	if !l.viewOwned[chunkIndex] {
+		chunk := make([]encoding.Member, len(l.viewChunks[chunkIndex]), viewChunkLength+1)
+		copy(chunk, l.viewChunks[chunkIndex])
+		l.viewChunks[chunkIndex] = chunk
+		l.viewOwned[chunkIndex] = true
+	}
+	return l.viewChunks[chunkIndex]
+}
+
+// rebuildViewChunks replaces the chunks of the view with full chunks of the given members. The members must be sorted
+// by address and must not be used by the caller afterward.
+func (l *List) rebuildViewChunks(members []encoding.Member) {
+	l.viewChunks = l.viewChunks[:0]
+	for chunk := range slices.Chunk(members, viewChunkLength) {
+		l.viewChunks = append(l.viewChunks, chunk)
+	}
+	l.viewLength = len(members)
+}
+
+// sortedMembers returns a copy of all members sorted by address.
+func (l *List) sortedMembers() []encoding.Member {
+	members := slices.Clone(l.members.Members())
+	slices.SortFunc(members, encoding.CompareMember)
+	return members
+}
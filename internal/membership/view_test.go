@@ -0,0 +1,146 @@
+package membership_test
+
+import (
+	"math/rand/v2"
+	"net"
+	"slices"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/encoding"
+	"github.com/backbone81/membership/internal/membership"
+)
+
+var _ = Describe("View", func() {
+	It("should publish an initial view", func() {
+		list := newTestList(
+			membership.WithBootstrapMembers([]encoding.Address{TestAddress2}),
+		)
+		view := list.View()
+		Expect(view).ToNot(BeNil())
+		Expect(view.Len()).To(Equal(1))
+		member, found := view.Get(TestAddress2)
+		Expect(found).To(BeTrue())
+		Expect(member.State).To(Equal(encoding.MemberStateAlive))
+		_, found = view.Get(TestAddress3)
+		Expect(found).To(BeFalse())
+	})
+
+	It("should publish a new view on membership changes", func() {
+		list := newTestList()
+		oldView := list.View()
+
+		Expect(DispatchDatagram(list, encoding.MessageAlive{
+			Destination: TestAddress2,
+		}.ToMessage())).To(Succeed())
+		newView := list.View()
+		Expect(newView.Version()).To(BeNumerically(">", oldView.Version()))
+		Expect(newView.Len()).To(Equal(1))
+		Expect(oldView.Len()).To(Equal(0))
+
+		Expect(DispatchDatagram(list, encoding.MessageSuspect{
+			Source:      TestAddress3,
+			Destination: TestAddress2,
+		}.ToMessage())).To(Succeed())
+		suspectView := list.View()
+		Expect(suspectView.Version()).To(BeNumerically(">", newView.Version()))
+		member, _ := suspectView.Get(TestAddress2)
+		Expect(member.State).To(Equal(encoding.MemberStateSuspect))
+		member, _ = newView.Get(TestAddress2)
+		Expect(member.State).To(Equal(encoding.MemberStateAlive))
+	})
+
+	It("should not publish a new view without membership changes", func() {
+		list := newTestList()
+		Expect(DispatchDatagram(list, encoding.MessageAlive{
+			Destination: TestAddress2,
+		}.ToMessage())).To(Succeed())
+		view := list.View()
+
+		Expect(DispatchDatagram(list, encoding.MessageAlive{
+			Destination: TestAddress2,
+		}.ToMessage())).To(Succeed())
+		Expect(DispatchDatagram(list, encoding.MessageDirectPing{
+			Source: TestAddress2,
+		}.ToMessage())).To(Succeed())
+		Expect(list.View()).To(BeIdenticalTo(view))
+	})
+
+	It("should iterate in address order", func() {
+		list := newTestList()
+		for _, address := range []encoding.Address{TestAddress3, TestAddress2} {
+			Expect(DispatchDatagram(list, encoding.MessageAlive{
+				Destination: address,
+			}.ToMessage())).To(Succeed())
+		}
+		var addresses []encoding.Address
+		list.View().ForEach(func(member encoding.Member) bool {
+			addresses = append(addresses, member.Address)
+			return true
+		})
+		Expect(addresses).To(Equal([]encoding.Address{TestAddress2, TestAddress3}))
+	})
+
+	It("should keep views sorted and unchanged across many changes", func() {
+		list := newTestList()
+		addresses := make([]encoding.Address, 0, 1000)
+		for i := range 1000 {
+			addresses = append(addresses, encoding.NewAddress(net.IPv4(10, 0, byte(i/256), byte(i%256)), 1024))
+		}
+		rand.Shuffle(len(addresses), func(i, j int) {
+			addresses[i], addresses[j] = addresses[j], addresses[i]
+		})
+		for _, address := range addresses {
+			Expect(DispatchDatagram(list, encoding.MessageAlive{
+				Destination: address,
+			}.ToMessage())).To(Succeed())
+		}
+		fullView := list.View()
+
+		for _, address := range addresses[:900] {
+			Expect(DispatchDatagram(list, encoding.MessageFaulty{
+				Source:      TestAddress2,
+				Destination: address,
+			}.ToMessage())).To(Succeed())
+		}
+		remaining := slices.SortedFunc(slices.Values(addresses[900:]), encoding.CompareAddress)
+
+		viewAddresses := func(view *membership.View) []encoding.Address {
+			var result []encoding.Address
+			view.ForEach(func(member encoding.Member) bool {
+				result = append(result, member.Address)
+				return true
+			})
+			return result
+		}
+		Expect(list.View().Len()).To(Equal(100))
+		Expect(viewAddresses(list.View())).To(Equal(remaining))
+		_, found := list.View().Get(addresses[0])
+		Expect(found).To(BeFalse())
+		_, found = list.View().Get(addresses[999])
+		Expect(found).To(BeTrue())
+
+		Expect(fullView.Len()).To(Equal(1000))
+		Expect(viewAddresses(fullView)).To(Equal(slices.SortedFunc(slices.Values(addresses), encoding.CompareAddress)))
+		_, found = fullView.Get(addresses[0])
+		Expect(found).To(BeTrue())
+	})
+
+	It("should allow calling the list while iterating", func() {
+		list := newTestList()
+		Expect(DispatchDatagram(list, encoding.MessageAlive{
+			Destination: TestAddress2,
+		}.ToMessage())).To(Succeed())
+		var count int
+		list.ForEach(func(address encoding.Address) bool {
+			Expect(DispatchDatagram(list, encoding.MessageAlive{
+				Destination: TestAddress3,
+			}.ToMessage())).To(Succeed())
+			count++
+			return true
+		})
+		Expect(count).To(Equal(1))
+		Expect(list.Len()).To(Equal(2))
+	})
+})
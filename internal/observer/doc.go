@@ -0,0 +1,3 @@
+// Package observer provides the interface for observing protocol activity. It allows for wiring in tracing or custom
+// telemetry without relying on log output or the global prometheus metrics.
+package observer
@@ -0,0 +1,69 @@
+package observer
+
+import (
+	"time"
+
+	"github.com/backbone81/membership/internal/encoding"
+)
+
+// Observer is notified about protocol activity of the membership list, the scheduler and the transports.
+//
+// All methods are called synchronously from the protocol. Most of them execute under the lock of the membership list.
+// Implementations must return quickly and must not call any method on the membership list, otherwise they create a
+// deadlock. Methods of the transports are called from multiple goroutines, so implementations must be safe for
+// concurrent use.
+//
+// Embed Nop in your implementation to only implement the methods you are interested in.
+type Observer interface {
+	// DirectPingSent is called when a direct ping was sent to the given member.
+	DirectPingSent(destination encoding.Address, sequenceNumber uint16)
+
+	// DirectAckReceived is called when a direct ack was received for a direct ping we sent. It carries the observed
+	// round trip time.
+	DirectAckReceived(source encoding.Address, roundTripTime time.Duration)
+
+	// IndirectPingCompleted is called when an indirect ping we requested for the given member either succeeded or
+	// did not succeed within the protocol period.
+	IndirectPingCompleted(destination encoding.Address, success bool)
+
+	// SuspicionRaised is called when a member became suspect, either because it did not respond to our pings or
+	// because of gossip by some other member.
+	SuspicionRaised(address encoding.Address, incarnationNumber uint16)
+
+	// SuspicionRefuted is called when a suspect member was reported alive with a newer incarnation number. It is also
+	// called when we refuted a suspicion about ourselves.
+	SuspicionRefuted(address encoding.Address, incarnationNumber uint16)
+
+	// GossipSent is called when gossip was piggybacked on a network message to the given member. It carries the number
+	// of gossip messages sent.
+	GossipSent(destination encoding.Address, count int)
+
+	// GossipReceived is called when a network message with gossip was received. It carries the number of gossip
+	// messages received. The source is the zero address when the network message did not tell its sender.
+	GossipReceived(source encoding.Address, count int)
+
+	// DecryptionFailed is called when a network message could not be decrypted with any of the encryption keys. It
+	// carries the name of the transport which received the network message.
+	DecryptionFailed(transport string)
+
+	// ProtocolPeriodOverrun is called when a protocol period took considerably longer than expected. This is a strong
+	// indication that the system is overloaded.
+	ProtocolPeriodOverrun(want time.Duration, got time.Duration)
+}
+
+// Nop is an observer which ignores all events. Embed it in your own observer to only implement the methods you are
+// interested in.
+type Nop struct{}
+
+// Nop implements Observer.
+var _ Observer = Nop{}
+
+func (Nop) DirectPingSent(encoding.Address, uint16)            {}
+func (Nop) DirectAckReceived(encoding.Address, time.Duration)  {}
+func (Nop) IndirectPingCompleted(encoding.Address, bool)       {}
+func (Nop) SuspicionRaised(encoding.Address, uint16)           {}
+func (Nop) SuspicionRefuted(encoding.Address, uint16)          {}
+func (Nop) GossipSent(encoding.Address, int)                   {}
+func (Nop) GossipReceived(encoding.Address, int)               {}
+func (Nop) DecryptionFailed(string)                            {}
+func (Nop) ProtocolPeriodOverrun(time.Duration, time.Duration) {}
@@ -0,0 +1,13 @@
+package passiveview
+
+// Config is the configuration for the passive view.
+type Config struct {
+	// MaxLength is the maximum number of members the passive view holds. Adding a member to a full passive view evicts
+	// a random member.
+	MaxLength int
+}
+
+// DefaultConfig provides a default configuration for the passive view with sane defaults for most situations.
+var DefaultConfig = Config{
+	MaxLength: 30,
+}
@@ -0,0 +1,5 @@
+// Package passiveview provides the passive view of the HyParView membership protocol. The passive view is a bounded
+// random sample of members which are not part of the active view. It serves as a pool of replacements for active
+// members which failed or left. The sample is kept random by evicting random members when full and by periodically
+// exchanging parts of it with other members.
+package passiveview
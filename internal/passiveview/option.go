@@ -0,0 +1,11 @@
+package passiveview
+
+// Option is the function signature for all passive view options to implement.
+type Option func(config *Config)
+
+func WithMaxLength(maxLength int) Option {
+	maxLength = max(1, maxLength)
+	return func(config *Config) {
+		config.MaxLength = maxLength
+	}
+}
@@ -0,0 +1,116 @@
+package passiveview
+
+import (
+	"math/rand"
+
+	"github.com/backbone81/membership/internal/encoding"
+)
+
+// View is a bounded set of member addresses with random eviction. Lookups, additions and removals are constant time.
+//
+// View is not safe for concurrent use by multiple goroutines. Callers must serialize access to all methods.
+type View struct {
+	// config holds the current configuration of the passive view.
+	config Config
+
+	// addresses holds the addresses of the passive view in no particular order.
+	addresses []encoding.Address
+
+	// indexes maps every address to its index in addresses.
+	indexes map[encoding.Address]int
+}
+
+// NewView creates a new passive view.
+func NewView(options ...Option) *View {
+	config := DefaultConfig
+	for _, option := range options {
+		option(&config)
+	}
+	return &View{
+		config:    config,
+		addresses: make([]encoding.Address, 0, config.MaxLength),
+		indexes:   make(map[encoding.Address]int, config.MaxLength),
+	}
+}
+
+// Config returns the current configuration of the passive view.
+func (v *View) Config() Config {
+	return v.config
+}
+
+// Len returns the number of addresses in the passive view.
+func (v *View) Len() int {
+	return len(v.addresses)
+}
+
+// Contains reports if the given address is part of the passive view.
+func (v *View) Contains(address encoding.Address) bool {
+	_, found := v.indexes[address]
+	return found
+}
+
+// Add adds the given address to the passive view. A random address is evicted when the passive view is full. Reports
+// false if the address was already part of the passive view.
+func (v *View) Add(address encoding.Address) bool {
+	if v.Contains(address) {
+		return false
+	}
+	if len(v.addresses) >= v.config.MaxLength {
+		v.Remove(v.addresses[rand.Intn(len(v.addresses))]) //nolint:gosec // we do not need crypto/rand here
+	}
+	v.indexes[address] = len(v.addresses)
+	v.addresses = append(v.addresses, address)
+	return true
+}
+
+// Remove removes the given address from the passive view. Reports false if the address was not part of the passive
+// view.
+func (v *View) Remove(address encoding.Address) bool {
+	index, found := v.indexes[address]
+	if !found {
+		return false
+	}
+	last := len(v.addresses) - 1
+	if index != last {
+		v.addresses[index] = v.addresses[last]
+		v.indexes[v.addresses[index]] = index
+	}
+	v.addresses = v.addresses[:last]
+	delete(v.indexes, address)
+	return true
+}
+
+// Random returns a random address of the passive view. Reports false if the passive view is empty.
+func (v *View) Random() (encoding.Address, bool) {
+	if len(v.addresses) == 0 {
+		return encoding.ZeroAddress, false
+	}
+	return v.addresses[rand.Intn(len(v.addresses))], true //nolint:gosec // we do not need crypto/rand here
+}
+
+// Sample appends up to count unique random addresses of the passive view to the given slice and returns it. This
+// allows the caller to re-use memory.
+func (v *View) Sample(count int, addresses []encoding.Address) []encoding.Address {
+	count = min(count, len(v.addresses))
+
+	// We do a partial Fisher-Yates shuffle in place. The order of the addresses does not carry any meaning, we only need
+	// to keep the indexes up to date.
+	for i := range count {
+		j := i + rand.Intn(len(v.addresses)-i) //nolint:gosec // we do not need crypto/rand here
+		v.addresses[i], v.addresses[j] = v.addresses[j], v.addresses[i]
+		v.indexes[v.addresses[i]] = i
+		v.indexes[v.addresses[j]] = j
+		addresses = append(addresses, v.addresses[i])
+	}
+	return addresses
+}
+
+// ForEach executes the given function for all addresses of the passive view. Return false to abort the iteration. The
+// passive view must not be modified during the iteration.
+func (v *View) ForEach(fn func(address encoding.Address) bool) {
+	for _, address := range v.addresses {
+		if !fn(address) {
+			return
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package passiveview_test
+
+import (
+	"net"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/encoding"
+	"github.com/backbone81/membership/internal/passiveview"
+)
+
+var _ = Describe("View", func() {
+	// addresses returns count distinct addresses.
+	addresses := func(count int) []encoding.Address {
+		result := make([]encoding.Address, 0, count)
+		for i := range count {
+			result = append(result, encoding.NewAddress(net.IPv4(10, 0, byte(i/256), byte(i%256)), 1024))
+		}
+		return result
+	}
+
+	It("should be empty when created", func() {
+		view := passiveview.NewView()
+		Expect(view.Len()).To(BeZero())
+		Expect(view.Random()).Error().To(BeFalse())
+	})
+
+	It("should add addresses only once", func() {
+		view := passiveview.NewView()
+		Expect(view.Add(TestAddress)).To(BeTrue())
+		Expect(view.Add(TestAddress)).To(BeFalse())
+		Expect(view.Len()).To(Equal(1))
+		Expect(view.Contains(TestAddress)).To(BeTrue())
+		Expect(view.Contains(TestAddress2)).To(BeFalse())
+	})
+
+	It("should remove addresses", func() {
+		view := passiveview.NewView()
+		view.Add(TestAddress)
+		view.Add(TestAddress2)
+		Expect(view.Remove(TestAddress)).To(BeTrue())
+		Expect(view.Remove(TestAddress)).To(BeFalse())
+		Expect(view.Contains(TestAddress)).To(BeFalse())
+		Expect(view.Contains(TestAddress2)).To(BeTrue())
+		Expect(view.Len()).To(Equal(1))
+	})
+
+	It("should evict a random address when full", func() {
+		view := passiveview.NewView(passiveview.WithMaxLength(10))
+		for _, address := range addresses(100) {
+			view.Add(address)
+			Expect(view.Contains(address)).To(BeTrue())
+			Expect(view.Len()).To(BeNumerically("<=", 10))
+		}
+		Expect(view.Len()).To(Equal(10))
+	})
+
+	It("should return random addresses", func() {
+		view := passiveview.NewView()
+		view.Add(TestAddress)
+		view.Add(TestAddress2)
+		seen := make(map[encoding.Address]int)
+		for range 100 {
+			address, found := view.Random()
+			Expect(found).To(BeTrue())
+			seen[address]++
+		}
+		Expect(seen).To(HaveLen(2))
+	})
+
+	It("should sample unique addresses", func() {
+		view := passiveview.NewView()
+		for _, address := range addresses(20) {
+			view.Add(address)
+		}
+		sample := view.Sample(5, nil)
+		Expect(sample).To(HaveLen(5))
+		for i, address := range sample {
+			Expect(view.Contains(address)).To(BeTrue())
+			Expect(sample[i+1:]).ToNot(ContainElement(address))
+		}
+	})
+
+	It("should keep lookups working after sampling", func() {
+		view := passiveview.NewView()
+		all := addresses(20)
+		for _, address := range all {
+			view.Add(address)
+		}
+		view.Sample(10, nil)
+		for _, address := range all {
+			Expect(view.Remove(address)).To(BeTrue())
+		}
+		Expect(view.Len()).To(BeZero())
+	})
+
+	It("should limit the sample to the length of the view", func() {
+		view := passiveview.NewView()
+		view.Add(TestAddress)
+		Expect(view.Sample(5, nil)).To(Equal([]encoding.Address{TestAddress}))
+	})
+})
+
+func BenchmarkView_Add(b *testing.B) {
+	view := passiveview.NewView()
+	var i int
+	for b.Loop() {
+		view.Add(encoding.NewAddress(net.IPv4(10, 0, byte(i/256), byte(i%256)), 1024))
+		i++
+	}
+}
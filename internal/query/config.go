@@ -0,0 +1,13 @@
+package query
+
+// Config is the configuration for the query queue.
+type Config struct {
+	// MaxTransmissionCount is the maximum number of times queries in the queue are transmitted before they are
+	// dropped.
+	MaxTransmissionCount int
+}
+
+// DefaultConfig provides a default configuration for the query queue with sane defaults for most situations.
+var DefaultConfig = Config{
+	MaxTransmissionCount: 8,
+}
@@ -0,0 +1,3 @@
+// Package query provides the bookkeeping for disseminating cluster-wide queries as gossip. Queries are not about a
+// specific member and can therefore not be managed by the gossip queue which deduplicates by member address.
+package query
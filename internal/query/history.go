@@ -0,0 +1,59 @@
+package query
+
+import (
+	"github.com/backbone81/membership/internal/encoding"
+)
+
+// historyKey identifies a query within the cluster.
+type historyKey struct {
+	source  encoding.Address
+	queryID uint32
+}
+
+// History remembers the queries which were seen recently. This allows us to process and relay every query only once,
+// even though it arrives over and over again as gossip.
+//
+// History is not safe for concurrent use by multiple goroutines. Callers must serialize access to all methods.
+type History struct {
+	// seen maps every query to the protocol period it was seen first.
+	seen map[historyKey]int
+
+	// period is the current protocol period.
+	period int
+}
+
+// NewHistory creates a new query history.
+func NewHistory() *History {
+	return &History{
+		seen: make(map[historyKey]int),
+	}
+}
+
+// Len returns the number of queries remembered.
+func (h *History) Len() int {
+	return len(h.seen)
+}
+
+// Add remembers the query with the given source and id. Reports false when the query was already seen before.
+func (h *History) Add(source encoding.Address, queryID uint32) bool {
+	key := historyKey{
+		source:  source,
+		queryID: queryID,
+	}
+	if _, found := h.seen[key]; found {
+		return false
+	}
+	h.seen[key] = h.period
+	return true
+}
+
+// EndOfProtocolPeriod moves to the next protocol period and forgets all queries which were seen more than maxAge
+// protocol periods ago.
+func (h *History) EndOfProtocolPeriod(maxAge int) {
+	h.period++
+	for key, period := range h.seen {
+		if h.period-period > maxAge {
+			delete(h.seen, key)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package query_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/query"
+)
+
+var _ = Describe("History", func() {
+	It("should report queries only once", func() {
+		history := query.NewHistory()
+		Expect(history.Add(TestAddress, 1)).To(BeTrue())
+		Expect(history.Add(TestAddress, 1)).To(BeFalse())
+		Expect(history.Add(TestAddress, 2)).To(BeTrue())
+		Expect(history.Add(TestAddress2, 1)).To(BeTrue())
+		Expect(history.Len()).To(Equal(3))
+	})
+
+	It("should forget queries after the maximum age", func() {
+		history := query.NewHistory()
+		Expect(history.Add(TestAddress, 1)).To(BeTrue())
+		history.EndOfProtocolPeriod(2)
+		history.EndOfProtocolPeriod(2)
+		Expect(history.Len()).To(Equal(1))
+		history.EndOfProtocolPeriod(2)
+		Expect(history.Len()).To(Equal(0))
+		Expect(history.Add(TestAddress, 1)).To(BeTrue())
+	})
+})
@@ -0,0 +1,95 @@
+package query
+
+import (
+	"github.com/backbone81/membership/internal/encoding"
+)
+
+// queueEntry is a single query which is disseminated as gossip.
+type queueEntry struct {
+	// data is the encoded query.
+	data []byte
+
+	// transmissionCount is the number of times the query was gossiped.
+	transmissionCount int
+}
+
+// Queue holds the queries which still need to be disseminated as gossip. Queries are stored in their encoded form to
+// not share memory with the network buffer they were received in and to not encode them again for every transmission.
+//
+// Queue is not safe for concurrent use by multiple goroutines. Callers must serialize access to all methods.
+type Queue struct {
+	// entries holds the queries in the order they were added.
+	entries []queueEntry
+
+	// config is the configuration of the queue.
+	config Config
+}
+
+// NewQueue creates a new query queue.
+func NewQueue(options ...Option) *Queue {
+	config := DefaultConfig
+	for _, option := range options {
+		option(&config)
+	}
+	return &Queue{
+		config: config,
+	}
+}
+
+// Len returns the number of queries in the queue.
+func (q *Queue) Len() int {
+	return len(q.entries)
+}
+
+// SetMaxTransmissionCount sets the number of times a query is gossiped before it is dropped. Queries which already
+// reached the new maximum are dropped right away.
+func (q *Queue) SetMaxTransmissionCount(count int) {
+	WithMaxTransmissionCount(count)(&q.config)
+	q.cleanup()
+}
+
+// Add puts the given query into the queue.
+func (q *Queue) Add(query encoding.MessageQuery) error {
+	data, _, err := query.AppendToBuffer(nil)
+	if err != nil {
+		return err
+	}
+	q.entries = append(q.entries, queueEntry{
+		data: data,
+	})
+	return nil
+}
+
+// AppendToBuffer appends as many queries to the given buffer as fit into maxLength bytes. All appended queries are
+// marked as transmitted. Queries which reached the maximum transmission count are dropped.
+// Returns the buffer with the queries appended.
+func (q *Queue) AppendToBuffer(buffer []byte, maxLength int) []byte {
+	if len(q.entries) == 0 {
+		return buffer
+	}
+
+	for i := range q.entries {
+		entry := &q.entries[i]
+		if len(buffer)+len(entry.data) > maxLength {
+			continue
+		}
+		buffer = append(buffer, entry.data...)
+		entry.transmissionCount++
+	}
+	q.cleanup()
+	return buffer
+}
+
+// cleanup drops all queries which reached the maximum transmission count.
+func (q *Queue) cleanup() {
+	n := 0
+	for _, entry := range q.entries {
+		if entry.transmissionCount >= q.config.MaxTransmissionCount {
+			continue
+		}
+		q.entries[n] = entry
+		n++
+	}
+	clear(q.entries[n:])
+	q.entries = q.entries[:n]
+}
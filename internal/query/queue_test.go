@@ -0,0 +1,68 @@
+package query_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/encoding"
+	"github.com/backbone81/membership/internal/query"
+)
+
+var _ = Describe("Queue", func() {
+	It("should not append anything when empty", func() {
+		queue := query.NewQueue()
+		Expect(queue.AppendToBuffer([]byte{1}, 512)).To(Equal([]byte{1}))
+	})
+
+	It("should append queries and drop them after the maximum transmission count", func() {
+		queue := query.NewQueue(query.WithMaxTransmissionCount(2))
+		Expect(queue.Add(encoding.MessageQuery{
+			Source:  TestAddress,
+			QueryID: 1,
+			Name:    "test",
+		})).To(Succeed())
+		Expect(queue.Len()).To(Equal(1))
+
+		buffer := queue.AppendToBuffer(nil, 512)
+		var message encoding.MessageQuery
+		n, err := message.FromBuffer(buffer)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(n).To(Equal(len(buffer)))
+		Expect(message.QueryID).To(Equal(uint32(1)))
+		Expect(queue.Len()).To(Equal(1))
+
+		Expect(queue.AppendToBuffer(nil, 512)).ToNot(BeEmpty())
+		Expect(queue.Len()).To(Equal(0))
+	})
+
+	It("should drop queries when lowering the maximum transmission count", func() {
+		queue := query.NewQueue()
+		Expect(queue.Add(encoding.MessageQuery{
+			Source:  TestAddress,
+			QueryID: 1,
+		})).To(Succeed())
+		Expect(queue.AppendToBuffer(nil, 512)).ToNot(BeEmpty())
+		queue.SetMaxTransmissionCount(1)
+		Expect(queue.AppendToBuffer(nil, 512)).To(BeEmpty())
+		Expect(queue.Len()).To(Equal(0))
+	})
+
+	It("should skip queries which do not fit", func() {
+		queue := query.NewQueue(query.WithMaxTransmissionCount(1))
+		Expect(queue.Add(encoding.MessageQuery{
+			Source:  TestAddress,
+			QueryID: 1,
+			Payload: make([]byte, 100),
+		})).To(Succeed())
+		Expect(queue.Add(encoding.MessageQuery{
+			Source:  TestAddress,
+			QueryID: 2,
+		})).To(Succeed())
+
+		buffer := queue.AppendToBuffer(nil, 50)
+		var message encoding.MessageQuery
+		Expect(message.FromBuffer(buffer)).Error().ToNot(HaveOccurred())
+		Expect(message.QueryID).To(Equal(uint32(2)))
+		Expect(queue.Len()).To(Equal(1))
+	})
+})
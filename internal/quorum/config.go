@@ -0,0 +1,21 @@
+package quorum
+
+// Config is the configuration for the quorum tracker.
+type Config struct {
+	// ExpectedSize is the cluster size the operator expects. It is used as the reference size instead of the largest
+	// cluster size observed recently when bigger than zero.
+	ExpectedSize int
+
+	// Fraction is the fraction of the reference size a partition needs to exceed to have quorum. A fraction of 0.5
+	// requires a strict majority.
+	Fraction float64
+
+	// Window is the number of protocol periods the largest observed cluster size is remembered.
+	Window int
+}
+
+// DefaultConfig provides a default configuration for the quorum tracker with sane defaults for most situations.
+var DefaultConfig = Config{
+	Fraction: 0.5,
+	Window:   300,
+}
@@ -0,0 +1,5 @@
+// Package quorum provides the bookkeeping for detecting that this member ended up in a minority partition. It compares
+// the current cluster size against a reference size, which is either configured by the operator or the largest cluster
+// size observed recently. A cluster which shrinks below the quorum fraction of the reference size is most likely split
+// by a network partition, with this member being on the smaller side.
+package quorum
@@ -0,0 +1,25 @@
+package quorum
+
+// Option is the function signature for all tracker options to implement.
+type Option func(config *Config)
+
+func WithExpectedSize(size int) Option {
+	size = max(0, size)
+	return func(config *Config) {
+		config.ExpectedSize = size
+	}
+}
+
+func WithFraction(fraction float64) Option {
+	fraction = min(1, max(0, fraction))
+	return func(config *Config) {
+		config.Fraction = fraction
+	}
+}
+
+func WithWindow(periods int) Option {
+	periods = max(1, periods)
+	return func(config *Config) {
+		config.Window = periods
+	}
+}
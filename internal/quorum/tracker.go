@@ -0,0 +1,84 @@
+package quorum
+
+// Tracker keeps track of the cluster size over the last protocol periods and reports if the current cluster size lost
+// quorum. Without an expected size, the reference size is the largest cluster size observed within the window. That
+// way a cluster which shrinks slowly over time, like during a scale down, keeps quorum, while a sudden drop like a
+// network partition loses quorum. As the largest cluster size is forgotten after the window, a minority partition
+// which lasts longer than the window regains quorum. Configure an expected size for a signal which lasts until the
+// partition heals.
+//
+// Tracker is not safe for concurrent use by multiple goroutines. Callers must serialize access to all methods.
+type Tracker struct {
+	// config holds the current configuration of the tracker.
+	config Config
+
+	// period is the current protocol period. It is increased with every call to EndOfProtocolPeriod.
+	period int
+
+	// samples holds the candidates for the largest cluster size within the window. The periods are increasing and the
+	// sizes are decreasing, which makes the first sample the largest cluster size within the window.
+	samples []sample
+
+	// minority reports if the cluster size was below quorum at the end of the last protocol period.
+	minority bool
+}
+
+// sample is the cluster size observed in a protocol period.
+type sample struct {
+	period int
+	size   int
+}
+
+// NewTracker creates a new quorum tracker.
+func NewTracker(options ...Option) *Tracker {
+	config := DefaultConfig
+	for _, option := range options {
+		option(&config)
+	}
+	return &Tracker{
+		config: config,
+	}
+}
+
+// Config returns the current configuration of the tracker.
+func (t *Tracker) Config() Config {
+	return t.config
+}
+
+// Minority reports if the cluster size was below quorum at the end of the last protocol period.
+func (t *Tracker) Minority() bool {
+	return t.minority
+}
+
+// ReferenceSize returns the cluster size quorum is calculated against.
+func (t *Tracker) ReferenceSize() int {
+	if t.config.ExpectedSize > 0 {
+		return t.config.ExpectedSize
+	}
+	if len(t.samples) == 0 {
+		return 0
+	}
+	return t.samples[0].size
+}
+
+// EndOfProtocolPeriod records the cluster size at the end of the current protocol period and moves the tracker one
+// protocol period forward. Returns true when the minority state changed.
+func (t *Tracker) EndOfProtocolPeriod(size int) bool {
+	// Smaller sizes before the new sample can never become the largest cluster size within the window again.
+	for len(t.samples) > 0 && t.samples[len(t.samples)-1].size <= size {
+		t.samples = t.samples[:len(t.samples)-1]
+	}
+	t.samples = append(t.samples, sample{
+		period: t.period,
+		size:   size,
+	})
+	for t.samples[0].period <= t.period-t.config.Window {
+		t.samples = t.samples[1:]
+	}
+	t.period++
+
+	minority := float64(size) <= t.config.Fraction*float64(t.ReferenceSize())
+	changed := minority != t.minority
+	t.minority = minority
+	return changed
+}
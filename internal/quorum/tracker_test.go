@@ -0,0 +1,78 @@
+package quorum_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/quorum"
+)
+
+var _ = Describe("Tracker", func() {
+	It("should have quorum while the cluster grows", func() {
+		tracker := quorum.NewTracker()
+		for size := 1; size <= 10; size++ {
+			Expect(tracker.EndOfProtocolPeriod(size)).To(BeFalse())
+			Expect(tracker.Minority()).To(BeFalse())
+		}
+		Expect(tracker.ReferenceSize()).To(Equal(10))
+	})
+
+	It("should lose quorum when the cluster drops to half its size", func() {
+		tracker := quorum.NewTracker()
+		Expect(tracker.EndOfProtocolPeriod(10)).To(BeFalse())
+		Expect(tracker.EndOfProtocolPeriod(6)).To(BeFalse())
+		Expect(tracker.Minority()).To(BeFalse())
+		Expect(tracker.EndOfProtocolPeriod(5)).To(BeTrue())
+		Expect(tracker.Minority()).To(BeTrue())
+		Expect(tracker.EndOfProtocolPeriod(5)).To(BeFalse())
+		Expect(tracker.Minority()).To(BeTrue())
+	})
+
+	It("should regain quorum when the cluster grows again", func() {
+		tracker := quorum.NewTracker()
+		tracker.EndOfProtocolPeriod(10)
+		tracker.EndOfProtocolPeriod(3)
+		Expect(tracker.Minority()).To(BeTrue())
+		Expect(tracker.EndOfProtocolPeriod(10)).To(BeTrue())
+		Expect(tracker.Minority()).To(BeFalse())
+	})
+
+	It("should forget the largest cluster size after the window", func() {
+		tracker := quorum.NewTracker(quorum.WithWindow(5))
+		tracker.EndOfProtocolPeriod(10)
+		for range 4 {
+			tracker.EndOfProtocolPeriod(4)
+			Expect(tracker.Minority()).To(BeTrue())
+			Expect(tracker.ReferenceSize()).To(Equal(10))
+		}
+		Expect(tracker.EndOfProtocolPeriod(4)).To(BeTrue())
+		Expect(tracker.Minority()).To(BeFalse())
+		Expect(tracker.ReferenceSize()).To(Equal(4))
+	})
+
+	It("should keep quorum while the cluster shrinks slowly", func() {
+		tracker := quorum.NewTracker(quorum.WithWindow(5))
+		for size := 100; size > 10; size-- {
+			tracker.EndOfProtocolPeriod(size)
+			Expect(tracker.Minority()).To(BeFalse())
+		}
+	})
+
+	It("should use the expected size as reference", func() {
+		tracker := quorum.NewTracker(quorum.WithExpectedSize(5), quorum.WithWindow(1))
+		Expect(tracker.EndOfProtocolPeriod(2)).To(BeTrue())
+		Expect(tracker.Minority()).To(BeTrue())
+		Expect(tracker.ReferenceSize()).To(Equal(5))
+		Expect(tracker.EndOfProtocolPeriod(3)).To(BeTrue())
+		Expect(tracker.Minority()).To(BeFalse())
+	})
+
+	It("should respect the quorum fraction", func() {
+		tracker := quorum.NewTracker(quorum.WithFraction(0.75))
+		tracker.EndOfProtocolPeriod(8)
+		tracker.EndOfProtocolPeriod(7)
+		Expect(tracker.Minority()).To(BeFalse())
+		tracker.EndOfProtocolPeriod(6)
+		Expect(tracker.Minority()).To(BeTrue())
+	})
+})
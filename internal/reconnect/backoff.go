@@ -0,0 +1,106 @@
+package reconnect
+
+import (
+	"math/rand"
+
+	"github.com/backbone81/membership/internal/encoding"
+)
+
+// Backoff keeps track of reconnect attempts per address and decides when the next attempt is due. An address without
+// any failed attempt is due right away. Every attempt which did not succeed pushes the next attempt further into the
+// future, doubling the delay up to the configured maximum. Time is measured in protocol periods, which keeps the
+// backoff independent of wall clock time and allows tests to drive it without delays.
+//
+// Backoff is not safe for concurrent use by multiple goroutines. Callers must serialize access to all methods.
+type Backoff struct {
+	// config holds the current configuration of the backoff.
+	config Config
+
+	// period is the current protocol period. It is increased with every call to EndOfProtocolPeriod.
+	period int
+
+	// attempts holds the reconnect attempts per address which did not succeed yet.
+	attempts map[encoding.Address]attempt
+}
+
+// attempt describes the reconnect attempts for a single address.
+type attempt struct {
+	// count is the number of attempts which did not succeed.
+	count int
+
+	// duePeriod is the protocol period in which the next attempt is due.
+	duePeriod int
+}
+
+// NewBackoff creates a new reconnect backoff.
+func NewBackoff(options ...Option) *Backoff {
+	config := DefaultConfig
+	for _, option := range options {
+		option(&config)
+	}
+	if config.MaxDelay < config.MinDelay {
+		config.MaxDelay = config.MinDelay
+	}
+	return &Backoff{
+		config:   config,
+		attempts: make(map[encoding.Address]attempt),
+	}
+}
+
+// Config returns the current configuration of the backoff.
+func (b *Backoff) Config() Config {
+	return b.config
+}
+
+// Len returns the number of addresses with reconnect attempts which did not succeed yet.
+func (b *Backoff) Len() int {
+	return len(b.attempts)
+}
+
+// Due reports if a reconnect attempt for the given address is due.
+func (b *Backoff) Due(address encoding.Address) bool {
+	attempt, found := b.attempts[address]
+	return !found || attempt.duePeriod <= b.period
+}
+
+// Attempted records a reconnect attempt for the given address. The attempt is considered failed until Succeeded is
+// called for the same address. The next attempt is due after the backoff delay.
+func (b *Backoff) Attempted(address encoding.Address) {
+	attempt := b.attempts[address]
+	attempt.count++
+	attempt.duePeriod = b.period + b.delay(attempt.count)
+	b.attempts[address] = attempt
+}
+
+// Succeeded resets the backoff for the given address, as the address is reachable again.
+func (b *Backoff) Succeeded(address encoding.Address) {
+	delete(b.attempts, address)
+}
+
+// EndOfProtocolPeriod moves the backoff one protocol period forward.
+func (b *Backoff) EndOfProtocolPeriod() {
+	b.period++
+}
+
+// Prune forgets all addresses for which the given function returns false. This prevents the backoff from growing
+// without bounds with addresses which are not of interest anymore.
+func (b *Backoff) Prune(keep func(address encoding.Address) bool) {
+	for address := range b.attempts {
+		if !keep(address) {
+			delete(b.attempts, address)
+		}
+	}
+}
+
+// delay returns the number of protocol periods to wait after the given number of failed attempts.
+func (b *Backoff) delay(count int) int {
+	delay := b.config.MaxDelay
+	if shift := count - 1; shift < 31 {
+		delay = min(b.config.MinDelay<<shift, b.config.MaxDelay)
+	}
+	jitter := int(float64(delay) * b.config.Jitter)
+	if jitter > 0 {
+		delay += rand.Intn(jitter + 1) //nolint:gosec // we do not need crypto/rand here
+	}
+	return delay
+}
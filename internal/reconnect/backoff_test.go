@@ -0,0 +1,74 @@
+package reconnect_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/encoding"
+	"github.com/backbone81/membership/internal/reconnect"
+)
+
+var _ = Describe("Backoff", func() {
+	// periodsUntilDue returns the number of protocol periods until the next attempt for the given address is due.
+	periodsUntilDue := func(backoff *reconnect.Backoff, address encoding.Address) int {
+		var periods int
+		for !backoff.Due(address) {
+			backoff.EndOfProtocolPeriod()
+			periods++
+		}
+		return periods
+	}
+
+	It("should be due for unknown addresses", func() {
+		backoff := reconnect.NewBackoff()
+		Expect(backoff.Due(TestAddress)).To(BeTrue())
+	})
+
+	It("should double the delay with every attempt", func() {
+		backoff := reconnect.NewBackoff(reconnect.WithJitter(0))
+		for _, want := range []int{1, 2, 4, 8, 16, 32, 64, 64, 64} {
+			backoff.Attempted(TestAddress)
+			Expect(periodsUntilDue(backoff, TestAddress)).To(Equal(want))
+		}
+	})
+
+	It("should add jitter to the delay", func() {
+		backoff := reconnect.NewBackoff(reconnect.WithMinDelay(8), reconnect.WithJitter(0.5))
+		for range 100 {
+			backoff.Succeeded(TestAddress)
+			backoff.Attempted(TestAddress)
+			Expect(periodsUntilDue(backoff, TestAddress)).To(And(
+				BeNumerically(">=", 8),
+				BeNumerically("<=", 12),
+			))
+		}
+	})
+
+	It("should reset the delay on success", func() {
+		backoff := reconnect.NewBackoff(reconnect.WithJitter(0))
+		backoff.Attempted(TestAddress)
+		backoff.Attempted(TestAddress)
+		Expect(backoff.Due(TestAddress)).To(BeFalse())
+		backoff.Succeeded(TestAddress)
+		Expect(backoff.Due(TestAddress)).To(BeTrue())
+		Expect(backoff.Len()).To(BeZero())
+	})
+
+	It("should keep addresses independent", func() {
+		backoff := reconnect.NewBackoff()
+		backoff.Attempted(TestAddress)
+		Expect(backoff.Due(TestAddress)).To(BeFalse())
+		Expect(backoff.Due(TestAddress2)).To(BeTrue())
+	})
+
+	It("should prune addresses", func() {
+		backoff := reconnect.NewBackoff()
+		backoff.Attempted(TestAddress)
+		backoff.Attempted(TestAddress2)
+		backoff.Prune(func(address encoding.Address) bool {
+			return address.Equal(TestAddress)
+		})
+		Expect(backoff.Len()).To(Equal(1))
+		Expect(backoff.Due(TestAddress2)).To(BeTrue())
+	})
+})
@@ -0,0 +1,22 @@
+package reconnect
+
+// Config is the configuration for the reconnect backoff.
+type Config struct {
+	// MinDelay is the number of protocol periods to wait after the first failed reconnect attempt. The delay doubles
+	// with every further failed attempt.
+	MinDelay int
+
+	// MaxDelay is the maximum number of protocol periods to wait between two reconnect attempts.
+	MaxDelay int
+
+	// Jitter is the fraction of the delay which is randomly added on top of the delay. A jitter of 0.5 results in a
+	// delay between 100% and 150% of the exponential delay. This prevents members from reconnecting in lockstep.
+	Jitter float64
+}
+
+// DefaultConfig provides a default configuration for the reconnect backoff with sane defaults for most situations.
+var DefaultConfig = Config{
+	MinDelay: 1,
+	MaxDelay: 64,
+	Jitter:   0.5,
+}
@@ -0,0 +1,4 @@
+// Package reconnect provides the bookkeeping for reconnecting members which dropped from the member list. It keeps track
+// of reconnect attempts per address and spreads them out with exponential backoff and jitter. That way an unreachable
+// member is not probed over and over again, while a reachable member is picked up again eventually.
+package reconnect
@@ -0,0 +1,25 @@
+package reconnect
+
+// Option is the function signature for all backoff options to implement.
+type Option func(config *Config)
+
+func WithMinDelay(periods int) Option {
+	periods = max(1, periods)
+	return func(config *Config) {
+		config.MinDelay = periods
+	}
+}
+
+func WithMaxDelay(periods int) Option {
+	periods = max(1, periods)
+	return func(config *Config) {
+		config.MaxDelay = periods
+	}
+}
+
+func WithJitter(jitter float64) Option {
+	jitter = max(0, jitter)
+	return func(config *Config) {
+		config.Jitter = jitter
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/go-logr/logr"
 
+	"github.com/backbone81/membership/internal/observer"
 	"github.com/backbone81/membership/internal/roundtriptime"
 )
 
@@ -29,6 +30,14 @@ type Config struct {
 	// RoundTripTimeTracker is the roundtrip time tracker which the membership list records the measured network round
 	// trips to.
 	RoundTripTimeTracker *roundtriptime.Tracker
+
+	// Observer is notified when a protocol period took considerably longer than expected. No observer is notified
+	// when nil.
+	Observer observer.Observer
+
+	// Metrics holds the metrics collectors the scheduler reports to. New metrics which are not registered anywhere are
+	// created when nil.
+	Metrics *Metrics
 }
 
 // DefaultConfig provides a scheduler configuration with sane defaults for most situations.
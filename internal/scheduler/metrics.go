@@ -2,44 +2,60 @@ package scheduler
 
 import "github.com/prometheus/client_golang/prometheus"
 
-var (
-	OperationsTotal = prometheus.NewCounterVec(
-		prometheus.CounterOpts{
-			Name: "membership_scheduler_operations_total",
-			Help: "Total number of scheduler operations executed.",
-		},
-		[]string{"operation"}, // direct_ping, indirect_ping, end_of_period, request_list
-	)
-	OperationErrorsTotal = prometheus.NewCounterVec(
-		prometheus.CounterOpts{
-			Name: "membership_scheduler_operation_errors_total",
-			Help: "Total number of scheduler operation errors.",
-		},
-		[]string{"operation"},
-	)
-	OperationDurationSeconds = prometheus.NewHistogramVec(
-		prometheus.HistogramOpts{
-			Name:    "membership_scheduler_operation_duration_seconds",
-			Help:    "Duration of scheduler operations in seconds.",
-			Buckets: prometheus.DefBuckets,
-		},
-		[]string{"operation"},
-	)
-	ExpectedRTTSeconds = prometheus.NewGauge(
-		prometheus.GaugeOpts{
-			Name: "membership_scheduler_expected_rtt_seconds",
-			Help: "Current expected round-trip time in seconds.",
-		},
-	)
-)
+// Metrics holds the metrics collectors of a scheduler. Every scheduler owns its own metrics, which allows for running
+// multiple schedulers in the same process without mixing up their numbers.
+type Metrics struct {
+	OperationsTotal          *prometheus.CounterVec
+	OperationErrorsTotal     *prometheus.CounterVec
+	OperationDurationSeconds *prometheus.HistogramVec
+	ExpectedRTTSeconds       prometheus.Gauge
+}
+
+// NewMetrics creates new metrics collectors with the given const labels. The const labels might be nil.
+func NewMetrics(constLabels prometheus.Labels) *Metrics {
+	return &Metrics{
+		OperationsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "membership_scheduler_operations_total",
+				Help:        "Total number of scheduler operations executed.",
+				ConstLabels: constLabels,
+			},
+			[]string{"operation"}, // direct_ping, indirect_ping, end_of_period, request_list
+		),
+		OperationErrorsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "membership_scheduler_operation_errors_total",
+				Help:        "Total number of scheduler operation errors.",
+				ConstLabels: constLabels,
+			},
+			[]string{"operation"},
+		),
+		OperationDurationSeconds: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:        "membership_scheduler_operation_duration_seconds",
+				Help:        "Duration of scheduler operations in seconds.",
+				ConstLabels: constLabels,
+				Buckets:     prometheus.DefBuckets,
+			},
+			[]string{"operation"},
+		),
+		ExpectedRTTSeconds: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "membership_scheduler_expected_rtt_seconds",
+				Help:        "Current expected round-trip time in seconds.",
+				ConstLabels: constLabels,
+			},
+		),
+	}
+}
 
-// RegisterMetrics registers all metrics collectors with the given prometheus registerer.
-func RegisterMetrics(registerer prometheus.Registerer) error {
+// Register registers all metrics collectors with the given prometheus registerer.
+func (m *Metrics) Register(registerer prometheus.Registerer) error {
 	metrics := []prometheus.Collector{
-		OperationsTotal,
-		OperationErrorsTotal,
-		OperationDurationSeconds,
-		ExpectedRTTSeconds,
+		m.OperationsTotal,
+		m.OperationErrorsTotal,
+		m.OperationDurationSeconds,
+		m.ExpectedRTTSeconds,
 	}
 	for _, metric := range metrics {
 		if err := registerer.Register(metric); err != nil {
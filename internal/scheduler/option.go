@@ -5,6 +5,7 @@ import (
 
 	"github.com/go-logr/logr"
 
+	"github.com/backbone81/membership/internal/observer"
 	"github.com/backbone81/membership/internal/roundtriptime"
 )
 
@@ -44,3 +45,17 @@ func WithRoundTripTimeTracker(rttTracker *roundtriptime.Tracker) Option {
 		config.RoundTripTimeTracker = rttTracker
 	}
 }
+
+// WithObserver sets the given observer for the scheduler.
+func WithObserver(observer observer.Observer) Option {
+	return func(config *Config) {
+		config.Observer = observer
+	}
+}
+
+// WithMetrics sets the given metrics collectors for the scheduler.
+func WithMetrics(metrics *Metrics) Option {
+	return func(config *Config) {
+		config.Metrics = metrics
+	}
+}
@@ -32,6 +32,10 @@ func New(target Target, options ...Option) *Scheduler {
 	if config.RoundTripTimeTracker == nil {
 		panic("you must provide a round trip time tracker")
 	}
+	if config.Metrics == nil {
+		// Metrics which are not registered anywhere are simply never collected.
+		config.Metrics = NewMetrics(nil)
+	}
 
 	return &Scheduler{
 		logger:   config.Logger,
@@ -90,7 +94,7 @@ func (s *Scheduler) protocolPeriodTask() {
 		// for the timeout, but we also want to create a log entry, when the timeout changes significantly. Therefore,
 		// we only log when we move at least 10% away of the last time we logged.
 		currExpectedRoundTripTime := s.config.RoundTripTimeTracker.GetCalculated()
-		ExpectedRTTSeconds.Set(currExpectedRoundTripTime.Seconds())
+		s.config.Metrics.ExpectedRTTSeconds.Set(currExpectedRoundTripTime.Seconds())
 		logThreshold := lastExpectedRoundTripTime / 10
 		if math.Abs(float64(currExpectedRoundTripTime)-float64(lastExpectedRoundTripTime)) > float64(logThreshold) {
 			s.logger.Info(
@@ -133,6 +137,9 @@ func (s *Scheduler) protocolPeriodTask() {
 				"want-duration", s.config.ProtocolPeriod,
 				"got-duration", gotProtocolPeriod,
 			)
+			if s.config.Observer != nil {
+				s.config.Observer.ProtocolPeriodOverrun(s.config.ProtocolPeriod, gotProtocolPeriod)
+			}
 		}
 	}
 }
@@ -209,8 +216,8 @@ func (s *Scheduler) requestListTask() {
 func (s *Scheduler) measure(operation string, f func() error) {
 	start := time.Now()
 	if err := f(); err != nil {
-		OperationErrorsTotal.WithLabelValues(operation).Inc()
+		s.config.Metrics.OperationErrorsTotal.WithLabelValues(operation).Inc()
 	}
-	OperationDurationSeconds.WithLabelValues(operation).Observe(time.Since(start).Seconds())
-	OperationsTotal.WithLabelValues(operation).Inc()
+	s.config.Metrics.OperationDurationSeconds.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	s.config.Metrics.OperationsTotal.WithLabelValues(operation).Inc()
 }
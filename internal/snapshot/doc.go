@@ -0,0 +1,3 @@
+// Package snapshot provides functionality for persisting the known members to disk. A member which restarts can use
+// the members of its last snapshot as additional join candidates, in case its bootstrap members are unavailable.
+package snapshot
@@ -0,0 +1,183 @@
+package snapshot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"math"
+	"os"
+	"time"
+
+	"github.com/backbone81/membership/internal/encoding"
+	"github.com/backbone81/membership/internal/utility"
+)
+
+// Version is the version of the snapshot file format. It is increased every time the file format changes in an
+// incompatible way.
+const Version uint8 = 2
+
+// magic is the byte sequence every snapshot file starts with. It allows us to reject files which are not snapshots
+// at all.
+var magic = []byte("MSNP")
+
+// headerLength is the length of the magic byte sequence, the file format version and the timestamp.
+var headerLength = len(magic) + 1 + 8
+
+// checksumLength is the length of the CRC32 checksum at the end of a snapshot.
+const checksumLength = 4
+
+// checksumTable is the CRC32 table used for calculating the checksum of a snapshot.
+var checksumTable = crc32.MakeTable(crc32.Castagnoli)
+
+// Snapshot is the state of the membership list at a given point in time.
+type Snapshot struct {
+	// Timestamp is the point in time the snapshot was taken.
+	Timestamp time.Time
+
+	// Members are the alive and suspect members as well as the tombstones of faulty members at the time of the
+	// snapshot.
+	Members []Member
+}
+
+// Member is a single member of a snapshot.
+type Member struct {
+	encoding.Member
+
+	// LastSeen is the point in time we heard from or about the member the last time. This allows for ignoring single
+	// members which are stale. It is the zero value for tombstones of faulty members.
+	LastSeen time.Time
+}
+
+// AppendToBuffer appends the snapshot to the provided buffer. The encoding consists of a magic byte sequence, the file
+// format version, the timestamp, the members with their last seen timestamp and a CRC32 checksum over everything
+// before it.
+// Returns the buffer with the data appended, the number of bytes appended and any error which occurred.
+func (s *Snapshot) AppendToBuffer(buffer []byte) ([]byte, int, error) {
+	start := len(buffer)
+	buffer = append(buffer, magic...)
+	buffer = append(buffer, Version)
+	buffer = appendTimestamp(buffer, s.Timestamp)
+
+	var err error
+	buffer, _, err = encoding.AppendMemberCountToBuffer(buffer, len(s.Members))
+	if err != nil {
+		return buffer[:start], 0, err
+	}
+	for _, member := range s.Members {
+		buffer, _, err = encoding.AppendMemberToBuffer(buffer, member.Member)
+		if err != nil {
+			return buffer[:start], 0, err
+		}
+		buffer = appendTimestamp(buffer, member.LastSeen)
+	}
+
+	buffer = encoding.Endian.AppendUint32(buffer, crc32.Checksum(buffer[start:], checksumTable))
+	return buffer, len(buffer) - start, nil
+}
+
+// FromBuffer reads the snapshot from the provided buffer. The buffer must hold exactly one snapshot, as the checksum
+// is expected at the very end. The checksum is validated before any member is read, which leaves the snapshot
+// untouched for corrupted buffers. The members slice of the snapshot is re-used to reduce memory allocations.
+// Returns the number of bytes read and any error which occurred.
+func (s *Snapshot) FromBuffer(buffer []byte) (int, error) {
+	if len(buffer) < headerLength+checksumLength {
+		return 0, errors.New("snapshot buffer too small")
+	}
+	if !bytes.Equal(buffer[:len(magic)], magic) {
+		return 0, errors.New("snapshot magic mismatch")
+	}
+	if buffer[len(magic)] != Version {
+		return 0, fmt.Errorf("unsupported snapshot version %d", buffer[len(magic)])
+	}
+	checksumOffset := len(buffer) - checksumLength
+	if crc32.Checksum(buffer[:checksumOffset], checksumTable) != encoding.Endian.Uint32(buffer[checksumOffset:]) {
+		return 0, errors.New("snapshot checksum mismatch")
+	}
+
+	timestamp, err := timestampFromBuffer(buffer[len(magic)+1:])
+	if err != nil {
+		return 0, err
+	}
+	memberCount, n, err := encoding.MemberCountFromBuffer(buffer[headerLength:checksumOffset])
+	if err != nil {
+		return 0, err
+	}
+	members := s.Members[:0]
+	offset := headerLength + n
+	for range memberCount {
+		member, memberN, err := encoding.MemberFromBuffer(buffer[offset:checksumOffset])
+		if err != nil {
+			return 0, err
+		}
+		offset += memberN
+		if checksumOffset-offset < 8 {
+			return 0, errors.New("snapshot buffer too small")
+		}
+		lastSeen, err := timestampFromBuffer(buffer[offset:])
+		if err != nil {
+			return 0, err
+		}
+		offset += 8
+		members = append(members, Member{
+			Member:   member,
+			LastSeen: lastSeen,
+		})
+	}
+	if offset != checksumOffset {
+		return 0, errors.New("snapshot has trailing data")
+	}
+
+	s.Timestamp = timestamp
+	s.Members = members
+	return len(buffer), nil
+}
+
+// appendTimestamp appends the given timestamp as nanoseconds since the unix epoch. The zero value is encoded as 0.
+func appendTimestamp(buffer []byte, timestamp time.Time) []byte {
+	if timestamp.IsZero() {
+		return encoding.Endian.AppendUint64(buffer, 0)
+	}
+	return encoding.Endian.AppendUint64(buffer, uint64(timestamp.UnixNano())) //nolint:gosec // round trips through timestampFromBuffer
+}
+
+// timestampFromBuffer reads a timestamp written by appendTimestamp. The caller must make sure that the buffer holds at
+// least 8 bytes.
+func timestampFromBuffer(buffer []byte) (time.Time, error) {
+	timestamp := encoding.Endian.Uint64(buffer)
+	if timestamp == 0 {
+		return time.Time{}, nil
+	}
+	if timestamp > math.MaxInt64 {
+		return time.Time{}, errors.New("snapshot timestamp out of bounds")
+	}
+	return time.Unix(0, int64(timestamp)), nil
+}
+
+// WriteFile writes the snapshot to the given path. The file is replaced atomically and synced to disk before
+// WriteFile returns. The buffer is used for encoding the snapshot and returned to allow re-use between calls.
+func WriteFile(path string, snapshot *Snapshot, buffer []byte) ([]byte, error) {
+	buffer, _, err := snapshot.AppendToBuffer(buffer[:0])
+	if err != nil {
+		return buffer, fmt.Errorf("encoding snapshot: %w", err)
+	}
+	if err := utility.WriteFileAtomic(path, buffer); err != nil {
+		return buffer, fmt.Errorf("writing snapshot file: %w", err)
+	}
+	return buffer, nil
+}
+
+// ReadFile reads the snapshot from the given path. Returns an error wrapping fs.ErrNotExist when no snapshot was
+// written yet.
+func ReadFile(path string) (Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("reading snapshot file: %w", err)
+	}
+
+	var snapshot Snapshot
+	if _, err := snapshot.FromBuffer(data); err != nil {
+		return Snapshot{}, fmt.Errorf("decoding snapshot file %q: %w", path, err)
+	}
+	return snapshot, nil
+}
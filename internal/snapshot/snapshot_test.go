@@ -0,0 +1,140 @@
+package snapshot_test
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/encoding"
+	"github.com/backbone81/membership/internal/snapshot"
+)
+
+var _ = Describe("Snapshot", func() {
+	var testSnapshot snapshot.Snapshot
+
+	BeforeEach(func() {
+		testSnapshot = snapshot.Snapshot{
+			Timestamp: time.Unix(0, 1234567890),
+			Members: []snapshot.Member{
+				{
+					Member: encoding.Member{
+						Address:           TestAddress,
+						State:             encoding.MemberStateAlive,
+						IncarnationNumber: 3,
+					},
+					LastSeen: time.Unix(0, 1234567000),
+				},
+				{
+					Member: encoding.Member{
+						Address:           TestAddress2,
+						State:             encoding.MemberStateFaulty,
+						IncarnationNumber: 7,
+					},
+				},
+			},
+		}
+	})
+
+	It("should append to nil buffer", func() {
+		buffer, n, err := testSnapshot.AppendToBuffer(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buffer).ToNot(BeNil())
+		Expect(n).To(Equal(len(buffer)))
+	})
+
+	It("should read from buffer", func() {
+		buffer, appendN, err := testSnapshot.AppendToBuffer(nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		var readSnapshot snapshot.Snapshot
+		readN, err := readSnapshot.FromBuffer(buffer)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(readN).To(Equal(appendN))
+		Expect(readSnapshot.Timestamp.Equal(testSnapshot.Timestamp)).To(BeTrue())
+		Expect(readSnapshot.Members).To(Equal(testSnapshot.Members))
+	})
+
+	It("should fail to read from a buffer which is too small", func() {
+		buffer, _, err := testSnapshot.AppendToBuffer(nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		for i := range len(buffer) {
+			var readSnapshot snapshot.Snapshot
+			_, err := readSnapshot.FromBuffer(buffer[:i])
+			Expect(err).To(HaveOccurred())
+		}
+	})
+
+	It("should fail to read a corrupted buffer", func() {
+		buffer, _, err := testSnapshot.AppendToBuffer(nil)
+		Expect(err).ToNot(HaveOccurred())
+		buffer[len(buffer)-6] ^= 0xff
+
+		var readSnapshot snapshot.Snapshot
+		_, err = readSnapshot.FromBuffer(buffer)
+		Expect(err).To(MatchError(ContainSubstring("checksum")))
+	})
+
+	It("should leave the members untouched when the checksum does not match", func() {
+		buffer, _, err := testSnapshot.AppendToBuffer(nil)
+		Expect(err).ToNot(HaveOccurred())
+		buffer[len(buffer)-6] ^= 0xff
+
+		readSnapshot := snapshot.Snapshot{
+			Members: make([]snapshot.Member, 1, 2),
+		}
+		members := readSnapshot.Members[:cap(readSnapshot.Members)]
+		_, err = readSnapshot.FromBuffer(buffer)
+		Expect(err).To(HaveOccurred())
+		Expect(members).To(HaveEach(BeZero()))
+	})
+
+	It("should fail to read a buffer with trailing data", func() {
+		buffer, _, err := testSnapshot.AppendToBuffer(nil)
+		Expect(err).ToNot(HaveOccurred())
+		buffer, _, err = testSnapshot.AppendToBuffer(buffer)
+		Expect(err).ToNot(HaveOccurred())
+
+		var readSnapshot snapshot.Snapshot
+		_, err = readSnapshot.FromBuffer(buffer)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail to read an unsupported version", func() {
+		buffer, _, err := testSnapshot.AppendToBuffer(nil)
+		Expect(err).ToNot(HaveOccurred())
+		buffer[4] = snapshot.Version + 1
+
+		var readSnapshot snapshot.Snapshot
+		_, err = readSnapshot.FromBuffer(buffer)
+		Expect(err).To(MatchError(ContainSubstring("version")))
+	})
+
+	It("should read what was written to file", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "snapshot")
+		_, err := snapshot.WriteFile(path, &testSnapshot, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		readSnapshot, err := snapshot.ReadFile(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(readSnapshot.Timestamp.Equal(testSnapshot.Timestamp)).To(BeTrue())
+		Expect(readSnapshot.Members).To(Equal(testSnapshot.Members))
+	})
+
+	It("should report a missing file", func() {
+		_, err := snapshot.ReadFile(filepath.Join(GinkgoT().TempDir(), "snapshot"))
+		Expect(err).To(MatchError(fs.ErrNotExist))
+	})
+
+	It("should fail on a file which is no snapshot", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "snapshot")
+		Expect(os.WriteFile(path, []byte("no snapshot at all"), 0o600)).To(Succeed())
+
+		_, err := snapshot.ReadFile(path)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,186 @@
+package transport
+
+import (
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/backbone81/membership/internal/encoding"
+)
+
+// Async provides a client transport which hands sends over to a bounded pool of send workers. Send only copies the
+// buffer into a pre-allocated buffer and queues it, the actual network I/O happens in the send workers. This keeps
+// slow members from blocking the caller, which usually holds the lock of the membership list.
+//
+// Every send worker owns one of the given transports, which allows for transports which are not safe for concurrent
+// use. When all pre-allocated buffers are in use, new sends are dropped. The membership protocol is designed to
+// tolerate lost network messages.
+//
+// Sends which are queued before Startup are sent once the send workers run. Shutdown sends what is still queued until
+// the drain timeout is over and drops the rest. Sends which are queued after Shutdown are never sent.
+//
+// Async is safe for concurrent use by multiple goroutines.
+type Async struct {
+	logger     logr.Logger
+	name       string
+	transports []Transport
+	metrics    *Metrics
+	waitGroup  sync.WaitGroup
+
+	// bufferLength is the capacity of the pre-allocated buffers.
+	bufferLength int
+
+	// drainTimeout is the maximum time Shutdown keeps sending what is still queued.
+	drainTimeout time.Duration
+
+	// drainDeadline is the point in time the send workers stop sending what is still queued. It is set by Shutdown
+	// before stop is closed.
+	drainDeadline time.Time
+
+	// pending holds the sends waiting for a send worker.
+	pending chan asyncSend
+
+	// buffers holds the pre-allocated buffers which are not in use.
+	buffers chan []byte
+
+	// stop is closed to signal the send workers to finish.
+	stop chan struct{}
+}
+
+// asyncSend is a single send waiting for a send worker.
+type asyncSend struct {
+	address encoding.Address
+	buffer  []byte
+}
+
+// Async implements Transport.
+var _ Transport = (*Async)(nil)
+
+// NewAsync creates a new Async transport with one send worker for every given transport. The name is used as transport
+// label for metrics and logs. Up to queueLength sends wait for a send worker. Buffers are pre-allocated with
+// bufferLength capacity and only grow for the duration of a bigger send. Shutdown keeps sending for up to drainTimeout.
+// New metrics which are not registered anywhere are created when the given metrics are nil.
+func NewAsync(
+	logger logr.Logger,
+	name string,
+	transports []Transport,
+	queueLength int,
+	bufferLength int,
+	drainTimeout time.Duration,
+	metrics *Metrics,
+) *Async {
+	if metrics == nil {
+		// Metrics which are not registered anywhere are simply never collected.
+		metrics = NewMetrics(nil)
+	}
+
+	// Every send worker holds on to one buffer while sending, in addition to the buffers waiting in the queue. That
+	// way the queue is never full when a buffer is available, and Send never blocks.
+	bufferCount := queueLength + len(transports)
+	buffers := make(chan []byte, bufferCount)
+	for range bufferCount {
+		buffers <- make([]byte, 0, bufferLength)
+	}
+	return &Async{
+		logger:       logger,
+		name:         name,
+		transports:   transports,
+		metrics:      metrics,
+		bufferLength: bufferLength,
+		drainTimeout: drainTimeout,
+		pending:      make(chan asyncSend, bufferCount),
+		buffers:      buffers,
+		stop:         make(chan struct{}),
+	}
+}
+
+// Startup starts the send workers.
+func (a *Async) Startup() error {
+	a.logger.Info("Async transport startup", "transport", a.name)
+	for _, transport := range a.transports {
+		a.waitGroup.Go(func() {
+			a.backgroundTask(transport)
+		})
+	}
+	return nil
+}
+
+// Shutdown ends the send workers. Queued sends are still sent until the drain timeout is over, the rest is dropped.
+// A send which is in progress when the drain timeout is over is not interrupted.
+func (a *Async) Shutdown() error {
+	a.logger.Info("Async transport shutdown", "transport", a.name)
+	a.drainDeadline = time.Now().Add(a.drainTimeout)
+	close(a.stop)
+	a.waitGroup.Wait()
+
+	dropped := 0
+	for {
+		select {
+		case pendingSend := <-a.pending:
+			a.metrics.SendQueueDepth.WithLabelValues(a.name).Dec()
+			a.metrics.SendDrops.WithLabelValues(a.name).Inc()
+			a.buffers <- pendingSend.buffer
+			dropped++
+		default:
+			if dropped > 0 {
+				a.logger.Info("Dropped queued sends on shutdown", "transport", a.name, "dropped", dropped)
+			}
+			return nil
+		}
+	}
+}
+
+// Send queues the given buffer for being sent to the member with the given address. The buffer is copied, the caller
+// can re-use it right away. The send is dropped when the queue is full.
+func (a *Async) Send(address encoding.Address, buffer []byte) error {
+	var queuedBuffer []byte
+	select {
+	case queuedBuffer = <-a.buffers:
+	default:
+		a.metrics.SendDrops.WithLabelValues(a.name).Inc()
+		return nil
+	}
+
+	queuedBuffer = append(queuedBuffer[:0], buffer...)
+	a.metrics.SendQueueDepth.WithLabelValues(a.name).Inc()
+	a.pending <- asyncSend{
+		address: address,
+		buffer:  queuedBuffer,
+	}
+	return nil
+}
+
+func (a *Async) backgroundTask(transport Transport) {
+	for {
+		select {
+		case pendingSend := <-a.pending:
+			a.send(transport, pendingSend)
+		case <-a.stop:
+			// Send what is still queued before finishing. This allows for the shutdown of the membership list to
+			// announce its own failure. Unreachable members must not delay the shutdown forever, though.
+			for time.Now().Before(a.drainDeadline) {
+				select {
+				case pendingSend := <-a.pending:
+					a.send(transport, pendingSend)
+				default:
+					return
+				}
+			}
+			return
+		}
+	}
+}
+
+func (a *Async) send(transport Transport, pendingSend asyncSend) {
+	a.metrics.SendQueueDepth.WithLabelValues(a.name).Dec()
+	if err := transport.Send(pendingSend.address, pendingSend.buffer); err != nil {
+		a.logger.Error(err, "Asynchronous send.", "transport", a.name, "destination", pendingSend.address)
+	}
+	if cap(pendingSend.buffer) > a.bufferLength {
+		// We do not hold on to the memory of rare big sends like member list syncs. Otherwise, the queue would keep
+		// the memory of the biggest send for every single buffer over time.
+		pendingSend.buffer = make([]byte, 0, a.bufferLength)
+	}
+	a.buffers <- pendingSend.buffer
+}
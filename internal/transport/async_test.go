@@ -0,0 +1,134 @@
+package transport_test
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/backbone81/membership/internal/encoding"
+	"github.com/backbone81/membership/internal/transport"
+)
+
+// syncStore is a transport which records all sends like Store, but is safe for concurrent use. It blocks every send
+// until release is closed, when release is not nil.
+type syncStore struct {
+	mutex   sync.Mutex
+	release chan struct{}
+	buffers [][]byte
+}
+
+func (s *syncStore) Send(_ encoding.Address, buffer []byte) error {
+	if s.release != nil {
+		<-s.release
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.buffers = append(s.buffers, append([]byte(nil), buffer...))
+	return nil
+}
+
+func (s *syncStore) Buffers() [][]byte {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.buffers
+}
+
+var _ = Describe("Async", func() {
+	address := encoding.NewAddress(net.IPv4(1, 2, 3, 4), 1024)
+
+	It("should send through the wrapped transports", func() {
+		var store syncStore
+		async := transport.NewAsync(GinkgoLogr, "test", []transport.Transport{&store}, 16, 64, time.Second, nil)
+		Expect(async.Startup()).To(Succeed())
+
+		Expect(async.Send(address, []byte("foo"))).To(Succeed())
+		Expect(async.Send(address, []byte("bar"))).To(Succeed())
+		Eventually(store.Buffers).Should(Equal([][]byte{[]byte("foo"), []byte("bar")}))
+
+		Expect(async.Shutdown()).To(Succeed())
+	})
+
+	It("should copy the buffer", func() {
+		var store syncStore
+		async := transport.NewAsync(GinkgoLogr, "test", []transport.Transport{&store}, 16, 64, time.Second, nil)
+
+		buffer := []byte("foo")
+		Expect(async.Send(address, buffer)).To(Succeed())
+		copy(buffer, "bar")
+
+		Expect(async.Startup()).To(Succeed())
+		Expect(async.Shutdown()).To(Succeed())
+		Expect(store.Buffers()).To(Equal([][]byte{[]byte("foo")}))
+	})
+
+	It("should send buffers bigger than the pre-allocated buffers", func() {
+		var store syncStore
+		async := transport.NewAsync(GinkgoLogr, "test", []transport.Transport{&store}, 16, 2, time.Second, nil)
+
+		Expect(async.Send(address, []byte("foo bar"))).To(Succeed())
+		Expect(async.Send(address, []byte("baz"))).To(Succeed())
+
+		Expect(async.Startup()).To(Succeed())
+		Expect(async.Shutdown()).To(Succeed())
+		Expect(store.Buffers()).To(Equal([][]byte{[]byte("foo bar"), []byte("baz")}))
+	})
+
+	It("should send all queued sends on shutdown", func() {
+		var store syncStore
+		async := transport.NewAsync(GinkgoLogr, "test", []transport.Transport{&store, &store}, 16, 64, time.Second, nil)
+		for range 10 {
+			Expect(async.Send(address, []byte("foo"))).To(Succeed())
+		}
+
+		Expect(async.Startup()).To(Succeed())
+		Expect(async.Shutdown()).To(Succeed())
+		Expect(store.Buffers()).To(HaveLen(10))
+	})
+
+	It("should drop queued sends which were not sent until the drain timeout", func() {
+		var store syncStore
+		metrics := transport.NewMetrics(nil)
+		async := transport.NewAsync(GinkgoLogr, "test", []transport.Transport{&store}, 16, 64, 0, metrics)
+		for range 10 {
+			Expect(async.Send(address, []byte("foo"))).To(Succeed())
+		}
+
+		Expect(async.Shutdown()).To(Succeed())
+		Expect(store.Buffers()).To(BeEmpty())
+		Expect(testutil.ToFloat64(metrics.SendDrops.WithLabelValues("test"))).To(Equal(10.0))
+		Expect(testutil.ToFloat64(metrics.SendQueueDepth.WithLabelValues("test"))).To(Equal(0.0))
+	})
+
+	It("should not block when a send is slow", func() {
+		store := syncStore{
+			release: make(chan struct{}),
+		}
+		metrics := transport.NewMetrics(nil)
+		async := transport.NewAsync(GinkgoLogr, "test", []transport.Transport{&store}, 2, 64, time.Second, metrics)
+		Expect(async.Startup()).To(Succeed())
+
+		By("Blocking the only send worker")
+		Expect(async.Send(address, []byte("foo"))).To(Succeed())
+		Eventually(func() float64 {
+			return testutil.ToFloat64(metrics.SendQueueDepth.WithLabelValues("test"))
+		}).Should(Equal(0.0))
+
+		By("Filling up the queue")
+		Expect(async.Send(address, []byte("bar"))).To(Succeed())
+		Expect(async.Send(address, []byte("baz"))).To(Succeed())
+		Expect(testutil.ToFloat64(metrics.SendQueueDepth.WithLabelValues("test"))).To(Equal(2.0))
+
+		By("Dropping sends while the queue is full")
+		Expect(async.Send(address, []byte("qux"))).To(Succeed())
+		Expect(testutil.ToFloat64(metrics.SendDrops.WithLabelValues("test"))).To(Equal(1.0))
+
+		close(store.release)
+		Expect(async.Shutdown()).To(Succeed())
+		Expect(store.Buffers()).To(Equal([][]byte{[]byte("foo"), []byte("bar"), []byte("baz")}))
+		Expect(testutil.ToFloat64(metrics.SendQueueDepth.WithLabelValues("test"))).To(Equal(0.0))
+	})
+})
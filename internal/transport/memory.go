@@ -19,6 +19,9 @@ type Memory struct {
 	// targets is the list of targets indexed by their address.
 	targets map[encoding.Address]Target
 
+	// removedTargets holds the addresses of targets which were removed. Sends to them are dropped right away.
+	removedTargets map[encoding.Address]struct{}
+
 	// pendingSends stores all sends for the given address for flushing later.
 	pendingSends map[encoding.Address][][]byte
 
@@ -29,9 +32,10 @@ type Memory struct {
 // NewMemory creates a new Memory instance.
 func NewMemory() *Memory {
 	return &Memory{
-		targets:      make(map[encoding.Address]Target, 1024),
-		pendingSends: make(map[encoding.Address][][]byte, 1024),
-		bufferPool:   make([][]byte, 0, 1024),
+		targets:        make(map[encoding.Address]Target, 1024),
+		removedTargets: make(map[encoding.Address]struct{}),
+		pendingSends:   make(map[encoding.Address][][]byte, 1024),
+		bufferPool:     make([][]byte, 0, 1024),
 	}
 }
 
@@ -48,6 +52,19 @@ func (m *Memory) AddTarget(address encoding.Address, target Target) {
 	defer m.mutex.Unlock()
 
 	m.targets[address] = target
+	delete(m.removedTargets, address)
+}
+
+// RemoveTarget removes the target with the given address. Pending sends to the target are dropped, as are all future
+// sends to it. This simulates a member which crashed.
+func (m *Memory) RemoveTarget(address encoding.Address) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	delete(m.targets, address)
+	m.removedTargets[address] = struct{}{}
+	m.bufferPool = append(m.bufferPool, m.pendingSends[address]...)
+	delete(m.pendingSends, address)
 }
 
 // acquireBuffer is a helper function which either allocates a new memory buffer if none is available in the pool or
@@ -74,6 +91,10 @@ func (m *Memory) AddPendingSend(address encoding.Address, buffer []byte) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
+	if _, removed := m.removedTargets[address]; removed {
+		m.bufferPool = append(m.bufferPool, buffer)
+		return
+	}
 	m.pendingSends[address] = append(m.pendingSends[address], buffer)
 }
 
@@ -2,60 +2,101 @@ package transport
 
 import "github.com/prometheus/client_golang/prometheus"
 
-var (
-	TransmitBytes = prometheus.NewCounterVec(
-		prometheus.CounterOpts{
-			Name: "membership_list_transport_transmit_bytes_total",
-			Help: "Total number of bytes transmitted.",
-		},
-		[]string{"transport"},
-	)
-	TransmitErrors = prometheus.NewCounterVec(
-		prometheus.CounterOpts{
-			Name: "membership_list_transport_transmit_errors_total",
-			Help: "Total number of errors during transmit.",
-		},
-		[]string{"transport"},
-	)
-	ReceiveBytes = prometheus.NewCounterVec(
-		prometheus.CounterOpts{
-			Name: "membership_list_transport_receive_bytes_total",
-			Help: "Total number of bytes received.",
-		},
-		[]string{"transport"},
-	)
-	ReceiveErrors = prometheus.NewCounterVec(
-		prometheus.CounterOpts{
-			Name: "membership_list_transport_receive_errors_total",
-			Help: "Total number of errors during receive.",
-		},
-		[]string{"transport"},
-	)
-	Encryptions = prometheus.NewCounterVec(
-		prometheus.CounterOpts{
-			Name: "membership_list_transport_encryptions_total",
-			Help: "Total number of encryption operations performed.",
-		},
-		[]string{"transport"},
-	)
-	Decryptions = prometheus.NewCounterVec(
-		prometheus.CounterOpts{
-			Name: "membership_list_transport_decryptions_total",
-			Help: "Total number of decryption operations performed.",
-		},
-		[]string{"transport"},
-	)
-)
+// Metrics holds the metrics collectors of the transports. The transports of a membership list share their metrics,
+// which are labeled by transport. This allows for running multiple membership lists in the same process without
+// mixing up their numbers.
+type Metrics struct {
+	TransmitBytes  *prometheus.CounterVec
+	TransmitErrors *prometheus.CounterVec
+	ReceiveBytes   *prometheus.CounterVec
+	ReceiveErrors  *prometheus.CounterVec
+	Encryptions    *prometheus.CounterVec
+	Decryptions    *prometheus.CounterVec
+	SendQueueDepth *prometheus.GaugeVec
+	SendDrops      *prometheus.CounterVec
+}
+
+// NewMetrics creates new metrics collectors with the given const labels. The const labels might be nil.
+func NewMetrics(constLabels prometheus.Labels) *Metrics {
+	return &Metrics{
+		TransmitBytes: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "membership_list_transport_transmit_bytes_total",
+				Help:        "Total number of bytes transmitted.",
+				ConstLabels: constLabels,
+			},
+			[]string{"transport"},
+		),
+		TransmitErrors: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "membership_list_transport_transmit_errors_total",
+				Help:        "Total number of errors during transmit.",
+				ConstLabels: constLabels,
+			},
+			[]string{"transport"},
+		),
+		ReceiveBytes: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "membership_list_transport_receive_bytes_total",
+				Help:        "Total number of bytes received.",
+				ConstLabels: constLabels,
+			},
+			[]string{"transport"},
+		),
+		ReceiveErrors: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "membership_list_transport_receive_errors_total",
+				Help:        "Total number of errors during receive.",
+				ConstLabels: constLabels,
+			},
+			[]string{"transport"},
+		),
+		Encryptions: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "membership_list_transport_encryptions_total",
+				Help:        "Total number of encryption operations performed.",
+				ConstLabels: constLabels,
+			},
+			[]string{"transport"},
+		),
+		Decryptions: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "membership_list_transport_decryptions_total",
+				Help:        "Total number of decryption operations performed.",
+				ConstLabels: constLabels,
+			},
+			[]string{"transport"},
+		),
+		SendQueueDepth: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "membership_list_transport_send_queue_depth",
+				Help:        "Current number of sends waiting for a send worker.",
+				ConstLabels: constLabels,
+			},
+			[]string{"transport"},
+		),
+		SendDrops: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "membership_list_transport_send_drops_total",
+				Help:        "Total number of sends dropped because the send queue was full.",
+				ConstLabels: constLabels,
+			},
+			[]string{"transport"},
+		),
+	}
+}
 
-// RegisterMetrics registers all metrics collectors with the given prometheus registerer.
-func RegisterMetrics(registerer prometheus.Registerer) error {
+// Register registers all metrics collectors with the given prometheus registerer.
+func (m *Metrics) Register(registerer prometheus.Registerer) error {
 	metrics := []prometheus.Collector{
-		TransmitBytes,
-		TransmitErrors,
-		ReceiveBytes,
-		ReceiveErrors,
-		Encryptions,
-		Decryptions,
+		m.TransmitBytes,
+		m.TransmitErrors,
+		m.ReceiveBytes,
+		m.ReceiveErrors,
+		m.Encryptions,
+		m.Decryptions,
+		m.SendQueueDepth,
+		m.SendDrops,
 	}
 	for _, metric := range metrics {
 		if err := registerer.Register(metric); err != nil {
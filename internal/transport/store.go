@@ -1,9 +1,14 @@
 package transport
 
-import "github.com/backbone81/membership/internal/encoding"
+import (
+	"bytes"
+
+	"github.com/backbone81/membership/internal/encoding"
+)
 
 // Store provides a client transport which stores all data and always reports success. This is useful for
-// tests, when we need to check if some specific data was transmitted.
+// tests, when we need to check if some specific data was transmitted. The data is copied, as senders re-use their
+// buffers.
 type Store struct {
 	Addresses []encoding.Address
 	Buffers   [][]byte
@@ -14,7 +19,7 @@ var _ Transport = (*Store)(nil)
 
 func (s *Store) Send(address encoding.Address, buffer []byte) error {
 	s.Addresses = append(s.Addresses, address)
-	s.Buffers = append(s.Buffers, buffer)
+	s.Buffers = append(s.Buffers, bytes.Clone(buffer))
 	return nil
 }
 
@@ -16,8 +16,9 @@ import (
 // TCPClient provides reliable transport for sending data to a member.
 //
 // TCPClient is not safe for concurrent use by multiple goroutines. Callers must serialize access to all methods.
-// As this client is always called under the lock of the membership.List we have that serialization there.
+// Wrap one client for every send worker with Async to send concurrently.
 type TCPClient struct {
+	metrics      *Metrics
 	gcm          cipher.AEAD
 	ciphertext   []byte
 	dialTimeout  time.Duration
@@ -27,8 +28,13 @@ type TCPClient struct {
 // TCPClient implements Transport.
 var _ Transport = (*TCPClient)(nil)
 
-// NewTCPClient creates a new TCPClient transport.
-func NewTCPClient(key encryption.Key) (*TCPClient, error) {
+// NewTCPClient creates a new TCPClient transport. New metrics which are not registered anywhere are created when the
+// given metrics are nil.
+func NewTCPClient(key encryption.Key, metrics *Metrics) (*TCPClient, error) {
+	if metrics == nil {
+		// Metrics which are not registered anywhere are simply never collected.
+		metrics = NewMetrics(nil)
+	}
 	aesCipher, err := aes.NewCipher(key[:])
 	if err != nil {
 		return nil, err
@@ -40,6 +46,7 @@ func NewTCPClient(key encryption.Key) (*TCPClient, error) {
 	}
 
 	return &TCPClient{
+		metrics:      metrics,
 		gcm:          gcm,
 		ciphertext:   make([]byte, 0, 1024),
 		dialTimeout:  1 * time.Second,
@@ -65,7 +72,7 @@ func (c *TCPClient) send(address encoding.Address, plaintext []byte) error {
 	encoding.Endian.PutUint32(lengthBuffer[:], uint32(len(plaintext))) //nolint:gosec // we already checked before
 	c.ciphertext = c.gcm.Seal(c.ciphertext[:0], nil, lengthBuffer[:], nil)
 	c.ciphertext = c.gcm.Seal(c.ciphertext, nil, plaintext, nil)
-	Encryptions.WithLabelValues("tcp_client").Add(2)
+	c.metrics.Encryptions.WithLabelValues("tcp_client").Add(2)
 
 	connection, err := net.DialTimeout("tcp", address.String(), c.dialTimeout)
 	if err != nil {
@@ -78,9 +85,9 @@ func (c *TCPClient) send(address encoding.Address, plaintext []byte) error {
 	}
 
 	n, err := connection.Write(c.ciphertext)
-	TransmitBytes.WithLabelValues("tcp_client").Add(float64(n))
+	c.metrics.TransmitBytes.WithLabelValues("tcp_client").Add(float64(n))
 	if err != nil {
-		TransmitErrors.WithLabelValues("tcp_client").Inc()
+		c.metrics.TransmitErrors.WithLabelValues("tcp_client").Inc()
 		return fmt.Errorf("sending the datagram payload: %w", err)
 	}
 	return nil
@@ -30,7 +30,7 @@ var _ = Describe("TCPClient", func() {
 		listenerAddr, ok := listener.Addr().(*net.TCPAddr)
 		Expect(ok).To(BeTrue())
 
-		client, err := transport.NewTCPClient(key1)
+		client, err := transport.NewTCPClient(key1, nil)
 		Expect(err).ToNot(HaveOccurred())
 
 		payload := []byte("foo bar")
@@ -57,7 +57,7 @@ var _ = Describe("TCPClient", func() {
 		listenerAddr, ok := listener.Addr().(*net.TCPAddr)
 		Expect(ok).To(BeTrue())
 
-		client, err := transport.NewTCPClient(key1)
+		client, err := transport.NewTCPClient(key1, nil)
 		Expect(err).ToNot(HaveOccurred())
 
 		payload := []byte("foo bar")
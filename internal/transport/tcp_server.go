@@ -15,6 +15,7 @@ import (
 
 	"github.com/backbone81/membership/internal/encoding"
 	"github.com/backbone81/membership/internal/encryption"
+	"github.com/backbone81/membership/internal/observer"
 )
 
 // TCPServer provides reliable transport for receiving data from members.
@@ -23,6 +24,8 @@ import (
 type TCPServer struct {
 	logger      logr.Logger
 	target      Target
+	observer    observer.Observer
+	metrics     *Metrics
 	bindAddress string
 	listener    net.Listener
 	waitGroup   sync.WaitGroup
@@ -34,8 +37,13 @@ type TCPServer struct {
 	readTimeout time.Duration
 }
 
-// NewTCPServer creates a new TCPServer transport.
-func NewTCPServer(logger logr.Logger, target Target, bindAddress string, keys []encryption.Key) (*TCPServer, error) {
+// NewTCPServer creates a new TCPServer transport. The observer is notified about network messages which could not be
+// decrypted and might be nil. New metrics which are not registered anywhere are created when the given metrics are nil.
+func NewTCPServer(logger logr.Logger, target Target, observer observer.Observer, metrics *Metrics, bindAddress string, keys []encryption.Key) (*TCPServer, error) {
+	if metrics == nil {
+		// Metrics which are not registered anywhere are simply never collected.
+		metrics = NewMetrics(nil)
+	}
 	var gcms []cipher.AEAD //nolint:prealloc // no need to pre-allocate here
 	for _, key := range keys {
 		aesCipher, err := aes.NewCipher(key[:])
@@ -52,6 +60,8 @@ func NewTCPServer(logger logr.Logger, target Target, bindAddress string, keys []
 	return &TCPServer{
 		logger:      logger,
 		target:      target,
+		observer:    observer,
+		metrics:     metrics,
 		bindAddress: bindAddress,
 		gcms:        gcms,
 		buffers:     make([][]byte, 0, 16),
@@ -142,9 +152,9 @@ func (t *TCPServer) handleConnectionImpl(connection net.Conn) error {
 		return fmt.Errorf("setting read deadline: %w", err)
 	}
 	n, err := io.ReadFull(connection, buffer[:4+encryption.Overhead])
-	ReceiveBytes.WithLabelValues("tcp_server").Add(float64(n))
+	t.metrics.ReceiveBytes.WithLabelValues("tcp_server").Add(float64(n))
 	if err != nil {
-		ReceiveErrors.WithLabelValues("tcp_server").Inc()
+		t.metrics.ReceiveErrors.WithLabelValues("tcp_server").Inc()
 		return err
 	}
 	datagramLength, err := t.decryptMessageLength(buffer[:n])
@@ -160,9 +170,9 @@ func (t *TCPServer) handleConnectionImpl(connection net.Conn) error {
 		return fmt.Errorf("setting read deadline: %w", err)
 	}
 	n, err = io.ReadFull(connection, buffer[:datagramLength+encryption.Overhead])
-	ReceiveBytes.WithLabelValues("tcp_server").Add(float64(n))
+	t.metrics.ReceiveBytes.WithLabelValues("tcp_server").Add(float64(n))
 	if err != nil {
-		ReceiveErrors.WithLabelValues("tcp_server").Inc()
+		t.metrics.ReceiveErrors.WithLabelValues("tcp_server").Inc()
 		return err
 	}
 
@@ -184,7 +194,7 @@ func (t *TCPServer) decryptMessageLength(buffer []byte) (int, error) {
 		// decryption fails, the plaintext buffer is overwritten with garbage, so we cannot directly decrypt into
 		// buffer.
 		_, err = gcm.Open(plaintext[:0], nil, buffer, nil)
-		Decryptions.WithLabelValues("tcp_server").Add(1)
+		t.metrics.Decryptions.WithLabelValues("tcp_server").Add(1)
 		if err != nil {
 			// Decryption failed. Let's try the next key.
 			joinedErr = errors.Join(joinedErr, err)
@@ -193,6 +203,9 @@ func (t *TCPServer) decryptMessageLength(buffer []byte) (int, error) {
 		return int(encoding.Endian.Uint32(plaintext[:4])), nil
 	}
 	joinedErr = errors.Join(joinedErr, errors.New("no encryption key could decrypt the network message"))
+	if t.observer != nil {
+		t.observer.DecryptionFailed("tcp_server")
+	}
 	return 0, joinedErr
 }
 
@@ -213,7 +226,7 @@ func (t *TCPServer) decryptAndDispatch(buffer []byte) error {
 		// Note that we are using the plaintext buffer for decrypting the buffer. In case the decryption fails, the
 		// plaintext buffer is overwritten with garbage, so we cannot directly decrypt into buffer.
 		plaintext, err = gcm.Open(plaintext[:0], nil, buffer, nil)
-		Decryptions.WithLabelValues("tcp_server").Add(1)
+		t.metrics.Decryptions.WithLabelValues("tcp_server").Add(1)
 		if err != nil {
 			// Decryption failed. Let's try the next key.
 			joinedErr = errors.Join(joinedErr, err)
@@ -222,6 +235,9 @@ func (t *TCPServer) decryptAndDispatch(buffer []byte) error {
 		return t.target.DispatchDatagram(plaintext)
 	}
 	joinedErr = errors.Join(joinedErr, errors.New("no encryption key could decrypt the network message"))
+	if t.observer != nil {
+		t.observer.DecryptionFailed("tcp_server")
+	}
 	return joinedErr
 }
 
@@ -28,13 +28,13 @@ var _ = Describe("TCPServer", func() {
 
 	It("should correctly receive data with the same key", func() {
 		var target TestTarget
-		server, err := transport.NewTCPServer(GinkgoLogr, &target, "localhost:0", []encryption.Key{key1})
+		server, err := transport.NewTCPServer(GinkgoLogr, &target, nil, nil, "localhost:0", []encryption.Key{key1})
 		Expect(err).ToNot(HaveOccurred())
 		Expect(server.Startup()).To(Succeed())
 		serverAddress, err := server.Addr()
 		Expect(err).ToNot(HaveOccurred())
 
-		client, err := transport.NewTCPClient(key1)
+		client, err := transport.NewTCPClient(key1, nil)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(client.Send(serverAddress, []byte("foo bar"))).To(Succeed())
 		time.Sleep(100 * time.Millisecond)
@@ -45,13 +45,13 @@ var _ = Describe("TCPServer", func() {
 
 	It("should support additional keys", func() {
 		var target TestTarget
-		server, err := transport.NewTCPServer(GinkgoLogr, &target, "localhost:0", []encryption.Key{key1, key2, key3})
+		server, err := transport.NewTCPServer(GinkgoLogr, &target, nil, nil, "localhost:0", []encryption.Key{key1, key2, key3})
 		Expect(err).ToNot(HaveOccurred())
 		Expect(server.Startup()).To(Succeed())
 		serverAddress, err := server.Addr()
 		Expect(err).ToNot(HaveOccurred())
 
-		client, err := transport.NewTCPClient(key1)
+		client, err := transport.NewTCPClient(key1, nil)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(client.Send(serverAddress, []byte("foo bar"))).To(Succeed())
 		time.Sleep(100 * time.Millisecond)
@@ -62,13 +62,13 @@ var _ = Describe("TCPServer", func() {
 
 	It("should try all keys to decrypt", func() {
 		var target TestTarget
-		server, err := transport.NewTCPServer(GinkgoLogr, &target, "localhost:0", []encryption.Key{key1, key2, key3})
+		server, err := transport.NewTCPServer(GinkgoLogr, &target, nil, nil, "localhost:0", []encryption.Key{key1, key2, key3})
 		Expect(err).ToNot(HaveOccurred())
 		Expect(server.Startup()).To(Succeed())
 		serverAddress, err := server.Addr()
 		Expect(err).ToNot(HaveOccurred())
 
-		client, err := transport.NewTCPClient(key3)
+		client, err := transport.NewTCPClient(key3, nil)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(client.Send(serverAddress, []byte("foo bar"))).To(Succeed())
 		time.Sleep(100 * time.Millisecond)
@@ -79,13 +79,13 @@ var _ = Describe("TCPServer", func() {
 
 	It("should fail to decrypt with the wrong key", func() {
 		var target TestTarget
-		server, err := transport.NewTCPServer(GinkgoLogr, &target, "localhost:0", []encryption.Key{key1})
+		server, err := transport.NewTCPServer(GinkgoLogr, &target, nil, nil, "localhost:0", []encryption.Key{key1})
 		Expect(err).ToNot(HaveOccurred())
 		Expect(server.Startup()).To(Succeed())
 		serverAddress, err := server.Addr()
 		Expect(err).ToNot(HaveOccurred())
 
-		client, err := transport.NewTCPClient(key2)
+		client, err := transport.NewTCPClient(key2, nil)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(client.Send(serverAddress, []byte("foo bar"))).To(Succeed())
 		time.Sleep(100 * time.Millisecond)
@@ -104,7 +104,7 @@ var _ = Describe("TCPServer", func() {
 		listenerAddr, ok := listener.Addr().(*net.TCPAddr)
 		Expect(ok).To(BeTrue())
 
-		client, err := transport.NewTCPClient(key1)
+		client, err := transport.NewTCPClient(key1, nil)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(client.Send(encoding.NewAddress(listenerAddr.IP, listenerAddr.Port), []byte("foo bar"))).To(Succeed())
 		time.Sleep(100 * time.Millisecond)
@@ -116,7 +116,7 @@ var _ = Describe("TCPServer", func() {
 		Expect(err).ToNot(HaveOccurred())
 
 		var target TestTarget
-		server, err := transport.NewTCPServer(GinkgoLogr, &target, "localhost:0", []encryption.Key{key1})
+		server, err := transport.NewTCPServer(GinkgoLogr, &target, nil, nil, "localhost:0", []encryption.Key{key1})
 		Expect(err).ToNot(HaveOccurred())
 		Expect(server.Startup()).To(Succeed())
 		serverAddress, err := server.Addr()
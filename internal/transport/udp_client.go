@@ -14,9 +14,10 @@ import (
 // UDPClient provides unreliable transport for sending data to a member.
 //
 // UDPClient is not safe for concurrent use by multiple goroutines. Callers must serialize access to all methods.
-// As this client is always called under the lock of the membership.List we have that serialization there.
+// Wrap one client for every send worker with Async to send concurrently.
 type UDPClient struct {
 	maxDatagramLength int
+	metrics           *Metrics
 	gcm               cipher.AEAD
 	ciphertext        []byte
 }
@@ -24,8 +25,13 @@ type UDPClient struct {
 // UDPClient implements Transport.
 var _ Transport = (*UDPClient)(nil)
 
-// NewUDPClient creates a new UDPClient transport.
-func NewUDPClient(maxDatagramLength int, key encryption.Key) (*UDPClient, error) {
+// NewUDPClient creates a new UDPClient transport. New metrics which are not registered anywhere are created when the
+// given metrics are nil.
+func NewUDPClient(maxDatagramLength int, key encryption.Key, metrics *Metrics) (*UDPClient, error) {
+	if metrics == nil {
+		// Metrics which are not registered anywhere are simply never collected.
+		metrics = NewMetrics(nil)
+	}
 	aesCipher, err := aes.NewCipher(key[:])
 	if err != nil {
 		return nil, err
@@ -38,6 +44,7 @@ func NewUDPClient(maxDatagramLength int, key encryption.Key) (*UDPClient, error)
 
 	return &UDPClient{
 		maxDatagramLength: maxDatagramLength,
+		metrics:           metrics,
 		gcm:               gcm,
 		ciphertext:        make([]byte, 0, maxDatagramLength),
 	}, nil
@@ -50,7 +57,7 @@ func (c *UDPClient) Send(address encoding.Address, buffer []byte) error {
 	// Note that we do not encrypt in-place here, because the buffer might grow for encryption. In that case we want to
 	// hold onto the bigger buffer instead of dropping it again and allocating a bigger buffer again next time.
 	c.ciphertext = c.gcm.Seal(c.ciphertext[:0], nil, buffer, nil)
-	Encryptions.WithLabelValues("udp_client").Add(1)
+	c.metrics.Encryptions.WithLabelValues("udp_client").Add(1)
 	if err := c.send(address, c.ciphertext); err != nil {
 		return fmt.Errorf("UDP client transport send: %w", err)
 	}
@@ -69,9 +76,9 @@ func (c *UDPClient) send(address encoding.Address, buffer []byte) error {
 	defer connection.Close() //nolint:errcheck
 
 	n, err := connection.Write(buffer)
-	TransmitBytes.WithLabelValues("udp_client").Add(float64(n))
+	c.metrics.TransmitBytes.WithLabelValues("udp_client").Add(float64(n))
 	if err != nil {
-		TransmitErrors.WithLabelValues("udp_client").Inc()
+		c.metrics.TransmitErrors.WithLabelValues("udp_client").Inc()
 		return fmt.Errorf("sending the datagram payload: %w", err)
 	}
 	return nil
@@ -29,7 +29,7 @@ var _ = Describe("UDPClient", func() {
 		listenerAddr, ok := listener.LocalAddr().(*net.UDPAddr)
 		Expect(ok).To(BeTrue())
 
-		client, err := transport.NewUDPClient(512, key1)
+		client, err := transport.NewUDPClient(512, key1, nil)
 		Expect(err).ToNot(HaveOccurred())
 
 		payload := []byte("foo bar")
@@ -54,7 +54,7 @@ var _ = Describe("UDPClient", func() {
 		listenerAddr, ok := listener.LocalAddr().(*net.UDPAddr)
 		Expect(ok).To(BeTrue())
 
-		client, err := transport.NewUDPClient(512, key1)
+		client, err := transport.NewUDPClient(512, key1, nil)
 		Expect(err).ToNot(HaveOccurred())
 
 		payload := []byte("foo bar")
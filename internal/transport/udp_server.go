@@ -13,6 +13,7 @@ import (
 
 	"github.com/backbone81/membership/internal/encoding"
 	"github.com/backbone81/membership/internal/encryption"
+	"github.com/backbone81/membership/internal/observer"
 )
 
 // UDPServer provides unreliable transport for receiving data from members.
@@ -23,6 +24,8 @@ import (
 type UDPServer struct {
 	logger              logr.Logger
 	target              Target
+	observer            observer.Observer
+	metrics             *Metrics
 	bindAddress         string
 	connection          *net.UDPConn
 	waitGroup           sync.WaitGroup
@@ -31,8 +34,13 @@ type UDPServer struct {
 	plaintext           []byte
 }
 
-// NewUDPServer creates a new UDPServer.
-func NewUDPServer(logger logr.Logger, target Target, bindAddress string, receiveBufferLength int, keys []encryption.Key) (*UDPServer, error) {
+// NewUDPServer creates a new UDPServer. The observer is notified about network messages which could not be decrypted
+// and might be nil. New metrics which are not registered anywhere are created when the given metrics are nil.
+func NewUDPServer(logger logr.Logger, target Target, observer observer.Observer, metrics *Metrics, bindAddress string, receiveBufferLength int, keys []encryption.Key) (*UDPServer, error) {
+	if metrics == nil {
+		// Metrics which are not registered anywhere are simply never collected.
+		metrics = NewMetrics(nil)
+	}
 	var gcms []cipher.AEAD //nolint:prealloc // no need to pre-allocate here
 	for _, key := range keys {
 		aesCipher, err := aes.NewCipher(key[:])
@@ -49,6 +57,8 @@ func NewUDPServer(logger logr.Logger, target Target, bindAddress string, receive
 	return &UDPServer{
 		logger:              logger,
 		target:              target,
+		observer:            observer,
+		metrics:             metrics,
 		bindAddress:         bindAddress,
 		receiveBufferLength: receiveBufferLength,
 		gcms:                gcms,
@@ -112,9 +122,9 @@ func (t *UDPServer) backgroundTask() {
 	buffer := make([]byte, t.receiveBufferLength)
 	for {
 		n, _, err := t.connection.ReadFromUDP(buffer)
-		ReceiveBytes.WithLabelValues("udp_server").Add(float64(n))
+		t.metrics.ReceiveBytes.WithLabelValues("udp_server").Add(float64(n))
 		if err != nil {
-			ReceiveErrors.WithLabelValues("udp_server").Inc()
+			t.metrics.ReceiveErrors.WithLabelValues("udp_server").Inc()
 			if !errors.Is(err, net.ErrClosed) {
 				t.logger.Error(err, "Reading UDP message.")
 			}
@@ -137,7 +147,7 @@ func (t *UDPServer) decryptAndDispatch(buffer []byte) error {
 		// Note that we are using the plaintext buffer for decrypting the buffer. In case the decryption fails, the
 		// plaintext buffer is overwritten with garbage, so we cannot directly decrypt into buffer.
 		t.plaintext, err = gcm.Open(t.plaintext[:0], nil, buffer, nil)
-		Decryptions.WithLabelValues("udp_server").Add(1)
+		t.metrics.Decryptions.WithLabelValues("udp_server").Add(1)
 		if err != nil {
 			// Decryption failed. Let's try the next key.
 			joinedErr = errors.Join(joinedErr, err)
@@ -146,5 +156,8 @@ func (t *UDPServer) decryptAndDispatch(buffer []byte) error {
 		return t.target.DispatchDatagram(t.plaintext)
 	}
 	joinedErr = errors.Join(joinedErr, errors.New("no encryption key could decrypt the network message"))
+	if t.observer != nil {
+		t.observer.DecryptionFailed("udp_server")
+	}
 	return joinedErr
 }
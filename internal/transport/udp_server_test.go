@@ -27,13 +27,13 @@ var _ = Describe("UDPServer", func() {
 
 	It("should correctly receive data with the same key", func() {
 		var target TestTarget
-		server, err := transport.NewUDPServer(GinkgoLogr, &target, "localhost:0", 512, []encryption.Key{key1})
+		server, err := transport.NewUDPServer(GinkgoLogr, &target, nil, nil, "localhost:0", 512, []encryption.Key{key1})
 		Expect(err).ToNot(HaveOccurred())
 		Expect(server.Startup()).To(Succeed())
 		serverAddress, err := server.Addr()
 		Expect(err).ToNot(HaveOccurred())
 
-		client, err := transport.NewUDPClient(512, key1)
+		client, err := transport.NewUDPClient(512, key1, nil)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(client.Send(serverAddress, []byte("foo bar"))).To(Succeed())
 		time.Sleep(100 * time.Millisecond)
@@ -44,13 +44,13 @@ var _ = Describe("UDPServer", func() {
 
 	It("should support additional keys", func() {
 		var target TestTarget
-		server, err := transport.NewUDPServer(GinkgoLogr, &target, "localhost:0", 512, []encryption.Key{key1, key2, key3})
+		server, err := transport.NewUDPServer(GinkgoLogr, &target, nil, nil, "localhost:0", 512, []encryption.Key{key1, key2, key3})
 		Expect(err).ToNot(HaveOccurred())
 		Expect(server.Startup()).To(Succeed())
 		serverAddress, err := server.Addr()
 		Expect(err).ToNot(HaveOccurred())
 
-		client, err := transport.NewUDPClient(512, key1)
+		client, err := transport.NewUDPClient(512, key1, nil)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(client.Send(serverAddress, []byte("foo bar"))).To(Succeed())
 		time.Sleep(100 * time.Millisecond)
@@ -61,13 +61,13 @@ var _ = Describe("UDPServer", func() {
 
 	It("should try all keys to decrypt", func() {
 		var target TestTarget
-		server, err := transport.NewUDPServer(GinkgoLogr, &target, "localhost:0", 512, []encryption.Key{key1, key2, key3})
+		server, err := transport.NewUDPServer(GinkgoLogr, &target, nil, nil, "localhost:0", 512, []encryption.Key{key1, key2, key3})
 		Expect(err).ToNot(HaveOccurred())
 		Expect(server.Startup()).To(Succeed())
 		serverAddress, err := server.Addr()
 		Expect(err).ToNot(HaveOccurred())
 
-		client, err := transport.NewUDPClient(512, key3)
+		client, err := transport.NewUDPClient(512, key3, nil)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(client.Send(serverAddress, []byte("foo bar"))).To(Succeed())
 		time.Sleep(100 * time.Millisecond)
@@ -78,13 +78,13 @@ var _ = Describe("UDPServer", func() {
 
 	It("should fail to decrypt with wrong key", func() {
 		var target TestTarget
-		server, err := transport.NewUDPServer(GinkgoLogr, &target, "localhost:0", 512, []encryption.Key{key1})
+		server, err := transport.NewUDPServer(GinkgoLogr, &target, nil, nil, "localhost:0", 512, []encryption.Key{key1})
 		Expect(err).ToNot(HaveOccurred())
 		Expect(server.Startup()).To(Succeed())
 		serverAddress, err := server.Addr()
 		Expect(err).ToNot(HaveOccurred())
 
-		client, err := transport.NewUDPClient(512, key2)
+		client, err := transport.NewUDPClient(512, key2, nil)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(client.Send(serverAddress, []byte("foo bar"))).To(Succeed())
 		time.Sleep(100 * time.Millisecond)
@@ -103,7 +103,7 @@ var _ = Describe("UDPServer", func() {
 		listenerAddr, ok := listener.LocalAddr().(*net.UDPAddr)
 		Expect(ok).To(BeTrue())
 
-		client, err := transport.NewUDPClient(512, key1)
+		client, err := transport.NewUDPClient(512, key1, nil)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(client.Send(encoding.NewAddress(listenerAddr.IP, listenerAddr.Port), []byte("foo bar"))).To(Succeed())
 		time.Sleep(100 * time.Millisecond)
@@ -113,7 +113,7 @@ var _ = Describe("UDPServer", func() {
 		Expect(err).ToNot(HaveOccurred())
 
 		var target TestTarget
-		server, err := transport.NewUDPServer(GinkgoLogr, &target, "localhost:0", 512, []encryption.Key{key1})
+		server, err := transport.NewUDPServer(GinkgoLogr, &target, nil, nil, "localhost:0", 512, []encryption.Key{key1})
 		Expect(err).ToNot(HaveOccurred())
 		Expect(server.Startup()).To(Succeed())
 		serverAddress, err := server.Addr()
@@ -0,0 +1,50 @@
+package utility
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// WriteFileAtomic replaces the file at the given path with the given data. The data is written to a temporary file in
+// the same directory first, synced to disk and then renamed over the existing file. The directory is synced afterward
+// to make the rename durable. That way a crash during the write never leaves a partially written file behind.
+func WriteFileAtomic(path string, data []byte) error {
+	directory := filepath.Dir(path)
+	tempFile, err := os.CreateTemp(directory, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("creating temporary file: %w", err)
+	}
+	tempPath := tempFile.Name()
+	defer os.Remove(tempPath) //nolint:errcheck // after a successful rename, the temporary file is already gone
+
+	if _, err := tempFile.Write(data); err != nil {
+		_ = tempFile.Close()
+		return fmt.Errorf("writing temporary file: %w", err)
+	}
+	if err := tempFile.Sync(); err != nil {
+		_ = tempFile.Close()
+		return fmt.Errorf("syncing temporary file: %w", err)
+	}
+	if err := tempFile.Close(); err != nil {
+		return fmt.Errorf("closing temporary file: %w", err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		return fmt.Errorf("renaming temporary file: %w", err)
+	}
+	return syncDirectory(directory)
+}
+
+// syncDirectory syncs the given directory to make sure that a rename within that directory is persisted.
+func syncDirectory(directory string) error {
+	dir, err := os.Open(directory)
+	if err != nil {
+		return fmt.Errorf("opening directory for sync: %w", err)
+	}
+	defer dir.Close() //nolint:errcheck
+
+	if err := dir.Sync(); err != nil {
+		return fmt.Errorf("syncing directory: %w", err)
+	}
+	return nil
+}
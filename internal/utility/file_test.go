@@ -0,0 +1,36 @@
+package utility_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/internal/utility"
+)
+
+var _ = Describe("WriteFileAtomic", func() {
+	It("should create a new file", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "file")
+		Expect(utility.WriteFileAtomic(path, []byte("content"))).To(Succeed())
+		Expect(os.ReadFile(path)).To(Equal([]byte("content")))
+	})
+
+	It("should replace an existing file without leaving temporary files behind", func() {
+		directory := GinkgoT().TempDir()
+		path := filepath.Join(directory, "file")
+		Expect(utility.WriteFileAtomic(path, []byte("old content"))).To(Succeed())
+		Expect(utility.WriteFileAtomic(path, []byte("new"))).To(Succeed())
+		Expect(os.ReadFile(path)).To(Equal([]byte("new")))
+
+		entries, err := os.ReadDir(directory)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(entries).To(HaveLen(1))
+	})
+
+	It("should fail for a missing directory", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "missing", "file")
+		Expect(utility.WriteFileAtomic(path, []byte("content"))).ToNot(Succeed())
+	})
+})
@@ -0,0 +1,374 @@
+package balancer
+
+import (
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/backbone81/membership/pkg/membership"
+)
+
+// member is the bookkeeping the balancer keeps for every member.
+type member struct {
+	// address is the address of the member.
+	address membership.Address
+
+	// suspect reports if the member is currently suspected to have failed. Suspect members are never picked.
+	suspect bool
+
+	// failedUntil is the point in time until which the member is avoided because of a failure reported by the caller.
+	failedUntil time.Time
+
+	// roundTripTime is the smoothed round trip time observed for the member. Zero when nothing was observed yet.
+	roundTripTime time.Duration
+
+	// load is the number of requests currently in flight to the member.
+	load int
+}
+
+// Balancer selects members for sending requests to.
+//
+// The balancer learns about members through MemberAdded, MemberRemoved, MemberStateChanged and
+// ObserveRoundTripTime. Use Options to have the membership list call those automatically. All methods only take the
+// lock of the balancer and never call back into the membership list. It is therefore safe to call them from within the
+// callbacks of the membership list.
+//
+// Balancer is safe for concurrent use by multiple goroutines.
+type Balancer struct {
+	// mutex is responsible for serializing concurrent access to members of this struct.
+	mutex sync.Mutex
+
+	// config holds the configuration of the balancer.
+	config Config
+
+	// members holds all known alive and suspect members in no particular order.
+	members []member
+
+	// indexByAddress maps the address of a member to its index in members.
+	indexByAddress map[membership.Address]int
+
+	// nextRoundRobin is the index into members which is checked next for round-robin selection.
+	nextRoundRobin int
+}
+
+// New creates a new balancer. Provide options to customize default config.
+func New(options ...Option) *Balancer {
+	config := DefaultConfig
+	for _, option := range options {
+		option(&config)
+	}
+
+	return &Balancer{
+		config:         config,
+		members:        make([]member, 0, config.MemberPreAllocation),
+		indexByAddress: make(map[membership.Address]int, config.MemberPreAllocation),
+	}
+}
+
+// Config returns the config of the balancer.
+func (b *Balancer) Config() Config {
+	return b.config
+}
+
+// Options returns the options for the membership list which keep the balancer up to date.
+func (b *Balancer) Options() []membership.Option {
+	return []membership.Option{
+		membership.WithMemberAddedCallback(b.MemberAdded),
+		membership.WithMemberRemovedCallback(b.MemberRemoved),
+		membership.WithMemberStateChangedCallback(b.MemberStateChanged),
+		membership.WithMemberRoundTripTimeCallback(b.ObserveRoundTripTime),
+	}
+}
+
+// Len returns the number of members known to the balancer, including suspect members.
+func (b *Balancer) Len() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return len(b.members)
+}
+
+// MemberAdded adds the member with the given address as alive.
+func (b *Balancer) MemberAdded(address membership.Address) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if _, found := b.indexByAddress[address]; found {
+		return
+	}
+	b.indexByAddress[address] = len(b.members)
+	b.members = append(b.members, member{
+		address: address,
+	})
+}
+
+// MemberRemoved removes the member with the given address.
+func (b *Balancer) MemberRemoved(address membership.Address) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	index, found := b.indexByAddress[address]
+	if !found {
+		return
+	}
+	delete(b.indexByAddress, address)
+
+	// We move the last member into the gap to not have to shift all members.
+	lastIndex := len(b.members) - 1
+	if index != lastIndex {
+		b.members[index] = b.members[lastIndex]
+		b.indexByAddress[b.members[index].address] = index
+	}
+	b.members[lastIndex] = member{}
+	b.members = b.members[:lastIndex]
+}
+
+// MemberStateChanged updates the state of the member with the given address. Suspect members are never picked.
+func (b *Balancer) MemberStateChanged(address membership.Address, state membership.MemberState) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	index, found := b.indexByAddress[address]
+	if !found {
+		return
+	}
+	b.members[index].suspect = state == membership.MemberStateSuspect
+}
+
+// ObserveRoundTripTime records the given round trip time for the member with the given address. Callers can report
+// the latencies of their own requests here in addition to the round trip times observed by the membership list.
+func (b *Balancer) ObserveRoundTripTime(address membership.Address, roundTripTime time.Duration) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	index, found := b.indexByAddress[address]
+	if !found {
+		return
+	}
+	m := &b.members[index]
+	if m.roundTripTime == 0 {
+		m.roundTripTime = roundTripTime
+		return
+	}
+	m.roundTripTime = time.Duration(b.config.RoundTripTimeAlpha*float64(roundTripTime) +
+		(1-b.config.RoundTripTimeAlpha)*float64(m.roundTripTime))
+}
+
+// ReportFailure reports a failed request to the member with the given address. The member is avoided until the
+// failure timeout expires.
+func (b *Balancer) ReportFailure(address membership.Address) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	index, found := b.indexByAddress[address]
+	if !found {
+		return
+	}
+	b.members[index].failedUntil = time.Now().Add(b.config.FailureTimeout)
+}
+
+// Acquire reports the start of a request to the member with the given address. The number of requests in flight is
+// used by PowerOfTwoChoices to prefer the least loaded member. Every call to Acquire must be followed by a call to
+// Release.
+func (b *Balancer) Acquire(address membership.Address) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	index, found := b.indexByAddress[address]
+	if !found {
+		return
+	}
+	b.members[index].load++
+}
+
+// Release reports the end of a request to the member with the given address.
+func (b *Balancer) Release(address membership.Address) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	index, found := b.indexByAddress[address]
+	if !found {
+		return
+	}
+	b.members[index].load = max(0, b.members[index].load-1)
+}
+
+// Random returns a member picked at random. Reports false when no member is available.
+func (b *Balancer) Random() (membership.Address, bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	index := b.firstRandom(time.Now())
+	if index == -1 {
+		return membership.Address{}, false
+	}
+	return b.members[index].address, true
+}
+
+// RandomN appends up to count distinct members picked at random to the given slice and returns it. Fewer members are
+// appended when not enough members are available. This does not allocate, when the given slice has enough capacity.
+func (b *Balancer) RandomN(addresses []membership.Address, count int) []membership.Address {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := time.Now()
+	for _, ignoreFailures := range [...]bool{false, true} {
+		start, stride := b.randomPermutation()
+		for i := range len(b.members) {
+			if count <= 0 {
+				return addresses
+			}
+			m := &b.members[(start+i*stride)%len(b.members)]
+			if !b.usable(m, now, ignoreFailures) || (ignoreFailures && b.usable(m, now, false)) {
+				// On the second pass, we skip all members which were already picked during the first pass.
+				continue
+			}
+			addresses = append(addresses, m.address)
+			count--
+		}
+	}
+	return addresses
+}
+
+// RoundRobin returns the members one after another. Reports false when no member is available.
+func (b *Balancer) RoundRobin() (membership.Address, bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := time.Now()
+	for _, ignoreFailures := range [...]bool{false, true} {
+		for range len(b.members) {
+			if b.nextRoundRobin >= len(b.members) {
+				b.nextRoundRobin = 0
+			}
+			m := &b.members[b.nextRoundRobin]
+			b.nextRoundRobin++
+			if b.usable(m, now, ignoreFailures) {
+				return m.address, true
+			}
+		}
+	}
+	return membership.Address{}, false
+}
+
+// PowerOfTwoChoices picks two members at random and returns the one with less requests in flight. Ties are broken by
+// the lower round trip time. Reports false when no member is available.
+func (b *Balancer) PowerOfTwoChoices() (membership.Address, bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := time.Now()
+	for _, ignoreFailures := range [...]bool{false, true} {
+		first, second := -1, -1
+		start, stride := b.randomPermutation()
+		for i := range len(b.members) {
+			index := (start + i*stride) % len(b.members)
+			if !b.usable(&b.members[index], now, ignoreFailures) {
+				continue
+			}
+			if first == -1 {
+				first = index
+				continue
+			}
+			second = index
+			break
+		}
+		if first == -1 {
+			continue
+		}
+		if second == -1 || b.lessLoaded(&b.members[first], &b.members[second]) {
+			return b.members[first].address, true
+		}
+		return b.members[second].address, true
+	}
+	return membership.Address{}, false
+}
+
+// LowestRoundTripTime returns the member with the lowest round trip time. Members without any observed round trip
+// time are assumed to have the default round trip time. Reports false when no member is available.
+func (b *Balancer) LowestRoundTripTime() (membership.Address, bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := time.Now()
+	for _, ignoreFailures := range [...]bool{false, true} {
+		best := -1
+		for i := range b.members {
+			if !b.usable(&b.members[i], now, ignoreFailures) {
+				continue
+			}
+			if best == -1 || b.roundTripTime(&b.members[i]) < b.roundTripTime(&b.members[best]) {
+				best = i
+			}
+		}
+		if best != -1 {
+			return b.members[best].address, true
+		}
+	}
+	return membership.Address{}, false
+}
+
+// usable reports if the given member can be picked. Suspect members are never usable. Members with a recent failure
+// are only usable when ignoring failures. That way we fall back to members with failures when nothing else is left.
+func (b *Balancer) usable(m *member, now time.Time, ignoreFailures bool) bool {
+	if m.suspect {
+		return false
+	}
+	return ignoreFailures || !now.Before(m.failedUntil)
+}
+
+// firstRandom returns the index of the first usable member in a random order. Returns -1 when no member is usable.
+func (b *Balancer) firstRandom(now time.Time) int {
+	for _, ignoreFailures := range [...]bool{false, true} {
+		start, stride := b.randomPermutation()
+		for i := range len(b.members) {
+			index := (start + i*stride) % len(b.members)
+			if b.usable(&b.members[index], now, ignoreFailures) {
+				return index
+			}
+		}
+	}
+	return -1
+}
+
+// randomPermutation returns a random start index and a random stride which is coprime to the number of members.
+// Visiting (start + i*stride) % len(members) for all i from 0 to len(members)-1 visits every member exactly once in a
+// random order. This allows us to iterate in a random order without allocating a shuffled slice of indexes.
+func (b *Balancer) randomPermutation() (int, int) {
+	if len(b.members) < 2 {
+		return 0, 1
+	}
+	start := rand.IntN(len(b.members)) //nolint:gosec // we do not need crypto/rand here
+	for {
+		stride := rand.IntN(len(b.members)-1) + 1 //nolint:gosec // we do not need crypto/rand here
+		if gcd(stride, len(b.members)) == 1 {
+			return start, stride
+		}
+	}
+}
+
+// lessLoaded reports if lhs should be preferred over rhs because it has less requests in flight or the same number of
+// requests in flight but a lower round trip time.
+func (b *Balancer) lessLoaded(lhs *member, rhs *member) bool {
+	if lhs.load != rhs.load {
+		return lhs.load < rhs.load
+	}
+	return b.roundTripTime(lhs) <= b.roundTripTime(rhs)
+}
+
+// roundTripTime returns the round trip time of the given member or the default round trip time when nothing was
+// observed yet.
+func (b *Balancer) roundTripTime(m *member) time.Duration {
+	if m.roundTripTime == 0 {
+		return b.config.DefaultRoundTripTime
+	}
+	return m.roundTripTime
+}
+
+// gcd returns the greatest common divisor of a and b.
+func gcd(a int, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
@@ -0,0 +1,233 @@
+package balancer_test
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/pkg/membership"
+	"github.com/backbone81/membership/pkg/membership/balancer"
+)
+
+var _ = Describe("Balancer", func() {
+	var myBalancer *balancer.Balancer
+
+	BeforeEach(func() {
+		myBalancer = balancer.New()
+		myBalancer.MemberAdded(TestAddress)
+		myBalancer.MemberAdded(TestAddress2)
+		myBalancer.MemberAdded(TestAddress3)
+	})
+
+	It("should track added and removed members", func() {
+		Expect(myBalancer.Len()).To(Equal(3))
+		myBalancer.MemberAdded(TestAddress)
+		Expect(myBalancer.Len()).To(Equal(3))
+
+		myBalancer.MemberRemoved(TestAddress)
+		Expect(myBalancer.Len()).To(Equal(2))
+		for range 100 {
+			address, ok := myBalancer.Random()
+			Expect(ok).To(BeTrue())
+			Expect(address).ToNot(Equal(TestAddress))
+		}
+	})
+
+	It("should report nothing when empty", func() {
+		emptyBalancer := balancer.New()
+		Expect(Picked(emptyBalancer.Random())).To(BeFalse())
+		Expect(Picked(emptyBalancer.RoundRobin())).To(BeFalse())
+		Expect(Picked(emptyBalancer.PowerOfTwoChoices())).To(BeFalse())
+		Expect(Picked(emptyBalancer.LowestRoundTripTime())).To(BeFalse())
+		Expect(emptyBalancer.RandomN(nil, 3)).To(BeEmpty())
+	})
+
+	It("should pick all members at random", func() {
+		picked := make(map[membership.Address]int)
+		for range 300 {
+			address, ok := myBalancer.Random()
+			Expect(ok).To(BeTrue())
+			picked[address]++
+		}
+		Expect(picked).To(HaveLen(3))
+	})
+
+	It("should pick distinct members at random", func() {
+		addresses := myBalancer.RandomN(nil, 2)
+		Expect(addresses).To(HaveLen(2))
+		Expect(addresses[0]).ToNot(Equal(addresses[1]))
+
+		addresses = myBalancer.RandomN(nil, 5)
+		Expect(addresses).To(ConsistOf(TestAddress, TestAddress2, TestAddress3))
+	})
+
+	It("should pick members round-robin", func() {
+		var addresses []membership.Address
+		for range 6 {
+			address, ok := myBalancer.RoundRobin()
+			Expect(ok).To(BeTrue())
+			addresses = append(addresses, address)
+		}
+		Expect(addresses[:3]).To(ConsistOf(TestAddress, TestAddress2, TestAddress3))
+		Expect(addresses[3:]).To(Equal(addresses[:3]))
+	})
+
+	It("should skip suspect members", func() {
+		myBalancer.MemberStateChanged(TestAddress, membership.MemberStateSuspect)
+		myBalancer.MemberStateChanged(TestAddress2, membership.MemberStateSuspect)
+		for range 10 {
+			Expect(MustPick(myBalancer.Random())).To(Equal(TestAddress3))
+			Expect(MustPick(myBalancer.RoundRobin())).To(Equal(TestAddress3))
+			Expect(MustPick(myBalancer.PowerOfTwoChoices())).To(Equal(TestAddress3))
+			Expect(MustPick(myBalancer.LowestRoundTripTime())).To(Equal(TestAddress3))
+		}
+
+		myBalancer.MemberStateChanged(TestAddress3, membership.MemberStateSuspect)
+		Expect(Picked(myBalancer.Random())).To(BeFalse())
+
+		myBalancer.MemberStateChanged(TestAddress, membership.MemberStateAlive)
+		Expect(MustPick(myBalancer.Random())).To(Equal(TestAddress))
+	})
+
+	It("should avoid members with failures until the failure timeout expires", func() {
+		synctest.Test(testingT, func(t *testing.T) {
+			defer GinkgoRecover()
+
+			myBalancer := balancer.New(balancer.WithFailureTimeout(10 * time.Second))
+			myBalancer.MemberAdded(TestAddress)
+			myBalancer.MemberAdded(TestAddress2)
+			myBalancer.ReportFailure(TestAddress)
+			for range 10 {
+				Expect(MustPick(myBalancer.Random())).To(Equal(TestAddress2))
+				Expect(MustPick(myBalancer.RoundRobin())).To(Equal(TestAddress2))
+			}
+
+			time.Sleep(10 * time.Second)
+			picked := make(map[membership.Address]int)
+			for range 10 {
+				address, ok := myBalancer.RoundRobin()
+				Expect(ok).To(BeTrue())
+				picked[address]++
+			}
+			Expect(picked).To(HaveLen(2))
+		})
+	})
+
+	It("should fall back to members with failures when nothing else is left", func() {
+		myBalancer.ReportFailure(TestAddress)
+		myBalancer.ReportFailure(TestAddress2)
+		myBalancer.ReportFailure(TestAddress3)
+		Expect(Picked(myBalancer.Random())).To(BeTrue())
+		Expect(Picked(myBalancer.RoundRobin())).To(BeTrue())
+		Expect(Picked(myBalancer.PowerOfTwoChoices())).To(BeTrue())
+		Expect(Picked(myBalancer.LowestRoundTripTime())).To(BeTrue())
+	})
+
+	It("should prefer members without failures when picking multiple", func() {
+		myBalancer.ReportFailure(TestAddress)
+		addresses := myBalancer.RandomN(nil, 3)
+		Expect(addresses).To(HaveLen(3))
+		Expect(addresses[2]).To(Equal(TestAddress))
+	})
+
+	It("should pick the member with the lowest round trip time", func() {
+		myBalancer.ObserveRoundTripTime(TestAddress, 300*time.Millisecond)
+		myBalancer.ObserveRoundTripTime(TestAddress2, 10*time.Millisecond)
+		myBalancer.ObserveRoundTripTime(TestAddress3, 200*time.Millisecond)
+		Expect(MustPick(myBalancer.LowestRoundTripTime())).To(Equal(TestAddress2))
+
+		myBalancer.ReportFailure(TestAddress2)
+		Expect(MustPick(myBalancer.LowestRoundTripTime())).To(Equal(TestAddress3))
+	})
+
+	It("should smooth observed round trip times", func() {
+		myBalancer := balancer.New(balancer.WithRoundTripTimeAlpha(0.5))
+		myBalancer.MemberAdded(TestAddress)
+		myBalancer.MemberAdded(TestAddress2)
+		myBalancer.ObserveRoundTripTime(TestAddress, 100*time.Millisecond)
+		myBalancer.ObserveRoundTripTime(TestAddress2, 120*time.Millisecond)
+		Expect(MustPick(myBalancer.LowestRoundTripTime())).To(Equal(TestAddress))
+
+		// A single spike moves the average only half way.
+		myBalancer.ObserveRoundTripTime(TestAddress, 160*time.Millisecond)
+		Expect(MustPick(myBalancer.LowestRoundTripTime())).To(Equal(TestAddress2))
+	})
+
+	It("should prefer the less loaded member with power of two choices", func() {
+		myBalancer := balancer.New()
+		myBalancer.MemberAdded(TestAddress)
+		myBalancer.MemberAdded(TestAddress2)
+		myBalancer.Acquire(TestAddress)
+		for range 10 {
+			Expect(MustPick(myBalancer.PowerOfTwoChoices())).To(Equal(TestAddress2))
+		}
+
+		myBalancer.Release(TestAddress)
+		myBalancer.ObserveRoundTripTime(TestAddress, time.Millisecond)
+		for range 10 {
+			Expect(MustPick(myBalancer.PowerOfTwoChoices())).To(Equal(TestAddress))
+		}
+	})
+
+	It("should ignore unknown members", func() {
+		unknown := membership.NewAddress(net.IPv4(31, 32, 33, 34), 1024)
+		myBalancer.MemberRemoved(unknown)
+		myBalancer.MemberStateChanged(unknown, membership.MemberStateSuspect)
+		myBalancer.ObserveRoundTripTime(unknown, time.Millisecond)
+		myBalancer.ReportFailure(unknown)
+		myBalancer.Acquire(unknown)
+		myBalancer.Release(unknown)
+		Expect(myBalancer.Len()).To(Equal(3))
+	})
+
+	It("should not allocate memory when picking", func() {
+		bigBalancer := balancer.New()
+		for i := range 1000 {
+			bigBalancer.MemberAdded(membership.NewAddress(net.IPv4(10, 0, byte(i/256), byte(i%256)), 1024))
+		}
+		addresses := make([]membership.Address, 0, 10)
+
+		Expect(testing.AllocsPerRun(100, func() {
+			_, _ = bigBalancer.Random()
+			_, _ = bigBalancer.RoundRobin()
+			_, _ = bigBalancer.PowerOfTwoChoices()
+			_, _ = bigBalancer.LowestRoundTripTime()
+			addresses = bigBalancer.RandomN(addresses[:0], 10)
+		})).To(BeZero())
+	})
+})
+
+func BenchmarkBalancer(b *testing.B) {
+	for _, memberCount := range []int{16, 1024, 16384} {
+		myBalancer := balancer.New()
+		for i := range memberCount {
+			myBalancer.MemberAdded(membership.NewAddress(net.IPv4(10, 0, byte(i/256), byte(i%256)), 1024))
+		}
+
+		b.Run(fmt.Sprintf("Random with %d members", memberCount), func(b *testing.B) {
+			for b.Loop() {
+				_, _ = myBalancer.Random()
+			}
+		})
+		b.Run(fmt.Sprintf("RoundRobin with %d members", memberCount), func(b *testing.B) {
+			for b.Loop() {
+				_, _ = myBalancer.RoundRobin()
+			}
+		})
+		b.Run(fmt.Sprintf("PowerOfTwoChoices with %d members", memberCount), func(b *testing.B) {
+			for b.Loop() {
+				_, _ = myBalancer.PowerOfTwoChoices()
+			}
+		})
+		b.Run(fmt.Sprintf("LowestRoundTripTime with %d members", memberCount), func(b *testing.B) {
+			for b.Loop() {
+				_, _ = myBalancer.LowestRoundTripTime()
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package balancer
+
+import "time"
+
+// Config is the configuration the balancer is using.
+type Config struct {
+	// FailureTimeout is the time a member is avoided after a failure was reported for it.
+	FailureTimeout time.Duration
+
+	// DefaultRoundTripTime is the round trip time assumed for members which do not have any observed round trip time
+	// yet.
+	DefaultRoundTripTime time.Duration
+
+	// RoundTripTimeAlpha is the smoothing factor for the exponential moving average of observed round trip times.
+	// Values close to 1.0 follow new observations quickly, values close to 0.0 smooth out spikes.
+	RoundTripTimeAlpha float64
+
+	// MemberPreAllocation is the number of members which are pre-allocated to reduce allocations later.
+	MemberPreAllocation int
+}
+
+// DefaultConfig provides a balancer configuration with sane defaults for most situations.
+var DefaultConfig = Config{
+	FailureTimeout:       10 * time.Second,
+	DefaultRoundTripTime: 100 * time.Millisecond,
+	RoundTripTimeAlpha:   0.2,
+	MemberPreAllocation:  128,
+}
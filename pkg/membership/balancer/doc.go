@@ -0,0 +1,5 @@
+// Package balancer provides peer selection and client-side load-balancing on top of the membership list. It tracks
+// membership changes through the callbacks of the membership list and offers random, round-robin,
+// power-of-two-choices and lowest round trip time selection. Suspect members are skipped and members with failures
+// reported by the caller are avoided for some time. Picking a member does not allocate any memory.
+package balancer
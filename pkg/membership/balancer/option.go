@@ -0,0 +1,34 @@
+package balancer
+
+import "time"
+
+// Option is the function signature for all balancer options to implement.
+type Option func(config *Config)
+
+// WithFailureTimeout sets the time a member is avoided after a failure was reported for it.
+func WithFailureTimeout(timeout time.Duration) Option {
+	return func(config *Config) {
+		config.FailureTimeout = max(0, timeout)
+	}
+}
+
+// WithDefaultRoundTripTime sets the round trip time assumed for members without any observation.
+func WithDefaultRoundTripTime(roundTripTime time.Duration) Option {
+	return func(config *Config) {
+		config.DefaultRoundTripTime = max(0, roundTripTime)
+	}
+}
+
+// WithRoundTripTimeAlpha sets the smoothing factor for observed round trip times.
+func WithRoundTripTimeAlpha(alpha float64) Option {
+	return func(config *Config) {
+		config.RoundTripTimeAlpha = min(1, max(0, alpha))
+	}
+}
+
+// WithMemberPreAllocation sets the number of members which are pre-allocated.
+func WithMemberPreAllocation(count int) Option {
+	return func(config *Config) {
+		config.MemberPreAllocation = max(0, count)
+	}
+}
@@ -0,0 +1,31 @@
+package membership
+
+// chainCallback returns a callback which calls the first and then the second given callback. This allows for giving
+// the same callback option multiple times, for example to keep a balancer and a hash ring up to date with the same
+// membership list. A nil callback is skipped.
+func chainCallback[A any](first func(A), second func(A)) func(A) {
+	if first == nil {
+		return second
+	}
+	if second == nil {
+		return first
+	}
+	return func(a A) {
+		first(a)
+		second(a)
+	}
+}
+
+// chainCallback2 is like chainCallback for callbacks with two arguments.
+func chainCallback2[A any, B any](first func(A, B), second func(A, B)) func(A, B) {
+	if first == nil {
+		return second
+	}
+	if second == nil {
+		return first
+	}
+	return func(a A, b B) {
+		first(a, b)
+		second(a, b)
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/backbone81/membership/internal/encoding"
 	"github.com/backbone81/membership/internal/encryption"
+	"github.com/backbone81/membership/internal/health"
 	intmembership "github.com/backbone81/membership/internal/membership"
 	"github.com/backbone81/membership/internal/scheduler"
 )
@@ -40,6 +42,9 @@ type Config struct {
 	ListRequestInterval time.Duration
 
 	// MemberAddedCallback is the callback which is triggered when a new member is added to the list.
+	// The options for this and the other notification callbacks can be given multiple times. All given callbacks are
+	// triggered in the order of the options. That way packages like the balancer, the coordinator and the hash ring
+	// can be combined with callbacks of your own.
 	// This callback executes under the lock of the membership list. If you call any method on the membership list
 	// during that callback, you create a deadlock. If you need to call the membership list during your callback,
 	// create a go routine which executes what you want to do.
@@ -57,6 +62,62 @@ type Config struct {
 	// price when necessary.
 	MemberRemovedCallback func(address encoding.Address)
 
+	// MemberStateChangedCallback is the callback which is triggered when a member changes between alive and suspect.
+	// It is also triggered when a member is added as suspect, as members are otherwise considered alive when added.
+	// This callback executes under the lock of the membership list. The same restrictions as for MemberAddedCallback
+	// apply.
+	MemberStateChangedCallback func(address encoding.Address, state MemberState)
+
+	// MemberRoundTripTimeCallback is the callback which is triggered when the round trip time of a direct ping to a
+	// member was observed. This callback executes under the lock of the membership list. The same restrictions as for
+	// MemberAddedCallback apply.
+	MemberRoundTripTimeCallback func(address encoding.Address, roundTripTime time.Duration)
+
+	// AdmissionCallback is the callback which is asked before an unknown member is added to the list. It receives the
+	// address of the member and the address of the member which gossiped it. Return an error to veto the member.
+	// This callback executes under the lock of the membership list. The same restrictions as for MemberAddedCallback
+	// apply.
+	AdmissionCallback func(address encoding.Address, source encoding.Address) error
+
+	// MergeCallback is the callback which is triggered when a full member list sync merged two partitions of the
+	// cluster, for example after a network partition healed. It is triggered once per sync in addition to the
+	// individual MemberAddedCallback calls. This callback executes under the lock of the membership list. The same
+	// restrictions as for MemberAddedCallback apply.
+	MergeCallback func(event MergeEvent)
+
+	// AckPayloadCallback is the callback which provides a small application payload, like the current load or a
+	// readiness bit, attached to every ack this member sends. The members pinging this member record the latest payload,
+	// which is available through List.AckPayload. No payload is attached when nil. This callback executes under the
+	// lock of the membership list. The same restrictions as for MemberAddedCallback apply.
+	AckPayloadCallback func() []byte
+
+	// MaxAckPayloadLength is the maximum length in bytes of the payload provided by AckPayloadCallback. Acks are sent
+	// without payload when the payload is longer.
+	MaxAckPayloadLength int
+
+	// BroadcastCallback is the callback which is triggered when a broadcast started by another member with
+	// List.Broadcast was received. Every broadcast is delivered only once. The payload must not be modified. This
+	// callback executes under the lock of the membership list. The same restrictions as for MemberAddedCallback apply.
+	BroadcastCallback func(origin Address, payload []byte)
+
+	// MinorityPartitionCallback is the callback which is triggered when this member ends up in a minority partition
+	// and when it regains quorum. A consensus layer can refuse writes or elections while in a minority partition. This
+	// callback executes under the lock of the membership list. The same restrictions as for MemberAddedCallback apply.
+	MinorityPartitionCallback func(minority bool)
+
+	// Observer is notified about protocol activity like pings, suspicions, gossip and decryption failures. No observer
+	// is notified when nil, which keeps the protocol free of any overhead.
+	Observer Observer
+
+	// MetricsRegisterer is the prometheus registerer the metrics of this membership list are registered with. Every
+	// membership list owns its own metrics, so multiple membership lists can be registered with different registerers
+	// or with the same registerer and different MetricsConstLabels. No metrics are registered when nil.
+	MetricsRegisterer prometheus.Registerer
+
+	// MetricsConstLabels are the labels which are added to all metrics of this membership list. They allow for telling
+	// apart multiple membership lists registered with the same registerer.
+	MetricsConstLabels prometheus.Labels
+
 	// SafetyFactor is a multiplier which describes the safety margin for disseminating gossip and declaring a suspect
 	// as faulty. A factor of 1.0 wil return the minimal number of periods required in a perfect world. A factor of 2.0
 	// will double the number of periods. Small values between 2.0 and 4.0 should usually be a safe value.
@@ -93,20 +154,203 @@ type Config struct {
 	// the bootstrap members are static. In cases where bootstrap members are ephemeral, this might lead to unnecessary
 	// adds and removes and should better be disabled.
 	ReconnectBootstrapMembers bool
+
+	// ReconnectFaultyMemberCount is the number of faulty members which are probed at the end of every protocol period,
+	// to find out if they are reachable again. A faulty member which responds rejoins, and its member list is requested
+	// to merge the two partitions. No faulty members are probed when zero.
+	ReconnectFaultyMemberCount int
+
+	// ReconnectMaxBackoff is the maximum time between two attempts to reconnect the same faulty member or bootstrap
+	// member. The time between attempts starts at one protocol period and doubles with every attempt which was not
+	// successful. It is rounded up to full protocol periods.
+	ReconnectMaxBackoff time.Duration
+
+	// PushPullListSync reports if list requests carry our own member list. The recipient merges our member list into
+	// its own before responding with its member list. This heals the view of both sides in a single round trip, which
+	// speeds up convergence after network partitions and joins. As the request becomes as big as the response, it is
+	// sent over TCP instead of UDP. Members running an older version do not understand requests with a member list,
+	// enable this only once all members are updated.
+	PushPullListSync bool
+
+	// IncarnationStore is the store the incarnation number of this member is persisted to. On startup, the member
+	// resumes with the persisted incarnation number plus one. This prevents a restarted member from being ignored or
+	// immediately suspected because of gossip about its previous life. No incarnation number is persisted when nil.
+	IncarnationStore IncarnationStore
+
+	// SnapshotPath is the path of the file the known members are periodically written to. On startup, the members of
+	// that snapshot are contacted in addition to the bootstrap members. This allows a restarted member to rejoin, even
+	// when all bootstrap members are gone. No snapshot is written or read when empty.
+	SnapshotPath string
+
+	// SnapshotInterval is the time between two snapshots. It is rounded up to full protocol periods.
+	SnapshotInterval time.Duration
+
+	// SnapshotMaxAge is the maximum time since we last heard from or about a member in the snapshot for it to be
+	// considered on startup. Older members are ignored, as they are most likely gone. Members of any age are considered
+	// when zero.
+	SnapshotMaxAge time.Duration
+
+	// HealthPolicy describes how this member announces itself to the other members while any of its health checks
+	// fails. Once all health checks succeed again, this member refutes with a new incarnation number and rejoins.
+	HealthPolicy HealthPolicy
+
+	// HealthCheckInterval is the time between two runs of all health checks.
+	HealthCheckInterval time.Duration
+
+	// HealthCheckTimeout is the maximum time a single health check is allowed to take. A health check which does not
+	// complete in time is considered failed.
+	HealthCheckTimeout time.Duration
+
+	// AllowedNetworks is the list of networks unknown members must be part of to be added. Every entry is a network in
+	// CIDR notation, optionally followed by a colon and a port or port range, like "10.0.0.0/8:3000-3999". Any member
+	// is allowed when empty.
+	AllowedNetworks []string
+
+	// DeniedNetworks is the list of networks unknown members must not be part of to be added. The format is the same
+	// as for AllowedNetworks. Denied networks take precedence over allowed networks.
+	DeniedNetworks []string
+
+	// ForceRemoveListRequestCount is the number of list requests a force removed member is kept as a tombstone.
+	ForceRemoveListRequestCount int
+
+	// QueryTimeout is the time responses to a query are collected, when the context of the query has no deadline.
+	QueryTimeout time.Duration
+
+	// QueryHandlerTimeout is the maximum time a query handler is allowed to take for answering a query.
+	QueryHandlerTimeout time.Duration
+
+	// QueryResponseBufferSize is the number of responses buffered for every query. Responses which arrive while the
+	// buffer is full are dropped.
+	QueryResponseBufferSize int
+
+	// MaxConcurrentQueryHandlers is the maximum number of query handlers running at the same time. Queries which arrive
+	// while that many query handlers are running are dropped.
+	MaxConcurrentQueryHandlers int
+
+	// MergeMinRevivedMembers is the minimum number of faulty members which need to be revived by a single full member
+	// list sync to report the sync as a merge of two partitions. A sync with a reconnected bootstrap member is always
+	// reported as a merge.
+	MergeMinRevivedMembers int
+
+	// Passive reports if this member only follows the membership of the cluster without being a member itself. This is
+	// intended for monitoring tools, CLIs and proxies. A passive member learns about the members through list requests
+	// and gossip and answers pings, but never gossips about itself. It therefore never appears in the member list of
+	// other members, is not probed by them and does not count towards the cluster size. A passive member does not
+	// declare other members as suspect or faulty either, it follows the gossip of the other members instead.
+	Passive bool
+
+	// MemberHistoryLength is the number of recent state transitions kept for every member and reported by
+	// List.MemberLifecycle. The history is intended for debugging and is not kept when zero.
+	MemberHistoryLength int
+
+	// PartialView makes this member keep only a partial view of the cluster, HyParView style. This is intended for very
+	// large clusters, where keeping and syncing the full member list on every member is too expensive. The member list
+	// is then the small active view, which failure detection and gossip run over. A larger passive view holds
+	// replacements for failed active members. The passive view is kept random by periodic shuffles which replace the
+	// full member list sync. All members of a cluster need to use the same mode.
+	PartialView bool
+
+	// ActiveViewSize is the maximum number of members in the active view in partial view mode.
+	ActiveViewSize int
+
+	// PassiveViewSize is the maximum number of members in the passive view in partial view mode.
+	PassiveViewSize int
+
+	// EagerPushMemberCount is the number of members a broadcast is pushed to eagerly over TCP. All other members only
+	// receive a small announcement as gossip and ask for the broadcast when they did not receive it otherwise. Bigger
+	// values deliver broadcasts faster at the cost of more duplicate transfers. In partial view mode, broadcasts are
+	// pushed to the whole active view instead.
+	EagerPushMemberCount int
+
+	// BroadcastGraftTimeout is the time to wait for a broadcast which was announced to this member, before asking the
+	// announcing member for it.
+	BroadcastGraftTimeout time.Duration
+
+	// FailureDetector selects how members are declared suspect. The phi accrual failure detector is tracked for every
+	// member independent of this setting and can be inspected with List.Phi.
+	FailureDetector FailureDetector
+
+	// PhiThreshold is the suspicion level above which the phi accrual failure detector declares a member as suspect.
+	// A threshold of 1 means a 10% chance of a false suspicion, a threshold of 2 a 1% chance and so on.
+	PhiThreshold float64
+
+	// ExpectedClusterSize is the number of members including this member the operator expects in the cluster. Quorum
+	// is calculated against this size instead of the largest cluster size observed within the QuorumWindow when bigger
+	// than zero. This keeps a minority partition in minority until the partition heals, no matter how long it lasts.
+	ExpectedClusterSize int
+
+	// QuorumFraction is the fraction of the reference cluster size a partition needs to exceed to have quorum. A
+	// fraction of 0.5 requires a strict majority.
+	QuorumFraction float64
+
+	// QuorumWindow is the time the largest observed cluster size is remembered as reference for quorum. A cluster
+	// which shrinks below quorum within that window is considered partitioned, while a cluster which shrinks slower is
+	// considered scaled down.
+	QuorumWindow time.Duration
+
+	// MaxGossipQueueLength is the maximum number of gossip messages waiting for dissemination. When the gossip queue
+	// is full, the most transmitted messages are evicted, but never the refutations of this member. A member which
+	// evicted gossip requests the full member list to make up for it. Zero means that the gossip queue is unbounded.
+	MaxGossipQueueLength int
+
+	// EvictionListRequestInterval is the minimum time between two member list requests which make up for evicted
+	// gossip. It is rounded up to full protocol periods.
+	EvictionListRequestInterval time.Duration
+
+	// SendWorkerCount is the number of workers sending UDP network messages and the number of workers sending TCP
+	// network messages. The membership list only queues network messages for the send workers, which keeps slow
+	// members from blocking the protocol.
+	SendWorkerCount int
+
+	// SendQueueLength is the maximum number of UDP network messages and the maximum number of TCP network messages
+	// waiting for a send worker. Network messages are dropped when the queue is full.
+	SendQueueLength int
+
+	// SendDrainTimeout is the maximum time the send workers keep sending queued network messages on shutdown. Network
+	// messages which are still queued after that time are dropped.
+	SendDrainTimeout time.Duration
 }
 
 var DefaultConfig = Config{
-	ProtocolPeriod:            scheduler.DefaultConfig.ProtocolPeriod,
-	MaxDatagramLengthSend:     intmembership.DefaultConfig.MaxDatagramLengthSend,
-	MaxDatagramLengthReceive:  intmembership.DefaultConfig.MaxDatagramLengthSend,
-	BindAddress:               ":3000",
-	MaxSleepDuration:          scheduler.DefaultConfig.MaxSleepDuration,
-	ListRequestInterval:       scheduler.DefaultConfig.ListRequestInterval,
-	SafetyFactor:              intmembership.DefaultConfig.SafetyFactor,
-	ShutdownMemberCount:       intmembership.DefaultConfig.ShutdownMemberCount,
-	DirectPingMemberCount:     intmembership.DefaultConfig.DirectPingMemberCount,
-	MinDirectPingMemberCount:  intmembership.DefaultConfig.MinDirectPingMemberCount,
-	MaxDirectPingMemberCount:  intmembership.DefaultConfig.MaxDirectPingMemberCount,
-	IndirectPingMemberCount:   intmembership.DefaultConfig.IndirectPingMemberCount,
-	ReconnectBootstrapMembers: intmembership.DefaultConfig.ReconnectBootstrapMembers,
+	ProtocolPeriod:              scheduler.DefaultConfig.ProtocolPeriod,
+	MaxDatagramLengthSend:       intmembership.DefaultConfig.MaxDatagramLengthSend,
+	MaxDatagramLengthReceive:    intmembership.DefaultConfig.MaxDatagramLengthSend,
+	BindAddress:                 ":3000",
+	MaxSleepDuration:            scheduler.DefaultConfig.MaxSleepDuration,
+	ListRequestInterval:         scheduler.DefaultConfig.ListRequestInterval,
+	SafetyFactor:                intmembership.DefaultConfig.SafetyFactor,
+	ShutdownMemberCount:         intmembership.DefaultConfig.ShutdownMemberCount,
+	DirectPingMemberCount:       intmembership.DefaultConfig.DirectPingMemberCount,
+	MinDirectPingMemberCount:    intmembership.DefaultConfig.MinDirectPingMemberCount,
+	MaxDirectPingMemberCount:    intmembership.DefaultConfig.MaxDirectPingMemberCount,
+	IndirectPingMemberCount:     intmembership.DefaultConfig.IndirectPingMemberCount,
+	ReconnectBootstrapMembers:   intmembership.DefaultConfig.ReconnectBootstrapMembers,
+	ReconnectFaultyMemberCount:  intmembership.DefaultConfig.ReconnectFaultyMemberCount,
+	ReconnectMaxBackoff:         time.Duration(intmembership.DefaultConfig.ReconnectMaxDelay) * scheduler.DefaultConfig.ProtocolPeriod,
+	PushPullListSync:            intmembership.DefaultConfig.PushPullListSync,
+	SnapshotInterval:            time.Minute,
+	SnapshotMaxAge:              time.Hour,
+	HealthPolicy:                intmembership.DefaultConfig.HealthPolicy,
+	HealthCheckInterval:         health.DefaultConfig.Interval,
+	HealthCheckTimeout:          health.DefaultConfig.Timeout,
+	ForceRemoveListRequestCount: intmembership.DefaultConfig.ForceRemoveListRequestCount,
+	QueryTimeout:                5 * time.Second,
+	QueryHandlerTimeout:         time.Second,
+	QueryResponseBufferSize:     128,
+	MaxConcurrentQueryHandlers:  16,
+	MergeMinRevivedMembers:      intmembership.DefaultConfig.MergeMinRevivedMembers,
+	MaxAckPayloadLength:         intmembership.DefaultConfig.MaxAckPayloadLength,
+	ActiveViewSize:              intmembership.DefaultConfig.ActiveViewSize,
+	PassiveViewSize:             intmembership.DefaultConfig.PassiveViewSize,
+	EagerPushMemberCount:        intmembership.DefaultConfig.EagerPushMemberCount,
+	BroadcastGraftTimeout:       time.Duration(intmembership.DefaultConfig.BroadcastGraftTimeout) * scheduler.DefaultConfig.ProtocolPeriod,
+	FailureDetector:             intmembership.DefaultConfig.FailureDetector,
+	PhiThreshold:                intmembership.DefaultConfig.PhiThreshold,
+	QuorumFraction:              intmembership.DefaultConfig.QuorumFraction,
+	QuorumWindow:                time.Duration(intmembership.DefaultConfig.QuorumWindow) * scheduler.DefaultConfig.ProtocolPeriod,
+	MaxGossipQueueLength:        intmembership.DefaultConfig.MaxGossipQueueLength,
+	EvictionListRequestInterval: time.Duration(intmembership.DefaultConfig.EvictionListRequestInterval) * scheduler.DefaultConfig.ProtocolPeriod,
+	SendWorkerCount:             4,
+	SendQueueLength:             1024,
+	SendDrainTimeout:            time.Second,
 }
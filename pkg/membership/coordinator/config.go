@@ -0,0 +1,31 @@
+package coordinator
+
+import "time"
+
+// Config is the configuration the coordinator is using.
+type Config struct {
+	// Job is the name of the work the coordinator is picked for. It is used by StrategyRendezvous to spread different
+	// jobs across the members.
+	Job string
+
+	// Strategy selects how the coordinator is picked among the members.
+	Strategy Strategy
+
+	// StabilityDelay is the time the picked member needs to stay the same before the coordinator role moves to it.
+	// Brief flaps of members within that time do not move the role.
+	StabilityDelay time.Duration
+
+	// AcquiredCallback is the callback which is triggered when this member acquired the coordinator role. The callback
+	// executes on a goroutine of its own and without holding any lock.
+	AcquiredCallback func()
+
+	// LostCallback is the callback which is triggered when this member lost the coordinator role. The callback
+	// executes on a goroutine of its own and without holding any lock.
+	LostCallback func()
+}
+
+// DefaultConfig provides a coordinator configuration with sane defaults for most situations.
+var DefaultConfig = Config{
+	Strategy:       StrategyLowestAddress,
+	StabilityDelay: 10 * time.Second,
+}
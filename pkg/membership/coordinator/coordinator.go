@@ -0,0 +1,258 @@
+package coordinator
+
+import (
+	"sync"
+	"time"
+
+	"github.com/backbone81/membership/internal/encoding"
+	"github.com/backbone81/membership/internal/hashing"
+	"github.com/backbone81/membership/pkg/membership"
+)
+
+// Coordinator picks a single coordinator among the members.
+//
+// The coordinator learns about members through MemberAdded, MemberRemoved and MemberStateChanged. Use Options to have
+// the membership list call those automatically. This member itself is always a candidate, suspect members never are.
+// Whenever the picked member changes, the coordinator role moves to it once it stayed the same for the stability
+// delay. This also applies to the initial pick, which gives a new member time to learn about the other members before
+// claiming the role. All methods only take the lock of the coordinator and never call back into the membership list.
+// It is therefore safe to call them from within the callbacks of the membership list.
+//
+// Coordinator is safe for concurrent use by multiple goroutines.
+type Coordinator struct {
+	// mutex is responsible for serializing concurrent access to members of this struct.
+	mutex sync.Mutex
+
+	// callbackMutex serializes the callbacks, so that acquiring and losing the role are never reported out of order.
+	callbackMutex sync.Mutex
+
+	// config holds the configuration of the coordinator.
+	config Config
+
+	// self is the address of this member.
+	self membership.Address
+
+	// jobHash is the hash of the job name for rendezvous hashing.
+	jobHash uint64
+
+	// members holds all known alive and suspect members, and reports for each of them if it is suspect.
+	members map[membership.Address]bool
+
+	// coordinator is the member holding the coordinator role. The zero address when no member holds the role yet.
+	coordinator membership.Address
+
+	// candidate is the member the coordinator role moves to once the stability delay expired. Only valid while
+	// pending is true.
+	candidate membership.Address
+
+	// pending reports if the coordinator role is about to move to candidate.
+	pending bool
+
+	// generation is increased whenever the pending move is replaced or canceled. This allows a timer which fires late
+	// to detect that it is outdated.
+	generation int
+
+	// timer moves the coordinator role to the candidate once the stability delay expired.
+	timer *time.Timer
+
+	// stopped reports if Stop was called.
+	stopped bool
+}
+
+// New creates a new coordinator for the member with the given address. Provide options to customize default config.
+func New(self membership.Address, options ...Option) *Coordinator {
+	config := DefaultConfig
+	for _, option := range options {
+		option(&config)
+	}
+
+	newCoordinator := &Coordinator{
+		config:  config,
+		self:    self,
+		jobHash: hashing.String(config.Job),
+		members: make(map[membership.Address]bool),
+	}
+	newCoordinator.mutex.Lock()
+	defer newCoordinator.mutex.Unlock()
+	newCoordinator.update()
+	return newCoordinator
+}
+
+// Config returns the config of the coordinator.
+func (c *Coordinator) Config() Config {
+	return c.config
+}
+
+// Options returns the options for the membership list which keep the coordinator up to date.
+func (c *Coordinator) Options() []membership.Option {
+	return []membership.Option{
+		membership.WithMemberAddedCallback(c.MemberAdded),
+		membership.WithMemberRemovedCallback(c.MemberRemoved),
+		membership.WithMemberStateChangedCallback(c.MemberStateChanged),
+	}
+}
+
+// MemberAdded adds the member with the given address as alive.
+func (c *Coordinator) MemberAdded(address membership.Address) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, found := c.members[address]; found {
+		return
+	}
+	c.members[address] = false
+	c.update()
+}
+
+// MemberRemoved removes the member with the given address.
+func (c *Coordinator) MemberRemoved(address membership.Address) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, found := c.members[address]; !found {
+		return
+	}
+	delete(c.members, address)
+	c.update()
+}
+
+// MemberStateChanged updates the state of the member with the given address. Suspect members are never picked.
+func (c *Coordinator) MemberStateChanged(address membership.Address, state membership.MemberState) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, found := c.members[address]; !found {
+		return
+	}
+	c.members[address] = state == membership.MemberStateSuspect
+	c.update()
+}
+
+// Coordinator returns the member holding the coordinator role from the point of view of this member. Reports false
+// when no member holds the role yet.
+func (c *Coordinator) Coordinator() (membership.Address, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.coordinator, !c.coordinator.IsZero()
+}
+
+// IsCoordinator reports if this member holds the coordinator role.
+func (c *Coordinator) IsCoordinator() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.coordinator == c.self
+}
+
+// Stop stops the coordinator. The lost callback is triggered when this member held the coordinator role. The
+// coordinator does not pick any member afterward. Stop must not be called from within the acquired or lost callback.
+func (c *Coordinator) Stop() {
+	c.callbackMutex.Lock()
+	defer c.callbackMutex.Unlock()
+
+	c.mutex.Lock()
+	c.stopped = true
+	c.cancelPending()
+	wasCoordinator := c.coordinator == c.self
+	c.coordinator = encoding.ZeroAddress
+	c.mutex.Unlock()
+
+	if wasCoordinator && c.config.LostCallback != nil {
+		c.config.LostCallback()
+	}
+}
+
+// update picks the coordinator among the current members and schedules the move of the coordinator role if the picked
+// member changed. The caller must hold the lock.
+func (c *Coordinator) update() {
+	if c.stopped {
+		return
+	}
+
+	candidate := c.pick()
+	if candidate == c.coordinator {
+		// The flap is over before the stability delay expired.
+		c.cancelPending()
+		return
+	}
+	if c.pending && candidate == c.candidate {
+		// The move is already scheduled.
+		return
+	}
+
+	c.cancelPending()
+	c.candidate = candidate
+	c.pending = true
+	generation := c.generation
+	c.timer = time.AfterFunc(c.config.StabilityDelay, func() {
+		c.promote(generation)
+	})
+}
+
+// cancelPending cancels the pending move of the coordinator role. The caller must hold the lock.
+func (c *Coordinator) cancelPending() {
+	c.generation++
+	c.pending = false
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+}
+
+// promote moves the coordinator role to the candidate and triggers the callbacks, unless the move was canceled in the
+// meantime.
+func (c *Coordinator) promote(generation int) {
+	c.callbackMutex.Lock()
+	defer c.callbackMutex.Unlock()
+
+	c.mutex.Lock()
+	if c.stopped || !c.pending || generation != c.generation {
+		c.mutex.Unlock()
+		return
+	}
+	wasCoordinator := c.coordinator == c.self
+	c.coordinator = c.candidate
+	c.pending = false
+	c.timer = nil
+	isCoordinator := c.coordinator == c.self
+	c.mutex.Unlock()
+
+	if wasCoordinator && !isCoordinator && c.config.LostCallback != nil {
+		c.config.LostCallback()
+	}
+	if !wasCoordinator && isCoordinator && c.config.AcquiredCallback != nil {
+		c.config.AcquiredCallback()
+	}
+}
+
+// pick returns the member which should hold the coordinator role according to the configured strategy. This member
+// itself is always a candidate. The caller must hold the lock.
+func (c *Coordinator) pick() membership.Address {
+	best := c.self
+	for address, suspect := range c.members {
+		if suspect {
+			continue
+		}
+		if c.better(address, best) {
+			best = address
+		}
+	}
+	return best
+}
+
+// better reports if lhs should rather hold the coordinator role than rhs.
+func (c *Coordinator) better(lhs membership.Address, rhs membership.Address) bool {
+	switch c.config.Strategy {
+	case StrategyRendezvous:
+		lhsScore := hashing.Combine(c.jobHash, hashing.Address(lhs))
+		rhsScore := hashing.Combine(c.jobHash, hashing.Address(rhs))
+		if lhsScore != rhsScore {
+			return lhsScore > rhsScore
+		}
+		// Fall back to the lowest address for the unlikely case of a hash collision, to stay deterministic.
+		return encoding.CompareAddress(lhs, rhs) < 0
+	default:
+		return encoding.CompareAddress(lhs, rhs) < 0
+	}
+}
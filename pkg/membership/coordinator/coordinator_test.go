@@ -0,0 +1,218 @@
+package coordinator_test
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/pkg/membership"
+	"github.com/backbone81/membership/pkg/membership/coordinator"
+)
+
+var _ = Describe("Coordinator", func() {
+	// events records the acquired and lost callbacks of a coordinator.
+	type events struct {
+		acquired int
+		lost     int
+	}
+
+	// newCoordinator creates a coordinator for self which records its callbacks in the given events.
+	newCoordinator := func(self membership.Address, recorded *events, options ...coordinator.Option) *coordinator.Coordinator {
+		return coordinator.New(self, append([]coordinator.Option{
+			coordinator.WithStabilityDelay(10 * time.Second),
+			coordinator.WithAcquiredCallback(func() { recorded.acquired++ }),
+			coordinator.WithLostCallback(func() { recorded.lost++ }),
+		}, options...)...)
+	}
+
+	It("should acquire the role after the stability delay", func() {
+		synctest.Test(testingT, func(t *testing.T) {
+			defer GinkgoRecover()
+
+			var recorded events
+			myCoordinator := newCoordinator(TestAddress, &recorded)
+			myCoordinator.MemberAdded(TestAddress2)
+			Expect(myCoordinator.IsCoordinator()).To(BeFalse())
+			_, ok := myCoordinator.Coordinator()
+			Expect(ok).To(BeFalse())
+
+			time.Sleep(10 * time.Second)
+			synctest.Wait()
+			Expect(myCoordinator.IsCoordinator()).To(BeTrue())
+			Expect(recorded).To(Equal(events{acquired: 1}))
+		})
+	})
+
+	It("should pick the lowest address", func() {
+		synctest.Test(testingT, func(t *testing.T) {
+			defer GinkgoRecover()
+
+			var recorded events
+			myCoordinator := newCoordinator(TestAddress2, &recorded)
+			myCoordinator.MemberAdded(TestAddress3)
+			myCoordinator.MemberAdded(TestAddress)
+
+			time.Sleep(10 * time.Second)
+			synctest.Wait()
+			coordinatorAddress, ok := myCoordinator.Coordinator()
+			Expect(ok).To(BeTrue())
+			Expect(coordinatorAddress).To(Equal(TestAddress))
+			Expect(recorded).To(Equal(events{}))
+		})
+	})
+
+	It("should not move the role on brief flaps", func() {
+		synctest.Test(testingT, func(t *testing.T) {
+			defer GinkgoRecover()
+
+			var recorded events
+			myCoordinator := newCoordinator(TestAddress2, &recorded)
+			myCoordinator.MemberAdded(TestAddress)
+			time.Sleep(10 * time.Second)
+			synctest.Wait()
+
+			myCoordinator.MemberStateChanged(TestAddress, membership.MemberStateSuspect)
+			time.Sleep(5 * time.Second)
+			myCoordinator.MemberStateChanged(TestAddress, membership.MemberStateAlive)
+			time.Sleep(10 * time.Second)
+			synctest.Wait()
+			Expect(MustCoordinator(myCoordinator.Coordinator())).To(Equal(TestAddress))
+			Expect(recorded).To(Equal(events{}))
+		})
+	})
+
+	It("should take over the role when the coordinator is gone", func() {
+		synctest.Test(testingT, func(t *testing.T) {
+			defer GinkgoRecover()
+
+			var recorded events
+			myCoordinator := newCoordinator(TestAddress2, &recorded)
+			myCoordinator.MemberAdded(TestAddress)
+			time.Sleep(10 * time.Second)
+			synctest.Wait()
+
+			myCoordinator.MemberRemoved(TestAddress)
+			time.Sleep(9 * time.Second)
+			synctest.Wait()
+			Expect(myCoordinator.IsCoordinator()).To(BeFalse())
+			time.Sleep(time.Second)
+			synctest.Wait()
+			Expect(myCoordinator.IsCoordinator()).To(BeTrue())
+			Expect(recorded).To(Equal(events{acquired: 1}))
+		})
+	})
+
+	It("should lose the role to a lower address", func() {
+		synctest.Test(testingT, func(t *testing.T) {
+			defer GinkgoRecover()
+
+			var recorded events
+			myCoordinator := newCoordinator(TestAddress2, &recorded)
+			time.Sleep(10 * time.Second)
+			synctest.Wait()
+			Expect(myCoordinator.IsCoordinator()).To(BeTrue())
+
+			myCoordinator.MemberAdded(TestAddress)
+			time.Sleep(10 * time.Second)
+			synctest.Wait()
+			Expect(myCoordinator.IsCoordinator()).To(BeFalse())
+			Expect(recorded).To(Equal(events{acquired: 1, lost: 1}))
+		})
+	})
+
+	It("should lose the role when stopped", func() {
+		synctest.Test(testingT, func(t *testing.T) {
+			defer GinkgoRecover()
+
+			var recorded events
+			myCoordinator := newCoordinator(TestAddress, &recorded)
+			time.Sleep(10 * time.Second)
+			synctest.Wait()
+
+			myCoordinator.Stop()
+			Expect(myCoordinator.IsCoordinator()).To(BeFalse())
+			Expect(recorded).To(Equal(events{acquired: 1, lost: 1}))
+
+			myCoordinator.MemberAdded(TestAddress2)
+			time.Sleep(10 * time.Second)
+			synctest.Wait()
+			Expect(recorded).To(Equal(events{acquired: 1, lost: 1}))
+		})
+	})
+
+	It("should spread jobs with rendezvous hashing", func() {
+		synctest.Test(testingT, func(t *testing.T) {
+			defer GinkgoRecover()
+
+			picked := make(map[membership.Address]int)
+			for i := range 30 {
+				job := fmt.Sprintf("job-%d", i)
+				var coordinatorAddresses []membership.Address
+				for _, self := range []membership.Address{TestAddress, TestAddress2, TestAddress3} {
+					var recorded events
+					myCoordinator := newCoordinator(self, &recorded,
+						coordinator.WithStrategy(coordinator.StrategyRendezvous),
+						coordinator.WithJob(job),
+					)
+					for _, member := range []membership.Address{TestAddress, TestAddress2, TestAddress3} {
+						if member != self {
+							myCoordinator.MemberAdded(member)
+						}
+					}
+					time.Sleep(10 * time.Second)
+					synctest.Wait()
+					coordinatorAddresses = append(coordinatorAddresses, MustCoordinator(myCoordinator.Coordinator()))
+					myCoordinator.Stop()
+				}
+
+				// All members agree on the coordinator of the job.
+				Expect(coordinatorAddresses[1]).To(Equal(coordinatorAddresses[0]))
+				Expect(coordinatorAddresses[2]).To(Equal(coordinatorAddresses[0]))
+				picked[coordinatorAddresses[0]]++
+			}
+			Expect(picked).To(HaveLen(3))
+		})
+	})
+
+	It("should only move the jobs of a removed member with rendezvous hashing", func() {
+		synctest.Test(testingT, func(t *testing.T) {
+			defer GinkgoRecover()
+
+			self := membership.NewAddress(net.IPv4(10, 0, 0, 1), 1024)
+			other := membership.NewAddress(net.IPv4(10, 0, 0, 2), 1024)
+			for i := range 30 {
+				var recorded events
+				myCoordinator := newCoordinator(self, &recorded,
+					coordinator.WithStrategy(coordinator.StrategyRendezvous),
+					coordinator.WithJob(fmt.Sprintf("job-%d", i)),
+				)
+				myCoordinator.MemberAdded(other)
+				myCoordinator.MemberAdded(TestAddress)
+				time.Sleep(10 * time.Second)
+				synctest.Wait()
+				before := MustCoordinator(myCoordinator.Coordinator())
+
+				myCoordinator.MemberRemoved(other)
+				time.Sleep(10 * time.Second)
+				synctest.Wait()
+				after := MustCoordinator(myCoordinator.Coordinator())
+				if before != other {
+					Expect(after).To(Equal(before))
+				}
+				myCoordinator.Stop()
+			}
+		})
+	})
+})
+
+// MustCoordinator returns the coordinator and fails the test when there is none.
+func MustCoordinator(address membership.Address, ok bool) membership.Address {
+	GinkgoHelper()
+	Expect(ok).To(BeTrue())
+	return address
+}
@@ -0,0 +1,10 @@
+// Package coordinator picks a single coordinator among the members for work which should run on one member only, like
+// cron-style jobs. Every member computes the coordinator deterministically from its own view of the members, either
+// as the member with the lowest address or by rendezvous hashing of a job name. A stability delay keeps brief flaps of
+// members from moving the coordinator role back and forth.
+//
+// The guarantees are weak and eventually consistent. As every member decides based on its own view, and views differ
+// while membership changes propagate through the cluster, two members might hold the coordinator role at the same time
+// for a short while, or no member might hold it at all. The same is true during network partitions, where every
+// partition elects its own coordinator. Use a consensus system instead, when the work must never run twice.
+package coordinator
@@ -0,0 +1,41 @@
+package coordinator
+
+import "time"
+
+// Option is the function signature for all coordinator options to implement.
+type Option func(config *Config)
+
+// WithJob sets the name of the work the coordinator is picked for.
+func WithJob(job string) Option {
+	return func(config *Config) {
+		config.Job = job
+	}
+}
+
+// WithStrategy sets how the coordinator is picked among the members.
+func WithStrategy(strategy Strategy) Option {
+	return func(config *Config) {
+		config.Strategy = strategy
+	}
+}
+
+// WithStabilityDelay sets the time the picked member needs to stay the same before the coordinator role moves.
+func WithStabilityDelay(delay time.Duration) Option {
+	return func(config *Config) {
+		config.StabilityDelay = max(0, delay)
+	}
+}
+
+// WithAcquiredCallback sets the callback which is triggered when this member acquired the coordinator role.
+func WithAcquiredCallback(acquiredCallback func()) Option {
+	return func(config *Config) {
+		config.AcquiredCallback = acquiredCallback
+	}
+}
+
+// WithLostCallback sets the callback which is triggered when this member lost the coordinator role.
+func WithLostCallback(lostCallback func()) Option {
+	return func(config *Config) {
+		config.LostCallback = lostCallback
+	}
+}
@@ -0,0 +1,25 @@
+package coordinator
+
+// Strategy selects how the coordinator is picked among the members.
+type Strategy int
+
+const (
+	// StrategyLowestAddress picks the member with the lowest address. All jobs end up on the same member.
+	StrategyLowestAddress Strategy = iota
+
+	// StrategyRendezvous picks the member with the highest rendezvous hash of the job name and the member address.
+	// Different jobs are spread across the members, and only the jobs of a member which leaves move to other members.
+	StrategyRendezvous
+)
+
+// String returns a human-readable representation of the strategy.
+func (s Strategy) String() string {
+	switch s {
+	case StrategyLowestAddress:
+		return "lowest-address"
+	case StrategyRendezvous:
+		return "rendezvous"
+	default:
+		return "unknown"
+	}
+}
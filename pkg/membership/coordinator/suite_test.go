@@ -0,0 +1,28 @@
+package coordinator_test
+
+import (
+	"net"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/pkg/membership"
+)
+
+// As Ginkgo does not yet support testing/synctest, we need to capture t during test suite initialization and make it
+// available to our Ginkgo tests. Keep an eye on https://github.com/onsi/ginkgo/issues/1601 and remove this hack
+// when Ginkgo provides support for it.
+var testingT *testing.T
+
+var (
+	TestAddress  = membership.NewAddress(net.IPv4(1, 2, 3, 4), 1024)
+	TestAddress2 = membership.NewAddress(net.IPv4(11, 12, 13, 14), 1024)
+	TestAddress3 = membership.NewAddress(net.IPv4(21, 22, 23, 24), 1024)
+)
+
+func TestSuite(t *testing.T) {
+	testingT = t
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Coordinator Suite")
+}
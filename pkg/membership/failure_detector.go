@@ -0,0 +1,20 @@
+package membership
+
+import (
+	intmembership "github.com/backbone81/membership/internal/membership"
+)
+
+// FailureDetector selects how members are declared suspect.
+type FailureDetector = intmembership.FailureDetector
+
+const (
+	// FailureDetectorSWIM declares a member suspect when it did not answer a direct ping and all indirect pings within
+	// a protocol period.
+	FailureDetectorSWIM = intmembership.FailureDetectorSWIM
+
+	// FailureDetectorPhiAccrual declares a member suspect when it did not answer a direct ping and the time since this
+	// member last heard from it is unlikely given the inter-arrival times observed before. This adapts to members which
+	// are slow or far away and reduces false suspicions on unreliable networks. Until enough inter-arrival times were
+	// observed for a member, it falls back to FailureDetectorSWIM.
+	FailureDetectorPhiAccrual = intmembership.FailureDetectorPhiAccrual
+)
@@ -0,0 +1,27 @@
+package membership
+
+import (
+	inthealth "github.com/backbone81/membership/internal/health"
+	intmembership "github.com/backbone81/membership/internal/membership"
+)
+
+// HealthCheck is a function which verifies that some part of the application is working. It returns an error when the
+// application is broken. The context is canceled when the check exceeds the configured timeout.
+type HealthCheck = inthealth.Check
+
+// HealthPolicy describes how this member announces itself to the other members while its application is unhealthy.
+type HealthPolicy = intmembership.HealthPolicy
+
+const (
+	// HealthPolicyStopAcking stops acknowledging direct pings. The other members detect this member as failed through
+	// the normal failure detection.
+	HealthPolicyStopAcking = intmembership.HealthPolicyStopAcking
+
+	// HealthPolicyDegraded keeps acknowledging direct pings, but gossips this member as suspect. The other members see
+	// this member as suspect right away and declare it faulty after the suspicion timeout, unless it recovers before.
+	HealthPolicyDegraded = intmembership.HealthPolicyDegraded
+
+	// HealthPolicyLeave stops acknowledging direct pings and broadcasts a graceful leave like during shutdown. The
+	// other members remove this member right away.
+	HealthPolicyLeave = intmembership.HealthPolicyLeave
+)
@@ -0,0 +1,36 @@
+package membership
+
+import "github.com/backbone81/membership/internal/incarnation"
+
+// IncarnationStore persists the incarnation number of this member across restarts. Every new incarnation number is
+// saved before it is gossiped, and a restarted member resumes with the next bigger one.
+type IncarnationStore = incarnation.Store
+
+// NewIncarnationFile creates an incarnation store which persists the incarnation number to a file at the given path. The
+// directory of the path must exist.
+var NewIncarnationFile = incarnation.NewFile
+
+// resumeIncarnationNumber returns the incarnation number to start with. When a previous incarnation number was
+// persisted, we resume with the next bigger incarnation number. Otherwise, we start with 0. In both cases the
+// incarnation number is persisted before it is used, so that the next restart resumes with a bigger one. Returns 0 when
+// no store is given.
+func resumeIncarnationNumber(store IncarnationStore) (uint16, error) {
+	if store == nil {
+		return 0, nil
+	}
+
+	persisted, found, err := store.Load()
+	if err != nil {
+		return 0, err
+	}
+
+	var incarnationNumber uint16
+	if found {
+		// Note that the incarnation number is expected to wrap around, which is dealt with by the membership list.
+		incarnationNumber = persisted + 1
+	}
+	if err := store.Save(incarnationNumber); err != nil {
+		return 0, err
+	}
+	return incarnationNumber, nil
+}
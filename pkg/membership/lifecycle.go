@@ -0,0 +1,12 @@
+package membership
+
+import (
+	intmembership "github.com/backbone81/membership/internal/membership"
+)
+
+// MemberLifecycle provides timing information about a member which is alive or suspect, like when it joined, when its
+// state changed and when we heard from it the last time.
+type MemberLifecycle = intmembership.MemberLifecycle
+
+// StateTransition describes a single change of the state of a member.
+type StateTransition = intmembership.StateTransition
@@ -1,9 +1,16 @@
 package membership
 
 import (
+	"context"
 	"errors"
+	"math/rand"
+	"slices"
+	"sync/atomic"
+	"time"
 
+	"github.com/backbone81/membership/internal/admission"
 	"github.com/backbone81/membership/internal/encoding"
+	inthealth "github.com/backbone81/membership/internal/health"
 	intmembership "github.com/backbone81/membership/internal/membership"
 	"github.com/backbone81/membership/internal/roundtriptime"
 	intscheduler "github.com/backbone81/membership/internal/scheduler"
@@ -13,8 +20,23 @@ import (
 type List struct {
 	list               *intmembership.List
 	scheduler          *intscheduler.Scheduler
+	udpClientTransport *inttransport.Async
+	tcpClientTransport *inttransport.Async
 	udpServerTransport *inttransport.UDPServer
 	tcpServerTransport *inttransport.TCPServer
+	healthChecker      *inthealth.Checker
+	queryManager       *queryManager
+	queryTimeout       time.Duration
+
+	// nextBroadcastID provides the id for the next broadcast. It starts at a random value to make collisions with
+	// broadcasts of a previous run of this member unlikely.
+	nextBroadcastID atomic.Uint32
+}
+
+// component is a part of the list which runs in the background between startup and shutdown.
+type component interface {
+	Startup() error
+	Shutdown() error
 }
 
 //nolint:funlen
@@ -35,14 +57,34 @@ func NewList(options ...Option) (*List, error) {
 		roundtriptime.WithDefault(defaultRTT),
 		roundtriptime.WithMaximum(maxRTT),
 	)
-	udpClientTransport, err := inttransport.NewUDPClient(config.MaxDatagramLengthSend, config.EncryptionKeys[0])
+	incarnationNumber, err := resumeIncarnationNumber(config.IncarnationStore)
 	if err != nil {
 		return nil, err
 	}
-	tcpClientTransport, err := inttransport.NewTCPClient(config.EncryptionKeys[0])
+	allowedNetworks, err := admission.ParseRules(config.AllowedNetworks)
 	if err != nil {
 		return nil, err
 	}
+	deniedNetworks, err := admission.ParseRules(config.DeniedNetworks)
+	if err != nil {
+		return nil, err
+	}
+	metrics, err := newListMetrics(config.MetricsRegisterer, config.MetricsConstLabels)
+	if err != nil {
+		return nil, err
+	}
+	joinCandidates := joinCandidatesFromSnapshot(config.Logger, config.SnapshotPath, config.SnapshotMaxAge, config.AdvertisedAddress)
+	udpClientTransport, tcpClientTransport, err := newClientTransports(config, metrics.transport)
+	if err != nil {
+		return nil, err
+	}
+	queryManager := newQueryManager(
+		config.Logger,
+		config.AdvertisedAddress,
+		config.QueryHandlerTimeout,
+		config.QueryResponseBufferSize,
+		config.MaxConcurrentQueryHandlers,
+	)
 	list := intmembership.NewList(
 		intmembership.WithLogger(config.Logger),
 		intmembership.WithBootstrapMembers(config.BootstrapMembers),
@@ -52,18 +94,59 @@ func NewList(options ...Option) (*List, error) {
 		intmembership.WithTCPClient(tcpClientTransport),
 		intmembership.WithMemberAddedCallback(config.MemberAddedCallback),
 		intmembership.WithMemberRemovedCallback(config.MemberRemovedCallback),
+		intmembership.WithMemberStateChangedCallback(config.MemberStateChangedCallback),
+		intmembership.WithMemberRoundTripTimeCallback(config.MemberRoundTripTimeCallback),
+		intmembership.WithQueryCallback(queryManager.OnQuery),
+		intmembership.WithQueryAckCallback(queryManager.OnQueryAck),
+		intmembership.WithQueryResponseCallback(queryManager.OnQueryResponse),
 		intmembership.WithSafetyFactor(config.SafetyFactor),
 		intmembership.WithShutdownMemberCount(config.ShutdownMemberCount),
 		intmembership.WithDirectPingMemberCount(config.DirectPingMemberCount),
 		intmembership.WithIndirectPingMemberCount(config.IndirectPingMemberCount),
 		intmembership.WithRoundTripTimeTracker(rttTracker),
 		intmembership.WithReconnectBootstrapMembers(config.ReconnectBootstrapMembers),
+		intmembership.WithReconnectFaultyMemberCount(config.ReconnectFaultyMemberCount),
+		intmembership.WithReconnectMaxDelay(protocolPeriods(config.ReconnectMaxBackoff, config.ProtocolPeriod)),
+		intmembership.WithPushPullListSync(config.PushPullListSync),
+		intmembership.WithIncarnationNumber(incarnationNumber),
+		intmembership.WithIncarnationStore(config.IncarnationStore),
+		intmembership.WithJoinCandidates(joinCandidates),
+		intmembership.WithSnapshotPath(config.SnapshotPath),
+		intmembership.WithSnapshotInterval(protocolPeriods(config.SnapshotInterval, config.ProtocolPeriod)),
+		intmembership.WithHealthPolicy(config.HealthPolicy),
+		intmembership.WithForceRemoveListRequestCount(config.ForceRemoveListRequestCount),
+		intmembership.WithAdmissionCallback(config.AdmissionCallback),
+		intmembership.WithAllowedNetworks(allowedNetworks),
+		intmembership.WithDeniedNetworks(deniedNetworks),
+		intmembership.WithMergeCallback(config.MergeCallback),
+		intmembership.WithAckPayloadCallback(config.AckPayloadCallback),
+		intmembership.WithMaxAckPayloadLength(config.MaxAckPayloadLength),
+		intmembership.WithMergeMinRevivedMembers(config.MergeMinRevivedMembers),
+		intmembership.WithMemberHistoryLength(config.MemberHistoryLength),
+		intmembership.WithPassive(config.Passive),
+		intmembership.WithPartialView(config.PartialView),
+		intmembership.WithActiveViewSize(config.ActiveViewSize),
+		intmembership.WithPassiveViewSize(config.PassiveViewSize),
+		intmembership.WithBroadcastCallback(config.BroadcastCallback),
+		intmembership.WithMinorityPartitionCallback(config.MinorityPartitionCallback),
+		intmembership.WithEagerPushMemberCount(config.EagerPushMemberCount),
+		intmembership.WithBroadcastGraftTimeout(protocolPeriods(config.BroadcastGraftTimeout, config.ProtocolPeriod)),
+		intmembership.WithFailureDetector(config.FailureDetector),
+		intmembership.WithPhiThreshold(config.PhiThreshold),
+		intmembership.WithExpectedClusterSize(config.ExpectedClusterSize),
+		intmembership.WithQuorumFraction(config.QuorumFraction),
+		intmembership.WithQuorumWindow(protocolPeriods(config.QuorumWindow, config.ProtocolPeriod)),
+		intmembership.WithMaxGossipQueueLength(config.MaxGossipQueueLength),
+		intmembership.WithEvictionListRequestInterval(protocolPeriods(config.EvictionListRequestInterval, config.ProtocolPeriod)),
+		intmembership.WithObserver(config.Observer),
+		intmembership.WithMetrics(metrics.list),
 	)
-	udpServerTransport, err := inttransport.NewUDPServer(config.Logger, list, config.BindAddress, config.MaxDatagramLengthReceive, config.EncryptionKeys)
+	queryManager.list = list
+	udpServerTransport, err := inttransport.NewUDPServer(config.Logger, list, config.Observer, metrics.transport, config.BindAddress, config.MaxDatagramLengthReceive, config.EncryptionKeys)
 	if err != nil {
 		return nil, err
 	}
-	tcpServerTransport, err := inttransport.NewTCPServer(config.Logger, list, config.BindAddress, config.EncryptionKeys)
+	tcpServerTransport, err := inttransport.NewTCPServer(config.Logger, list, config.Observer, metrics.transport, config.BindAddress, config.EncryptionKeys)
 	if err != nil {
 		return nil, err
 	}
@@ -74,44 +157,113 @@ func NewList(options ...Option) (*List, error) {
 		intscheduler.WithMaxSleepDuration(config.MaxSleepDuration),
 		intscheduler.WithListRequestInterval(config.ListRequestInterval),
 		intscheduler.WithRoundTripTimeTracker(rttTracker),
+		intscheduler.WithObserver(config.Observer),
+		intscheduler.WithMetrics(metrics.scheduler),
+	)
+	healthChecker := inthealth.New(
+		list,
+		inthealth.WithLogger(config.Logger),
+		inthealth.WithInterval(config.HealthCheckInterval),
+		inthealth.WithTimeout(config.HealthCheckTimeout),
+		inthealth.WithMetrics(metrics.health),
 	)
 
 	newList := List{
 		list:               list,
+		udpClientTransport: udpClientTransport,
+		tcpClientTransport: tcpClientTransport,
 		udpServerTransport: udpServerTransport,
 		tcpServerTransport: tcpServerTransport,
 		scheduler:          scheduler,
+		healthChecker:      healthChecker,
+		queryManager:       queryManager,
+		queryTimeout:       config.QueryTimeout,
 	}
+	newList.nextBroadcastID.Store(rand.Uint32())
 	return &newList, nil
 }
 
+// Startup starts the network transports, the protocol and the health checks. When any of them fails to start, the
+// ones started before are shut down again.
 func (l *List) Startup() error {
-	if err := l.udpServerTransport.Startup(); err != nil {
-		return err
+	components := []component{
+		l.udpClientTransport,
+		l.tcpClientTransport,
+		l.udpServerTransport,
+		l.tcpServerTransport,
+		l.scheduler,
+		l.healthChecker,
 	}
-	if err := l.tcpServerTransport.Startup(); err != nil {
-		return err
-	}
-	if err := l.scheduler.Startup(); err != nil {
-		return err
+	for i, startupComponent := range components {
+		if err := startupComponent.Startup(); err != nil {
+			// We shut down what was started already in reverse order. Otherwise, the background tasks of those
+			// components would keep running after the failed startup.
+			for _, shutdownComponent := range slices.Backward(components[:i]) {
+				err = errors.Join(err, shutdownComponent.Shutdown())
+			}
+			return err
+		}
 	}
 	return nil
 }
 
 func (l *List) Shutdown() error {
-	if err := l.scheduler.Shutdown(); err != nil {
-		return err
-	}
-	if err := l.tcpServerTransport.Shutdown(); err != nil {
-		return err
-	}
-	if err := l.udpServerTransport.Shutdown(); err != nil {
-		return err
-	}
-	if err := l.list.BroadcastShutdown(); err != nil {
-		return err
+	// Every step is taken, even when an earlier step failed. Otherwise, the background tasks of the remaining
+	// components would keep running.
+	var joinedErr error
+	joinedErr = errors.Join(joinedErr, l.healthChecker.Shutdown())
+	joinedErr = errors.Join(joinedErr, l.scheduler.Shutdown())
+	joinedErr = errors.Join(joinedErr, l.tcpServerTransport.Shutdown())
+	joinedErr = errors.Join(joinedErr, l.udpServerTransport.Shutdown())
+	joinedErr = errors.Join(joinedErr, l.list.WriteSnapshot())
+	joinedErr = errors.Join(joinedErr, l.list.BroadcastShutdown())
+
+	// The client transports are shut down last, because they still need to send what the membership list queued
+	// before, including the broadcast of our shutdown.
+	joinedErr = errors.Join(joinedErr, l.tcpClientTransport.Shutdown())
+	joinedErr = errors.Join(joinedErr, l.udpClientTransport.Shutdown())
+	return joinedErr
+}
+
+// newClientTransports creates the transports the membership list sends UDP and TCP network messages with. Every send
+// worker gets its own client, as the clients are not safe for concurrent use.
+func newClientTransports(config Config, metrics *inttransport.Metrics) (*inttransport.Async, *inttransport.Async, error) {
+	workerCount := max(1, config.SendWorkerCount)
+	udpClients := make([]inttransport.Transport, 0, workerCount)
+	tcpClients := make([]inttransport.Transport, 0, workerCount)
+	for range workerCount {
+		udpClient, err := inttransport.NewUDPClient(config.MaxDatagramLengthSend, config.EncryptionKeys[0], metrics)
+		if err != nil {
+			return nil, nil, err
+		}
+		udpClients = append(udpClients, udpClient)
+
+		tcpClient, err := inttransport.NewTCPClient(config.EncryptionKeys[0], metrics)
+		if err != nil {
+			return nil, nil, err
+		}
+		tcpClients = append(tcpClients, tcpClient)
 	}
-	return nil
+	queueLength := max(1, config.SendQueueLength)
+	udpClientTransport := inttransport.NewAsync(
+		config.Logger,
+		"udp_client",
+		udpClients,
+		queueLength,
+		config.MaxDatagramLengthSend,
+		config.SendDrainTimeout,
+		metrics,
+	)
+	tcpClientTransport := inttransport.NewAsync(
+		config.Logger,
+		"tcp_client",
+		tcpClients,
+		queueLength,
+		config.MaxDatagramLengthSend,
+		config.SendDrainTimeout,
+		metrics,
+	)
+	return udpClientTransport, tcpClientTransport, nil
 }
 
 func (l *List) Len() int {
@@ -121,3 +273,87 @@ func (l *List) Len() int {
 func (l *List) ForEach(fn func(encoding.Address) bool) {
 	l.list.ForEach(fn)
 }
+
+// View returns the latest view of the members. A new view is published after every change to the members. Reading the
+// view does not block the membership list.
+func (l *List) View() *View {
+	return l.list.View()
+}
+
+// MemberLifecycle returns the timing information about the member with the given address. Reports false if the
+// address is not an alive or suspect member.
+func (l *List) MemberLifecycle(address Address) (MemberLifecycle, bool) {
+	return l.list.MemberLifecycle(address)
+}
+
+// AckPayload returns the application payload the member with the given address attached to the last ack we received
+// from it. The payload is empty when no ack was received yet or when the member does not attach any payload. Reports
+// false if the address is not an alive or suspect member.
+func (l *List) AckPayload(address Address) ([]byte, bool) {
+	return l.list.AckPayload(address)
+}
+
+// Phi returns the suspicion level of the phi accrual failure detector for the member with the given address. Reports
+// false if the address is not an alive or suspect member or if not enough messages were received from that member yet.
+func (l *List) Phi(address Address) (float64, bool) {
+	return l.list.Phi(address)
+}
+
+// MinorityPartition reports if this member is in a minority partition which lost quorum. A consensus layer can use
+// this to refuse writes or elections. This member is never in a minority partition when running in partial view mode.
+func (l *List) MinorityPartition() bool {
+	return l.list.MinorityPartition()
+}
+
+// PassiveView returns the addresses of the passive view. The passive view is always empty when not running in partial
+// view mode.
+func (l *List) PassiveView() []Address {
+	return l.list.PassiveView()
+}
+
+// ForceRemove removes the member with the given address from the whole cluster right away, instead of waiting for
+// the failure detection. Use this for members which are known to be permanently gone. The member is kept as a
+// tombstone longer than a normal faulty member and is not reconnected as a bootstrap member during that time. A member
+// which is still alive refutes the removal and rejoins.
+func (l *List) ForceRemove(address Address) error {
+	return l.list.ForceRemove(address)
+}
+
+// RegisterHealthCheck adds the given health check which is run on a schedule. When any health check fails, this member
+// announces itself according to the configured health policy. Registering a health check with a name which already
+// exists replaces the existing health check.
+func (l *List) RegisterHealthCheck(name string, check HealthCheck) {
+	l.healthChecker.Register(name, check)
+}
+
+// Healthy reports if all health checks succeeded during the last run.
+func (l *List) Healthy() bool {
+	return l.list.Healthy()
+}
+
+// RegisterQueryHandler adds the given handler which answers queries with the given name. Registering a query handler
+// with a name which already exists replaces the existing query handler.
+func (l *List) RegisterQueryHandler(name string, handler QueryHandler) {
+	l.queryManager.Register(name, handler)
+}
+
+// Query asks all members matching the filter to answer the query with the given name. An empty filter matches all
+// members, including this member. The query is disseminated as gossip and every member answers directly to this
+// member. The responses are collected until the context is done. When the context has no deadline, the configured
+// query timeout applies.
+func (l *List) Query(ctx context.Context, name string, payload []byte, filter []Address) (*QueryResult, error) {
+	if _, found := ctx.Deadline(); !found {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.queryTimeout)
+		context.AfterFunc(ctx, cancel)
+	}
+	return l.queryManager.Query(ctx, name, payload, filter)
+}
+
+// Broadcast disseminates the payload reliably to all members of the cluster. In contrast to queries, the payload is
+// transferred over TCP along a broadcast tree and may exceed the maximum datagram length, which makes broadcasts
+// suitable for bigger payloads like configuration or schema updates. Every other member receives the payload through
+// the broadcast callback exactly once. The broadcast is not delivered to this member itself.
+func (l *List) Broadcast(payload []byte) error {
+	return l.list.Broadcast(l.nextBroadcastID.Add(1), payload)
+}
@@ -0,0 +1,12 @@
+package membership
+
+import "github.com/backbone81/membership/internal/encoding"
+
+// MemberState describes the state a member is in.
+type MemberState = encoding.MemberState
+
+const (
+	MemberStateAlive   = encoding.MemberStateAlive
+	MemberStateSuspect = encoding.MemberStateSuspect
+	MemberStateFaulty  = encoding.MemberStateFaulty
+)
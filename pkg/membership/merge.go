@@ -0,0 +1,10 @@
+package membership
+
+import (
+	intmembership "github.com/backbone81/membership/internal/membership"
+)
+
+// MergeEvent describes a full member list sync which merged two partitions of the cluster. It carries the member the
+// list was synced with, the members which were added by the sync, how many of them were considered faulty before and
+// how big our own partition was before the merge.
+type MergeEvent = intmembership.MergeEvent
@@ -3,25 +3,43 @@ package membership
 import (
 	"github.com/prometheus/client_golang/prometheus"
 
-	intgossip "github.com/backbone81/membership/internal/gossip"
+	inthealth "github.com/backbone81/membership/internal/health"
 	intmembership "github.com/backbone81/membership/internal/membership"
 	intscheduler "github.com/backbone81/membership/internal/scheduler"
 	inttransport "github.com/backbone81/membership/internal/transport"
 )
 
-// RegisterMetrics registers all metrics collectors with the given prometheus registerer.
-func RegisterMetrics(registerer prometheus.Registerer) error {
-	if err := intmembership.RegisterMetrics(registerer); err != nil {
-		return err
+// listMetrics holds the metrics collectors of all parts of a single membership list.
+type listMetrics struct {
+	list      *intmembership.Metrics
+	scheduler *intscheduler.Metrics
+	transport *inttransport.Metrics
+	health    *inthealth.Metrics
+}
+
+// newListMetrics creates the metrics collectors for a single membership list with the given const labels. The metrics
+// are registered with the given registerer, unless it is nil.
+func newListMetrics(registerer prometheus.Registerer, constLabels prometheus.Labels) (listMetrics, error) {
+	metrics := listMetrics{
+		list:      intmembership.NewMetrics(constLabels),
+		scheduler: intscheduler.NewMetrics(constLabels),
+		transport: inttransport.NewMetrics(constLabels),
+		health:    inthealth.NewMetrics(constLabels),
+	}
+	if registerer == nil {
+		return metrics, nil
+	}
+	if err := metrics.list.Register(registerer); err != nil {
+		return listMetrics{}, err
 	}
-	if err := intgossip.RegisterMetrics(registerer); err != nil {
-		return err
+	if err := metrics.scheduler.Register(registerer); err != nil {
+		return listMetrics{}, err
 	}
-	if err := intscheduler.RegisterMetrics(registerer); err != nil {
-		return err
+	if err := metrics.transport.Register(registerer); err != nil {
+		return listMetrics{}, err
 	}
-	if err := inttransport.RegisterMetrics(registerer); err != nil {
-		return err
+	if err := metrics.health.Register(registerer); err != nil {
+		return listMetrics{}, err
 	}
-	return nil
+	return metrics, nil
 }
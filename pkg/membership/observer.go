@@ -0,0 +1,15 @@
+package membership
+
+import (
+	intobserver "github.com/backbone81/membership/internal/observer"
+)
+
+// Observer is notified about protocol activity like pings, suspicions, gossip and decryption failures. It allows for
+// wiring in tracing or custom telemetry. Most methods execute under the lock of the membership list, so the same
+// restrictions as for Config.MemberAddedCallback apply. Methods might be called from multiple goroutines at the same
+// time.
+type Observer = intobserver.Observer
+
+// NopObserver is an observer which ignores all events. Embed it in your own observer to only implement the methods you
+// are interested in.
+type NopObserver = intobserver.Nop
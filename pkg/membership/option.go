@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/backbone81/membership/internal/encoding"
 	"github.com/backbone81/membership/internal/encryption"
@@ -63,13 +64,25 @@ func WithBindAddress(bindAddress string) Option {
 
 func WithMemberAddedCallback(memberAddedCallback func(address encoding.Address)) Option {
 	return func(config *Config) {
-		config.MemberAddedCallback = memberAddedCallback
+		config.MemberAddedCallback = chainCallback(config.MemberAddedCallback, memberAddedCallback)
 	}
 }
 
 func WithMemberRemovedCallback(memberRemovedCallback func(address encoding.Address)) Option {
 	return func(config *Config) {
-		config.MemberRemovedCallback = memberRemovedCallback
+		config.MemberRemovedCallback = chainCallback(config.MemberRemovedCallback, memberRemovedCallback)
+	}
+}
+
+func WithMemberStateChangedCallback(memberStateChangedCallback func(address encoding.Address, state MemberState)) Option {
+	return func(config *Config) {
+		config.MemberStateChangedCallback = chainCallback2(config.MemberStateChangedCallback, memberStateChangedCallback)
+	}
+}
+
+func WithMemberRoundTripTimeCallback(memberRoundTripTimeCallback func(address encoding.Address, roundTripTime time.Duration)) Option {
+	return func(config *Config) {
+		config.MemberRoundTripTimeCallback = chainCallback2(config.MemberRoundTripTimeCallback, memberRoundTripTimeCallback)
 	}
 }
 
@@ -120,3 +133,285 @@ func WithReconnectBootstrapMembers(reconnect bool) Option {
 		config.ReconnectBootstrapMembers = reconnect
 	}
 }
+
+// WithReconnectFaultyMemberCount sets the number of faulty members which are probed every protocol period.
+func WithReconnectFaultyMemberCount(memberCount int) Option {
+	return func(config *Config) {
+		config.ReconnectFaultyMemberCount = memberCount
+	}
+}
+
+// WithReconnectMaxBackoff sets the maximum time between two reconnect attempts to the same member.
+func WithReconnectMaxBackoff(maxBackoff time.Duration) Option {
+	return func(config *Config) {
+		config.ReconnectMaxBackoff = maxBackoff
+	}
+}
+
+func WithPushPullListSync(pushPull bool) Option {
+	return func(config *Config) {
+		config.PushPullListSync = pushPull
+	}
+}
+
+// WithIncarnationStore sets the given store for persisting the incarnation number across restarts.
+func WithIncarnationStore(store IncarnationStore) Option {
+	return func(config *Config) {
+		config.IncarnationStore = store
+	}
+}
+
+// WithIncarnationFile persists the incarnation number across restarts in the file with the given path.
+func WithIncarnationFile(path string) Option {
+	return func(config *Config) {
+		config.IncarnationStore = NewIncarnationFile(path)
+	}
+}
+
+// WithSnapshotPath periodically writes the known members to the file with the given path and reads them back as join
+// candidates on startup.
+func WithSnapshotPath(path string) Option {
+	return func(config *Config) {
+		config.SnapshotPath = path
+	}
+}
+
+func WithSnapshotInterval(interval time.Duration) Option {
+	return func(config *Config) {
+		config.SnapshotInterval = interval
+	}
+}
+
+func WithSnapshotMaxAge(maxAge time.Duration) Option {
+	return func(config *Config) {
+		config.SnapshotMaxAge = maxAge
+	}
+}
+
+func WithHealthPolicy(policy HealthPolicy) Option {
+	return func(config *Config) {
+		config.HealthPolicy = policy
+	}
+}
+
+func WithHealthCheckInterval(interval time.Duration) Option {
+	return func(config *Config) {
+		config.HealthCheckInterval = interval
+	}
+}
+
+func WithHealthCheckTimeout(timeout time.Duration) Option {
+	return func(config *Config) {
+		config.HealthCheckTimeout = timeout
+	}
+}
+
+func WithAdmissionCallback(admissionCallback func(address encoding.Address, source encoding.Address) error) Option {
+	return func(config *Config) {
+		config.AdmissionCallback = admissionCallback
+	}
+}
+
+func WithMergeCallback(mergeCallback func(event MergeEvent)) Option {
+	return func(config *Config) {
+		config.MergeCallback = chainCallback(config.MergeCallback, mergeCallback)
+	}
+}
+
+// WithAckPayloadCallback sets the callback which provides the payload attached to every ack this member sends.
+func WithAckPayloadCallback(ackPayloadCallback func() []byte) Option {
+	return func(config *Config) {
+		config.AckPayloadCallback = ackPayloadCallback
+	}
+}
+
+func WithMaxAckPayloadLength(length int) Option {
+	return func(config *Config) {
+		config.MaxAckPayloadLength = length
+	}
+}
+
+// WithBroadcastCallback adds the callback which receives the broadcasts of other members.
+func WithBroadcastCallback(broadcastCallback func(origin Address, payload []byte)) Option {
+	return func(config *Config) {
+		config.BroadcastCallback = chainCallback2(config.BroadcastCallback, broadcastCallback)
+	}
+}
+
+// WithMinorityPartitionCallback adds the callback which is notified when this member loses or regains quorum.
+func WithMinorityPartitionCallback(minorityPartitionCallback func(minority bool)) Option {
+	return func(config *Config) {
+		config.MinorityPartitionCallback = chainCallback(config.MinorityPartitionCallback, minorityPartitionCallback)
+	}
+}
+
+// WithObserver sets the given observer which is notified about protocol activity.
+func WithObserver(observer Observer) Option {
+	return func(config *Config) {
+		config.Observer = observer
+	}
+}
+
+// WithMetricsRegisterer registers the metrics of the list with the given prometheus registerer. The const labels are
+// added to all metrics and might be nil.
+func WithMetricsRegisterer(registerer prometheus.Registerer, constLabels prometheus.Labels) Option {
+	return func(config *Config) {
+		config.MetricsRegisterer = registerer
+		config.MetricsConstLabels = constLabels
+	}
+}
+
+// WithAllowedNetworks only admits unknown members from the given networks. See Config.AllowedNetworks for the format.
+func WithAllowedNetworks(networks []string) Option {
+	return func(config *Config) {
+		config.AllowedNetworks = append(config.AllowedNetworks, networks...)
+	}
+}
+
+// WithDeniedNetworks never admits unknown members from the given networks. See Config.AllowedNetworks for the format.
+func WithDeniedNetworks(networks []string) Option {
+	return func(config *Config) {
+		config.DeniedNetworks = append(config.DeniedNetworks, networks...)
+	}
+}
+
+func WithForceRemoveListRequestCount(count int) Option {
+	return func(config *Config) {
+		config.ForceRemoveListRequestCount = count
+	}
+}
+
+func WithQueryTimeout(timeout time.Duration) Option {
+	return func(config *Config) {
+		config.QueryTimeout = timeout
+	}
+}
+
+func WithQueryHandlerTimeout(timeout time.Duration) Option {
+	return func(config *Config) {
+		config.QueryHandlerTimeout = timeout
+	}
+}
+
+func WithQueryResponseBufferSize(size int) Option {
+	return func(config *Config) {
+		config.QueryResponseBufferSize = max(1, size)
+	}
+}
+
+func WithMaxConcurrentQueryHandlers(count int) Option {
+	return func(config *Config) {
+		config.MaxConcurrentQueryHandlers = max(1, count)
+	}
+}
+
+func WithMergeMinRevivedMembers(count int) Option {
+	return func(config *Config) {
+		config.MergeMinRevivedMembers = max(1, count)
+	}
+}
+
+// WithPassive makes this member follow the membership of the cluster without being a member itself.
+func WithPassive(passive bool) Option {
+	return func(config *Config) {
+		config.Passive = passive
+	}
+}
+
+// WithMemberHistoryLength keeps the given number of recent state transitions for every member.
+func WithMemberHistoryLength(length int) Option {
+	return func(config *Config) {
+		config.MemberHistoryLength = length
+	}
+}
+
+// WithPartialView makes this member keep only a partial view of the cluster. See Config.PartialView for details.
+func WithPartialView(partialView bool) Option {
+	return func(config *Config) {
+		config.PartialView = partialView
+	}
+}
+
+func WithActiveViewSize(size int) Option {
+	return func(config *Config) {
+		config.ActiveViewSize = size
+	}
+}
+
+func WithPassiveViewSize(size int) Option {
+	return func(config *Config) {
+		config.PassiveViewSize = size
+	}
+}
+
+func WithEagerPushMemberCount(count int) Option {
+	return func(config *Config) {
+		config.EagerPushMemberCount = count
+	}
+}
+
+func WithBroadcastGraftTimeout(timeout time.Duration) Option {
+	return func(config *Config) {
+		config.BroadcastGraftTimeout = timeout
+	}
+}
+
+func WithFailureDetector(detector FailureDetector) Option {
+	return func(config *Config) {
+		config.FailureDetector = detector
+	}
+}
+
+func WithPhiThreshold(threshold float64) Option {
+	return func(config *Config) {
+		config.PhiThreshold = threshold
+	}
+}
+
+func WithExpectedClusterSize(size int) Option {
+	return func(config *Config) {
+		config.ExpectedClusterSize = size
+	}
+}
+
+func WithQuorumFraction(fraction float64) Option {
+	return func(config *Config) {
+		config.QuorumFraction = fraction
+	}
+}
+
+func WithQuorumWindow(window time.Duration) Option {
+	return func(config *Config) {
+		config.QuorumWindow = window
+	}
+}
+
+func WithMaxGossipQueueLength(length int) Option {
+	return func(config *Config) {
+		config.MaxGossipQueueLength = length
+	}
+}
+
+func WithEvictionListRequestInterval(interval time.Duration) Option {
+	return func(config *Config) {
+		config.EvictionListRequestInterval = interval
+	}
+}
+
+func WithSendWorkerCount(count int) Option {
+	return func(config *Config) {
+		config.SendWorkerCount = count
+	}
+}
+
+func WithSendQueueLength(length int) Option {
+	return func(config *Config) {
+		config.SendQueueLength = length
+	}
+}
+
+func WithSendDrainTimeout(timeout time.Duration) Option {
+	return func(config *Config) {
+		config.SendDrainTimeout = timeout
+	}
+}
@@ -0,0 +1,280 @@
+package membership
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/backbone81/membership/internal/encoding"
+	intmembership "github.com/backbone81/membership/internal/membership"
+)
+
+// QueryHandler answers a query received from some member. The returned payload is sent back to the member which
+// started the query. No response is sent when an error is returned. The context is canceled when the handler exceeds
+// the configured timeout.
+type QueryHandler func(ctx context.Context, payload []byte) ([]byte, error)
+
+// QueryResponse is the answer of a single member to a query.
+type QueryResponse struct {
+	// From is the address of the member which responded.
+	From Address
+
+	// Payload is the data returned by the query handler of that member.
+	Payload []byte
+}
+
+// QueryStats provides the number of members which acknowledged and responded to a query.
+type QueryStats struct {
+	// Acks is the number of members which received the query and matched the filter.
+	Acks int
+
+	// Responses is the number of members which responded to the query.
+	Responses int
+
+	// Dropped is the number of responses which were dropped because the response channel was full.
+	Dropped int
+}
+
+// QueryResult collects the responses to a query until the context of the query is done.
+type QueryResult struct {
+	// responses receives every response to the query. It is closed when the context of the query is done.
+	responses chan QueryResponse
+
+	// mutex serializes access to stats and closed. Sending to responses happens under the lock as well, to never send
+	// on a closed channel.
+	mutex  sync.Mutex
+	stats  QueryStats
+	closed bool
+}
+
+func newQueryResult(bufferSize int) *QueryResult {
+	return &QueryResult{
+		responses: make(chan QueryResponse, bufferSize),
+	}
+}
+
+// Responses returns the channel which receives the responses to the query. The channel is closed when the context of
+// the query is done. Responses which arrive while the channel is full are dropped, so the channel should be drained
+// continuously.
+func (r *QueryResult) Responses() <-chan QueryResponse {
+	return r.responses
+}
+
+// Stats returns the number of members which acknowledged and responded to the query so far.
+func (r *QueryResult) Stats() QueryStats {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.stats
+}
+
+func (r *QueryResult) ack() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.stats.Acks++
+}
+
+func (r *QueryResult) respond(response QueryResponse) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.closed {
+		return
+	}
+	r.stats.Responses++
+	select {
+	case r.responses <- response:
+	default:
+		r.stats.Dropped++
+	}
+}
+
+func (r *QueryResult) close() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.closed = true
+	close(r.responses)
+}
+
+// queryManager keeps track of the registered query handlers and the queries started by this member. It is bound to
+// the query callbacks of the internal membership list.
+type queryManager struct {
+	logger             logr.Logger
+	self               Address
+	handlerTimeout     time.Duration
+	responseBufferSize int
+
+	// list is the membership list to send responses with. It is set after the list was created, as the list needs
+	// the callbacks of the query manager on creation.
+	list *intmembership.List
+
+	// handlerSlots limits the number of query handlers running at the same time. A query handler occupies a slot by
+	// sending to the channel and frees it by receiving from the channel.
+	handlerSlots chan struct{}
+
+	// nextQueryID provides the id for the next query. It starts at a random value to make collisions with queries of
+	// a previous run of this member unlikely.
+	nextQueryID atomic.Uint32
+
+	// mutex serializes access to handlers and pending.
+	mutex    sync.Mutex
+	handlers map[string]QueryHandler
+	pending  map[uint32]*QueryResult
+}
+
+func newQueryManager(
+	logger logr.Logger,
+	self Address,
+	handlerTimeout time.Duration,
+	responseBufferSize int,
+	maxConcurrentHandlers int,
+) *queryManager {
+	manager := queryManager{
+		logger:             logger,
+		self:               self,
+		handlerTimeout:     handlerTimeout,
+		responseBufferSize: responseBufferSize,
+		handlerSlots:       make(chan struct{}, maxConcurrentHandlers),
+		handlers:           make(map[string]QueryHandler),
+		pending:            make(map[uint32]*QueryResult),
+	}
+	manager.nextQueryID.Store(rand.Uint32())
+	return &manager
+}
+
+// Register adds the given handler for queries with the given name. Registering a handler with a name which already
+// exists replaces the existing handler.
+func (m *queryManager) Register(name string, handler QueryHandler) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.handlers[name] = handler
+}
+
+// Query starts a new query and collects the responses until the given context is done.
+func (m *queryManager) Query(ctx context.Context, name string, payload []byte, filter []Address) (*QueryResult, error) {
+	queryID := m.nextQueryID.Add(1)
+	result := newQueryResult(m.responseBufferSize)
+
+	// The query needs to be pending before it is disseminated, otherwise we might miss early acks and responses.
+	m.mutex.Lock()
+	m.pending[queryID] = result
+	m.mutex.Unlock()
+
+	if err := m.list.Query(queryID, name, payload, filter); err != nil {
+		m.finish(queryID)
+		return nil, err
+	}
+
+	// The membership list does not deliver our own queries to ourselves. We take care of that here.
+	query := encoding.MessageQuery{
+		Source:  m.self,
+		QueryID: queryID,
+		Name:    name,
+		Payload: payload,
+		Filter:  filter,
+	}
+	if query.Matches(m.self) {
+		result.ack()
+		m.handle(query)
+	}
+
+	go func() {
+		<-ctx.Done()
+		m.finish(queryID)
+	}()
+	return result, nil
+}
+
+// finish stops collecting responses for the query with the given id.
+func (m *queryManager) finish(queryID uint32) {
+	m.mutex.Lock()
+	result, found := m.pending[queryID]
+	delete(m.pending, queryID)
+	m.mutex.Unlock()
+
+	if found {
+		result.close()
+	}
+}
+
+// OnQuery is bound to the query callback of the membership list. It executes under the lock of the membership list.
+func (m *queryManager) OnQuery(query encoding.MessageQuery) {
+	// The payload aliases the network buffer. We need a copy, as the handler runs after the callback returned.
+	query.Payload = append([]byte(nil), query.Payload...)
+	query.Filter = nil
+	m.handle(query)
+}
+
+// OnQueryAck is bound to the query ack callback of the membership list. It executes under the lock of the membership
+// list.
+func (m *queryManager) OnQueryAck(_ encoding.Address, queryID uint32) {
+	m.mutex.Lock()
+	result, found := m.pending[queryID]
+	m.mutex.Unlock()
+
+	if found {
+		result.ack()
+	}
+}
+
+// OnQueryResponse is bound to the query response callback of the membership list. It executes under the lock of the
+// membership list.
+func (m *queryManager) OnQueryResponse(source encoding.Address, queryID uint32, payload []byte) {
+	m.mutex.Lock()
+	result, found := m.pending[queryID]
+	m.mutex.Unlock()
+
+	if found {
+		result.respond(QueryResponse{
+			From:    source,
+			Payload: append([]byte(nil), payload...),
+		})
+	}
+}
+
+// handle runs the handler registered for the query in a separate go routine and sends the response back to the member
+// which started the query. The query is dropped when the maximum number of query handlers is running already. That way
+// a flood of queries cannot pile up an unbounded number of go routines.
+func (m *queryManager) handle(query encoding.MessageQuery) {
+	m.mutex.Lock()
+	handler, found := m.handlers[query.Name]
+	m.mutex.Unlock()
+
+	if !found {
+		return
+	}
+	select {
+	case m.handlerSlots <- struct{}{}:
+	default:
+		m.logger.V(1).Info("Dropping query, too many query handlers are running", "name", query.Name, "source", query.Source)
+		return
+	}
+	go func() {
+		defer func() {
+			<-m.handlerSlots
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), m.handlerTimeout)
+		defer cancel()
+
+		response, err := handler(ctx, query.Payload)
+		if err != nil {
+			m.logger.Error(err, "Query handler failed.", "name", query.Name, "source", query.Source)
+			return
+		}
+		if query.Source == m.self {
+			m.OnQueryResponse(m.self, query.QueryID, response)
+			return
+		}
+		if err := m.list.RespondToQuery(query.Source, query.QueryID, response); err != nil {
+			m.logger.Error(err, "Sending the query response failed.", "name", query.Name, "source", query.Source)
+		}
+	}()
+}
@@ -0,0 +1,42 @@
+package ring
+
+import "github.com/backbone81/membership/pkg/membership"
+
+// Change describes a member joining or leaving the ring.
+type Change struct {
+	// Member is the member which joined or left.
+	Member membership.Address
+
+	// Joined reports if the member joined the ring. The member left the ring otherwise.
+	Joined bool
+
+	// Moved holds the key ranges which changed their primary owner, ordered by their start. It is only provided with
+	// StrategyConsistent. With the other strategies, keys do not form ranges, and applications need to check the
+	// owners of their keys again.
+	Moved []Range
+}
+
+// Range is a range of key hashes which moved from one primary owner to another one. The range includes all key hashes
+// after Start up to and including End, as returned by Hash. The range wraps around the end of the hash space when End
+// is not bigger than Start.
+type Range struct {
+	// Start is the key hash before the range.
+	Start uint64
+
+	// End is the last key hash of the range.
+	End uint64
+
+	// From is the primary owner before the change. The zero address when the ring was empty before.
+	From membership.Address
+
+	// To is the primary owner after the change. The zero address when the ring is empty after.
+	To membership.Address
+}
+
+// Contains reports if the given key hash is part of the range.
+func (r Range) Contains(keyHash uint64) bool {
+	if r.Start < r.End {
+		return r.Start < keyHash && keyHash <= r.End
+	}
+	return r.Start < keyHash || keyHash <= r.End
+}
@@ -0,0 +1,176 @@
+package ring
+
+import (
+	"slices"
+	"sort"
+)
+
+// chunkLength is the maximum number of elements in a single chunk of a chunkedList.
+const chunkLength = 256
+
+// chunkedList is an immutable list of elements split into chunks of at most chunkLength elements. Changes are made
+// through an editor, which copies only the chunks it modifies and shares all other chunks with the list it was created
+// from. This keeps the cost of a change to a big list at copying the chunk headers and the changed chunks, instead of
+// copying all elements, which would make mass joins quadratic.
+type chunkedList[T any] struct {
+	// chunks holds the elements. No chunk is empty. Chunks are shared between lists and must never be modified after
+	// the list was built.
+	chunks [][]T
+
+	// starts holds the index of the first element of every chunk.
+	starts []int
+
+	// length is the number of elements over all chunks.
+	length int
+}
+
+// len returns the number of elements.
+func (l *chunkedList[T]) len() int {
+	return l.length
+}
+
+// at returns the element with the given index.
+func (l *chunkedList[T]) at(index int) T {
+	chunkIndex := l.chunkIndex(index)
+	return l.chunks[chunkIndex][index-l.starts[chunkIndex]]
+}
+
+// forEach executes the given function for all elements in order. Return false to abort the iteration.
+func (l *chunkedList[T]) forEach(fn func(T) bool) {
+	for _, chunk := range l.chunks {
+		for _, element := range chunk {
+			if !fn(element) {
+				return
+			}
+		}
+	}
+}
+
+// chunkIndex returns the index of the chunk holding the element with the given index.
+func (l *chunkedList[T]) chunkIndex(index int) int {
+	return sort.Search(len(l.starts), func(i int) bool {
+		return l.starts[i] > index
+	}) - 1
+}
+
+// searchChunkedList searches the given target in the list, which must be sorted by the given compare function.
+// Returns the index the target is at or would be inserted at, and reports if the target was found.
+func searchChunkedList[T, E any](l *chunkedList[T], target E, cmp func(T, E) int) (int, bool) {
+	if len(l.chunks) == 0 {
+		return 0, false
+	}
+	chunkIndex, offset, found := searchChunks(l.chunks, target, cmp)
+	return l.starts[chunkIndex] + offset, found
+}
+
+// searchChunks searches the given target in the given chunks, which must be sorted by the given compare function.
+// Returns the chunk and the offset within the chunk the target is at or would be inserted at, and reports if the target
+// was found. Targets after the last element are inserted at the end of the last chunk.
+func searchChunks[T, E any](chunks [][]T, target E, cmp func(T, E) int) (int, int, bool) {
+	chunkIndex, _ := slices.BinarySearchFunc(chunks, target, func(chunk []T, target E) int {
+		return cmp(chunk[len(chunk)-1], target)
+	})
+	if chunkIndex == len(chunks) {
+		if chunkIndex == 0 {
+			return 0, 0, false
+		}
+		return chunkIndex - 1, len(chunks[chunkIndex-1]), false
+	}
+	offset, found := slices.BinarySearchFunc(chunks[chunkIndex], target, cmp)
+	return chunkIndex, offset, found
+}
+
+// chunkedListEditor builds a new chunkedList from an existing one. Chunks shared with the existing list are copied
+// before they are modified. The editor addresses elements by chunk and offset, as the starts of the chunks are only
+// updated once the new list is built. This keeps many changes to a big list from updating all starts every time.
+type chunkedListEditor[T any] struct {
+	chunks [][]T
+
+	// owned reports for every chunk if it was copied by this editor and can be modified in place.
+	owned []bool
+}
+
+// edit returns an editor for a new list which starts out with the elements of this list. The list itself is never
+// modified.
+func (l *chunkedList[T]) edit() *chunkedListEditor[T] {
+	return &chunkedListEditor[T]{
+		chunks: slices.Clone(l.chunks),
+		owned:  make([]bool, len(l.chunks)),
+	}
+}
+
+// search searches the given target in the elements, which must be sorted by the given compare function. See
+// searchChunks for details.
+func (e *chunkedListEditor[T]) search(target T, cmp func(T, T) int) (int, int, bool) {
+	return searchChunks(e.chunks, target, cmp)
+}
+
+// insert inserts the given element at the given offset of the given chunk.
+func (e *chunkedListEditor[T]) insert(chunkIndex int, offset int, element T) {
+	if len(e.chunks) == 0 {
+		e.chunks = append(e.chunks, make([]T, 0, chunkLength+1))
+		e.owned = append(e.owned, true)
+	}
+	chunk := slices.Insert(e.own(chunkIndex), offset, element)
+	e.chunks[chunkIndex] = chunk
+	if len(chunk) <= chunkLength {
+		return
+	}
+
+	// The chunk is full, we split it in half.
+	half := len(chunk) / 2
+	upperHalf := make([]T, len(chunk)-half, chunkLength+1)
+	copy(upperHalf, chunk[half:])
+	e.chunks[chunkIndex] = chunk[:half]
+	e.chunks = slices.Insert(e.chunks, chunkIndex+1, upperHalf)
+	e.owned = slices.Insert(e.owned, chunkIndex+1, true)
+}
+
+// delete removes the element at the given offset of the given chunk.
+func (e *chunkedListEditor[T]) delete(chunkIndex int, offset int) {
+	if len(e.chunks[chunkIndex]) == 1 {
+		// Chunks are never empty.
+		e.chunks = slices.Delete(e.chunks, chunkIndex, chunkIndex+1)
+		e.owned = slices.Delete(e.owned, chunkIndex, chunkIndex+1)
+		return
+	}
+	e.chunks[chunkIndex] = slices.Delete(e.own(chunkIndex), offset, offset+1)
+}
+
+// own returns the chunk with the given index for modification. The chunk is copied first, unless this editor already
+// copied it. The copy has room for one more element than a full chunk holds, which allows for inserting before
+// splitting without growing the chunk.
+func (e *chunkedListEditor[T]) own(chunkIndex int) []T {
+	if !e.owned[chunkIndex] {
+		chunk := make([]T, len(e.chunks[chunkIndex]), chunkLength+1)
+		copy(chunk, e.chunks[chunkIndex])
+		e.chunks[chunkIndex] = chunk
+		e.owned[chunkIndex] = true
+	}
+	return e.chunks[chunkIndex]
+}
+
+// list returns the new list. The editor must not be used afterward.
+func (e *chunkedListEditor[T]) list() *chunkedList[T] {
+	length := 0
+	for _, chunk := range e.chunks {
+		length += len(chunk)
+	}
+	chunks := e.chunks
+	if len(chunks) > 1 && length < len(chunks)*chunkLength/4 {
+		// Many elements were removed, leaving mostly empty chunks behind. We pack the elements into full chunks again
+		// to keep the number of chunks proportional to the number of elements.
+		chunks = slices.Collect(slices.Chunk(slices.Concat(chunks...), chunkLength))
+	}
+	result := &chunkedList[T]{
+		chunks: chunks,
+		starts: make([]int, 0, len(chunks)),
+		length: length,
+	}
+	start := 0
+	for _, chunk := range chunks {
+		result.starts = append(result.starts, start)
+		start += len(chunk)
+	}
+	return result
+}
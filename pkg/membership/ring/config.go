@@ -0,0 +1,22 @@
+package ring
+
+// Config is the configuration the ring is using.
+type Config struct {
+	// Strategy selects how keys are placed on members.
+	Strategy Strategy
+
+	// VirtualNodes is the number of virtual nodes every member is placed on the ring with, when using
+	// StrategyConsistent. More virtual nodes spread the keys more evenly at the cost of memory and slower membership
+	// changes.
+	VirtualNodes int
+
+	// ChangedCallback is the callback which is triggered after a member joined or left the ring. It executes under the
+	// lock of the ring, but lookups do not take that lock and can be used from within the callback.
+	ChangedCallback func(change Change)
+}
+
+// DefaultConfig provides a ring configuration with sane defaults for most situations.
+var DefaultConfig = Config{
+	Strategy:     StrategyConsistent,
+	VirtualNodes: 128,
+}
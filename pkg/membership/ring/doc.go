@@ -0,0 +1,8 @@
+// Package ring places keys on members for partitioning data across the cluster, like in distributed databases and cache
+// clusters. It offers consistent hashing with virtual nodes, rendezvous hashing and jump hashing. The ring follows
+// membership changes through the callbacks of the membership list and reports which key ranges moved, so applications
+// can rebalance their data. Lookups never take any lock and never touch the membership list.
+//
+// Like the membership list itself, the ring is eventually consistent. While membership changes propagate through the
+// cluster, different members might disagree about the owners of a key for a short while.
+package ring
@@ -0,0 +1,25 @@
+package ring
+
+// Option is the function signature for all ring options to implement.
+type Option func(config *Config)
+
+// WithStrategy sets how keys are placed on members.
+func WithStrategy(strategy Strategy) Option {
+	return func(config *Config) {
+		config.Strategy = strategy
+	}
+}
+
+// WithVirtualNodes sets the number of virtual nodes every member is placed on the ring with.
+func WithVirtualNodes(count int) Option {
+	return func(config *Config) {
+		config.VirtualNodes = max(1, count)
+	}
+}
+
+// WithChangedCallback sets the callback which is triggered after a member joined or left the ring.
+func WithChangedCallback(changedCallback func(change Change)) Option {
+	return func(config *Config) {
+		config.ChangedCallback = changedCallback
+	}
+}
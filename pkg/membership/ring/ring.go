@@ -0,0 +1,389 @@
+package ring
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/backbone81/membership/internal/encoding"
+	"github.com/backbone81/membership/internal/hashing"
+	"github.com/backbone81/membership/pkg/membership"
+)
+
+// Ring places keys on members.
+//
+// The ring learns about members through MemberAdded and MemberRemoved. Use Options to have the membership list call
+// those automatically. Note that the membership list never reports this member itself. Call MemberAdded with the
+// address of this member, when this member owns keys as well. Suspect members stay on the ring, as moving their keys
+// back and forth on brief flaps would be more expensive than a short unavailability.
+//
+// Every change builds a new immutable state from the previous one, which lookups read without taking any lock. That
+// way lookups scale with the number of goroutines and never wait for membership changes. The new state shares all
+// chunks of members and virtual nodes which did not change with the previous state. This keeps changes cheap in big
+// clusters, as they run within the callbacks of the membership list.
+//
+// Ring is safe for concurrent use by multiple goroutines.
+type Ring struct {
+	// mutex is responsible for serializing membership changes. Lookups do not take the lock.
+	mutex sync.Mutex
+
+	// config holds the configuration of the ring.
+	config Config
+
+	// state is the current immutable state of the ring.
+	state atomic.Pointer[state]
+}
+
+// state is an immutable snapshot of the ring.
+type state struct {
+	// members holds all members ordered by address.
+	members *chunkedList[member]
+
+	// virtualNodes holds the virtual nodes of all members ordered by hash. Only used for StrategyConsistent.
+	virtualNodes *chunkedList[virtualNode]
+}
+
+// member is a member on the ring.
+type member struct {
+	address membership.Address
+
+	// hash is the hash of the address. Only used for StrategyRendezvous.
+	hash uint64
+}
+
+// compareMembers orders members by address.
+func compareMembers(a member, b member) int {
+	return encoding.CompareAddress(a.address, b.address)
+}
+
+// virtualNode is a position of a member on the hash ring.
+type virtualNode struct {
+	hash    uint64
+	address membership.Address
+}
+
+// compareVirtualNodes orders virtual nodes by hash. Hash collisions are ordered by address to stay deterministic.
+func compareVirtualNodes(lhs virtualNode, rhs virtualNode) int {
+	if result := cmp.Compare(lhs.hash, rhs.hash); result != 0 {
+		return result
+	}
+	return encoding.CompareAddress(lhs.address, rhs.address)
+}
+
+// Hash returns the key hash the ring places the given key with. Use it to check if a key is part of a moved range.
+func Hash(key string) uint64 {
+	return hashing.String(key)
+}
+
+// New creates a new ring. Provide options to customize default config.
+func New(options ...Option) *Ring {
+	config := DefaultConfig
+	for _, option := range options {
+		option(&config)
+	}
+
+	newRing := &Ring{
+		config: config,
+	}
+	newRing.state.Store(&state{
+		members:      &chunkedList[member]{},
+		virtualNodes: &chunkedList[virtualNode]{},
+	})
+	return newRing
+}
+
+// Config returns the config of the ring.
+func (r *Ring) Config() Config {
+	return r.config
+}
+
+// Options returns the options for the membership list which keep the ring up to date.
+func (r *Ring) Options() []membership.Option {
+	return []membership.Option{
+		membership.WithMemberAddedCallback(r.MemberAdded),
+		membership.WithMemberRemovedCallback(r.MemberRemoved),
+	}
+}
+
+// Len returns the number of members on the ring.
+func (r *Ring) Len() int {
+	return r.state.Load().members.len()
+}
+
+// Members appends all members on the ring ordered by address to the given slice and returns it.
+func (r *Ring) Members(addresses []membership.Address) []membership.Address {
+	r.state.Load().members.forEach(func(member member) bool {
+		addresses = append(addresses, member.address)
+		return true
+	})
+	return addresses
+}
+
+// MemberAdded places the member with the given address on the ring.
+func (r *Ring) MemberAdded(address membership.Address) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	previous := r.state.Load()
+	added := member{
+		address: address,
+		hash:    hashing.Address(address),
+	}
+	members := previous.members.edit()
+	chunkIndex, offset, found := members.search(added, compareMembers)
+	if found {
+		return
+	}
+	members.insert(chunkIndex, offset, added)
+	next := &state{
+		members:      members.list(),
+		virtualNodes: previous.virtualNodes,
+	}
+	switch r.config.Strategy {
+	case StrategyConsistent:
+		next.virtualNodes = r.addVirtualNodes(previous.virtualNodes, address)
+	case StrategyJump, StrategyRendezvous:
+	}
+	r.state.Store(next)
+	r.changed(previous, next, address, true)
+}
+
+// MemberRemoved removes the member with the given address from the ring.
+func (r *Ring) MemberRemoved(address membership.Address) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	previous := r.state.Load()
+	members := previous.members.edit()
+	chunkIndex, offset, found := members.search(member{address: address}, compareMembers)
+	if !found {
+		return
+	}
+	members.delete(chunkIndex, offset)
+	next := &state{
+		members:      members.list(),
+		virtualNodes: previous.virtualNodes,
+	}
+	switch r.config.Strategy {
+	case StrategyConsistent:
+		next.virtualNodes = r.removeVirtualNodes(previous.virtualNodes, address)
+	case StrategyJump, StrategyRendezvous:
+	}
+	r.state.Store(next)
+	r.changed(previous, next, address, false)
+}
+
+// Owner returns the primary owner of the given key. Reports false when the ring is empty.
+func (r *Ring) Owner(key string) (membership.Address, bool) {
+	var owners [1]membership.Address
+	if len(r.Owners(owners[:0], key, 1)) == 0 {
+		return membership.Address{}, false
+	}
+	return owners[0], true
+}
+
+// Owners appends up to count distinct owners of the given key to the given slice and returns it. The primary owner
+// comes first, followed by the replicas. Fewer owners are appended when there are not enough members on the ring. This
+// does not allocate, when the given slice has enough capacity.
+func (r *Ring) Owners(addresses []membership.Address, key string, count int) []membership.Address {
+	current := r.state.Load()
+	count = min(count, current.members.len())
+	if count <= 0 {
+		return addresses
+	}
+
+	keyHash := Hash(key)
+	switch r.config.Strategy {
+	case StrategyRendezvous:
+		return current.rendezvousOwners(addresses, keyHash, count)
+	case StrategyJump:
+		return current.jumpOwners(addresses, keyHash, count)
+	default:
+		return current.consistentOwners(addresses, keyHash, count)
+	}
+}
+
+// consistentOwners appends the members of the next virtual nodes after the given key hash on the ring.
+func (s *state) consistentOwners(addresses []membership.Address, keyHash uint64, count int) []membership.Address {
+	start := len(addresses)
+	index := s.virtualNodeIndex(keyHash)
+	nodeCount := s.virtualNodes.len()
+	for i := range nodeCount {
+		address := s.virtualNodes.at((index + i) % nodeCount).address
+		if slices.Contains(addresses[start:], address) {
+			continue
+		}
+		addresses = append(addresses, address)
+		if len(addresses)-start == count {
+			break
+		}
+	}
+	return addresses
+}
+
+// rendezvousOwners appends the members with the highest hashes of the given key hash and the member address.
+func (s *state) rendezvousOwners(addresses []membership.Address, keyHash uint64, count int) []membership.Address {
+	start := len(addresses)
+	for range count {
+		var best member
+		var bestScore uint64
+		found := false
+		s.members.forEach(func(member member) bool {
+			score := hashing.Combine(keyHash, member.hash)
+			if found && score <= bestScore {
+				// On equal scores, the member with the lower address wins, as members are ordered by address.
+				return true
+			}
+			if slices.Contains(addresses[start:], member.address) {
+				return true
+			}
+			best, bestScore, found = member, score, true
+			return true
+		})
+		addresses = append(addresses, best.address)
+	}
+	return addresses
+}
+
+// jumpOwners appends the member picked by jump consistent hashing, followed by the next members ordered by address.
+// The buckets of jump consistent hashing are the members ordered by address, which is the only order all members agree
+// on. Keys only move minimally when members join or leave at the end of that order, see StrategyJump.
+func (s *state) jumpOwners(addresses []membership.Address, keyHash uint64, count int) []membership.Address {
+	memberCount := s.members.len()
+	index := hashing.Jump(keyHash, memberCount)
+	for i := range count {
+		addresses = append(addresses, s.members.at((index+i)%memberCount).address)
+	}
+	return addresses
+}
+
+// virtualNodeIndex returns the index of the first virtual node at or after the given hash, wrapping around at the end
+// of the ring.
+func (s *state) virtualNodeIndex(hash uint64) int {
+	index, _ := searchChunkedList(s.virtualNodes, hash, func(node virtualNode, hash uint64) int {
+		return cmp.Compare(node.hash, hash)
+	})
+	if index == s.virtualNodes.len() {
+		return 0
+	}
+	return index
+}
+
+// primaryOwner returns the member owning the given hash. Returns the zero address when the ring is empty.
+func (s *state) primaryOwner(hash uint64) membership.Address {
+	if s.virtualNodes.len() == 0 {
+		return membership.Address{}
+	}
+	return s.virtualNodes.at(s.virtualNodeIndex(hash)).address
+}
+
+// memberVirtualNodes returns the virtual nodes of the member with the given address.
+func (r *Ring) memberVirtualNodes(address membership.Address) []virtualNode {
+	addressHash := hashing.Address(address)
+	nodes := make([]virtualNode, 0, r.config.VirtualNodes)
+	for i := range r.config.VirtualNodes {
+		nodes = append(nodes, virtualNode{
+			hash:    hashing.Combine(addressHash, uint64(i)),
+			address: address,
+		})
+	}
+	return nodes
+}
+
+// addVirtualNodes returns new virtual nodes with the virtual nodes of the given member added.
+func (r *Ring) addVirtualNodes(
+	virtualNodes *chunkedList[virtualNode],
+	address membership.Address,
+) *chunkedList[virtualNode] {
+	editor := virtualNodes.edit()
+	for _, node := range r.memberVirtualNodes(address) {
+		if chunkIndex, offset, found := editor.search(node, compareVirtualNodes); !found {
+			editor.insert(chunkIndex, offset, node)
+		}
+	}
+	return editor.list()
+}
+
+// removeVirtualNodes returns new virtual nodes without the virtual nodes of the given member.
+func (r *Ring) removeVirtualNodes(
+	virtualNodes *chunkedList[virtualNode],
+	address membership.Address,
+) *chunkedList[virtualNode] {
+	editor := virtualNodes.edit()
+	for _, node := range r.memberVirtualNodes(address) {
+		if chunkIndex, offset, found := editor.search(node, compareVirtualNodes); found {
+			editor.delete(chunkIndex, offset)
+		}
+	}
+	return editor.list()
+}
+
+// changed triggers the changed callback for the change from the previous to the next state.
+func (r *Ring) changed(previous *state, next *state, address membership.Address, joined bool) {
+	if r.config.ChangedCallback == nil {
+		return
+	}
+	change := Change{
+		Member: address,
+		Joined: joined,
+	}
+	if r.config.Strategy == StrategyConsistent {
+		change.Moved = r.movedRanges(previous, next, address, joined)
+	}
+	r.config.ChangedCallback(change)
+}
+
+// movedRanges returns the key ranges which changed their primary owner between the previous and the next state. Every
+// key range ends at a virtual node and is owned by the member of that virtual node. Only the ranges ending at the
+// virtual nodes of the member which joined or left change their primary owner. We therefore only look at the virtual
+// nodes of that member, which keeps the work independent of the size of the ring.
+func (r *Ring) movedRanges(previous *state, next *state, address membership.Address, joined bool) []Range {
+	// The virtual nodes of the member are part of the next state when it joined, and of the previous state when it left.
+	nodes := next.virtualNodes
+	if !joined {
+		nodes = previous.virtualNodes
+	}
+	indexes := make([]int, 0, r.config.VirtualNodes)
+	for _, node := range r.memberVirtualNodes(address) {
+		if index, found := searchChunkedList(nodes, node, compareVirtualNodes); found {
+			indexes = append(indexes, index)
+		}
+	}
+	slices.Sort(indexes)
+	indexes = slices.Compact(indexes)
+	if len(indexes) > 0 && indexes[0] == 0 {
+		// The range ending at the first virtual node wraps around the end of the ring. We visit it last, to keep the
+		// result ordered by start.
+		indexes = append(indexes[1:], 0)
+	}
+
+	var moved []Range
+	for _, index := range indexes {
+		start := nodes.at((index + nodes.len() - 1) % nodes.len()).hash
+		end := nodes.at(index).hash
+		if start == end && nodes.len() > 1 {
+			// The virtual node shares its hash with the virtual node before it. The range is empty.
+			continue
+		}
+		from := previous.primaryOwner(end)
+		to := next.primaryOwner(end)
+		if from == to {
+			continue
+		}
+		if len(moved) > 0 {
+			last := &moved[len(moved)-1]
+			if last.End == start && last.From == from && last.To == to {
+				last.End = end
+				continue
+			}
+		}
+		moved = append(moved, Range{
+			Start: start,
+			End:   end,
+			From:  from,
+			To:    to,
+		})
+	}
+	return moved
+}
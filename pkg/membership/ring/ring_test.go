@@ -0,0 +1,290 @@
+package ring_test
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/pkg/membership"
+	"github.com/backbone81/membership/pkg/membership/ring"
+)
+
+var _ = Describe("Ring", func() {
+	// keys returns the given number of distinct keys.
+	keys := func(count int) []string {
+		result := make([]string, 0, count)
+		for i := range count {
+			result = append(result, fmt.Sprintf("key-%d", i))
+		}
+		return result
+	}
+
+	// owners returns the primary owner of every given key.
+	owners := func(myRing *ring.Ring, keys []string) map[string]membership.Address {
+		result := make(map[string]membership.Address, len(keys))
+		for _, key := range keys {
+			owner, ok := myRing.Owner(key)
+			Expect(ok).To(BeTrue())
+			result[key] = owner
+		}
+		return result
+	}
+
+	for _, strategy := range []ring.Strategy{ring.StrategyConsistent, ring.StrategyRendezvous, ring.StrategyJump} {
+		Context(strategy.String(), func() {
+			var myRing *ring.Ring
+
+			BeforeEach(func() {
+				myRing = ring.New(ring.WithStrategy(strategy))
+				myRing.MemberAdded(TestAddress)
+				myRing.MemberAdded(TestAddress2)
+				myRing.MemberAdded(TestAddress3)
+			})
+
+			It("should track added and removed members", func() {
+				Expect(myRing.Len()).To(Equal(3))
+				myRing.MemberAdded(TestAddress)
+				Expect(myRing.Len()).To(Equal(3))
+				myRing.MemberRemoved(TestAddress2)
+				Expect(myRing.Members(nil)).To(Equal([]membership.Address{TestAddress, TestAddress3}))
+			})
+
+			It("should report nothing when empty", func() {
+				emptyRing := ring.New(ring.WithStrategy(strategy))
+				_, ok := emptyRing.Owner("key")
+				Expect(ok).To(BeFalse())
+				Expect(emptyRing.Owners(nil, "key", 3)).To(BeEmpty())
+			})
+
+			It("should return distinct owners", func() {
+				for _, key := range keys(100) {
+					result := myRing.Owners(nil, key, 3)
+					Expect(result).To(ConsistOf(TestAddress, TestAddress2, TestAddress3))
+					Expect(result[0]).To(Equal(MustOwner(myRing.Owner(key))))
+					Expect(myRing.Owners(nil, key, 5)).To(Equal(result))
+				}
+			})
+
+			It("should place keys deterministically", func() {
+				otherRing := ring.New(ring.WithStrategy(strategy))
+				otherRing.MemberAdded(TestAddress3)
+				otherRing.MemberAdded(TestAddress)
+				otherRing.MemberAdded(TestAddress2)
+				Expect(owners(otherRing, keys(100))).To(Equal(owners(myRing, keys(100))))
+			})
+
+			It("should spread keys across all members", func() {
+				counts := make(map[membership.Address]int)
+				for _, owner := range owners(myRing, keys(3000)) {
+					counts[owner]++
+				}
+				Expect(counts).To(HaveLen(3))
+				for _, count := range counts {
+					Expect(count).To(BeNumerically(">", 500))
+				}
+			})
+		})
+	}
+
+	for _, strategy := range []ring.Strategy{ring.StrategyConsistent, ring.StrategyRendezvous} {
+		It(fmt.Sprintf("should only move the keys of a removed member with %s", strategy), func() {
+			myRing := ring.New(ring.WithStrategy(strategy))
+			myRing.MemberAdded(TestAddress)
+			myRing.MemberAdded(TestAddress2)
+			myRing.MemberAdded(TestAddress3)
+			before := owners(myRing, keys(1000))
+			myRing.MemberRemoved(TestAddress2)
+			after := owners(myRing, keys(1000))
+			for key, owner := range before {
+				if owner != TestAddress2 {
+					Expect(after[key]).To(Equal(owner))
+				}
+			}
+		})
+	}
+
+	It("should only move keys to a member joining at the end with jump", func() {
+		myRing := ring.New(ring.WithStrategy(ring.StrategyJump))
+		myRing.MemberAdded(TestAddress)
+		myRing.MemberAdded(TestAddress2)
+		before := owners(myRing, keys(1000))
+		myRing.MemberAdded(TestAddress3)
+		after := owners(myRing, keys(1000))
+		for key, owner := range after {
+			if owner != TestAddress3 {
+				Expect(owner).To(Equal(before[key]))
+			}
+		}
+	})
+
+	It("should place keys like a new ring after many joins and leaves", func() {
+		address := func(i int) membership.Address {
+			return membership.NewAddress(net.IPv4(10, 0, byte(i>>8), byte(i)), 1024)
+		}
+		for _, strategy := range []ring.Strategy{ring.StrategyConsistent, ring.StrategyRendezvous, ring.StrategyJump} {
+			myRing := ring.New(ring.WithStrategy(strategy))
+			for i := range 1000 {
+				myRing.MemberAdded(address(i))
+			}
+			for i := range 1000 {
+				if i%10 != 0 {
+					myRing.MemberRemoved(address(i))
+				}
+			}
+
+			otherRing := ring.New(ring.WithStrategy(strategy))
+			for i := 990; i >= 0; i -= 10 {
+				otherRing.MemberAdded(address(i))
+			}
+			Expect(myRing.Members(nil)).To(Equal(otherRing.Members(nil)))
+			for _, key := range keys(1000) {
+				Expect(myRing.Owners(nil, key, 3)).To(Equal(otherRing.Owners(nil, key, 3)))
+			}
+		}
+	})
+
+	It("should report the moved ranges", func() {
+		var changes []ring.Change
+		myRing := ring.New(
+			ring.WithVirtualNodes(16),
+			ring.WithChangedCallback(func(change ring.Change) {
+				changes = append(changes, change)
+			}),
+		)
+
+		By("adding the first member")
+		myRing.MemberAdded(TestAddress)
+		Expect(changes).To(HaveLen(1))
+		Expect(changes[0].Member).To(Equal(TestAddress))
+		Expect(changes[0].Joined).To(BeTrue())
+		Expect(changes[0].Moved).To(HaveLen(1))
+		Expect(changes[0].Moved[0].From).To(Equal(membership.Address{}))
+		Expect(changes[0].Moved[0].To).To(Equal(TestAddress))
+		Expect(changes[0].Moved[0].Start).To(Equal(changes[0].Moved[0].End))
+
+		By("adding more members")
+		for i := range 10 {
+			myRing.MemberAdded(membership.NewAddress(net.IPv4(10, 0, 0, byte(i+1)), 1024))
+		}
+
+		By("comparing the moved ranges with the moved keys")
+		for _, joined := range []bool{true, false} {
+			before := owners(myRing, keys(3000))
+			changes = nil
+			if joined {
+				myRing.MemberAdded(TestAddress2)
+			} else {
+				myRing.MemberRemoved(TestAddress2)
+			}
+			after := owners(myRing, keys(3000))
+			Expect(changes).To(HaveLen(1))
+			Expect(changes[0].Joined).To(Equal(joined))
+			for key := range before {
+				var movedRange *ring.Range
+				for i := range changes[0].Moved {
+					if changes[0].Moved[i].Contains(ring.Hash(key)) {
+						Expect(movedRange).To(BeNil())
+						movedRange = &changes[0].Moved[i]
+					}
+				}
+				if before[key] == after[key] {
+					Expect(movedRange).To(BeNil())
+					continue
+				}
+				Expect(movedRange).ToNot(BeNil())
+				Expect(movedRange.From).To(Equal(before[key]))
+				Expect(movedRange.To).To(Equal(after[key]))
+			}
+		}
+	})
+
+	It("should not report moved ranges with other strategies", func() {
+		var changes []ring.Change
+		myRing := ring.New(
+			ring.WithStrategy(ring.StrategyRendezvous),
+			ring.WithChangedCallback(func(change ring.Change) {
+				changes = append(changes, change)
+			}),
+		)
+		myRing.MemberAdded(TestAddress)
+		myRing.MemberRemoved(TestAddress)
+		Expect(changes).To(Equal([]ring.Change{
+			{Member: TestAddress, Joined: true},
+			{Member: TestAddress},
+		}))
+	})
+
+	It("should not allocate memory for lookups", func() {
+		myRing := ring.New()
+		myRing.MemberAdded(TestAddress)
+		myRing.MemberAdded(TestAddress2)
+		myRing.MemberAdded(TestAddress3)
+		addresses := make([]membership.Address, 0, 3)
+		Expect(testing.AllocsPerRun(100, func() {
+			addresses = myRing.Owners(addresses[:0], "key", 3)
+		})).To(BeZero())
+	})
+})
+
+// MustOwner returns the owner and fails the test when there is none.
+func MustOwner(address membership.Address, ok bool) membership.Address {
+	GinkgoHelper()
+	Expect(ok).To(BeTrue())
+	return address
+}
+
+func BenchmarkRing_Owners(b *testing.B) {
+	for _, strategy := range []ring.Strategy{ring.StrategyConsistent, ring.StrategyRendezvous, ring.StrategyJump} {
+		for _, memberCount := range []int{16, 256} {
+			b.Run(fmt.Sprintf("%s with %d members", strategy, memberCount), func(b *testing.B) {
+				myRing := ring.New(ring.WithStrategy(strategy))
+				for i := range memberCount {
+					myRing.MemberAdded(membership.NewAddress(net.IPv4(10, 0, byte(i>>8), byte(i)), 1024))
+				}
+				addresses := make([]membership.Address, 0, 3)
+				b.ReportAllocs()
+				for b.Loop() {
+					addresses = myRing.Owners(addresses[:0], "key", 3)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkRing_MassJoin(b *testing.B) {
+	for _, memberCount := range []int{1024, 4096} {
+		b.Run(fmt.Sprintf("%d members", memberCount), func(b *testing.B) {
+			addresses := make([]membership.Address, 0, memberCount)
+			for i := range memberCount {
+				addresses = append(addresses, membership.NewAddress(net.IPv4(10, byte(i>>16), byte(i>>8), byte(i)), 1024))
+			}
+			b.ReportAllocs()
+			for b.Loop() {
+				myRing := ring.New(ring.WithChangedCallback(func(ring.Change) {}))
+				for _, address := range addresses {
+					myRing.MemberAdded(address)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkRing_MemberAdded(b *testing.B) {
+	for _, memberCount := range []int{256, 4096} {
+		b.Run(fmt.Sprintf("%d members", memberCount), func(b *testing.B) {
+			myRing := ring.New(ring.WithChangedCallback(func(ring.Change) {}))
+			for i := range memberCount {
+				myRing.MemberAdded(membership.NewAddress(net.IPv4(10, 0, byte(i>>8), byte(i)), 1024))
+			}
+			address := membership.NewAddress(net.IPv4(11, 0, 0, 0), 1024)
+			b.ReportAllocs()
+			for b.Loop() {
+				myRing.MemberAdded(address)
+				myRing.MemberRemoved(address)
+			}
+		})
+	}
+}
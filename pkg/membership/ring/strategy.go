@@ -0,0 +1,39 @@
+package ring
+
+// Strategy selects how keys are placed on members.
+type Strategy int
+
+const (
+	// StrategyConsistent places every member with a number of virtual nodes on a hash ring. A key is owned by the
+	// members of the next virtual nodes on the ring. Only the keys next to the virtual nodes of a member which joins or
+	// leaves move. This is the only strategy reporting the moved key ranges.
+	StrategyConsistent Strategy = iota
+
+	// StrategyRendezvous owns a key by the members with the highest hash of the key and the member address. Only the
+	// keys of a member which leaves or the keys a joining member wins move. Lookups scan all members, which makes this
+	// strategy a good fit for small clusters.
+	StrategyRendezvous
+
+	// StrategyJump places keys with jump consistent hashing on the members ordered by address. The replicas of a key
+	// are the members following its primary owner in that order. Lookups are fast and need no memory per member.
+	//
+	// This strategy assumes append-only membership: jump consistent hashing only moves keys minimally when members are
+	// added or removed at the end of the order. A member joining or leaving anywhere else shifts all members after it,
+	// which moves most keys and most replicas. Only use it when new members sort after all existing members by address
+	// and members rarely leave.
+	StrategyJump
+)
+
+// String returns a human-readable representation of the strategy.
+func (s Strategy) String() string {
+	switch s {
+	case StrategyConsistent:
+		return "consistent"
+	case StrategyRendezvous:
+		return "rendezvous"
+	case StrategyJump:
+		return "jump"
+	default:
+		return "unknown"
+	}
+}
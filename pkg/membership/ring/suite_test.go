@@ -0,0 +1,22 @@
+package ring_test
+
+import (
+	"net"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/backbone81/membership/pkg/membership"
+)
+
+var (
+	TestAddress  = membership.NewAddress(net.IPv4(1, 2, 3, 4), 1024)
+	TestAddress2 = membership.NewAddress(net.IPv4(11, 12, 13, 14), 1024)
+	TestAddress3 = membership.NewAddress(net.IPv4(21, 22, 23, 24), 1024)
+)
+
+func TestSuite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Ring Suite")
+}
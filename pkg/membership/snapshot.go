@@ -0,0 +1,50 @@
+package membership
+
+import (
+	"errors"
+	"io/fs"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/backbone81/membership/internal/encoding"
+	"github.com/backbone81/membership/internal/snapshot"
+)
+
+// joinCandidatesFromSnapshot returns the alive and suspect members of the snapshot at the given path. Those are
+// contacted in addition to the bootstrap members when joining. Tombstones of faulty members and this member itself are
+// skipped. Members we did not hear from or about for longer than maxAge are skipped as well, as they are most likely
+// gone. Members without a last seen timestamp fall back to the timestamp of the snapshot.
+//
+// A missing or broken snapshot must not prevent the member from starting up. We therefore only log problems and
+// continue without join candidates.
+func joinCandidatesFromSnapshot(logger logr.Logger, path string, maxAge time.Duration, self encoding.Address) []encoding.Address {
+	if path == "" {
+		return nil
+	}
+
+	memberSnapshot, err := snapshot.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			logger.Error(err, "Ignoring member snapshot")
+		}
+		return nil
+	}
+
+	var joinCandidates []encoding.Address
+	for _, member := range memberSnapshot.Members {
+		if member.State == encoding.MemberStateFaulty || member.Address.Equal(self) {
+			continue
+		}
+		lastSeen := member.LastSeen
+		if lastSeen.IsZero() {
+			lastSeen = memberSnapshot.Timestamp
+		}
+		if age := time.Since(lastSeen); maxAge > 0 && age > maxAge {
+			logger.V(1).Info("Ignoring stale member from snapshot", "address", member.Address, "age", age)
+			continue
+		}
+		joinCandidates = append(joinCandidates, member.Address)
+	}
+	return joinCandidates
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net"
 	"strconv"
+	"time"
 
 	"github.com/backbone81/membership/internal/encoding"
 )
@@ -77,3 +78,12 @@ func ResolveBootstrapMembers(bootstrapMembers []string) ([]encoding.Address, err
 	}
 	return result, joinedErr
 }
+
+// protocolPeriods converts the given duration into a number of protocol periods. It is rounded up to full protocol
+// periods and is at least one.
+func protocolPeriods(duration time.Duration, protocolPeriod time.Duration) int {
+	if protocolPeriod <= 0 {
+		return 1
+	}
+	return max(1, int((duration+protocolPeriod-1)/protocolPeriod))
+}
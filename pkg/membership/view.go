@@ -0,0 +1,13 @@
+package membership
+
+import (
+	"github.com/backbone81/membership/internal/encoding"
+	intmembership "github.com/backbone81/membership/internal/membership"
+)
+
+// Member describes a member with its address, state and incarnation number.
+type Member = encoding.Member
+
+// View is an immutable snapshot of the members which were alive or suspect at some point in time. A view never changes
+// after it was published and can be read without blocking the membership list.
+type View = intmembership.View